@@ -149,6 +149,10 @@ func (c *Client) initHTTPClient(serverName string) error {
 		tlsConfig.CipherSuites = tls.DefaultCipherSuites
 		//set the host name override
 		tlsConfig.ServerName = serverName
+		//set the client certificate override
+		if c.Config.TLSClientCert != nil {
+			tlsConfig.Certificates = append(tlsConfig.Certificates[:0], *c.Config.TLSClientCert)
+		}
 
 		tr.TLSClientConfig = tlsConfig
 	}
@@ -194,6 +198,10 @@ func (c *Client) GenCSR(req *api.CSRInfo, id string) ([]byte, core.Key, error) {
 
 	cr := c.newCertificateRequest(req)
 	cr.CN = id
+	//set the common name override
+	if req != nil && req.CN != "" {
+		cr.CN = req.CN
+	}
 
 	if (cr.KeyRequest == nil) || (cr.KeyRequest.Size() == 0 && cr.KeyRequest.Algo() == "") {
 		cr.KeyRequest = newCfsslBasicKeyRequest(api.NewBasicKeyRequest())
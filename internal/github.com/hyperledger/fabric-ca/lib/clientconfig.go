@@ -21,6 +21,8 @@ Please review third_party pinning scripts and patches for more details.
 package lib
 
 import (
+	stdtls "crypto/tls"
+
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/lib/tls"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
@@ -39,6 +41,8 @@ type ClientConfig struct {
 	CAName     string           `help:"Name of CA"`
 	CSP        core.CryptoSuite `mapstructure:"bccsp" hide:"true"`
 	ServerName string           `help:"CA server name to be used in case of host name override"`
+	// TLSClientCert overrides the TLS client key and certificate files when mutual authentication is enabled
+	TLSClientCert *stdtls.Certificate `hide:"true"`
 
 	Debug    bool   `opt:"d" help:"Enable debug level logging" hide:"true"`
 	LogLevel string `help:"Set logging level (info, warning, debug, error, fatal, critical)"`
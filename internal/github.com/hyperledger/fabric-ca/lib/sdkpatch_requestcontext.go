@@ -0,0 +1,46 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+Please review third_party pinning scripts and patches for more details.
+*/
+
+package lib
+
+import (
+	"context"
+	"net/http"
+)
+
+// WithContext returns a copy of the client whose requests are bound to the given context,
+// so that requests still in flight when the context is done are cancelled
+func (c *Client) WithContext(ctx context.Context) (*Client, error) {
+	err := c.Init()
+	if err != nil {
+		return nil, err
+	}
+	client := *c
+	client.httpClient = &http.Client{
+		Transport: &contextTransport{ctx: ctx, base: c.httpClient.Transport},
+		Timeout:   c.httpClient.Timeout,
+	}
+	return &client, nil
+}
+
+// contextTransport binds each request to a context before sending it
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+// RoundTrip sends the request with the context of the transport
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req.WithContext(t.ctx))
+}
@@ -79,22 +79,30 @@ func (csp *impl) KeyGen(opts bccsp.KeyGenOpts) (k bccsp.Key, err error) {
 	// Parse algorithm
 	switch opts.(type) {
 	case *bccsp.ECDSAKeyGenOpts:
-		ski, pub, err := csp.generateECKey(csp.conf.ellipticCurve, opts.Ephemeral())
+		ski, pub, err := csp.generateECKey(csp.conf.ellipticCurve, opts.Ephemeral(), sdkp11.ObjectAttributes{})
 		if err != nil {
 			return nil, errors.Wrapf(err, "Failed generating ECDSA key")
 		}
 		k = &ecdsaPrivateKey{ski, ecdsaPublicKey{ski, pub}}
 
 	case *bccsp.ECDSAP256KeyGenOpts:
-		ski, pub, err := csp.generateECKey(oidNamedCurveP256, opts.Ephemeral())
+		ski, pub, err := csp.generateECKey(oidNamedCurveP256, opts.Ephemeral(), sdkp11.ObjectAttributes{})
 		if err != nil {
 			return nil, errors.Wrapf(err, "Failed generating ECDSA P256 key")
 		}
 
 		k = &ecdsaPrivateKey{ski, ecdsaPublicKey{ski, pub}}
 
+	case *sdkp11.ECDSATemplateKeyGenOpts:
+		ski, pub, err := csp.generateECKey(csp.conf.ellipticCurve, opts.Ephemeral(), opts.(*sdkp11.ECDSATemplateKeyGenOpts).Template)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed generating ECDSA key from template")
+		}
+
+		k = &ecdsaPrivateKey{ski, ecdsaPublicKey{ski, pub}}
+
 	case *bccsp.ECDSAP384KeyGenOpts:
-		ski, pub, err := csp.generateECKey(oidNamedCurveP384, opts.Ephemeral())
+		ski, pub, err := csp.generateECKey(oidNamedCurveP384, opts.Ephemeral(), sdkp11.ObjectAttributes{})
 		if err != nil {
 			return nil, errors.Wrapf(err, "Failed generating ECDSA P384 key")
 		}
@@ -121,7 +121,7 @@ func oidFromNamedCurve(curve elliptic.Curve) (asn1.ObjectIdentifier, bool) {
 	return nil, false
 }
 
-func (csp *impl) generateECKey(curve asn1.ObjectIdentifier, ephemeral bool) (ski []byte, pubKey *ecdsa.PublicKey, err error) {
+func (csp *impl) generateECKey(curve asn1.ObjectIdentifier, ephemeral bool, template sdkp11.ObjectAttributes) (ski []byte, pubKey *ecdsa.PublicKey, err error) {
 
 	session := csp.pkcs11Ctx.GetSession()
 	defer csp.pkcs11Ctx.ReturnSession(session)
@@ -161,6 +161,15 @@ func (csp *impl) generateECKey(curve asn1.ObjectIdentifier, ephemeral bool) (ski
 		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
 	}
 
+	pubkeyT, err = sdkp11.MergeAttributes(pubkeyT, template.Public)
+	if err != nil {
+		return nil, nil, fmt.Errorf("P11: invalid public key template [%s]", err)
+	}
+	prvkeyT, err = sdkp11.MergeAttributes(prvkeyT, template.Private)
+	if err != nil {
+		return nil, nil, fmt.Errorf("P11: invalid private key template [%s]", err)
+	}
+
 	pub, prv, err := csp.pkcs11Ctx.GenerateKeyPair(session,
 		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)},
 		pubkeyT, prvkeyT)
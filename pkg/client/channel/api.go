@@ -8,6 +8,7 @@ package channel
 
 import (
 	reqContext "context"
+	"regexp"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
@@ -29,11 +30,43 @@ type requestOptions struct {
 	Timeouts      map[fab.TimeoutType]time.Duration //timeout options for channel client operations
 	ParentContext reqContext.Context                //parent grpc context for channel client operations (query, execute, invokehandler)
 	CCFilter      invoke.CCFilter
+	TransientData map[string][]byte //transient data added to the transient map of the request
+
+	// AdditionalChaincodes are added to the invocation chain so that their
+	// endorsement policies are included when selecting endorsers
+	AdditionalChaincodes []string
+
+	// MinBlockHeight is the minimum ledger height of the peers that the request is sent to
+	MinBlockHeight uint64
 }
 
 // RequestOption func for each Opts argument
 type RequestOption func(ctx context.Client, opts *requestOptions) error
 
+// ErrInvalidPattern is returned when registering for chaincode events with an event name pattern that is not a valid regular expression
+var ErrInvalidPattern = errors.New("invalid event name pattern")
+
+// eventOptions holds the options for registering for events
+type eventOptions struct {
+	startBlock       *uint64
+	eventNamePattern *regexp.Regexp
+}
+
+// EventOption func for each eventOptions argument
+type EventOption func(opts *eventOptions) error
+
+// privateDataOptions holds the options for querying private data
+type privateDataOptions struct {
+	fcn  string
+	args PrivateDataArgs
+}
+
+// PrivateDataOption func for each privateDataOptions argument
+type PrivateDataOption func(opts *privateDataOptions) error
+
+// PrivateDataArgs returns the chaincode arguments of a private data query for the given collection and key
+type PrivateDataArgs func(collection, key string) [][]byte
+
 // Request contains the parameters to query and execute an invocation transaction
 type Request struct {
 	ChaincodeID  string
@@ -60,6 +93,24 @@ type Response struct {
 	TxValidationCode pb.TxValidationCode
 	ChaincodeStatus  int32
 	Payload          []byte
+	// SimulationResult is set by Execute in simulation mode (see WithSimulationMode)
+	SimulationResult *invoke.SimulationResult
+}
+
+// QueryResponse holds the outcome of a single query submitted through BatchQuery
+type QueryResponse struct {
+	Response
+	// Err is the error returned by the query, nil if it succeeded
+	Err error
+}
+
+// TxCommitEvent contains the commit status of a transaction
+type TxCommitEvent struct {
+	TransactionID    fab.TransactionID
+	TxValidationCode pb.TxValidationCode
+	BlockNumber      uint64
+	// SourceURL is the URL of the peer that produced the event
+	SourceURL string
 }
 
 //WithTargets allows overriding of the target peers for the request
@@ -156,6 +207,49 @@ func WithParentContext(parentContext reqContext.Context) RequestOption {
 	}
 }
 
+// WithTransientData adds the given data to the transient map of the proposal.
+// Transient data is passed to the chaincode but not written to the ledger.
+// Entries override those with the same key in the transient map of the request.
+func WithTransientData(data map[string][]byte) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if o.TransientData == nil {
+			o.TransientData = make(map[string][]byte)
+		}
+		for k, v := range data {
+			o.TransientData[k] = v
+		}
+		return nil
+	}
+}
+
+// WithAdditionalEndorsers adds the endorsement policy of the given chaincode to the selection of endorsers.
+// This is needed if the invoked chaincode calls the given chaincode, since the endorsers must then satisfy
+// the endorsement policies of both chaincodes. It is equivalent to adding the chaincode to the invocation chain of the request.
+func WithAdditionalEndorsers(chaincodeName string) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if chaincodeName == "" {
+			return errors.New("chaincode name is required")
+		}
+		o.AdditionalChaincodes = append(o.AdditionalChaincodes, chaincodeName)
+		return nil
+	}
+}
+
+// WithMinBlockHeight sends the request only to peers whose ledger height is at least the given height, which
+// provides read-your-writes consistency for a query following a transaction: the height should then be the number
+// of the block of the transaction plus one. The ledger height of a peer is reported by gossip, so peer selection must
+// be based on Fabric's discovery service (see WithDiscoveryBasedSelection); peers whose height is unknown are excluded.
+// The client waits for a suitable peer until the request times out, in which case ErrNoPeerAtHeight is returned.
+func WithMinBlockHeight(height uint64) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		if height == 0 {
+			return errors.New("height must be greater than zero")
+		}
+		o.MinBlockHeight = height
+		return nil
+	}
+}
+
 //WithChaincodeFilter adds a chaincode filter for figuring out additional endorsers
 func WithChaincodeFilter(ccFilter invoke.CCFilter) RequestOption {
 	return func(ctx context.Client, o *requestOptions) error {
@@ -163,3 +257,46 @@ func WithChaincodeFilter(ccFilter invoke.CCFilter) RequestOption {
 		return nil
 	}
 }
+
+// WithStartBlock replays events starting at the given block number. The events of the
+// registration are received from an event service whose deliver stream is seeked to the
+// block, so that events missed e.g. while the application was down are delivered.
+func WithStartBlock(blockNum uint64) EventOption {
+	return func(o *eventOptions) error {
+		o.startBlock = &blockNum
+		return nil
+	}
+}
+
+// WithEventNamePattern matches the names of chaincode events against the given regular expression
+// instead of the event filter of the registration. The pattern is compiled when registering, and
+// ErrInvalidPattern is returned if it is not a valid regular expression. The event filter of the
+// registration must then be empty or equal to the pattern; registering fails otherwise. Since the
+// deliver service does not filter chaincode events, the pattern is applied by the event dispatcher
+// of the client.
+func WithEventNamePattern(pattern string) EventOption {
+	return func(o *eventOptions) error {
+		regExp, err := regexp.Compile(pattern)
+		if err != nil {
+			return errors.WithMessage(ErrInvalidPattern, err.Error())
+		}
+		o.eventNamePattern = regExp
+		return nil
+	}
+}
+
+// WithPrivateDataQuery sets the chaincode function that is invoked by QueryPrivateData, for chaincodes that
+// do not implement getPrivateData(collection, key). The arguments of the function are returned by args; if
+// args is nil, the function is invoked with the collection and the key.
+func WithPrivateDataQuery(fcn string, args PrivateDataArgs) PrivateDataOption {
+	return func(o *privateDataOptions) error {
+		if fcn == "" {
+			return errors.New("private data query function is required")
+		}
+		o.fcn = fcn
+		if args != nil {
+			o.args = args
+		}
+		return nil
+	}
+}
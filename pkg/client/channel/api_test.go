@@ -14,6 +14,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -117,3 +118,25 @@ func TestWithPeerSorter(t *testing.T) {
 	assert.NoError(t, err, "WithPeerSorter should not return error")
 	assert.Equal(t, opts.TargetSorter, &sorter, "sorter option should have been set")
 }
+
+func TestWithAdditionalEndorsers(t *testing.T) {
+	opts := requestOptions{}
+
+	err := WithAdditionalEndorsers("")(nil, &opts)
+	assert.Error(t, err, "expected error for empty chaincode name")
+
+	assert.NoError(t, WithAdditionalEndorsers("cc2")(nil, &opts))
+	assert.NoError(t, WithAdditionalEndorsers("cc3")(nil, &opts))
+	assert.Equal(t, []string{"cc2", "cc3"}, opts.AdditionalChaincodes)
+}
+
+func TestWithEventNamePattern(t *testing.T) {
+	opts := eventOptions{}
+
+	err := WithEventNamePattern("event[")(&opts)
+	assert.Equal(t, ErrInvalidPattern, errors.Cause(err))
+
+	assert.NoError(t, WithEventNamePattern("^event[0-9]+$")(&opts))
+	assert.True(t, opts.eventNamePattern.MatchString("event1"))
+	assert.False(t, opts.eventNamePattern.MatchString("myevent1"))
+}
@@ -17,21 +17,35 @@ package channel
 
 import (
 	reqContext "context"
+	"fmt"
+	"regexp"
+	"sync"
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/dynamicdiscovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/greylist"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
-	selectopts "github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/fabricselection"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/sorter/latencysorter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	"github.com/pkg/errors"
 )
 
+// defaultQueryWorkers is the default maximum number of queries that are submitted concurrently by BatchQuery
+const defaultQueryWorkers = 10
+
+// ErrTxTimeout is returned by WaitForTx if the transaction is not committed within the timeout
+var ErrTxTimeout = errors.New("timed out waiting for transaction to be committed")
+
 // Client enables access to a channel on a Fabric network.
 //
 // A channel client instance provides a handler to interact with peers on specified channel.
@@ -43,11 +57,100 @@ type Client struct {
 	eventService fab.EventService
 	greylist     *greylist.Filter
 	metrics      *metrics.ClientMetrics
+	targetPeers  []fab.Peer
+	replayRegs   *sync.Map // event service of each registration with a start block
+	selection    fab.SelectionService
+	discovery    fab.DiscoveryService
+
+	ordererFailover *ordererFailover
+	latencySorter   *latencysorter.Sorter
+	locality        string
+	simulation      bool
+	queryWorkers    int
 }
 
 // ClientOption describes a functional parameter for the New constructor
 type ClientOption func(*Client) error
 
+// WithTargetPeers sets the peers that all requests of the client are sent to, bypassing peer selection.
+// This is needed e.g. for private data collections and state-based endorsement policies, where the
+// chaincode's endorsement policy does not determine the required peers.
+// Targets specified for an individual request with WithTargets take precedence.
+func WithTargetPeers(peers []fab.Peer) ClientOption {
+	return func(client *Client) error {
+		if len(peers) == 0 {
+			return errors.New("at least one target peer is required")
+		}
+		for _, p := range peers {
+			if p == nil {
+				return errors.New("target peer is nil")
+			}
+		}
+		client.targetPeers = peers
+		return nil
+	}
+}
+
+// WithDiscoveryBasedSelection selects endorsers using Fabric's discovery service instead of the selection
+// service of the channel, which uses the static peer list and endorsement policies of the config if the
+// channel does not have the V1_2 capability. Peer membership and endorsement policies are then fetched
+// dynamically from the peers of the channel. Call Close to release the services when the client is no longer used.
+func WithDiscoveryBasedSelection() ClientOption {
+	return func(client *Client) error {
+		channelID := client.context.ChannelID()
+
+		discovery, err := dynamicdiscovery.NewChannelService(client.context, client.membership, channelID)
+		if err != nil {
+			return errors.WithMessage(err, "failed to create discovery service")
+		}
+
+		selection, err := fabricselection.New(client.context, channelID, discovery)
+		if err != nil {
+			discovery.Close()
+			return errors.WithMessage(err, "failed to create selection service")
+		}
+
+		client.discovery = discovery
+		client.selection = selection
+		return nil
+	}
+}
+
+// WithOrdererFailover sends transactions to the orderers of the channel in the order determined by the given strategy.
+// If an orderer fails with a transient error (e.g. the orderer is unavailable) then the transaction is sent to the next orderer.
+// The order is updated on each transaction (see RoundRobin and LowestLatency).
+func WithOrdererFailover(strategy OrdererFailoverStrategy) ClientOption {
+	return func(client *Client) error {
+		failover, err := newOrdererFailover(strategy)
+		if err != nil {
+			return err
+		}
+		client.ordererFailover = failover
+		return nil
+	}
+}
+
+// WithSimulationMode endorses transactions without sending them to the orderer, which is useful for testing
+// chaincode logic. Execute then returns the read-write sets and chaincode response of the transaction in the
+// SimulationResult of the response. ExecuteAsync is not supported in simulation mode.
+func WithSimulationMode() ClientOption {
+	return func(client *Client) error {
+		client.simulation = true
+		return nil
+	}
+}
+
+// WithQueryWorkers sets the maximum number of queries that are submitted concurrently by BatchQuery
+func WithQueryWorkers(workers int) ClientOption {
+	return func(client *Client) error {
+		if workers <= 0 {
+			return errors.New("number of query workers must be greater than zero")
+		}
+		client.queryWorkers = workers
+		return nil
+	}
+}
+
 // New returns a Client instance. Channel client can query chaincode, execute chaincode and register/unregister for chaincode events on specific channel.
 func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client, error) {
 
@@ -84,6 +187,23 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 	return &channelClient, nil
 }
 
+// Close releases the selection and discovery services created by WithDiscoveryBasedSelection and
+// removes the chaincode event registrations with a start block (see WithStartBlock), closing the
+// connections of their event services. The services of the channel are released by the SDK.
+func (cc *Client) Close() {
+	cc.replayRegs.Range(func(registration, eventService interface{}) bool {
+		cc.unregisterReplay(registration.(fab.Registration), eventService.(fab.EventService))
+		return true
+	})
+
+	if selection, ok := cc.selection.(*fabricselection.Service); ok {
+		selection.Close()
+	}
+	if discovery, ok := cc.discovery.(*dynamicdiscovery.ChannelService); ok {
+		discovery.Close()
+	}
+}
+
 // Query chaincode using request and optional request options
 //  Parameters:
 //  request holds info about mandatory chaincode ID and function
@@ -99,6 +219,51 @@ func (cc *Client) Query(request Request, options ...RequestOption) (Response, er
 	return callQuery(cc, request, options...)
 }
 
+// BatchQuery queries chaincode with each of the given requests, e.g. to query several chaincodes in one
+// logical operation. The queries are submitted concurrently; the maximum number of queries in flight is
+// bounded by the WithQueryWorkers client option.
+//  Parameters:
+//  requests holds info about mandatory chaincode ID and function of each query
+//  options holds optional request options that apply to all queries
+//
+//  Returns:
+//  the response of each query, in the order of the requests, and
+//  a combined error if any of the queries failed
+func (cc *Client) BatchQuery(requests []Request, options ...RequestOption) ([]QueryResponse, error) {
+	workers := cc.queryWorkers
+	if workers <= 0 {
+		workers = defaultQueryWorkers
+	}
+
+	// Query appends to the options, so make sure that concurrent queries do not share a backing array
+	options = options[:len(options):len(options)]
+
+	responses := make([]QueryResponse, len(requests))
+	sem := make(chan struct{}, workers)
+
+	var wg sync.WaitGroup
+	wg.Add(len(requests))
+	for i, request := range requests {
+		sem <- struct{}{}
+		go func(i int, request Request) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			response, err := cc.Query(request, options...)
+			responses[i] = QueryResponse{Response: response, Err: err}
+		}(i, request)
+	}
+	wg.Wait()
+
+	var errs error
+	for i, response := range responses {
+		if response.Err != nil {
+			errs = multi.Append(errs, errors.WithMessage(response.Err, fmt.Sprintf("query %d of chaincode [%s] failed", i, requests[i].ChaincodeID)))
+		}
+	}
+	return responses, errs
+}
+
 // Execute prepares and executes transaction using request and optional request options
 //  Parameters:
 //  request holds info about mandatory chaincode ID and function
@@ -110,9 +275,68 @@ func (cc *Client) Execute(request Request, options ...RequestOption) (Response,
 	options = append(options, addDefaultTimeout(fab.Execute))
 	options = append(options, addDefaultTargetFilter(cc.context, filter.EndorsingPeer))
 
+	if cc.simulation {
+		return cc.InvokeHandler(invoke.NewSimulationExecuteHandler(), request, options...)
+	}
 	return callExecute(cc, request, options...)
 }
 
+// ExecuteAsync prepares and submits a transaction using request and optional request options without waiting for it to be committed
+//  Parameters:
+//  request holds info about mandatory chaincode ID and function
+//  options holds optional request options
+//
+//  Returns:
+//  the transaction ID once the transaction has been endorsed and sent to the orderer, and a channel
+//  that receives the commit event of the transaction. The channel is closed after the event is delivered
+//  or, without delivering an event, if the transaction is not committed within the Execute timeout.
+func (cc *Client) ExecuteAsync(request Request, options ...RequestOption) (fab.TransactionID, <-chan *TxCommitEvent, error) {
+	if cc.simulation {
+		return fab.EmptyTransactionID, nil, errors.New("ExecuteAsync is not supported in simulation mode")
+	}
+
+	options = append(options, addDefaultTimeout(fab.Execute))
+	options = append(options, addDefaultTargetFilter(cc.context, filter.EndorsingPeer))
+
+	txnOpts, err := cc.prepareOptsFromOptions(cc.context, options...)
+	if err != nil {
+		return fab.EmptyTransactionID, nil, err
+	}
+
+	submitHandler := &submitTxHandler{}
+	response, err := cc.InvokeHandler(
+		invoke.NewSelectAndEndorseHandler(
+			invoke.NewEndorsementValidationHandler(
+				invoke.NewSignatureValidationHandler(submitHandler),
+			),
+		),
+		request, options...)
+	if err != nil {
+		return fab.EmptyTransactionID, nil, err
+	}
+
+	commitEvents := make(chan *TxCommitEvent, 1)
+	go func() {
+		defer close(commitEvents)
+		defer submitHandler.eventService.Unregister(submitHandler.reg)
+
+		select {
+		case txStatus, ok := <-submitHandler.statusNotifier:
+			if ok {
+				commitEvents <- &TxCommitEvent{
+					TransactionID:    response.TransactionID,
+					TxValidationCode: txStatus.TxValidationCode,
+					BlockNumber:      txStatus.BlockNumber,
+					SourceURL:        txStatus.SourceURL,
+				}
+			}
+		case <-time.After(txnOpts.Timeouts[fab.Execute]):
+		}
+	}()
+
+	return response.TransactionID, commitEvents, nil
+}
+
 // addDefaultTargetFilter adds default target filter if target filter is not specified
 func addDefaultTargetFilter(chCtx context.Channel, ft filter.EndpointType) RequestOption {
 	return func(ctx context.Client, o *requestOptions) error {
@@ -157,6 +381,12 @@ func (cc *Client) InvokeHandler(handler invoke.Handler, request Request, options
 		return Response{}, err
 	}
 
+	if txnOpts.MinBlockHeight > 0 {
+		if err := waitForPeerAtHeight(reqCtx, requestContext, clientContext); err != nil {
+			return Response{}, err
+		}
+	}
+
 	invoker := retry.NewInvoker(
 		requestContext.RetryHandler,
 		retry.WithBeforeRetry(
@@ -224,15 +454,16 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 	if err != nil {
 		return nil, nil, errors.WithMessage(err, "failed to create transactor")
 	}
-
-	selection, err := cc.context.ChannelService().Selection()
-	if err != nil {
-		return nil, nil, errors.WithMessage(err, "failed to create selection service")
+	if cc.ordererFailover != nil {
+		transactor = cc.ordererFailover.transactor(reqCtx, transactor)
+	}
+	if cc.latencySorter != nil {
+		transactor = &latencyTransactor{Transactor: transactor, sorter: cc.latencySorter, penalty: cc.context.EndpointConfig().Timeout(fab.PeerResponse)}
 	}
 
-	discovery, err := cc.context.ChannelService().Discovery()
+	selection, discovery, err := cc.selectionAndDiscovery()
 	if err != nil {
-		return nil, nil, errors.WithMessage(err, "failed to create discovery service")
+		return nil, nil, err
 	}
 
 	peerFilter := func(peer fab.Peer) bool {
@@ -242,15 +473,13 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 		if o.TargetFilter != nil && !o.TargetFilter.Accept(peer) {
 			return false
 		}
+		if o.MinBlockHeight > 0 && !isAtHeight(peer, o.MinBlockHeight) {
+			return false
+		}
 		return true
 	}
 
-	var peerSorter selectopts.PeerSorter
-	if o.TargetSorter != nil {
-		peerSorter = func(peers []fab.Peer) []fab.Peer {
-			return o.TargetSorter.Sort(peers)
-		}
-	}
+	peerSorter := cc.peerSorter(o)
 
 	clientContext := &invoke.ClientContext{
 		Selection:    selection,
@@ -261,7 +490,7 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 	}
 
 	requestContext := &invoke.RequestContext{
-		Request:         invoke.Request(request),
+		Request:         invoke.Request(withTransientData(request, o.TransientData)),
 		Opts:            invoke.Opts(o),
 		Response:        invoke.Response{},
 		RetryHandler:    retry.New(o.Retry),
@@ -273,9 +502,44 @@ func (cc *Client) prepareHandlerContexts(reqCtx reqContext.Context, request Requ
 	return requestContext, clientContext, nil
 }
 
+//selectionAndDiscovery returns the selection and discovery services of the client, or those of the channel if not set
+func (cc *Client) selectionAndDiscovery() (fab.SelectionService, fab.DiscoveryService, error) {
+	if cc.selection != nil {
+		return cc.selection, cc.discovery, nil
+	}
+
+	selection, err := cc.context.ChannelService().Selection()
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to create selection service")
+	}
+
+	discovery, err := cc.context.ChannelService().Discovery()
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to create discovery service")
+	}
+
+	return selection, discovery, nil
+}
+
+//withTransientData returns a copy of the request with the given data added to its transient map
+func withTransientData(request Request, data map[string][]byte) Request {
+	if len(data) == 0 {
+		return request
+	}
+	transientMap := make(map[string][]byte, len(request.TransientMap)+len(data))
+	for k, v := range request.TransientMap {
+		transientMap[k] = v
+	}
+	for k, v := range data {
+		transientMap[k] = v
+	}
+	request.TransientMap = transientMap
+	return request
+}
+
 //prepareOptsFromOptions Reads apitxn.Opts from Option array
 func (cc *Client) prepareOptsFromOptions(ctx context.Client, options ...RequestOption) (requestOptions, error) {
-	txnOpts := requestOptions{}
+	txnOpts := requestOptions{Targets: cc.targetPeers}
 	for _, option := range options {
 		err := option(ctx, &txnOpts)
 		if err != nil {
@@ -288,18 +552,177 @@ func (cc *Client) prepareOptsFromOptions(ctx context.Client, options ...RequestO
 // RegisterChaincodeEvent registers for chaincode events. Unregister must be called when the registration is no longer needed.
 //  Parameters:
 //  chaincodeID is the chaincode ID for which events are to be received
-//  eventFilter is the chaincode event filter (regular expression) for which events are to be received
+//  eventFilter is the chaincode event filter (regular expression) for which events are to be received; it must be empty or equal to the pattern of WithEventNamePattern, if given
+//  options holds optional event options
 //
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
-func (cc *Client) RegisterChaincodeEvent(chainCodeID string, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
-	// Register callback for CE
-	return cc.eventService.RegisterChaincodeEvent(chainCodeID, eventFilter)
+func (cc *Client) RegisterChaincodeEvent(chainCodeID string, eventFilter string, options ...EventOption) (fab.Registration, <-chan *fab.CCEvent, error) {
+	evtOpts := eventOptions{}
+	for _, option := range options {
+		if err := option(&evtOpts); err != nil {
+			return nil, nil, errors.WithMessage(err, "Failed to read event opts")
+		}
+	}
+
+	if evtOpts.eventNamePattern != nil {
+		pattern := evtOpts.eventNamePattern.String()
+		if eventFilter != "" && eventFilter != pattern {
+			return nil, nil, errors.Errorf("event filter [%s] conflicts with event name pattern [%s]", eventFilter, pattern)
+		}
+		eventFilter = pattern
+	}
+
+	if evtOpts.startBlock == nil {
+		// Register callback for CE
+		return cc.eventService.RegisterChaincodeEvent(chainCodeID, eventFilter)
+	}
+
+	// The deliver stream of the event service is seeked to the start block when it connects
+	eventService, err := cc.context.ChannelService().EventService(
+		deliverclient.WithSeekType(seek.FromBlock),
+		deliverclient.WithBlockNum(*evtOpts.startBlock),
+	)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "event service creation failed")
+	}
+
+	reg, eventch, err := eventService.RegisterChaincodeEvent(chainCodeID, eventFilter)
+	if err != nil {
+		return nil, nil, err
+	}
+	cc.replayRegs.Store(reg, eventService)
+	return reg, eventch, nil
 }
 
 // UnregisterChaincodeEvent removes the given registration and closes the event channel.
+// The connection of the event service of a registration with a start block is closed
+// when the event service has no registrations left.
 //  Parameters:
 //  registration is the registration handle that was returned from RegisterChaincodeEvent method
 func (cc *Client) UnregisterChaincodeEvent(registration fab.Registration) {
+	if eventService, ok := cc.replayRegs.Load(registration); ok {
+		cc.unregisterReplay(registration, eventService.(fab.EventService))
+		return
+	}
 	cc.eventService.Unregister(registration)
 }
+
+// unregisterReplay removes a registration with a start block and closes the connection
+// of its event service if the event service has no registrations left
+func (cc *Client) unregisterReplay(registration fab.Registration, eventService fab.EventService) {
+	cc.replayRegs.Delete(registration)
+	eventService.Unregister(registration)
+	if closer, ok := eventService.(idleCloser); ok {
+		closer.CloseIfIdle()
+	}
+}
+
+// idleCloser is implemented by event services whose connection may be closed when they have no registrations
+type idleCloser interface {
+	CloseIfIdle() bool
+}
+
+// SubscribeChaincodeEvents subscribes to the chaincode events with the given name, as an alternative to
+// RegisterChaincodeEvent that does not require the registration to be kept, e.g. for select loops.
+// The events are delivered by the event service of the client.
+//  Parameters:
+//  chaincodeID is the chaincode ID for which events are to be received
+//  eventName is the name of the events to be received
+//
+//  Returns:
+//  a channel that is used to receive events, and a function that ends the subscription and closes the channel.
+//  The function may be called more than once, e.g. when a context is done.
+func (cc *Client) SubscribeChaincodeEvents(chaincodeID, eventName string) (<-chan *fab.CCEvent, func(), error) {
+	if eventName == "" {
+		return nil, nil, errors.New("event name is required")
+	}
+
+	reg, eventch, err := cc.RegisterChaincodeEvent(chaincodeID, "^"+regexp.QuoteMeta(eventName)+"$")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() { cc.UnregisterChaincodeEvent(reg) })
+	}
+	return eventch, unsubscribe, nil
+}
+
+// WaitForTx blocks until the block containing the given transaction is committed or the timeout expires.
+// Only commits that are delivered after WaitForTx registers for the TxStatus event are seen: if the
+// transaction was already committed, WaitForTx waits until the timeout expires. The ledger client's
+// QueryTransaction can be used to check whether such a transaction has been committed.
+//  Parameters:
+//  txID is the ID of the transaction, e.g. the TransactionID of the response returned from Execute
+//  timeout is the maximum time to wait for the commit; if not positive the Execute timeout is used
+//
+//  Returns:
+//  the validation code and block number of the committed transaction, or ErrTxTimeout if the timeout expires
+func (cc *Client) WaitForTx(txID fab.TransactionID, timeout time.Duration) (*TxCommitEvent, error) {
+	if txID == "" {
+		return nil, errors.New("transaction ID is required")
+	}
+	if timeout <= 0 {
+		timeout = cc.context.EndpointConfig().Timeout(fab.Execute)
+	}
+
+	reg, statusNotifier, err := cc.eventService.RegisterTxStatusEvent(string(txID))
+	if err != nil {
+		return nil, errors.WithMessage(err, "error registering for TxStatus event")
+	}
+	defer cc.eventService.Unregister(reg)
+
+	select {
+	case txStatus, ok := <-statusNotifier:
+		if !ok {
+			return nil, errors.New("TxStatus event channel closed")
+		}
+		return &TxCommitEvent{
+			TransactionID:    txID,
+			TxValidationCode: txStatus.TxValidationCode,
+			BlockNumber:      txStatus.BlockNumber,
+			SourceURL:        txStatus.SourceURL,
+		}, nil
+	case <-time.After(timeout):
+		return nil, ErrTxTimeout
+	}
+}
+
+// submitTxHandler registers for the TxStatus event of the endorsed transaction and sends it to the orderer.
+// The registration is kept by the handler so that the commit can be awaited after the handler returns.
+type submitTxHandler struct {
+	eventService   fab.EventService
+	reg            fab.Registration
+	statusNotifier <-chan *fab.TxStatusEvent
+}
+
+// Handle handles submitting the transaction
+func (h *submitTxHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	reg, statusNotifier, err := clientContext.EventService.RegisterTxStatusEvent(string(requestContext.Response.TransactionID))
+	if err != nil {
+		requestContext.Error = errors.Wrap(err, "error registering for TxStatus event")
+		return
+	}
+
+	tx, err := clientContext.Transactor.CreateTransaction(fab.TransactionRequest{
+		Proposal:          requestContext.Response.Proposal,
+		ProposalResponses: requestContext.Response.Responses,
+	})
+	if err != nil {
+		clientContext.EventService.Unregister(reg)
+		requestContext.Error = errors.Wrap(err, "CreateTransaction failed")
+		return
+	}
+
+	if _, err := clientContext.Transactor.SendTransaction(tx); err != nil {
+		clientContext.EventService.Unregister(reg)
+		requestContext.Error = errors.Wrap(err, "SendTransaction failed")
+		return
+	}
+
+	h.eventService = clientContext.EventService
+	h.reg = reg
+	h.statusNotifier = statusNotifier
+}
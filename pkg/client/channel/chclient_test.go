@@ -14,9 +14,12 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/dynamicdiscovery"
 	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/fabricselection"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/staticselection"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
@@ -114,6 +117,34 @@ func TestQuery(t *testing.T) {
 
 }
 
+func TestBatchQuery(t *testing.T) {
+	testPeer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer.Payload = []byte("test1")
+	chClient := setupChannelClient([]fab.Peer{testPeer}, t)
+	require.NoError(t, WithQueryWorkers(2)(chClient))
+
+	requests := []Request{
+		{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("a")}},
+		{ChaincodeID: "testCC2", Args: [][]byte{[]byte("query"), []byte("b")}},
+		{ChaincodeID: "testCC3", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("c")}},
+	}
+	responses, err := chClient.BatchQuery(requests, WithTimeout(fab.Query, time.Minute))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "query 1 of chaincode [testCC2] failed")
+	require.Len(t, responses, len(requests))
+	assert.NoError(t, responses[0].Err)
+	assert.Equal(t, []byte("test1"), responses[0].Payload)
+	assert.Error(t, responses[1].Err)
+	assert.NoError(t, responses[2].Err)
+	assert.Equal(t, []byte("test1"), responses[2].Payload)
+
+	responses, err = chClient.BatchQuery(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, responses)
+
+	assert.Error(t, WithQueryWorkers(0)(chClient))
+}
+
 func TestQuerySelectionError(t *testing.T) {
 	chClient := setupChannelClientWithError(nil, errors.New("Test Error"), nil, t)
 
@@ -192,6 +223,169 @@ func TestQueryWithNilTargets(t *testing.T) {
 	}
 }
 
+func TestQueryWithTargetPeers(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = []byte("test1")
+	testPeer2 := fcmocks.NewMockPeer("Peer2", "http://peer2.com")
+	testPeer2.Payload = []byte("test2")
+
+	fabCtx := setupCustomTestContext(t, txnmocks.NewMockSelectionService(nil, testPeer1, testPeer2), txnmocks.NewMockDiscoveryService(nil), nil)
+	ctx := createChannelContext(fabCtx, channelID)
+
+	_, err := New(ctx, WithTargetPeers(nil))
+	assert.Error(t, err, "expected error for empty target peers")
+
+	_, err = New(ctx, WithTargetPeers([]fab.Peer{testPeer1, nil}))
+	assert.Error(t, err, "expected error for nil target peer")
+
+	chClient, err := New(ctx, WithTargetPeers([]fab.Peer{testPeer1}))
+	if err != nil {
+		t.Fatalf("Failed to create new channel client: %s", err)
+	}
+
+	// Selection would return both peers and result in a payload mismatch
+	response, err := chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}})
+	if err != nil {
+		t.Fatalf("Failed to invoke test cc: %s", err)
+	}
+	assert.Equal(t, []byte("test1"), response.Payload)
+	assert.Len(t, response.Responses, 1)
+
+	// Per-request targets take precedence
+	response, err = chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}}, WithTargets(testPeer2))
+	if err != nil {
+		t.Fatalf("Failed to invoke test cc: %s", err)
+	}
+	assert.Equal(t, []byte("test2"), response.Payload)
+}
+
+func TestWithDiscoveryBasedSelection(t *testing.T) {
+	channelSelection := txnmocks.NewMockSelectionService(nil)
+	fabCtx := setupCustomTestContext(t, channelSelection, txnmocks.NewMockDiscoveryService(nil), nil)
+	ctx := createChannelContext(fabCtx, channelID)
+
+	chClient, err := New(ctx)
+	require.NoError(t, err)
+	selection, _, err := chClient.selectionAndDiscovery()
+	require.NoError(t, err)
+	assert.Equal(t, channelSelection, selection, "expected selection service of the channel")
+
+	chClient, err = New(ctx, WithDiscoveryBasedSelection())
+	require.NoError(t, err)
+	defer chClient.Close()
+
+	selection, discovery, err := chClient.selectionAndDiscovery()
+	require.NoError(t, err)
+	assert.IsType(t, &fabricselection.Service{}, selection)
+	assert.IsType(t, &dynamicdiscovery.ChannelService{}, discovery)
+}
+
+func TestRegisterChaincodeEventWithStartBlock(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+
+	reg, _, err := chClient.RegisterChaincodeEvent("testCC", "event.*")
+	require.NoError(t, err)
+	_, ok := chClient.replayRegs.Load(reg)
+	assert.False(t, ok, "registration without start block should use the client's event service")
+	chClient.UnregisterChaincodeEvent(reg)
+
+	reg, eventch, err := chClient.RegisterChaincodeEvent("testCC", "event.*", WithStartBlock(5))
+	require.NoError(t, err)
+	require.NotNil(t, eventch)
+	_, ok = chClient.replayRegs.Load(reg)
+	assert.True(t, ok, "registration with start block should be tracked")
+
+	eventService := trackIdleClose(t, chClient, reg)
+	chClient.UnregisterChaincodeEvent(reg)
+	_, ok = chClient.replayRegs.Load(reg)
+	assert.False(t, ok, "registration should no longer be tracked after unregister")
+	assert.True(t, eventService.closed, "event service of the registration should be closed after unregister")
+
+	// Close removes the remaining registrations with a start block
+	reg, _, err = chClient.RegisterChaincodeEvent("testCC", "event.*", WithStartBlock(5))
+	require.NoError(t, err)
+	eventService = trackIdleClose(t, chClient, reg)
+	chClient.Close()
+	_, ok = chClient.replayRegs.Load(reg)
+	assert.False(t, ok, "registration should no longer be tracked after close")
+	assert.True(t, eventService.closed, "event service of the registration should be closed by close")
+}
+
+// trackIdleClose replaces the event service of a registration with a start block by one that records CloseIfIdle
+func trackIdleClose(t *testing.T, chClient *Client, reg fab.Registration) *idleClosingEventService {
+	eventService, ok := chClient.replayRegs.Load(reg)
+	require.True(t, ok, "registration with start block should be tracked")
+	closing := &idleClosingEventService{EventService: eventService.(fab.EventService)}
+	chClient.replayRegs.Store(reg, closing)
+	return closing
+}
+
+type idleClosingEventService struct {
+	fab.EventService
+	closed bool
+}
+
+func (s *idleClosingEventService) CloseIfIdle() bool {
+	s.closed = true
+	return true
+}
+
+func TestRegisterChaincodeEventWithPattern(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+
+	_, _, err := chClient.RegisterChaincodeEvent("testCC", "", WithEventNamePattern("event("))
+	assert.Equal(t, ErrInvalidPattern, errors.Cause(err))
+
+	_, _, err = chClient.RegisterChaincodeEvent("testCC", "event.*", WithEventNamePattern("^event[0-9]+$"))
+	assert.EqualError(t, err, "event filter [event.*] conflicts with event name pattern [^event[0-9]+$]")
+
+	reg, eventch, err := chClient.RegisterChaincodeEvent("testCC", "", WithEventNamePattern("^event[0-9]+$"))
+	require.NoError(t, err)
+	require.NotNil(t, eventch)
+	chClient.UnregisterChaincodeEvent(reg)
+
+	reg, _, err = chClient.RegisterChaincodeEvent("testCC", "^event[0-9]+$", WithEventNamePattern("^event[0-9]+$"))
+	require.NoError(t, err)
+	chClient.UnregisterChaincodeEvent(reg)
+}
+
+func TestSubscribeChaincodeEvents(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+	eventService := &subscriptionEventService{MockEventService: fcmocks.NewMockEventService()}
+	chClient.eventService = eventService
+
+	_, _, err := chClient.SubscribeChaincodeEvents("testCC", "")
+	assert.Error(t, err)
+
+	eventch, unsubscribe, err := chClient.SubscribeChaincodeEvents("testCC", "event.1")
+	require.NoError(t, err)
+	require.NotNil(t, eventch)
+	assert.Equal(t, `^event\.1$`, eventService.eventFilter)
+
+	unsubscribe()
+	assert.Equal(t, 1, eventService.unregistered)
+
+	// Unsubscribing again has no effect
+	unsubscribe()
+	assert.Equal(t, 1, eventService.unregistered)
+}
+
+// subscriptionEventService records chaincode event registrations
+type subscriptionEventService struct {
+	*fcmocks.MockEventService
+	eventFilter  string
+	unregistered int
+}
+
+func (s *subscriptionEventService) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	s.eventFilter = eventFilter
+	return s.MockEventService.RegisterChaincodeEvent(ccID, eventFilter)
+}
+
+func (s *subscriptionEventService) Unregister(reg fab.Registration) {
+	s.unregistered++
+}
+
 func TestExecuteTx(t *testing.T) {
 	chClient := setupChannelClient(nil, t)
 
@@ -228,6 +422,30 @@ func TestExecuteTx(t *testing.T) {
 
 }
 
+func TestExecuteTxSimulationMode(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = []byte("test1")
+	testPeer1.SetRwSets(fcmocks.NewRwSet("testCC"), fcmocks.NewRwSet("otherCC"))
+
+	// The orderer fails all transactions so that the test fails if the transaction is submitted
+	orderer := fcmocks.NewMockOrderer("", nil)
+	orderer.EnqueueSendBroadcastError(errors.New("transaction was sent to the orderer"))
+	chClient := setupChannelClientWithNodes([]fab.Peer{testPeer1}, []fab.Orderer{orderer}, t)
+	require.NoError(t, WithSimulationMode()(chClient))
+
+	resp, err := chClient.Execute(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("b")}})
+	require.NoError(t, err)
+	require.NotNil(t, resp.SimulationResult)
+	assert.Equal(t, "testCC", resp.SimulationResult.ChaincodeID)
+	assert.Equal(t, []byte("test1"), resp.SimulationResult.Payload)
+	require.Len(t, resp.SimulationResult.RWSets, 2)
+	assert.Equal(t, "testCC", resp.SimulationResult.RWSets[0].NameSpace)
+	assert.Equal(t, "otherCC", resp.SimulationResult.RWSets[1].NameSpace)
+
+	_, _, err = chClient.ExecuteAsync(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("b")}})
+	assert.Error(t, err, "expected ExecuteAsync to fail in simulation mode")
+}
+
 type customHandler struct {
 	expectedPayload []byte
 }
@@ -251,6 +469,36 @@ func TestInvokeHandler(t *testing.T) {
 	}
 }
 
+// transientMapHandler records the transient map of the request
+type transientMapHandler struct {
+	transientMap map[string][]byte
+}
+
+func (h *transientMapHandler) Handle(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) {
+	h.transientMap = requestContext.Request.TransientMap
+}
+
+func TestWithTransientData(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+	handler := &transientMapHandler{}
+
+	requestTransientMap := map[string][]byte{"a": []byte("1"), "b": []byte("2")}
+	request := Request{ChaincodeID: "testCC", Fcn: "move", Args: [][]byte{[]byte("a")}, TransientMap: requestTransientMap}
+
+	_, err := chClient.InvokeHandler(handler, request, WithTransientData(map[string][]byte{"b": []byte("3")}), WithTransientData(map[string][]byte{"c": []byte("4")}))
+	if err != nil {
+		t.Fatalf("Should have succeeded but got error %s", err)
+	}
+	assert.Equal(t, map[string][]byte{"a": []byte("1"), "b": []byte("3"), "c": []byte("4")}, handler.transientMap)
+	assert.Equal(t, map[string][]byte{"a": []byte("1"), "b": []byte("2")}, requestTransientMap, "request transient map should not be modified")
+
+	_, err = chClient.InvokeHandler(handler, request)
+	if err != nil {
+		t.Fatalf("Should have succeeded but got error %s", err)
+	}
+	assert.Equal(t, requestTransientMap, handler.transientMap)
+}
+
 // customEndorsementHandler ignores the channel in the ClientContext
 // and instead sends the proposal to the given channel
 type customEndorsementHandler struct {
@@ -401,6 +649,73 @@ func TestTransactionTimeout(t *testing.T) {
 	assert.EqualValues(t, statusError.Code, status.Timeout)
 }
 
+func TestExecuteAsync(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+	mockEventService := &unregisterCountingEventService{MockEventService: fcmocks.NewMockEventService()}
+	chClient.eventService = mockEventService
+
+	_, _, err := chClient.ExecuteAsync(Request{})
+	assert.Error(t, err, "expected error for empty request")
+
+	request := Request{ChaincodeID: "test", Fcn: "invoke", Args: [][]byte{[]byte("move"), []byte("a"), []byte("b"), []byte("1")}}
+	txID, commitEvents, err := chClient.ExecuteAsync(request)
+	require.NoError(t, err)
+	assert.NotEmpty(t, txID)
+
+	select {
+	case event, ok := <-commitEvents:
+		require.True(t, ok, "expected commit event")
+		assert.Equal(t, txID, event.TransactionID)
+		assert.Equal(t, pb.TxValidationCode_VALID, event.TxValidationCode)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for commit event")
+	}
+	_, ok := <-commitEvents
+	assert.False(t, ok, "expected commit event channel to be closed")
+	assert.Equal(t, 1, mockEventService.unregistered)
+
+	// The channel is closed without an event if the transaction is not committed in time
+	mockEventService.Timeout = true
+	_, commitEvents, err = chClient.ExecuteAsync(request, WithTimeout(fab.Execute, 10*time.Millisecond))
+	require.NoError(t, err)
+	_, ok = <-commitEvents
+	assert.False(t, ok, "expected commit event channel to be closed")
+	assert.Equal(t, 2, mockEventService.unregistered)
+}
+
+// unregisterCountingEventService counts the registrations removed from the event service
+type unregisterCountingEventService struct {
+	*fcmocks.MockEventService
+	unregistered int
+}
+
+func (s *unregisterCountingEventService) Unregister(reg fab.Registration) {
+	s.unregistered++
+	s.MockEventService.Unregister(reg)
+}
+
+func TestWaitForTx(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+	mockEventService := &unregisterCountingEventService{MockEventService: fcmocks.NewMockEventService()}
+	mockEventService.TxValidationCode = pb.TxValidationCode_MVCC_READ_CONFLICT
+	chClient.eventService = mockEventService
+
+	_, err := chClient.WaitForTx("", time.Second)
+	assert.Error(t, err, "expected error for empty transaction ID")
+
+	event, err := chClient.WaitForTx("txid", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, fab.TransactionID("txid"), event.TransactionID)
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, event.TxValidationCode)
+	assert.Equal(t, 1, mockEventService.unregistered)
+
+	mockEventService.Timeout = true
+	_, err = chClient.WaitForTx("txid", 10*time.Millisecond)
+	assert.Equal(t, ErrTxTimeout, err)
+	assert.Equal(t, 2, mockEventService.unregistered, "expected registration to be removed on timeout")
+}
+
 func TestExecuteTxWithRetries(t *testing.T) {
 	testStatus := status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "test", nil)
 	testResp := []byte("test")
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package channel
 
 import (
+	"sync"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery/greylist"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -19,6 +21,7 @@ func newClient(channelContext context.Channel, membership fab.ChannelMembership,
 		greylist:     greylistProvider,
 		context:      channelContext,
 		metrics:      channelContext.GetMetrics(),
+		replayRegs:   &sync.Map{},
 	}
 	return channelClient
 }
@@ -0,0 +1,180 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	"github.com/pkg/errors"
+)
+
+const (
+	cscc            = "cscc"
+	csccConfigBlock = "GetConfigBlock"
+)
+
+// ChannelConfig contains the settings of the latest channel configuration block
+type ChannelConfig struct {
+	BlockNumber      uint64
+	BatchTimeout     time.Duration
+	BatchSize        *ab.BatchSize
+	OrdererAddresses []string
+
+	// MSPs holds the MSP configs of the application organizations followed by those of the
+	// orderer organizations, each sorted by organization name
+	MSPs []*mb.MSPConfig
+
+	// ApplicationPolicies holds the policies of the Application config group by policy name
+	ApplicationPolicies map[string]*common.Policy
+}
+
+// GetChannelConfig queries the latest configuration block of the channel and parses it
+//  Parameters:
+//  options holds optional request options
+//
+//  Returns:
+//  the channel configuration
+func (cc *Client) GetChannelConfig(options ...RequestOption) (*ChannelConfig, error) {
+	block, err := cc.queryConfigBlock(options...)
+	if err != nil {
+		return nil, err
+	}
+	return newChannelConfig(block)
+}
+
+// queryConfigBlock queries the latest configuration block of the channel
+func (cc *Client) queryConfigBlock(options ...RequestOption) (*common.Block, error) {
+	response, err := cc.Query(Request{ChaincodeID: cscc, Fcn: csccConfigBlock, Args: [][]byte{[]byte(cc.context.ChannelID())}}, options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to query config block")
+	}
+
+	block := &common.Block{}
+	if err := proto.Unmarshal(response.Payload, block); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal config block")
+	}
+	return block, nil
+}
+
+func newChannelConfig(block *common.Block) (*ChannelConfig, error) {
+	channelGroup, err := channelGroupFromBlock(block)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ChannelConfig{
+		BlockNumber:         block.Header.Number,
+		ApplicationPolicies: make(map[string]*common.Policy),
+	}
+
+	if value, ok := channelGroup.Values[channelConfig.OrdererAddressesKey]; ok {
+		ordererAddresses := &common.OrdererAddresses{}
+		if err := proto.Unmarshal(value.Value, ordererAddresses); err != nil {
+			return nil, errors.Wrap(err, "unmarshal orderer addresses from config failed")
+		}
+		config.OrdererAddresses = ordererAddresses.Addresses
+	}
+
+	ordererGroup := channelGroup.Groups[string(fab.OrdererGroupKey)]
+	if err := loadOrdererValues(config, ordererGroup); err != nil {
+		return nil, err
+	}
+
+	applicationGroup := channelGroup.Groups[string(fab.ApplicationGroupKey)]
+	if applicationGroup != nil {
+		for name, configPolicy := range applicationGroup.Policies {
+			config.ApplicationPolicies[name] = configPolicy.Policy
+		}
+	}
+
+	for _, group := range []*common.ConfigGroup{applicationGroup, ordererGroup} {
+		if err := loadOrgMSPs(config, group); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+// channelGroupFromBlock returns the channel config group of a configuration block
+func channelGroupFromBlock(block *common.Block) (*common.ConfigGroup, error) {
+	if block.Header == nil || block.Data == nil || len(block.Data.Data) == 0 {
+		return nil, errors.New("config block is missing header or data")
+	}
+
+	configEnvelope, err := resource.CreateConfigEnvelope(block.Data.Data[0])
+	if err != nil {
+		return nil, err
+	}
+	if configEnvelope.Config == nil || configEnvelope.Config.ChannelGroup == nil {
+		return nil, errors.New("channel group is missing in config envelope")
+	}
+	return configEnvelope.Config.ChannelGroup, nil
+}
+
+// loadOrdererValues loads the batch settings of the Orderer config group
+func loadOrdererValues(config *ChannelConfig, ordererGroup *common.ConfigGroup) error {
+	if ordererGroup == nil {
+		return nil
+	}
+
+	if value, ok := ordererGroup.Values[channelConfig.BatchSizeKey]; ok {
+		batchSize := &ab.BatchSize{}
+		if err := proto.Unmarshal(value.Value, batchSize); err != nil {
+			return errors.Wrap(err, "unmarshal batch size from config failed")
+		}
+		config.BatchSize = batchSize
+	}
+
+	if value, ok := ordererGroup.Values[channelConfig.BatchTimeoutKey]; ok {
+		batchTimeout := &ab.BatchTimeout{}
+		if err := proto.Unmarshal(value.Value, batchTimeout); err != nil {
+			return errors.Wrap(err, "unmarshal batch timeout from config failed")
+		}
+		timeout, err := time.ParseDuration(batchTimeout.Timeout)
+		if err != nil {
+			return errors.Wrapf(err, "invalid batch timeout [%s]", batchTimeout.Timeout)
+		}
+		config.BatchTimeout = timeout
+	}
+
+	return nil
+}
+
+// loadOrgMSPs loads the MSP configs of the organizations in the given config group
+func loadOrgMSPs(config *ChannelConfig, group *common.ConfigGroup) error {
+	if group == nil {
+		return nil
+	}
+
+	var orgs []string
+	for org := range group.Groups {
+		orgs = append(orgs, org)
+	}
+	sort.Strings(orgs)
+
+	for _, org := range orgs {
+		value, ok := group.Groups[org].Values[channelConfig.MSPKey]
+		if !ok {
+			continue
+		}
+		mspConfig := &mb.MSPConfig{}
+		if err := proto.Unmarshal(value.Value, mspConfig); err != nil {
+			return errors.Wrapf(err, "unmarshal MSPConfig of organization [%s] from config failed", org)
+		}
+		config.MSPs = append(config.MSPs, mspConfig)
+	}
+	return nil
+}
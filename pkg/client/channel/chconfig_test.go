@@ -0,0 +1,60 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+func TestGetChannelConfig(t *testing.T) {
+	builder := &fcmocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: fcmocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       []string{"Org2MSP", "Org1MSP"},
+			OrdererAddress: "localhost:9999",
+		},
+		Index: 3,
+	}
+	payload, err := proto.Marshal(builder.Build())
+	require.NoError(t, err)
+
+	testPeer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer.Payload = payload
+	chClient := setupChannelClient([]fab.Peer{testPeer}, t)
+
+	config, err := chClient.GetChannelConfig()
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(3), config.BlockNumber)
+	assert.Equal(t, 2*time.Second, config.BatchTimeout)
+	require.NotNil(t, config.BatchSize)
+	assert.Equal(t, uint32(10), config.BatchSize.MaxMessageCount)
+	assert.Equal(t, []string{"localhost:9999"}, config.OrdererAddresses)
+	assert.Len(t, config.ApplicationPolicies, 3)
+	assert.NotNil(t, config.ApplicationPolicies["Writers"])
+
+	var mspNames []string
+	for _, mspConfig := range config.MSPs {
+		fabricMSPConfig := &mb.FabricMSPConfig{}
+		require.NoError(t, proto.Unmarshal(mspConfig.Config, fabricMSPConfig))
+		mspNames = append(mspNames, fabricMSPConfig.Name)
+	}
+	assert.Equal(t, []string{"Org1MSP", "Org2MSP", "OrdererMSP"}, mspNames)
+
+	testPeer.Payload = []byte("invalid")
+	_, err = chClient.GetChannelConfig()
+	assert.Error(t, err, "expected error for invalid config block")
+}
@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	lscc                  = "lscc"
+	lsccCollectionsConfig = "getcollectionsconfig"
+	lsccChaincodeData     = "getccdata"
+
+	collectionsNotDefinedMsg = "collections config not defined for chaincode"
+	chaincodeNotFoundMsg     = "could not find chaincode with name"
+)
+
+// ErrChaincodeNotFound is returned by QueryCollectionConfig if the chaincode is not instantiated on the channel
+var ErrChaincodeNotFound = errors.New("chaincode not found")
+
+// QueryCollectionConfig queries the private data collection configuration of an instantiated chaincode
+//  Parameters:
+//  chaincodeName is the mandatory name of the chaincode
+//  options holds optional request options
+//
+//  Returns:
+//  the collection config package of the chaincode; the package is empty if the chaincode defines no collections
+func (cc *Client) QueryCollectionConfig(chaincodeName string, options ...RequestOption) (*common.CollectionConfigPackage, error) {
+	if chaincodeName == "" {
+		return nil, errors.New("chaincode name is required")
+	}
+
+	response, err := cc.Query(Request{ChaincodeID: lscc, Fcn: lsccCollectionsConfig, Args: [][]byte{[]byte(chaincodeName)}}, options...)
+	if err != nil {
+		if !strings.Contains(err.Error(), collectionsNotDefinedMsg) {
+			return nil, errors.WithMessage(err, "failed to query collection config")
+		}
+		// lscc reports the same error for a chaincode without collections and for
+		// a chaincode that is not instantiated, so check whether the chaincode exists
+		if err := cc.checkChaincodeInstantiated(chaincodeName, options...); err != nil {
+			return nil, err
+		}
+		return &common.CollectionConfigPackage{}, nil
+	}
+
+	collConfig := &common.CollectionConfigPackage{}
+	if err := proto.Unmarshal(response.Payload, collConfig); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal collection config package")
+	}
+	return collConfig, nil
+}
+
+// checkChaincodeInstantiated returns ErrChaincodeNotFound if the chaincode is not instantiated on the channel
+func (cc *Client) checkChaincodeInstantiated(chaincodeName string, options ...RequestOption) error {
+	// A missing chaincode is otherwise treated as transient and retried. The options are copied so that
+	// the slice of the caller is not modified.
+	queryOpts := make([]RequestOption, len(options), len(options)+1)
+	copy(queryOpts, options)
+	queryOpts = append(queryOpts, WithRetry(retry.Opts{}))
+
+	request := Request{ChaincodeID: lscc, Fcn: lsccChaincodeData, Args: [][]byte{[]byte(cc.context.ChannelID()), []byte(chaincodeName)}}
+	if _, err := cc.Query(request, queryOpts...); err != nil {
+		if strings.Contains(err.Error(), chaincodeNotFoundMsg) {
+			return ErrChaincodeNotFound
+		}
+		return errors.WithMessage(err, "failed to query chaincode data")
+	}
+	return nil
+}
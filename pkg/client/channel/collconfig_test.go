@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	reqContext "context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+// sequenceMockPeer responds to each proposal with the next error
+type sequenceMockPeer struct {
+	*fcmocks.MockPeer
+	errs []error
+}
+
+func (p *sequenceMockPeer) ProcessTransactionProposal(ctx reqContext.Context, tp fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	p.Error = p.errs[p.ProcessProposalCalls]
+	return p.MockPeer.ProcessTransactionProposal(ctx, tp)
+}
+
+func TestQueryCollectionConfig(t *testing.T) {
+	collConfig := &common.CollectionConfigPackage{
+		Config: []*common.CollectionConfig{
+			{
+				Payload: &common.CollectionConfig_StaticCollectionConfig{
+					StaticCollectionConfig: &common.StaticCollectionConfig{Name: "coll1", RequiredPeerCount: 1, MaximumPeerCount: 2},
+				},
+			},
+		},
+	}
+	payload, err := proto.Marshal(collConfig)
+	require.NoError(t, err)
+
+	testPeer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer.Payload = payload
+	chClient := setupChannelClient([]fab.Peer{testPeer}, t)
+
+	_, err = chClient.QueryCollectionConfig("")
+	assert.Error(t, err, "expected error for empty chaincode name")
+
+	response, err := chClient.QueryCollectionConfig("testCC")
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(collConfig, response))
+}
+
+func TestQueryCollectionConfigNotDefined(t *testing.T) {
+	notDefinedErr := status.New(status.ChaincodeStatus, 500, fmt.Sprintf("%s testCC", collectionsNotDefinedMsg), nil)
+	notFoundErr := status.New(status.EndorserClientStatus, int32(status.ChaincodeNameNotFound), "could not find chaincode with name 'testCC'", nil)
+
+	// The chaincode is instantiated but defines no collections
+	testPeer := &sequenceMockPeer{MockPeer: fcmocks.NewMockPeer("Peer1", "http://peer1.com"), errs: []error{notDefinedErr, nil}}
+	chClient := setupChannelClient([]fab.Peer{testPeer}, t)
+
+	response, err := chClient.QueryCollectionConfig("testCC")
+	require.NoError(t, err)
+	assert.Empty(t, response.Config)
+
+	// The chaincode is not instantiated
+	testPeer = &sequenceMockPeer{MockPeer: fcmocks.NewMockPeer("Peer1", "http://peer1.com"), errs: []error{notDefinedErr, notFoundErr}}
+	chClient = setupChannelClient([]fab.Peer{testPeer}, t)
+
+	_, err = chClient.QueryCollectionConfig("testCC")
+	assert.Equal(t, ErrChaincodeNotFound, err)
+	assert.Equal(t, 2, testPeer.ProcessProposalCalls, "expected no retries")
+
+	// The options of the caller are not modified by the check
+	testPeer = &sequenceMockPeer{MockPeer: fcmocks.NewMockPeer("Peer1", "http://peer1.com"), errs: []error{nil}}
+	chClient = setupChannelClient([]fab.Peer{testPeer}, t)
+
+	options := make([]RequestOption, 1, 2)
+	options[0] = WithTargets(testPeer)
+	require.NoError(t, chClient.checkChaincodeInstantiated("testCC", options...))
+	assert.Nil(t, options[:2][1], "expected spare capacity of the options to be untouched")
+}
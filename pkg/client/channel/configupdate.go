@@ -0,0 +1,275 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"math/rand"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+const defaultModPolicy = channelConfig.AdminsPolicyKey
+
+// SubmitConfigUpdate signs the given channel configuration update and submits it to an orderer of the channel.
+// Before submission the signers are checked against the mod policies of the elements that are modified by the
+// write set, as found in the latest channel configuration. Implicit meta policies (e.g. MAJORITY Admins) are
+// evaluated against the organizations of the signers, and a policy of an organization is considered satisfied
+// by any signer of that organization. Other policies and the roles of the signers are validated by the orderer.
+//  Parameters:
+//  update is the mandatory config update, i.e. the read and write sets of the changed configuration; it is not
+//  modified, an update without a channel ID is submitted for the channel of the client
+//  signers are the identities that sign the update; if none are given the client's identity signs it
+//  options holds optional request options, used to query the latest channel configuration
+//
+//  Returns:
+//  an error if the signature threshold is not met or the update could not be submitted
+func (cc *Client) SubmitConfigUpdate(update *common.ConfigUpdate, signers []msp.SigningIdentity, options ...RequestOption) error {
+	if update == nil {
+		return errors.New("config update is required")
+	}
+	channelID := cc.context.ChannelID()
+	if update.ChannelId == "" {
+		update = proto.Clone(update).(*common.ConfigUpdate)
+		update.ChannelId = channelID
+	} else if update.ChannelId != channelID {
+		return errors.Errorf("config update is for channel [%s], not [%s]", update.ChannelId, channelID)
+	}
+
+	if len(signers) == 0 {
+		signers = []msp.SigningIdentity{cc.context}
+	}
+
+	txnOpts, err := cc.prepareOptsFromOptions(cc.context, options...)
+	if err != nil {
+		return err
+	}
+
+	block, err := cc.queryConfigBlock(options...)
+	if err != nil {
+		return err
+	}
+	channelGroup, err := channelGroupFromBlock(block)
+	if err != nil {
+		return err
+	}
+	if err := checkConfigUpdateSigners(channelGroup, update, signers); err != nil {
+		return err
+	}
+
+	configUpdate, err := proto.Marshal(update)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal config update")
+	}
+
+	var signatures []*common.ConfigSignature
+	for _, signer := range signers {
+		signature, err := resource.SignChannelConfig(cc.context, configUpdate, signer)
+		if err != nil {
+			return errors.WithMessage(err, "failed to sign config update")
+		}
+		signatures = append(signatures, signature)
+	}
+
+	orderer, err := cc.channelOrderer()
+	if err != nil {
+		return err
+	}
+
+	reqCtx, cancel := contextImpl.NewRequest(cc.context, contextImpl.WithTimeoutType(fab.OrdererResponse),
+		contextImpl.WithTimeout(txnOpts.Timeouts[fab.OrdererResponse]), contextImpl.WithParent(txnOpts.ParentContext))
+	defer cancel()
+
+	request := resource.CreateChannelRequest{
+		Name:       channelID,
+		Orderer:    orderer,
+		Config:     configUpdate,
+		Signatures: signatures,
+	}
+	if _, err := resource.CreateChannel(reqCtx, request, resource.WithRetry(txnOpts.Retry)); err != nil {
+		return errors.WithMessage(err, "failed to submit config update")
+	}
+	return nil
+}
+
+// checkConfigUpdateSigners checks that the signers satisfy the mod policies of the elements of the channel
+// config that are modified by the write set of the update, i.e. whose version differs from the current one
+func checkConfigUpdateSigners(channelGroup *common.ConfigGroup, update *common.ConfigUpdate, signers []msp.SigningIdentity) error {
+	if update.WriteSet == nil {
+		return nil
+	}
+
+	checker := &signersChecker{channelGroup: channelGroup, mspIDs: make(map[string]bool)}
+	for _, signer := range signers {
+		checker.mspIDs[signer.Identifier().MSPID] = true
+	}
+	return checker.checkGroup([]string{channelConfig.ChannelGroupKey}, channelGroup, update.WriteSet)
+}
+
+// signersChecker evaluates mod policies of the channel config against the MSP IDs of the signers
+type signersChecker struct {
+	channelGroup *common.ConfigGroup
+	mspIDs       map[string]bool
+}
+
+// checkGroup checks the mod policies of the modified elements of the group at the given path. New groups,
+// values and policies are covered by the mod policy of the group that contains them, since its version
+// must be incremented to add them.
+func (c *signersChecker) checkGroup(path []string, existing, written *common.ConfigGroup) error {
+	if existing == nil || written == nil {
+		return nil
+	}
+
+	if written.Version != existing.Version {
+		if err := c.checkModPolicy(path, existing.ModPolicy, strings.Join(path, "/")); err != nil {
+			return err
+		}
+	}
+	for key, value := range written.Values {
+		if current, ok := existing.Values[key]; ok && value != nil && value.Version != current.Version {
+			if err := c.checkModPolicy(path, current.ModPolicy, strings.Join(append(path, key), "/")); err != nil {
+				return err
+			}
+		}
+	}
+	for key, policy := range written.Policies {
+		if current, ok := existing.Policies[key]; ok && policy != nil && policy.Version != current.Version {
+			if err := c.checkModPolicy(path, current.ModPolicy, strings.Join(append(path, key), "/")); err != nil {
+				return err
+			}
+		}
+	}
+	for key, group := range written.Groups {
+		if err := c.checkGroup(append(path[:len(path):len(path)], key), existing.Groups[key], group); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkModPolicy checks that the signers satisfy the mod policy of an element of the group at the given path.
+// The mod policy is either an absolute path, e.g. /Channel/Application/Admins, or relative to the group.
+func (c *signersChecker) checkModPolicy(path []string, modPolicy, element string) error {
+	if modPolicy == "" {
+		modPolicy = defaultModPolicy
+	}
+
+	var policyPath []string
+	if strings.HasPrefix(modPolicy, "/") {
+		policyPath = strings.Split(strings.TrimPrefix(modPolicy, "/"), "/")
+		if len(policyPath) < 2 || policyPath[0] != channelConfig.ChannelGroupKey {
+			return nil
+		}
+	} else {
+		policyPath = append(path[:len(path):len(path)], strings.Split(modPolicy, "/")...)
+	}
+
+	group := c.channelGroup
+	for _, key := range policyPath[1 : len(policyPath)-1] {
+		if group = group.Groups[key]; group == nil {
+			// An unknown policy is left to the orderer
+			return nil
+		}
+	}
+
+	satisfied, err := c.satisfies(group, policyPath[len(policyPath)-1])
+	if err != nil {
+		return err
+	}
+	if !satisfied {
+		return errors.Errorf("signers do not satisfy the mod policy [%s] of [%s]", modPolicy, element)
+	}
+	return nil
+}
+
+// satisfies reports whether the signers satisfy the named policy of the group. Implicit meta policies are
+// evaluated against the sub-groups of the group. Any other policy is considered satisfied by a signer of the
+// organization of the group, or left to the orderer if the group is not an organization.
+func (c *signersChecker) satisfies(group *common.ConfigGroup, policyName string) (bool, error) {
+	configPolicy, ok := group.Policies[policyName]
+	if !ok || configPolicy.Policy == nil {
+		return true, nil
+	}
+
+	if configPolicy.Policy.Type != int32(common.Policy_IMPLICIT_META) {
+		mspID, err := organizationMSPID(group)
+		if err != nil {
+			return false, err
+		}
+		return mspID == "" || c.mspIDs[mspID], nil
+	}
+
+	implicitMetaPolicy := &common.ImplicitMetaPolicy{}
+	if err := proto.Unmarshal(configPolicy.Policy.Value, implicitMetaPolicy); err != nil {
+		return false, errors.Wrap(err, "unmarshal implicit meta policy from config failed")
+	}
+
+	var satisfied int
+	for _, subGroup := range group.Groups {
+		ok, err := c.satisfies(subGroup, implicitMetaPolicy.SubPolicy)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			satisfied++
+		}
+	}
+
+	var required int
+	switch implicitMetaPolicy.Rule {
+	case common.ImplicitMetaPolicy_ANY:
+		required = 1
+	case common.ImplicitMetaPolicy_ALL:
+		required = len(group.Groups)
+	case common.ImplicitMetaPolicy_MAJORITY:
+		required = len(group.Groups)/2 + 1
+	}
+	return satisfied >= required, nil
+}
+
+// organizationMSPID returns the MSP ID of an organization group, or an empty string if the group has no MSP
+func organizationMSPID(group *common.ConfigGroup) (string, error) {
+	value, ok := group.Values[channelConfig.MSPKey]
+	if !ok {
+		return "", nil
+	}
+	mspConfig := &mb.MSPConfig{}
+	if err := proto.Unmarshal(value.Value, mspConfig); err != nil {
+		return "", errors.Wrap(err, "unmarshal MSPConfig from config failed")
+	}
+	fabricMSPConfig := &mb.FabricMSPConfig{}
+	if err := proto.Unmarshal(mspConfig.Config, fabricMSPConfig); err != nil {
+		return "", errors.Wrap(err, "unmarshal FabricMSPConfig from config failed")
+	}
+	return fabricMSPConfig.Name, nil
+}
+
+// channelOrderer returns a random orderer of the channel, or any configured orderer if none is configured for the channel
+func (cc *Client) channelOrderer() (fab.Orderer, error) {
+	orderers := cc.context.EndpointConfig().ChannelOrderers(cc.context.ChannelID())
+	if len(orderers) == 0 {
+		orderers = cc.context.EndpointConfig().OrderersConfig()
+	}
+	if len(orderers) == 0 {
+		return nil, errors.New("no orderers found")
+	}
+
+	ordererCfg := orderers[rand.Intn(len(orderers))]
+	orderer, err := cc.context.InfraProvider().CreateOrdererFromConfig(&ordererCfg)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create orderer from config")
+	}
+	return orderer, nil
+}
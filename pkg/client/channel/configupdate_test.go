@@ -0,0 +1,138 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func newMockConfigBlock(mspNames ...string) *common.Block {
+	builder := &fcmocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: fcmocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       mspNames,
+			OrdererAddress: "localhost:9999",
+		},
+	}
+	return builder.Build()
+}
+
+func TestCheckConfigUpdateSigners(t *testing.T) {
+	channelGroup, err := channelGroupFromBlock(newMockConfigBlock("Org1MSP", "Org2MSP", "Org3MSP"))
+	require.NoError(t, err)
+
+	org1User := mspmocks.NewMockSigningIdentity("user1", "Org1MSP")
+	org1Admin := mspmocks.NewMockSigningIdentity("admin1", "Org1MSP")
+	org2Admin := mspmocks.NewMockSigningIdentity("admin2", "Org2MSP")
+	ordererAdmin := mspmocks.NewMockSigningIdentity("admin", "OrdererMSP")
+
+	appUpdate := &common.ConfigUpdate{
+		WriteSet: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{
+				string(fab.ApplicationGroupKey): {Version: 1},
+			},
+		},
+	}
+
+	// Signature policies of groups other than organizations are left to the orderer
+	assert.NoError(t, checkConfigUpdateSigners(channelGroup, appUpdate, []msp.SigningIdentity{org1User}))
+
+	channelGroup.Groups[string(fab.ApplicationGroupKey)].Policies["Admins"] = &common.ConfigPolicy{
+		Policy: &common.Policy{
+			Type:  int32(common.Policy_IMPLICIT_META),
+			Value: marshalOrFail(t, &common.ImplicitMetaPolicy{SubPolicy: "Admins", Rule: common.ImplicitMetaPolicy_MAJORITY}),
+		},
+	}
+
+	err = checkConfigUpdateSigners(channelGroup, appUpdate, []msp.SigningIdentity{org1Admin, org1User, ordererAdmin})
+	assert.EqualError(t, err, "signers do not satisfy the mod policy [Admins] of [Channel/Application]")
+
+	err = checkConfigUpdateSigners(channelGroup, appUpdate, []msp.SigningIdentity{org1Admin, org2Admin})
+	assert.NoError(t, err)
+
+	// An update of an organization only requires signers of that organization
+	orgUpdate := &common.ConfigUpdate{
+		WriteSet: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{
+				string(fab.ApplicationGroupKey): {
+					Groups: map[string]*common.ConfigGroup{
+						"Org1MSP": {Values: map[string]*common.ConfigValue{"MSP": {Version: 1}}},
+					},
+				},
+			},
+		},
+	}
+	err = checkConfigUpdateSigners(channelGroup, orgUpdate, []msp.SigningIdentity{org2Admin})
+	assert.EqualError(t, err, "signers do not satisfy the mod policy [Admins] of [Channel/Application/Org1MSP/MSP]")
+	assert.NoError(t, checkConfigUpdateSigners(channelGroup, orgUpdate, []msp.SigningIdentity{org1Admin}))
+
+	// Absolute mod policy paths are resolved from the channel group
+	channelGroup.Values["OrdererAddresses"].ModPolicy = "/Channel/Application/Admins"
+	channelUpdate := &common.ConfigUpdate{
+		WriteSet: &common.ConfigGroup{Values: map[string]*common.ConfigValue{"OrdererAddresses": {Version: 1}}},
+	}
+	err = checkConfigUpdateSigners(channelGroup, channelUpdate, []msp.SigningIdentity{org1Admin})
+	assert.EqualError(t, err, "signers do not satisfy the mod policy [/Channel/Application/Admins] of [Channel/OrdererAddresses]")
+	assert.NoError(t, checkConfigUpdateSigners(channelGroup, channelUpdate, []msp.SigningIdentity{org1Admin, org2Admin}))
+}
+
+func TestSubmitConfigUpdate(t *testing.T) {
+	payload, err := proto.Marshal(newMockConfigBlock("Org1MSP", "Org2MSP"))
+	require.NoError(t, err)
+
+	testPeer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer.Payload = payload
+	chClient := setupChannelClient([]fab.Peer{testPeer}, t)
+
+	broadcasts := make(chan *fab.SignedEnvelope, 1)
+	orderer := fcmocks.NewMockOrderer("", broadcasts)
+	defer orderer.CloseQueue()
+	chClient.context.InfraProvider().(*fcmocks.MockInfraProvider).SetCustomOrderer(orderer)
+
+	err = chClient.SubmitConfigUpdate(nil, nil)
+	assert.Error(t, err, "expected error for nil config update")
+
+	err = chClient.SubmitConfigUpdate(&common.ConfigUpdate{ChannelId: "otherchannel"}, nil)
+	assert.Error(t, err, "expected error for config update of another channel")
+
+	update := &common.ConfigUpdate{WriteSet: &common.ConfigGroup{Version: 1}}
+	signers := []msp.SigningIdentity{mspmocks.NewMockSigningIdentity("admin1", "Org1MSP"), mspmocks.NewMockSigningIdentity("admin2", "Org2MSP")}
+	require.NoError(t, chClient.SubmitConfigUpdate(update, signers))
+	assert.Empty(t, update.ChannelId, "the config update of the caller should not be modified")
+
+	select {
+	case envelope := <-broadcasts:
+		configUpdatePayload := &common.Payload{}
+		require.NoError(t, proto.Unmarshal(envelope.Payload, configUpdatePayload))
+		configUpdateEnvelope := &common.ConfigUpdateEnvelope{}
+		require.NoError(t, proto.Unmarshal(configUpdatePayload.Data, configUpdateEnvelope))
+		assert.Len(t, configUpdateEnvelope.Signatures, 2)
+
+		configUpdate := &common.ConfigUpdate{}
+		require.NoError(t, proto.Unmarshal(configUpdateEnvelope.ConfigUpdate, configUpdate))
+		assert.Equal(t, channelID, configUpdate.ChannelId)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for config update broadcast")
+	}
+}
+
+func marshalOrFail(t *testing.T, pb proto.Message) []byte {
+	bytes, err := proto.Marshal(pb)
+	require.NoError(t, err)
+	return bytes
+}
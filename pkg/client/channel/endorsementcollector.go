@@ -0,0 +1,178 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// EndorsementCollector collects the endorsements of a proposal as they arrive, e.g. in multi-party flows where
+// the endorsing organizations endorse the proposal at different times, until the endorsement policy is satisfied.
+// Principals of the policy are matched on the MSP ID of the endorser; the signatures of the endorsements are not
+// verified by the collector.
+type EndorsementCollector struct {
+	policy    *common.SignaturePolicyEnvelope
+	mutex     sync.Mutex
+	responses []*pb.ProposalResponse
+	endorsers []string
+	satisfied chan struct{}
+}
+
+// NewEndorsementCollector returns a collector of endorsements for the given endorsement policy
+func NewEndorsementCollector(policy *common.SignaturePolicyEnvelope) *EndorsementCollector {
+	return &EndorsementCollector{policy: policy, satisfied: make(chan struct{})}
+}
+
+// AddEndorsement adds the response of an endorser to the collected endorsements and checks whether the endorsement
+// policy is satisfied. Endorsements that are added after the policy is satisfied are also collected. The
+// endorsement is not collected if an error is returned.
+//  Parameters:
+//  resp is the successful proposal response of an endorser
+//
+//  Returns:
+//  an error if the response is not a successful endorsement of the proposal of the collected endorsements
+func (c *EndorsementCollector) AddEndorsement(resp *pb.ProposalResponse) error {
+	if resp == nil || resp.Response == nil {
+		return errors.New("proposal response is required")
+	}
+	if resp.Response.Status < int32(common.Status_SUCCESS) || resp.Response.Status >= int32(common.Status_BAD_REQUEST) {
+		return errors.Errorf("endorsement failed with status [%d]: %s", resp.Response.Status, resp.Response.Message)
+	}
+	if resp.Endorsement == nil {
+		return errors.New("proposal response has no endorsement")
+	}
+	endorser := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(resp.Endorsement.Endorser, endorser); err != nil {
+		return errors.Wrap(err, "unmarshal of endorser failed")
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, r := range c.responses {
+		if !bytes.Equal(r.Payload, resp.Payload) {
+			return errors.New("proposal response payload does not match the payloads of the collected endorsements")
+		}
+		if bytes.Equal(r.Endorsement.Endorser, resp.Endorsement.Endorser) {
+			return errors.Errorf("duplicate endorsement from [%s]", endorser.Mspid)
+		}
+	}
+
+	endorsers := append(append([]string(nil), c.endorsers...), endorser.Mspid)
+
+	select {
+	case <-c.satisfied:
+		c.add(resp, endorsers)
+		return nil
+	default:
+	}
+
+	// The endorsement is only collected if the policy can be evaluated, so that an invalid policy leaves the
+	// collected endorsements unchanged
+	satisfied, err := c.isSatisfied(endorsers)
+	if err != nil {
+		return err
+	}
+	c.add(resp, endorsers)
+	if satisfied {
+		close(c.satisfied)
+	}
+	return nil
+}
+
+// add records a response along with the endorsers of the collected endorsements
+func (c *EndorsementCollector) add(resp *pb.ProposalResponse, endorsers []string) {
+	c.responses = append(c.responses, resp)
+	c.endorsers = endorsers
+}
+
+// Wait blocks until the endorsement policy is satisfied by the collected endorsements or the timeout expires
+//  Parameters:
+//  timeout is the maximum time to wait
+//
+//  Returns:
+//  the collected endorsements
+func (c *EndorsementCollector) Wait(timeout time.Duration) ([]*pb.ProposalResponse, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-c.satisfied:
+	default:
+		select {
+		case <-c.satisfied:
+		case <-timer.C:
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+			return nil, status.New(status.ClientStatus, status.Timeout.ToInt32(),
+				fmt.Sprintf("endorsement policy not satisfied by endorsements of %v", c.endorsers), nil)
+		}
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	responses := make([]*pb.ProposalResponse, len(c.responses))
+	copy(responses, c.responses)
+	return responses, nil
+}
+
+// isSatisfied evaluates the endorsement policy against the endorsements of the given endorsers
+func (c *EndorsementCollector) isSatisfied(endorsers []string) (bool, error) {
+	if c.policy == nil || c.policy.Rule == nil {
+		return false, errors.New("endorsement policy is required")
+	}
+	return c.evaluate(c.policy.Rule, endorsers, make([]bool, len(endorsers)))
+}
+
+// evaluate evaluates a rule of the endorsement policy. As in Fabric, each endorsement may only satisfy one
+// principal, so used records the endorsements that satisfy principals of the rule.
+func (c *EndorsementCollector) evaluate(rule *common.SignaturePolicy, endorsers []string, used []bool) (bool, error) {
+	switch t := rule.Type.(type) {
+	case *common.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(c.policy.Identities) {
+			return false, errors.Errorf("endorsement policy identity index [%d] out of range", t.SignedBy)
+		}
+		mspID, err := principalMSPID(c.policy.Identities[t.SignedBy])
+		if err != nil {
+			return false, errors.WithMessage(err, "invalid endorsement policy")
+		}
+		for i, endorser := range endorsers {
+			if !used[i] && endorser == mspID {
+				used[i] = true
+				return true, nil
+			}
+		}
+		return false, nil
+	case *common.SignaturePolicy_NOutOf_:
+		verified := int32(0)
+		for _, r := range t.NOutOf.Rules {
+			ruleUsed := make([]bool, len(used))
+			copy(ruleUsed, used)
+			ok, err := c.evaluate(r, endorsers, ruleUsed)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				verified++
+				copy(used, ruleUsed)
+			}
+		}
+		return verified >= t.NOutOf.N, nil
+	default:
+		return false, errors.Errorf("unsupported endorsement policy rule type [%T]", rule.Type)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+func TestEndorsementCollector(t *testing.T) {
+	// Org1 and either Org2 or Org3
+	policy := memberPolicy(t, cauthdsl.And(cauthdsl.SignedBy(0), cauthdsl.Or(cauthdsl.SignedBy(1), cauthdsl.SignedBy(2))), "Org1MSP", "Org2MSP", "Org3MSP")
+	collector := NewEndorsementCollector(policy)
+
+	assert.EqualError(t, collector.AddEndorsement(nil), "proposal response is required")
+	failed := newEndorsement(t, "Org2MSP", "peer1.org2", "payload")
+	failed.Response = &pb.Response{Status: 500, Message: "simulation failed"}
+	assert.EqualError(t, collector.AddEndorsement(failed), "endorsement failed with status [500]: simulation failed")
+
+	require.NoError(t, collector.AddEndorsement(newEndorsement(t, "Org2MSP", "peer1.org2", "payload")))
+	assert.EqualError(t, collector.AddEndorsement(newEndorsement(t, "Org2MSP", "peer1.org2", "payload")), "duplicate endorsement from [Org2MSP]")
+	assert.EqualError(t, collector.AddEndorsement(newEndorsement(t, "Org1MSP", "peer1.org1", "other")),
+		"proposal response payload does not match the payloads of the collected endorsements")
+
+	// Endorsements of Org2 do not satisfy the principal of Org1
+	require.NoError(t, collector.AddEndorsement(newEndorsement(t, "Org2MSP", "peer2.org2", "payload")))
+	_, err := collector.Wait(10 * time.Millisecond)
+	s, ok := status.FromError(err)
+	require.True(t, ok, "expecting status error")
+	assert.Equal(t, status.Timeout.ToInt32(), s.Code)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		assert.NoError(t, collector.AddEndorsement(newEndorsement(t, "Org1MSP", "peer1.org1", "payload")))
+	}()
+	responses, err := collector.Wait(5 * time.Second)
+	require.NoError(t, err)
+	assert.Len(t, responses, 3)
+
+	// Endorsements are still collected after the policy is satisfied
+	require.NoError(t, collector.AddEndorsement(newEndorsement(t, "Org3MSP", "peer1.org3", "payload")))
+	responses, err = collector.Wait(0)
+	require.NoError(t, err)
+	assert.Len(t, responses, 4)
+}
+
+func TestEndorsementCollectorPrincipalUsedOnce(t *testing.T) {
+	// Two endorsements of Org1 are required, so one endorsement must not satisfy both principals
+	policy := memberPolicy(t, cauthdsl.And(cauthdsl.SignedBy(0), cauthdsl.SignedBy(0)), "Org1MSP")
+	collector := NewEndorsementCollector(policy)
+
+	require.NoError(t, collector.AddEndorsement(newEndorsement(t, "Org1MSP", "peer1.org1", "payload")))
+	_, err := collector.Wait(10 * time.Millisecond)
+	assert.Error(t, err)
+
+	require.NoError(t, collector.AddEndorsement(newEndorsement(t, "Org1MSP", "peer2.org1", "payload")))
+	responses, err := collector.Wait(time.Second)
+	require.NoError(t, err)
+	assert.Len(t, responses, 2)
+}
+
+func TestEndorsementCollectorInvalidPolicy(t *testing.T) {
+	collector := NewEndorsementCollector(nil)
+	assert.EqualError(t, collector.AddEndorsement(newEndorsement(t, "Org1MSP", "peer1.org1", "payload")), "endorsement policy is required")
+
+	collector = NewEndorsementCollector(memberPolicy(t, cauthdsl.SignedBy(1), "Org1MSP"))
+	assert.EqualError(t, collector.AddEndorsement(newEndorsement(t, "Org1MSP", "peer1.org1", "payload")), "endorsement policy identity index [1] out of range")
+
+	// The endorsements are not collected, so retrying the endorsement is not rejected as a duplicate
+	assert.EqualError(t, collector.AddEndorsement(newEndorsement(t, "Org1MSP", "peer1.org1", "payload")), "endorsement policy identity index [1] out of range")
+	_, err := collector.Wait(0)
+	s, ok := status.FromError(err)
+	require.True(t, ok, "expecting status error")
+	assert.Contains(t, s.Message, "endorsements of []")
+}
+
+// memberPolicy returns a policy with the given rule, where identity i is a member of the i-th MSP
+func memberPolicy(t *testing.T, rule *common.SignaturePolicy, mspIDs ...string) *common.SignaturePolicyEnvelope {
+	policy := &common.SignaturePolicyEnvelope{Rule: rule}
+	for _, mspID := range mspIDs {
+		role, err := proto.Marshal(&mb.MSPRole{Role: mb.MSPRole_MEMBER, MspIdentifier: mspID})
+		require.NoError(t, err)
+		policy.Identities = append(policy.Identities, &mb.MSPPrincipal{PrincipalClassification: mb.MSPPrincipal_ROLE, Principal: role})
+	}
+	return policy
+}
+
+func newEndorsement(t *testing.T, mspID, endorser, payload string) *pb.ProposalResponse {
+	identity, err := proto.Marshal(&mb.SerializedIdentity{Mspid: mspID, IdBytes: []byte(endorser)})
+	require.NoError(t, err)
+	return &pb.ProposalResponse{
+		Response:    &pb.Response{Status: int32(common.Status_SUCCESS)},
+		Payload:     []byte(payload),
+		Endorsement: &pb.Endorsement{Endorser: identity, Signature: []byte("signature")},
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -32,6 +33,14 @@ type Opts struct {
 	Timeouts      map[fab.TimeoutType]time.Duration
 	ParentContext reqContext.Context //parent grpc context
 	CCFilter      CCFilter
+	TransientData map[string][]byte
+
+	// AdditionalChaincodes are added to the invocation chain so that their
+	// endorsement policies are included when selecting endorsers
+	AdditionalChaincodes []string
+
+	// MinBlockHeight is the minimum ledger height of the peers that the request is sent to
+	MinBlockHeight uint64
 }
 
 // Request contains the parameters to execute transaction
@@ -60,6 +69,16 @@ type Response struct {
 	TxValidationCode pb.TxValidationCode
 	ChaincodeStatus  int32
 	Payload          []byte
+	SimulationResult *SimulationResult
+}
+
+// SimulationResult contains the result of a transaction that was endorsed but not sent to the orderer
+type SimulationResult struct {
+	ChaincodeID string
+	// Payload is the payload of the chaincode response
+	Payload []byte
+	// RWSets contains the read-write set of each chaincode (namespace) that was invoked by the transaction
+	RWSets []*rwsetutil.NsRwSet
 }
 
 //Handler for chaining transaction executions
@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package invoke
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+)
+
+//NewSignedProposalEndorsementHandler returns a handler that endorses a proposal that has been signed outside of the SDK
+func NewSignedProposalEndorsementHandler(proposal *fab.TransactionProposal, signedProposal *pb.SignedProposal, next ...Handler) *SignedProposalEndorsementHandler {
+	return &SignedProposalEndorsementHandler{proposal: proposal, signedProposal: signedProposal, next: getNext(next)}
+}
+
+//SignedProposalEndorsementHandler selects endorsers according to the policy of the chaincode, unless targets
+//are provided, and sends the signed proposal to those endorsers
+type SignedProposalEndorsementHandler struct {
+	proposal       *fab.TransactionProposal
+	signedProposal *pb.SignedProposal
+	next           Handler
+}
+
+//Handle for endorsing signed proposals
+func (e *SignedProposalEndorsementHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	targets := requestContext.Opts.Targets
+	if len(targets) == 0 {
+		var err error
+		_, targets, err = getEndorsers(requestContext, clientContext)
+		if err != nil {
+			requestContext.Error = err
+			return
+		}
+	}
+	if len(targets) == 0 {
+		requestContext.Error = status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "targets were not provided", nil)
+		return
+	}
+
+	transactionProposalResponses, err := txn.SendSignedProposal(requestContext.Ctx, e.signedProposal, peer.PeersToTxnProcessors(targets))
+
+	requestContext.Response.Proposal = e.proposal
+	requestContext.Response.TransactionID = e.proposal.TxnID
+
+	if err != nil {
+		requestContext.Error = err
+		return
+	}
+
+	requestContext.Response.Responses = transactionProposalResponses
+	if len(transactionProposalResponses) > 0 {
+		requestContext.Response.Payload = transactionProposalResponses[0].ProposalResponse.GetResponse().Payload
+		requestContext.Response.ChaincodeStatus = transactionProposalResponses[0].ChaincodeStatus
+	}
+
+	//Delegate to next step if any
+	if e.next != nil {
+		e.next.Handle(requestContext, clientContext)
+	}
+}
@@ -103,6 +103,11 @@ func (h *ProposalProcessorHandler) Handle(requestContext *RequestContext, client
 	}
 }
 
+// InvocationChain returns the chaincodes (and collections) whose endorsement policies determine the endorsers of the request
+func InvocationChain(requestContext *RequestContext) []*fab.ChaincodeCall {
+	return newInvocationChain(requestContext)
+}
+
 func newInvocationChain(requestContext *RequestContext) []*fab.ChaincodeCall {
 	invocChain := []*fab.ChaincodeCall{{ID: requestContext.Request.ChaincodeID}}
 	for _, ccCall := range requestContext.Request.InvocationChain {
@@ -112,9 +117,23 @@ func newInvocationChain(requestContext *RequestContext) []*fab.ChaincodeCall {
 			invocChain = append(invocChain, ccCall)
 		}
 	}
+	for _, ccID := range requestContext.Opts.AdditionalChaincodes {
+		if !containsChaincode(invocChain, ccID) {
+			invocChain = append(invocChain, &fab.ChaincodeCall{ID: ccID})
+		}
+	}
 	return invocChain
 }
 
+func containsChaincode(invocChain []*fab.ChaincodeCall, ccID string) bool {
+	for _, ccCall := range invocChain {
+		if ccCall.ID == ccID {
+			return true
+		}
+	}
+	return false
+}
+
 //EndorsementValidationHandler for transaction proposal response filtering
 type EndorsementValidationHandler struct {
 	next Handler
@@ -202,6 +221,36 @@ func (c *CommitTxHandler) Handle(requestContext *RequestContext, clientContext *
 	}
 }
 
+//SimulationHandler for collecting the results of a simulated transaction
+type SimulationHandler struct {
+	next Handler
+}
+
+//Handle sets the simulation result of the response from the endorsements of the transaction
+func (s *SimulationHandler) Handle(requestContext *RequestContext, clientContext *ClientContext) {
+	if len(requestContext.Response.Responses) == 0 {
+		requestContext.Error = errors.New("no endorsements to get the simulation result from")
+		return
+	}
+
+	rwSets, err := getRWSetsFromProposalResponse(requestContext.Response.Responses[0].ProposalResponse)
+	if err != nil {
+		requestContext.Error = errors.WithMessage(err, "error getting read-write sets from proposal response")
+		return
+	}
+
+	requestContext.Response.SimulationResult = &SimulationResult{
+		ChaincodeID: requestContext.Request.ChaincodeID,
+		Payload:     requestContext.Response.Payload,
+		RWSets:      rwSets,
+	}
+
+	//Delegate to next step if any
+	if s.next != nil {
+		s.next.Handle(requestContext, clientContext)
+	}
+}
+
 //NewQueryHandler returns query handler with chain of ProposalProcessorHandler, EndorsementHandler, EndorsementValidationHandler and SignatureValidationHandler
 func NewQueryHandler(next ...Handler) Handler {
 	return NewProposalProcessorHandler(
@@ -222,6 +271,16 @@ func NewExecuteHandler(next ...Handler) Handler {
 	)
 }
 
+//NewSimulationExecuteHandler returns execute handler with chain of SelectAndEndorseHandler, EndorsementValidationHandler, SignatureValidationHandler and SimulationHandler.
+//The transaction is endorsed but not sent to the orderer.
+func NewSimulationExecuteHandler(next ...Handler) Handler {
+	return NewSelectAndEndorseHandler(
+		NewEndorsementValidationHandler(
+			NewSignatureValidationHandler(NewSimulationHandler(next...)),
+		),
+	)
+}
+
 //NewProposalProcessorHandler returns a handler that selects proposal processors
 func NewProposalProcessorHandler(next ...Handler) *ProposalProcessorHandler {
 	return &ProposalProcessorHandler{next: getNext(next)}
@@ -247,6 +306,11 @@ func NewCommitHandler(next ...Handler) *CommitTxHandler {
 	return &CommitTxHandler{next: getNext(next)}
 }
 
+//NewSimulationHandler returns a handler that collects the results of a simulated transaction
+func NewSimulationHandler(next ...Handler) *SimulationHandler {
+	return &SimulationHandler{next: getNext(next)}
+}
+
 func getNext(next []Handler) Handler {
 	if len(next) > 0 {
 		return next[0]
@@ -319,6 +319,7 @@ func TestProposalProcessorHandler(t *testing.T) {
 func TestNewInvocationChain(t *testing.T) {
 	ccID1 := "cc1"
 	ccID2 := "cc2"
+	ccID3 := "cc3"
 	col1 := "col1"
 	col2 := "col2"
 
@@ -363,6 +364,13 @@ func TestNewInvocationChain(t *testing.T) {
 	require.Equal(t, ccID2, ccCalls[1].ID)
 	require.Truef(t, len(ccCalls[0].Collections) == 2, "expecting 2 collections for [%s]", ccID1)
 	require.Truef(t, len(ccCalls[1].Collections) == 1, "expecting 1 collection for [%s]", ccID2)
+
+	// Additional chaincodes are appended unless already in the invocation chain
+	ccCalls = newInvocationChain(&RequestContext{Request: request, Opts: Opts{AdditionalChaincodes: []string{ccID2, ccID3}}})
+	require.Truef(t, len(ccCalls) == 3, "expecting 3 CC calls")
+	require.Equal(t, ccID2, ccCalls[1].ID)
+	require.Truef(t, len(ccCalls[1].Collections) == 1, "expecting 1 collection for [%s]", ccID2)
+	require.Equal(t, ccID3, ccCalls[2].ID)
 }
 
 func TestMergeInvocationChains(t *testing.T) {
@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	reqContext "context"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	selectopts "github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// ErrNoPeerAtHeight is returned if no peer with the ledger height of WithMinBlockHeight is available before the request times out
+var ErrNoPeerAtHeight = errors.New("no peer at the required block height")
+
+// blockHeightPollInterval is the interval at which endorsers are selected again while waiting for a peer to reach the minimum block height
+var blockHeightPollInterval = time.Second
+
+// waitForPeerAtHeight waits until at least one endorser (or target) of the request is at the minimum
+// block height of the request, or the request is done.
+func waitForPeerAtHeight(reqCtx reqContext.Context, requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) error {
+	for {
+		ok, err := hasPeerAtHeight(requestContext, clientContext)
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-reqCtx.Done():
+			if err != nil {
+				// The peers at the required height may not satisfy the endorsement policy
+				return errors.WithMessage(ErrNoPeerAtHeight, err.Error())
+			}
+			return ErrNoPeerAtHeight
+		case <-time.After(blockHeightPollInterval):
+		}
+	}
+}
+
+func hasPeerAtHeight(requestContext *invoke.RequestContext, clientContext *invoke.ClientContext) (bool, error) {
+	if len(requestContext.Opts.Targets) > 0 {
+		for _, target := range requestContext.Opts.Targets {
+			if requestContext.SelectionFilter(target) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	peers, err := clientContext.Selection.GetEndorsersForChaincode(
+		invoke.InvocationChain(requestContext),
+		selectopts.WithPeerFilter(requestContext.SelectionFilter),
+	)
+	if err != nil {
+		return false, err
+	}
+	return len(peers) > 0, nil
+}
+
+// isAtHeight returns true if the ledger height of the given peer is known and at least the given height
+func isAtHeight(peer fab.Peer, height uint64) bool {
+	peerState, ok := peer.(fab.PeerState)
+	return ok && peerState.BlockHeight() >= height
+}
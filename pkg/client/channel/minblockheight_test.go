@@ -0,0 +1,67 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+type peerAtHeight struct {
+	*fcmocks.MockPeer
+	height uint64
+}
+
+func (p *peerAtHeight) BlockHeight() uint64 {
+	return p.height
+}
+
+func newPeerAtHeight(name, url string, height uint64) *peerAtHeight {
+	peer := fcmocks.NewMockPeer(name, url)
+	peer.Payload = []byte("value")
+	return &peerAtHeight{MockPeer: peer, height: height}
+}
+
+func TestQueryWithMinBlockHeight(t *testing.T) {
+	peer1 := newPeerAtHeight("Peer1", "http://peer1.com", 5)
+	peer2 := newPeerAtHeight("Peer2", "http://peer2.com", 10)
+	chClient := setupChannelClient([]fab.Peer{peer1, peer2}, t)
+
+	resp, err := chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}},
+		WithMinBlockHeight(8))
+	require.NoError(t, err)
+	require.Len(t, resp.Responses, 1)
+	assert.Equal(t, peer2.URL(), resp.Responses[0].Endorser, "expected query to be sent to the peer at the block height")
+}
+
+func TestQueryWithMinBlockHeightNoPeer(t *testing.T) {
+	pollInterval := blockHeightPollInterval
+	blockHeightPollInterval = 10 * time.Millisecond
+	defer func() { blockHeightPollInterval = pollInterval }()
+
+	peer1 := newPeerAtHeight("Peer1", "http://peer1.com", 5)
+	chClient := setupChannelClient([]fab.Peer{peer1}, t)
+
+	_, err := chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}},
+		WithMinBlockHeight(8), WithTimeout(fab.Execute, 100*time.Millisecond))
+	require.Error(t, err)
+	assert.Equal(t, ErrNoPeerAtHeight, errors.Cause(err))
+
+	_, err = chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}},
+		WithTargets(peer1), WithMinBlockHeight(8), WithTimeout(fab.Execute, 100*time.Millisecond))
+	assert.Equal(t, ErrNoPeerAtHeight, errors.Cause(err), "expected targets below the block height to be excluded")
+
+	_, err = chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke"}, WithMinBlockHeight(0))
+	assert.Error(t, err, "expected error for zero block height")
+}
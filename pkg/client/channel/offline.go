@@ -0,0 +1,247 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"bytes"
+	"encoding/pem"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/channel/invoke"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protoutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// GenerateUnsignedProposal creates a chaincode invoke proposal that is signed outside of the SDK, e.g. by a
+// hardware signer, and then submitted with SubmitSignedProposal. The creator of the proposal is the identity
+// of the client, so the proposal must be signed with the key of that identity. The bytes to sign are the
+// marshalled proposal, as returned by proto.Marshal(proposal.Proposal).
+//  Parameters:
+//  channelID is the ID of the channel of the client
+//  chaincodeID is the mandatory chaincode ID
+//  args holds the function name followed by the arguments of the function
+//
+//  Returns:
+//  the unsigned proposal
+func (cc *Client) GenerateUnsignedProposal(channelID, chaincodeID string, args []string) (*fab.TransactionProposal, error) {
+	if channelID != cc.context.ChannelID() {
+		return nil, errors.Errorf("channel client is bound to channel [%s], not [%s]", cc.context.ChannelID(), channelID)
+	}
+	if len(args) == 0 {
+		return nil, errors.New("function name is required")
+	}
+
+	txh, err := txn.NewHeader(cc.context, channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating transaction header failed")
+	}
+
+	request := fab.ChaincodeInvokeRequest{ChaincodeID: chaincodeID, Fcn: args[0]}
+	for _, arg := range args[1:] {
+		request.Args = append(request.Args, []byte(arg))
+	}
+	return txn.CreateChaincodeInvokeProposal(txh, request)
+}
+
+// SubmitSignedProposal attaches the signature of a proposal created by GenerateUnsignedProposal and sends the
+// signed proposal to the endorsers of the chaincode. The signature is verified against the creator of the
+// proposal before the proposal is sent. The proposal responses are returned rather than committed, since the
+// transaction envelope must also be signed by the creator: it can be built with txn.TransactionBuilder, signed
+// offline and then submitted with SubmitSignedTransaction.
+//  Parameters:
+//  proposal is the proposal created by GenerateUnsignedProposal
+//  signature is the signature of the marshalled proposal
+//  signerCert is the PEM-encoded certificate of the signer, which must be the creator of the proposal
+//  options holds optional request options
+//
+//  Returns:
+//  the transaction ID and the proposal responses from peer(s)
+func (cc *Client) SubmitSignedProposal(proposal *fab.TransactionProposal, signature []byte, signerCert []byte, options ...RequestOption) (Response, error) {
+	if proposal == nil || proposal.Proposal == nil {
+		return Response{}, errors.New("proposal is required")
+	}
+	if len(signature) == 0 {
+		return Response{}, errors.New("signature is required")
+	}
+
+	proposalBytes, err := proto.Marshal(proposal.Proposal)
+	if err != nil {
+		return Response{}, errors.Wrap(err, "marshal proposal failed")
+	}
+
+	hdr, err := protoutil.GetHeader(proposal.Header)
+	if err != nil {
+		return Response{}, err
+	}
+	if err := cc.verifyProposalSignature(hdr, proposalBytes, signature, signerCert); err != nil {
+		return Response{}, err
+	}
+
+	request, err := proposalRequest(hdr, proposal.Payload)
+	if err != nil {
+		return Response{}, err
+	}
+
+	signedProposal := &pb.SignedProposal{ProposalBytes: proposalBytes, Signature: signature}
+	options = append(options, addDefaultTimeout(fab.Execute))
+	options = append(options, addDefaultTargetFilter(cc.context, filter.EndorsingPeer))
+
+	return cc.InvokeHandler(invoke.NewSignedProposalEndorsementHandler(proposal, signedProposal, invoke.NewEndorsementValidationHandler()), request, options...)
+}
+
+// SubmitSignedTransaction sends a transaction envelope that has been signed outside of the SDK to the orderer
+// and waits for the transaction to be committed. The envelope is built from the proposal responses returned by
+// SubmitSignedProposal and its Signature is the signature of the Payload bytes by the creator of the proposal.
+// The signature is verified against the creator before the envelope is sent.
+//  Parameters:
+//  envelope is the signed transaction envelope
+//  options holds optional request options
+//
+//  Returns:
+//  the transaction ID and the validation code of the committed transaction
+func (cc *Client) SubmitSignedTransaction(envelope *common.Envelope, options ...RequestOption) (Response, error) {
+	if envelope == nil || len(envelope.Payload) == 0 {
+		return Response{}, errors.New("envelope is required")
+	}
+	if len(envelope.Signature) == 0 {
+		return Response{}, errors.New("signature is required")
+	}
+
+	channelHeader, err := cc.verifyTransactionSignature(envelope)
+	if err != nil {
+		return Response{}, err
+	}
+
+	options = append(options, addDefaultTimeout(fab.Execute))
+	txnOpts, err := cc.prepareOptsFromOptions(cc.context, options...)
+	if err != nil {
+		return Response{}, err
+	}
+
+	reqCtx, cancel := cc.createReqContext(&txnOpts)
+	defer cancel()
+
+	transactor, err := cc.context.ChannelService().Transactor(reqCtx)
+	if err != nil {
+		return Response{}, errors.WithMessage(err, "failed to create transactor")
+	}
+	provider, ok := transactor.(ordererProvider)
+	if !ok {
+		return Response{}, errors.New("transactor does not expose the orderers of the channel")
+	}
+
+	reg, statusNotifier, err := cc.eventService.RegisterTxStatusEvent(channelHeader.TxId)
+	if err != nil {
+		return Response{}, errors.WithMessage(err, "error registering for TxStatus event")
+	}
+	defer cc.eventService.Unregister(reg)
+
+	signedEnvelope := &fab.SignedEnvelope{Payload: envelope.Payload, Signature: envelope.Signature}
+	if _, err := txn.BroadcastEnvelope(reqCtx, signedEnvelope, provider.Orderers()); err != nil {
+		return Response{}, errors.WithMessage(err, "sending transaction to the orderer failed")
+	}
+
+	response := Response{TransactionID: fab.TransactionID(channelHeader.TxId)}
+	select {
+	case txStatus := <-statusNotifier:
+		response.TxValidationCode = txStatus.TxValidationCode
+		if txStatus.TxValidationCode != pb.TxValidationCode_VALID {
+			return response, status.New(status.EventServerStatus, int32(txStatus.TxValidationCode),
+				"received invalid transaction", nil)
+		}
+		return response, nil
+	case <-reqCtx.Done():
+		return response, status.New(status.ClientStatus, status.Timeout.ToInt32(),
+			"SubmitSignedTransaction didn't receive block event", nil)
+	}
+}
+
+// verifyTransactionSignature verifies that the transaction is for the channel of the client and that the
+// envelope is signed by the creator of the transaction, returning the channel header of the transaction
+func (cc *Client) verifyTransactionSignature(envelope *common.Envelope) (*common.ChannelHeader, error) {
+	payload, err := protoutil.GetPayload(envelope)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("transaction header is required")
+	}
+	channelHeader, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if channelHeader.ChannelId != cc.context.ChannelID() {
+		return nil, errors.Errorf("transaction is for channel [%s], not [%s]", channelHeader.ChannelId, cc.context.ChannelID())
+	}
+	shdr, err := protoutil.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cc.membership.Verify(shdr.Creator, envelope.Payload, envelope.Signature); err != nil {
+		return nil, errors.WithMessage(err, "transaction signature verification failed")
+	}
+	return channelHeader, nil
+}
+
+// verifyProposalSignature verifies that the signer is the creator of the proposal and that the signature
+// of the proposal is valid
+func (cc *Client) verifyProposalSignature(hdr *common.Header, proposalBytes, signature, signerCert []byte) error {
+	shdr, err := protoutil.GetSignatureHeader(hdr.SignatureHeader)
+	if err != nil {
+		return err
+	}
+	creator := &mb.SerializedIdentity{}
+	if err := proto.Unmarshal(shdr.Creator, creator); err != nil {
+		return errors.Wrap(err, "unmarshal proposal creator failed")
+	}
+	if !bytes.Equal(certBytes(signerCert), certBytes(creator.IdBytes)) {
+		return errors.New("signer certificate does not match the creator of the proposal")
+	}
+
+	if err := cc.membership.Verify(shdr.Creator, proposalBytes, signature); err != nil {
+		return errors.WithMessage(err, "proposal signature verification failed")
+	}
+	return nil
+}
+
+// proposalRequest returns the chaincode ID and the function of the proposal
+func proposalRequest(hdr *common.Header, payload []byte) (Request, error) {
+	ext, err := protoutil.GetChaincodeHeaderExtension(hdr)
+	if err != nil {
+		return Request{}, err
+	}
+	ccPayload, err := protoutil.GetChaincodeProposalPayload(payload)
+	if err != nil {
+		return Request{}, err
+	}
+	spec := &pb.ChaincodeInvocationSpec{}
+	if err := proto.Unmarshal(ccPayload.Input, spec); err != nil {
+		return Request{}, errors.Wrap(err, "unmarshal chaincode invocation spec failed")
+	}
+
+	args := spec.GetChaincodeSpec().GetInput().GetArgs()
+	if ext.GetChaincodeId().GetName() == "" || len(args) == 0 {
+		return Request{}, errors.New("proposal is not a chaincode invoke proposal")
+	}
+	return Request{ChaincodeID: ext.ChaincodeId.Name, Fcn: string(args[0]), Args: args[1:]}, nil
+}
+
+// certBytes returns the DER bytes of a PEM-encoded certificate, or the bytes as they are if they are not PEM-encoded
+func certBytes(cert []byte) []byte {
+	if block, _ := pem.Decode(cert); block != nil {
+		return block.Bytes
+	}
+	return cert
+}
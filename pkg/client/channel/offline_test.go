@@ -0,0 +1,140 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protoutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+func TestGenerateUnsignedProposal(t *testing.T) {
+	peer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	chClient := setupChannelClient([]fab.Peer{peer}, t)
+
+	_, err := chClient.GenerateUnsignedProposal("otherChannel", "testCC", []string{"move", "a", "b"})
+	assert.EqualError(t, err, "channel client is bound to channel [testChannel], not [otherChannel]")
+
+	_, err = chClient.GenerateUnsignedProposal(channelID, "testCC", nil)
+	assert.EqualError(t, err, "function name is required")
+
+	proposal, err := chClient.GenerateUnsignedProposal(channelID, "testCC", []string{"move", "a", "b"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, proposal.TxnID)
+
+	hdr, err := protoutil.GetHeader(proposal.Header)
+	require.NoError(t, err)
+	request, err := proposalRequest(hdr, proposal.Payload)
+	require.NoError(t, err)
+	assert.Equal(t, Request{ChaincodeID: "testCC", Fcn: "move", Args: [][]byte{[]byte("a"), []byte("b")}}, request)
+}
+
+func TestSubmitSignedProposal(t *testing.T) {
+	peer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peer.Payload = []byte("value")
+	chClient := setupChannelClient([]fab.Peer{peer}, t)
+
+	signerCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("signer")})
+	creator, err := proto.Marshal(&mb.SerializedIdentity{Mspid: "Org1MSP", IdBytes: signerCert})
+	require.NoError(t, err)
+	txh, err := txn.NewHeader(chClient.context, channelID, fab.WithCreator(creator))
+	require.NoError(t, err)
+	proposal, err := txn.CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{ChaincodeID: "testCC", Fcn: "move"})
+	require.NoError(t, err)
+
+	_, err = chClient.SubmitSignedProposal(proposal, nil, signerCert)
+	assert.EqualError(t, err, "signature is required")
+
+	otherCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("other")})
+	_, err = chClient.SubmitSignedProposal(proposal, []byte("signature"), otherCert)
+	assert.EqualError(t, err, "signer certificate does not match the creator of the proposal")
+
+	chClient.membership = &fcmocks.MockMembership{VerifyErr: errors.New("invalid signature")}
+	_, err = chClient.SubmitSignedProposal(proposal, []byte("signature"), signerCert)
+	assert.EqualError(t, err, "proposal signature verification failed: invalid signature")
+	assert.Equal(t, 0, peer.ProcessProposalCalls)
+
+	chClient.membership = fcmocks.NewMockMembership()
+	response, err := chClient.SubmitSignedProposal(proposal, []byte("signature"), signerCert)
+	require.NoError(t, err)
+	assert.Equal(t, proposal.TxnID, response.TransactionID)
+	assert.Equal(t, []byte("value"), response.Payload)
+	assert.Equal(t, 1, peer.ProcessProposalCalls)
+}
+
+func TestSubmitSignedTransaction(t *testing.T) {
+	peer := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	peer.Payload = []byte("value")
+	broadcasts := make(chan *fab.SignedEnvelope, 1)
+	orderer := fcmocks.NewMockOrderer("", broadcasts)
+	chClient := setupChannelClientWithNodes([]fab.Peer{peer}, []fab.Orderer{orderer}, t)
+	chClient.eventService = fcmocks.NewMockEventService()
+
+	chService := chClient.context.ChannelService().(*fcmocks.MockChannelService)
+	chService.SetTransactor(&ordererTransactor{MockTransactor: &txnmocks.MockTransactor{ChannelID: channelID, Orderers: []fab.Orderer{orderer}}})
+
+	signerCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("signer")})
+	creator, err := proto.Marshal(&mb.SerializedIdentity{Mspid: "Org1MSP", IdBytes: signerCert})
+	require.NoError(t, err)
+	txh, err := txn.NewHeader(chClient.context, channelID, fab.WithCreator(creator))
+	require.NoError(t, err)
+	proposal, err := txn.CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{ChaincodeID: "testCC", Fcn: "move"})
+	require.NoError(t, err)
+	proposalBytes, err := proto.Marshal(proposal.Proposal)
+	require.NoError(t, err)
+	response, err := chClient.SubmitSignedProposal(proposal, []byte("signature"), signerCert)
+	require.NoError(t, err)
+
+	builder := txn.NewTransactionBuilder(channelID).SetChaincodeID("testCC")
+	signedProposal := &pb.SignedProposal{ProposalBytes: proposalBytes, Signature: []byte("signature")}
+	require.NoError(t, builder.CollectEndorsement(signedProposal, response.Responses[0].ProposalResponse))
+	envelope, err := builder.Build()
+	require.NoError(t, err)
+
+	_, err = chClient.SubmitSignedTransaction(envelope)
+	assert.EqualError(t, err, "signature is required")
+
+	envelope.Signature = []byte("signature")
+	chClient.membership = &fcmocks.MockMembership{VerifyErr: errors.New("invalid signature")}
+	_, err = chClient.SubmitSignedTransaction(envelope)
+	assert.EqualError(t, err, "transaction signature verification failed: invalid signature")
+	assert.Empty(t, broadcasts, "expected the transaction not to be sent")
+
+	chClient.membership = fcmocks.NewMockMembership()
+	txResponse, err := chClient.SubmitSignedTransaction(envelope)
+	require.NoError(t, err)
+	assert.Equal(t, proposal.TxnID, txResponse.TransactionID)
+	assert.Equal(t, pb.TxValidationCode_VALID, txResponse.TxValidationCode)
+
+	select {
+	case sent := <-broadcasts:
+		assert.Equal(t, envelope.Payload, sent.Payload)
+		assert.Equal(t, envelope.Signature, sent.Signature)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the transaction to be sent to the orderer")
+	}
+
+	eventService := fcmocks.NewMockEventService()
+	eventService.TxValidationCode = pb.TxValidationCode_MVCC_READ_CONFLICT
+	chClient.eventService = eventService
+	txResponse, err = chClient.SubmitSignedTransaction(envelope)
+	assert.Error(t, err, "expected error for invalid transaction")
+	assert.Equal(t, pb.TxValidationCode_MVCC_READ_CONFLICT, txResponse.TxValidationCode)
+}
@@ -0,0 +1,195 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	reqContext "context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+	grpcCodes "google.golang.org/grpc/codes"
+)
+
+// OrdererFailoverStrategy determines the order in which the orderers of the channel are tried
+// when a transaction is sent
+type OrdererFailoverStrategy int
+
+const (
+	// RoundRobin starts each transaction at the orderer after the one that was tried first for the previous transaction
+	RoundRobin OrdererFailoverStrategy = iota
+	// LowestLatency tries the orderers in order of their average latency. Orderers that have not been used yet are tried first.
+	LowestLatency
+)
+
+// transientOrdererCodes are the error codes, grouped by source of error, for which
+// the transaction is sent to the next orderer
+var transientOrdererCodes = map[status.Group][]status.Code{
+	status.GRPCTransportStatus: {
+		status.Code(grpcCodes.Unavailable),
+		status.Code(grpcCodes.DeadlineExceeded),
+		status.Code(grpcCodes.ResourceExhausted),
+		status.Code(grpcCodes.Aborted),
+	},
+	status.OrdererClientStatus: {
+		status.ConnectionFailed,
+	},
+	status.OrdererServerStatus: {
+		status.Code(common.Status_SERVICE_UNAVAILABLE),
+	},
+}
+
+// ordererProvider is implemented by transactors that expose the orderers of the channel
+type ordererProvider interface {
+	Orderers() []fab.Orderer
+}
+
+// ordererFailover ranks the orderers of the channel according to the failover strategy
+type ordererFailover struct {
+	strategy  OrdererFailoverStrategy
+	lock      sync.Mutex
+	next      int
+	latencies map[string]time.Duration
+}
+
+func newOrdererFailover(strategy OrdererFailoverStrategy) (*ordererFailover, error) {
+	if strategy != RoundRobin && strategy != LowestLatency {
+		return nil, errors.Errorf("invalid orderer failover strategy: %d", strategy)
+	}
+	return &ordererFailover{
+		strategy:  strategy,
+		latencies: make(map[string]time.Duration),
+	}, nil
+}
+
+// transactor returns a transactor that sends transactions to the orderers in the ranked order.
+// The given transactor is returned if it does not expose its orderers.
+func (f *ordererFailover) transactor(reqCtx reqContext.Context, transactor fab.Transactor) fab.Transactor {
+	provider, ok := transactor.(ordererProvider)
+	if !ok {
+		return transactor
+	}
+	return &failoverTransactor{
+		Transactor: transactor,
+		reqCtx:     reqCtx,
+		orderers:   provider.Orderers(),
+		failover:   f,
+	}
+}
+
+// rank returns the orderers in the order in which they should be tried
+func (f *ordererFailover) rank(orderers []fab.Orderer) []fab.Orderer {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	ranked := make([]fab.Orderer, len(orderers))
+	if len(orderers) == 0 {
+		return ranked
+	}
+
+	switch f.strategy {
+	case LowestLatency:
+		copy(ranked, orderers)
+		sort.SliceStable(ranked, func(i, j int) bool {
+			return f.latencies[ranked[i].URL()] < f.latencies[ranked[j].URL()]
+		})
+	default:
+		start := f.next % len(orderers)
+		f.next = start + 1
+		copy(ranked, orderers[start:])
+		copy(ranked[len(orderers)-start:], orderers[:start])
+	}
+	return ranked
+}
+
+// record updates the average latency of the given orderer
+func (f *ordererFailover) record(url string, latency time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if avg, ok := f.latencies[url]; ok {
+		latency = (avg + latency) / 2
+	}
+	f.latencies[url] = latency
+}
+
+type failoverTransactor struct {
+	fab.Transactor
+	reqCtx   reqContext.Context
+	orderers []fab.Orderer
+	failover *ordererFailover
+}
+
+// SendTransaction sends the transaction to the ranked orderers one by one until an orderer accepts it.
+// The next orderer is only tried if the error is transient.
+func (t *failoverTransactor) SendTransaction(tx *fab.Transaction) (*fab.TransactionResponse, error) {
+	ctx, ok := contextImpl.RequestClientContext(t.reqCtx)
+	if !ok {
+		return nil, errors.New("failed get client context from reqContext for SendTransaction")
+	}
+
+	orderers := t.failover.rank(t.orderers)
+	if len(orderers) == 0 {
+		return nil, errors.New("orderers not set")
+	}
+
+	var lastErr error
+	for _, orderer := range orderers {
+		resp, err := t.send(ctx, tx, orderer)
+		if err == nil {
+			return resp, nil
+		}
+		if !isTransientOrdererError(err) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+func (t *failoverTransactor) send(ctx context.Client, tx *fab.Transaction, orderer fab.Orderer) (*fab.TransactionResponse, error) {
+	reqCtx, cancel := contextImpl.NewRequest(ctx, contextImpl.WithTimeoutType(fab.OrdererResponse), contextImpl.WithParent(t.reqCtx))
+	defer cancel()
+
+	start := time.Now()
+	resp, err := txn.Send(reqCtx, tx, []fab.Orderer{orderer})
+	if err != nil {
+		// Rank failed orderers behind the ones that respond within the timeout
+		t.failover.record(orderer.URL(), ctx.EndpointConfig().Timeout(fab.OrdererResponse))
+	} else {
+		t.failover.record(orderer.URL(), time.Since(start))
+	}
+	return resp, err
+}
+
+func isTransientOrdererError(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	if s.Group == status.ClientStatus && s.Code == status.MultipleErrors.ToInt32() {
+		for _, detail := range s.Details {
+			if e, ok := detail.(error); ok && isTransientOrdererError(e) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, code := range transientOrdererCodes[s.Group] {
+		if s.Code == code.ToInt32() {
+			return true
+		}
+	}
+	return false
+}
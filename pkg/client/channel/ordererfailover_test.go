@@ -0,0 +1,119 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	grpcCodes "google.golang.org/grpc/codes"
+
+	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+)
+
+// ordererTransactor exposes the orderers of the mock transactor
+type ordererTransactor struct {
+	*txnmocks.MockTransactor
+}
+
+func (t *ordererTransactor) Orderers() []fab.Orderer {
+	return t.MockTransactor.Orderers
+}
+
+func TestWithOrdererFailover(t *testing.T) {
+	chClient := setupChannelClient(nil, t)
+
+	err := WithOrdererFailover(OrdererFailoverStrategy(5))(chClient)
+	assert.Error(t, err, "expected error for invalid strategy")
+
+	require.NoError(t, WithOrdererFailover(LowestLatency)(chClient))
+	assert.Equal(t, LowestLatency, chClient.ordererFailover.strategy)
+}
+
+func TestOrdererFailoverRank(t *testing.T) {
+	orderer1 := fcmocks.NewMockOrderer("orderer1", nil)
+	orderer2 := fcmocks.NewMockOrderer("orderer2", nil)
+	orderer3 := fcmocks.NewMockOrderer("orderer3", nil)
+	orderers := []fab.Orderer{orderer1, orderer2, orderer3}
+
+	roundRobin, err := newOrdererFailover(RoundRobin)
+	require.NoError(t, err)
+	assert.Equal(t, []fab.Orderer{orderer1, orderer2, orderer3}, roundRobin.rank(orderers))
+	assert.Equal(t, []fab.Orderer{orderer2, orderer3, orderer1}, roundRobin.rank(orderers))
+	assert.Equal(t, []fab.Orderer{orderer3, orderer1, orderer2}, roundRobin.rank(orderers))
+	assert.Equal(t, []fab.Orderer{orderer1, orderer2, orderer3}, roundRobin.rank(orderers))
+
+	lowestLatency, err := newOrdererFailover(LowestLatency)
+	require.NoError(t, err)
+	lowestLatency.record("orderer1", 300*time.Millisecond)
+	lowestLatency.record("orderer2", 100*time.Millisecond)
+	assert.Equal(t, []fab.Orderer{orderer3, orderer2, orderer1}, lowestLatency.rank(orderers), "expected unused orderer first")
+
+	lowestLatency.record("orderer3", 500*time.Millisecond)
+	lowestLatency.record("orderer1", 50*time.Millisecond)
+	assert.Equal(t, []fab.Orderer{orderer2, orderer1, orderer3}, lowestLatency.rank(orderers), "expected average latency to be used")
+}
+
+func TestOrdererFailoverSendTransaction(t *testing.T) {
+	orderer1 := fcmocks.NewMockOrderer("orderer1", nil)
+	orderer2 := fcmocks.NewMockOrderer("orderer2", nil)
+
+	ctx := setupTestContext()
+	transactor := &ordererTransactor{MockTransactor: &txnmocks.MockTransactor{
+		Ctx:       ctx,
+		ChannelID: channelID,
+		Orderers:  []fab.Orderer{orderer1, orderer2},
+	}}
+
+	reqCtx, cancel := contextImpl.NewRequest(ctx, contextImpl.WithTimeout(10*time.Second))
+	defer cancel()
+
+	failover, err := newOrdererFailover(RoundRobin)
+	require.NoError(t, err)
+	failoverTransactor := failover.transactor(reqCtx, transactor)
+
+	tx := newTestTransaction(t, transactor)
+
+	orderer1.EnqueueSendBroadcastError(status.New(status.GRPCTransportStatus, int32(grpcCodes.Unavailable), "unavailable", nil))
+	resp, err := failoverTransactor.SendTransaction(tx)
+	require.NoError(t, err)
+	assert.Equal(t, "orderer2", resp.Orderer, "expected failover to second orderer")
+
+	// The second transaction starts at the second orderer
+	orderer2.EnqueueSendBroadcastError(status.New(status.OrdererServerStatus, 400, "bad request", nil))
+	_, err = failoverTransactor.SendTransaction(tx)
+	assert.Error(t, err, "expected no failover for non-transient error")
+}
+
+func TestIsTransientOrdererError(t *testing.T) {
+	assert.True(t, isTransientOrdererError(status.New(status.OrdererClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", nil)))
+	assert.True(t, isTransientOrdererError(status.New(status.GRPCTransportStatus, int32(grpcCodes.DeadlineExceeded), "timeout", nil)))
+	assert.False(t, isTransientOrdererError(status.New(status.GRPCTransportStatus, int32(grpcCodes.PermissionDenied), "denied", nil)))
+	assert.False(t, isTransientOrdererError(assert.AnError))
+}
+
+func newTestTransaction(t *testing.T, transactor fab.Transactor) *fab.Transaction {
+	txh, err := transactor.CreateTransactionHeader()
+	require.NoError(t, err)
+
+	proposal, err := txn.CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{ChaincodeID: "testCC", Fcn: "invoke"})
+	require.NoError(t, err)
+
+	responses, err := transactor.SendTransactionProposal(proposal, []fab.ProposalProcessor{fcmocks.NewMockPeer("Peer1", "http://peer1.com")})
+	require.NoError(t, err)
+
+	tx, err := transactor.CreateTransaction(fab.TransactionRequest{Proposal: proposal, ProposalResponses: responses})
+	require.NoError(t, err)
+	return tx
+}
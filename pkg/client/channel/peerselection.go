@@ -0,0 +1,123 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	reqContext "context"
+	"time"
+
+	selectopts "github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/sorter/latencysorter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/sorter/localitysorter"
+	coptions "github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// PeerSelectionStrategy determines which of the peers that satisfy the endorsement policy are preferred as endorsers
+type PeerSelectionStrategy int
+
+const (
+	// RandomPeerSelection chooses randomly among the peers that satisfy the endorsement policy (the default)
+	RandomPeerSelection PeerSelectionStrategy = iota
+	// LowestLatencyPeerSelection prefers peers whose average proposal latency is below the median latency
+	// of the peers that satisfy the endorsement policy
+	LowestLatencyPeerSelection
+)
+
+// WithPeerSelectionStrategy sets the strategy used to choose endorsers among the peers that satisfy the endorsement policy.
+// A target sorter specified for an individual request with WithTargetSorter takes precedence.
+func WithPeerSelectionStrategy(strategy PeerSelectionStrategy) ClientOption {
+	return func(client *Client) error {
+		switch strategy {
+		case RandomPeerSelection:
+			client.latencySorter = nil
+		case LowestLatencyPeerSelection:
+			client.latencySorter = latencysorter.New()
+		default:
+			return errors.Errorf("invalid peer selection strategy: %d", strategy)
+		}
+		return nil
+	}
+}
+
+// latencyTransactor records the latency of each endorser that a proposal is sent to
+type latencyTransactor struct {
+	fab.Transactor
+	sorter  *latencysorter.Sorter
+	penalty time.Duration
+}
+
+// SendTransactionProposal sends the proposal to the given targets, recording the latency of each target peer
+func (t *latencyTransactor) SendTransactionProposal(proposal *fab.TransactionProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+	timedTargets := make([]fab.ProposalProcessor, len(targets))
+	for i, target := range targets {
+		if peer, ok := target.(fab.Peer); ok {
+			timedTargets[i] = &timedPeer{Peer: peer, sorter: t.sorter, penalty: t.penalty}
+		} else {
+			timedTargets[i] = target
+		}
+	}
+	return t.Transactor.SendTransactionProposal(proposal, timedTargets)
+}
+
+type timedPeer struct {
+	fab.Peer
+	sorter  *latencysorter.Sorter
+	penalty time.Duration
+}
+
+// ProcessTransactionProposal sends the proposal to the peer and records the latency of the peer.
+// If the peer fails then the penalty (the peer response timeout) is recorded instead, so that failed
+// peers are ranked behind the ones that respond.
+func (p *timedPeer) ProcessTransactionProposal(ctx reqContext.Context, request fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	start := time.Now()
+	resp, err := p.Peer.ProcessTransactionProposal(ctx, request)
+	if err != nil {
+		p.sorter.Record(p.URL(), p.penalty)
+	} else {
+		p.sorter.Record(p.URL(), time.Since(start))
+	}
+	return resp, err
+}
+
+// WithGeographicPeerSelection prefers endorsers in the given locality. The locality of a peer is set with the
+// "locality" property of the peer in the config. Peers in other localities are selected if the peers in the
+// given locality do not satisfy the endorsement policy. Within each locality, peers are chosen according to
+// the peer selection strategy (see WithPeerSelectionStrategy).
+// A target sorter specified for an individual request with WithTargetSorter takes precedence.
+func WithGeographicPeerSelection(locality string) ClientOption {
+	return func(client *Client) error {
+		if locality == "" {
+			return errors.New("locality is required")
+		}
+		client.locality = locality
+		return nil
+	}
+}
+
+// peerSorter returns the sorter used to choose endorsers for a request, or nil if the selection service should choose randomly
+func (cc *Client) peerSorter(o requestOptions) selectopts.PeerSorter {
+	if o.TargetSorter != nil {
+		return func(peers []fab.Peer) []fab.Peer {
+			return o.TargetSorter.Sort(peers)
+		}
+	}
+
+	if cc.locality != "" {
+		var opts []coptions.Opt
+		if cc.latencySorter != nil {
+			opts = append(opts, localitysorter.WithBalancer(cc.latencySorter.Sort))
+		}
+		return localitysorter.New(cc.context.EndpointConfig(), cc.locality, opts...)
+	}
+
+	if cc.latencySorter != nil {
+		return cc.latencySorter.Sort
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	reqContext "context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/sorter/latencysorter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+)
+
+func TestWithPeerSelectionStrategy(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = []byte("test1")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+
+	err := WithPeerSelectionStrategy(PeerSelectionStrategy(5))(chClient)
+	assert.Error(t, err, "expected error for invalid strategy")
+
+	require.NoError(t, WithPeerSelectionStrategy(LowestLatencyPeerSelection)(chClient))
+	require.NotNil(t, chClient.latencySorter)
+
+	_, err = chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}})
+	require.NoError(t, err)
+
+	_, ok := chClient.latencySorter.Latency(testPeer1.URL())
+	assert.True(t, ok, "expected latency of endorser to be recorded")
+
+	require.NoError(t, WithPeerSelectionStrategy(RandomPeerSelection)(chClient))
+	assert.Nil(t, chClient.latencySorter)
+}
+
+func TestTimedPeer(t *testing.T) {
+	sorter := latencysorter.New()
+	penalty := 10 * time.Second
+
+	okPeer := &timedPeer{Peer: fcmocks.NewMockPeer("Peer1", "http://peer1.com"), sorter: sorter, penalty: penalty}
+	_, err := okPeer.ProcessTransactionProposal(reqContext.Background(), fab.ProcessProposalRequest{})
+	require.NoError(t, err)
+	latency, ok := sorter.Latency(okPeer.URL())
+	require.True(t, ok, "expected latency of endorser to be recorded")
+	assert.True(t, latency < penalty, "expected the measured latency to be recorded")
+
+	failingPeer := fcmocks.NewMockPeer("Peer2", "http://peer2.com")
+	failingPeer.Error = errors.New("endorsement failed")
+	errPeer := &timedPeer{Peer: failingPeer, sorter: sorter, penalty: penalty}
+	_, err = errPeer.ProcessTransactionProposal(reqContext.Background(), fab.ProcessProposalRequest{})
+	require.Error(t, err)
+	latency, ok = sorter.Latency(errPeer.URL())
+	require.True(t, ok, "expected penalty of failed endorser to be recorded")
+	assert.Equal(t, penalty, latency)
+}
+
+func TestWithGeographicPeerSelection(t *testing.T) {
+	testPeer1 := fcmocks.NewMockPeer("Peer1", "http://peer1.com")
+	testPeer1.Payload = []byte("test1")
+	chClient := setupChannelClient([]fab.Peer{testPeer1}, t)
+
+	assert.Nil(t, chClient.peerSorter(requestOptions{}), "expected no peer sorter by default")
+
+	err := WithGeographicPeerSelection("")(chClient)
+	assert.Error(t, err, "expected error for empty locality")
+
+	require.NoError(t, WithGeographicPeerSelection("us-east-1")(chClient))
+	assert.Equal(t, "us-east-1", chClient.locality)
+	assert.NotNil(t, chClient.peerSorter(requestOptions{}))
+
+	_, err = chClient.Query(Request{ChaincodeID: "testCC", Fcn: "invoke", Args: [][]byte{[]byte("query"), []byte("b")}})
+	require.NoError(t, err)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+// privateDataQueryFcn is the chaincode function that is invoked by QueryPrivateData by default
+const privateDataQueryFcn = "getPrivateData"
+
+// privateDataQueryArgs returns the collection and the key, which are the default arguments of privateDataQueryFcn
+func privateDataQueryArgs(collection, key string) [][]byte {
+	return [][]byte{[]byte(collection), []byte(key)}
+}
+
+// ErrCollectionNotFound is returned by QueryPrivateData if the chaincode does not define the collection
+var ErrCollectionNotFound = errors.New("collection not found")
+
+// QueryPrivateData queries the value of a key in a private data collection of a chaincode. By default, the
+// chaincode's getPrivateData function is invoked with the collection and the key as arguments; the function is
+// expected to return the value read with the shim's GetPrivateData. Chaincodes with another function can be
+// queried with WithPrivateDataQuery. The query is only sent to peers of the member
+// organizations of the collection, according to the collection config returned by QueryCollectionConfig.
+//  Parameters:
+//  channelID is the ID of the channel of the client
+//  chaincodeID is the mandatory chaincode ID
+//  collection is the mandatory name of the collection
+//  key is the mandatory key
+//  options holds optional private data query options
+//
+//  Returns:
+//  the private data value, or ErrCollectionNotFound if the chaincode does not define the collection
+func (cc *Client) QueryPrivateData(channelID, chaincodeID, collection, key string, options ...PrivateDataOption) ([]byte, error) {
+	if channelID != cc.context.ChannelID() {
+		return nil, errors.Errorf("channel client is bound to channel [%s], not [%s]", cc.context.ChannelID(), channelID)
+	}
+	if chaincodeID == "" || collection == "" || key == "" {
+		return nil, errors.New("chaincode ID, collection and key are required")
+	}
+	opts := privateDataOptions{fcn: privateDataQueryFcn, args: privateDataQueryArgs}
+	for _, option := range options {
+		if err := option(&opts); err != nil {
+			return nil, errors.WithMessage(err, "Failed to read private data opts")
+		}
+	}
+
+	collConfig, err := cc.QueryCollectionConfig(chaincodeID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := collectionMembers(collConfig, collection)
+	if err != nil {
+		return nil, err
+	}
+
+	targetFilter := &collectionMemberFilter{
+		members:        members,
+		endpointFilter: filter.NewEndpointFilter(cc.context, filter.ChaincodeQuery),
+	}
+	request := Request{ChaincodeID: chaincodeID, Fcn: opts.fcn, Args: opts.args(collection, key)}
+	response, err := cc.Query(request, WithTargetFilter(targetFilter))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to query private data")
+	}
+	return response.Payload, nil
+}
+
+// collectionMembers returns the MSP IDs of the member organizations of the given collection
+func collectionMembers(collConfig *common.CollectionConfigPackage, collection string) (map[string]bool, error) {
+	for _, config := range collConfig.Config {
+		staticConfig := config.GetStaticCollectionConfig()
+		if staticConfig == nil || staticConfig.Name != collection {
+			continue
+		}
+
+		policy := staticConfig.GetMemberOrgsPolicy().GetSignaturePolicy()
+		if policy == nil {
+			return nil, errors.Errorf("collection [%s] has no member orgs policy", collection)
+		}
+		members := make(map[string]bool)
+		for _, principal := range policy.Identities {
+			mspID, err := principalMSPID(principal)
+			if err != nil {
+				return nil, errors.WithMessage(err, "invalid member orgs policy")
+			}
+			members[mspID] = true
+		}
+		return members, nil
+	}
+	return nil, ErrCollectionNotFound
+}
+
+// principalMSPID returns the MSP ID of a role or organizational unit principal
+func principalMSPID(principal *mb.MSPPrincipal) (string, error) {
+	switch principal.PrincipalClassification {
+	case mb.MSPPrincipal_ROLE:
+		mspRole := &mb.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, mspRole); err != nil {
+			return "", errors.Wrap(err, "unmarshal of principal failed")
+		}
+		return mspRole.MspIdentifier, nil
+	case mb.MSPPrincipal_ORGANIZATION_UNIT:
+		unit := &mb.OrganizationUnit{}
+		if err := proto.Unmarshal(principal.Principal, unit); err != nil {
+			return "", errors.Wrap(err, "unmarshal of principal failed")
+		}
+		return unit.MspIdentifier, nil
+	default:
+		return "", errors.Errorf("unsupported principal classification [%s]", principal.PrincipalClassification)
+	}
+}
+
+// collectionMemberFilter accepts the chaincode query peers of the member organizations of a collection
+type collectionMemberFilter struct {
+	members        map[string]bool
+	endpointFilter fab.TargetFilter
+}
+
+// Accept returns true if the peer belongs to a member organization of the collection
+func (f *collectionMemberFilter) Accept(peer fab.Peer) bool {
+	return f.members[peer.MSPID()] && f.endpointFilter.Accept(peer)
+}
@@ -0,0 +1,140 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	reqContext "context"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protoutil "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// payloadSequenceMockPeer responds to each proposal with the next payload, and records the requests of the proposals
+type payloadSequenceMockPeer struct {
+	*fcmocks.MockPeer
+	payloads [][]byte
+	requests []Request
+}
+
+func (p *payloadSequenceMockPeer) ProcessTransactionProposal(ctx reqContext.Context, tp fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	proposal := &pb.Proposal{}
+	if err := proto.Unmarshal(tp.SignedProposal.ProposalBytes, proposal); err != nil {
+		return nil, err
+	}
+	hdr, err := protoutil.GetHeader(proposal.Header)
+	if err != nil {
+		return nil, err
+	}
+	request, err := proposalRequest(hdr, proposal.Payload)
+	if err != nil {
+		return nil, err
+	}
+	p.requests = append(p.requests, request)
+
+	p.Payload = p.payloads[p.ProcessProposalCalls]
+	return p.MockPeer.ProcessTransactionProposal(ctx, tp)
+}
+
+func TestQueryPrivateData(t *testing.T) {
+	principal, err := proto.Marshal(&mb.MSPRole{Role: mb.MSPRole_MEMBER, MspIdentifier: "Org1MSP"})
+	require.NoError(t, err)
+	collConfig := &common.CollectionConfigPackage{
+		Config: []*common.CollectionConfig{
+			{
+				Payload: &common.CollectionConfig_StaticCollectionConfig{
+					StaticCollectionConfig: &common.StaticCollectionConfig{
+						Name: "coll1",
+						MemberOrgsPolicy: &common.CollectionPolicyConfig{
+							Payload: &common.CollectionPolicyConfig_SignaturePolicy{
+								SignaturePolicy: &common.SignaturePolicyEnvelope{
+									Identities: []*mb.MSPPrincipal{{PrincipalClassification: mb.MSPPrincipal_ROLE, Principal: principal}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	payload, err := proto.Marshal(collConfig)
+	require.NoError(t, err)
+
+	memberPeer := &payloadSequenceMockPeer{MockPeer: fcmocks.NewMockPeer("Peer1", "http://peer1.com"), payloads: [][]byte{payload, []byte("value")}}
+	otherPeer := &payloadSequenceMockPeer{MockPeer: fcmocks.NewMockPeer("Peer2", "http://peer2.com"), payloads: [][]byte{payload}}
+	otherPeer.SetMSPID("Org2MSP")
+	chClient := setupChannelClient([]fab.Peer{memberPeer, otherPeer}, t)
+
+	_, err = chClient.QueryPrivateData("otherchannel", "testCC", "coll1", "key")
+	assert.Error(t, err)
+	_, err = chClient.QueryPrivateData(channelID, "testCC", "coll1", "")
+	assert.Error(t, err)
+
+	value, err := chClient.QueryPrivateData(channelID, "testCC", "coll1", "key")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+	assert.Equal(t, 2, memberPeer.ProcessProposalCalls)
+	assert.Equal(t, 1, otherPeer.ProcessProposalCalls, "expected private data query to target collection members only")
+	assert.Equal(t, Request{ChaincodeID: "testCC", Fcn: "getPrivateData", Args: [][]byte{[]byte("coll1"), []byte("key")}}, memberPeer.requests[1])
+
+	_, err = chClient.QueryPrivateData(channelID, "testCC", "coll1", "key", WithPrivateDataQuery("", nil))
+	assert.EqualError(t, err, "Failed to read private data opts: private data query function is required")
+
+	memberPeer.ProcessProposalCalls, otherPeer.ProcessProposalCalls = 0, 0
+	args := func(collection, key string) [][]byte { return [][]byte{[]byte(key), []byte(collection)} }
+	value, err = chClient.QueryPrivateData(channelID, "testCC", "coll1", "key", WithPrivateDataQuery("readPrivate", args))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+	assert.Equal(t, Request{ChaincodeID: "testCC", Fcn: "readPrivate", Args: [][]byte{[]byte("key"), []byte("coll1")}}, memberPeer.requests[3])
+}
+
+func TestCollectionMembers(t *testing.T) {
+	role, err := proto.Marshal(&mb.MSPRole{Role: mb.MSPRole_MEMBER, MspIdentifier: "Org1MSP"})
+	require.NoError(t, err)
+	unit, err := proto.Marshal(&mb.OrganizationUnit{MspIdentifier: "Org2MSP", OrganizationalUnitIdentifier: "ou"})
+	require.NoError(t, err)
+
+	newCollConfig := func(identities ...*mb.MSPPrincipal) *common.CollectionConfigPackage {
+		staticConfig := &common.StaticCollectionConfig{Name: "coll1"}
+		if identities != nil {
+			staticConfig.MemberOrgsPolicy = &common.CollectionPolicyConfig{
+				Payload: &common.CollectionPolicyConfig_SignaturePolicy{
+					SignaturePolicy: &common.SignaturePolicyEnvelope{Identities: identities},
+				},
+			}
+		}
+		return &common.CollectionConfigPackage{
+			Config: []*common.CollectionConfig{
+				{Payload: &common.CollectionConfig_StaticCollectionConfig{StaticCollectionConfig: staticConfig}},
+			},
+		}
+	}
+
+	members, err := collectionMembers(newCollConfig(
+		&mb.MSPPrincipal{PrincipalClassification: mb.MSPPrincipal_ROLE, Principal: role},
+		&mb.MSPPrincipal{PrincipalClassification: mb.MSPPrincipal_ORGANIZATION_UNIT, Principal: unit},
+	), "coll1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"Org1MSP": true, "Org2MSP": true}, members)
+
+	_, err = collectionMembers(newCollConfig(), "coll2")
+	assert.Equal(t, ErrCollectionNotFound, err)
+
+	_, err = collectionMembers(newCollConfig(), "coll1")
+	assert.Error(t, err)
+
+	_, err = collectionMembers(newCollConfig(&mb.MSPPrincipal{PrincipalClassification: mb.MSPPrincipal_IDENTITY}), "coll1")
+	assert.Error(t, err)
+}
@@ -67,14 +67,16 @@ func (r *mockDiscoverResponse) Target() string {
 }
 
 type response struct {
-	peers []*discclient.Peer
-	err   error
+	peers  []*discclient.Peer
+	config *discovery.ConfigResult
+	err    error
 }
 
 func (r *response) ForChannel(string) discclient.ChannelResponse {
 	return &channelResponse{
-		peers: r.peers,
-		err:   r.err,
+		peers:  r.peers,
+		config: r.config,
+		err:    r.err,
 	}
 }
 
@@ -86,13 +88,20 @@ func (r *response) ForLocal() discclient.LocalResponse {
 }
 
 type channelResponse struct {
-	peers discclient.Endorsers
-	err   error
+	peers  discclient.Endorsers
+	config *discovery.ConfigResult
+	err    error
 }
 
 // Config returns a response for a config query, or error if something went wrong
 func (cr *channelResponse) Config() (*discovery.ConfigResult, error) {
-	panic("not implemented")
+	if cr.err != nil {
+		return nil, cr.err
+	}
+	if cr.config == nil {
+		return nil, discclient.ErrNotFound
+	}
+	return cr.config, nil
 }
 
 // Peers returns a response for a peer membership query, or error if something went wrong
@@ -122,6 +131,7 @@ func (cr *localResponse) Peers() ([]*discclient.Peer, error) {
 type MockDiscoverEndpointResponse struct {
 	Target        string
 	PeerEndpoints []*discmocks.MockDiscoveryPeerEndpoint
+	Config        *discovery.ConfigResult
 	Error         error
 }
 
@@ -138,8 +148,9 @@ func (b *MockDiscoverEndpointResponse) Build() fabdiscovery.Response {
 	}
 	return &mockDiscoverResponse{
 		Response: &response{
-			peers: peers,
-			err:   b.Error,
+			peers:  peers,
+			config: b.Config,
+			err:    b.Error,
 		},
 		target: b.Target,
 	}
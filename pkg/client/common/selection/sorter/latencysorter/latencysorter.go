@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package latencysorter
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	coptions "github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// Sorter sorts peers according to their latency. The latency of a peer is an exponentially weighted
+// moving average of the latencies recorded for the peer (see Record). Peers with a latency below the
+// latency percentile of the group, and peers without a recorded latency, are preferred and sorted using
+// the provided balancer. The remaining peers are sorted according to latency.
+type Sorter struct {
+	*params
+	lock      sync.RWMutex
+	latencies map[string]float64
+}
+
+// New returns a peer sorter that uses the recorded latencies of the peers to sort the peers.
+func New(opts ...coptions.Opt) *Sorter {
+	params := defaultParams()
+	coptions.Apply(params, opts)
+
+	return &Sorter{
+		params:    params,
+		latencies: make(map[string]float64),
+	}
+}
+
+// Record updates the average latency of the peer with the given URL
+func (s *Sorter) Record(url string, latency time.Duration) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	avg, ok := s.latencies[url]
+	if !ok {
+		s.latencies[url] = float64(latency)
+		return
+	}
+	s.latencies[url] = s.smoothingFactor*float64(latency) + (1-s.smoothingFactor)*avg
+}
+
+// Latency returns the average latency of the peer with the given URL, or false if no latency was recorded
+func (s *Sorter) Latency(url string) (time.Duration, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	avg, ok := s.latencies[url]
+	return time.Duration(avg), ok
+}
+
+// Sort sorts the given peers according to latency and latency percentile.
+func (s *Sorter) Sort(peers []fab.Peer) []fab.Peer {
+	if len(peers) <= 1 {
+		return peers
+	}
+
+	s.lock.RLock()
+	latencies := make(map[string]float64)
+	var known []float64
+	for _, p := range peers {
+		if latency, ok := s.latencies[p.URL()]; ok {
+			latencies[p.URL()] = latency
+			known = append(known, latency)
+		}
+	}
+	s.lock.RUnlock()
+
+	if len(known) == 0 {
+		logger.Debugf("No latencies recorded for peers - returning balanced peers")
+		return s.balancer(peers)
+	}
+
+	threshold := percentile(known, s.latencyPercentile)
+	logger.Debugf("Latency threshold of peers: %s", time.Duration(threshold))
+
+	var preferredPeers, otherPeers []fab.Peer
+	for _, p := range peers {
+		latency, ok := latencies[p.URL()]
+		if !ok || latency <= threshold {
+			preferredPeers = append(preferredPeers, p)
+		} else {
+			otherPeers = append(otherPeers, p)
+		}
+	}
+
+	sort.SliceStable(otherPeers, func(i, j int) bool {
+		return latencies[otherPeers[i].URL()] < latencies[otherPeers[j].URL()]
+	})
+
+	return append(s.balancer(preferredPeers), otherPeers...)
+}
+
+// percentile returns the value at the given percentile of the given values (nearest-rank method)
+func percentile(values []float64, p float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
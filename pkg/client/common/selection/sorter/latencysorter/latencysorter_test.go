@@ -0,0 +1,89 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package latencysorter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/balancer"
+	fab "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	peer1URL = "peer1.org1.com:9999"
+	peer2URL = "peer2.org1.com:9999"
+	peer3URL = "peer3.org1.com:9999"
+	peer4URL = "peer4.org1.com:9999"
+)
+
+var (
+	peer1 = mocks.NewMockPeer("p1", peer1URL)
+	peer2 = mocks.NewMockPeer("p2", peer2URL)
+	peer3 = mocks.NewMockPeer("p3", peer3URL)
+	peer4 = mocks.NewMockPeer("p4", peer4URL)
+
+	allPeers = []fab.Peer{peer1, peer2, peer3, peer4}
+)
+
+func TestRecord(t *testing.T) {
+	sorter := New(WithSmoothingFactor(0.5))
+
+	_, ok := sorter.Latency(peer1URL)
+	assert.False(t, ok)
+
+	sorter.Record(peer1URL, 100*time.Millisecond)
+	latency, ok := sorter.Latency(peer1URL)
+	assert.True(t, ok)
+	assert.Equal(t, 100*time.Millisecond, latency)
+
+	sorter.Record(peer1URL, 200*time.Millisecond)
+	latency, _ = sorter.Latency(peer1URL)
+	assert.Equal(t, 150*time.Millisecond, latency)
+}
+
+func TestSortNoLatencies(t *testing.T) {
+	sorter := New(WithBalancer(balancer.RoundRobin()))
+
+	peers := sorter.Sort(allPeers)
+	assert.Len(t, peers, len(allPeers))
+}
+
+func TestSortByLatency(t *testing.T) {
+	sorter := New(WithBalancer(balancer.RoundRobin()), WithLatencyPercentile(0.25))
+
+	sorter.Record(peer1URL, 400*time.Millisecond)
+	sorter.Record(peer2URL, 100*time.Millisecond)
+	sorter.Record(peer3URL, 300*time.Millisecond)
+	sorter.Record(peer4URL, 200*time.Millisecond)
+
+	assert.Equal(t, []fab.Peer{peer2, peer4, peer3, peer1}, sorter.Sort(allPeers))
+
+	// A peer without a recorded latency is preferred so that its latency gets measured
+	peer5 := mocks.NewMockPeer("p5", "peer5.org1.com:9999")
+	peers := sorter.Sort(append(allPeers, peer5))
+	assert.Len(t, peers, 5)
+	assert.Contains(t, peers[:2], peer2)
+	assert.Contains(t, peers[:2], peer5)
+	assert.Equal(t, []fab.Peer{peer4, peer3, peer1}, peers[2:])
+}
+
+func TestSortPercentile(t *testing.T) {
+	sorter := New(WithLatencyPercentile(0.5))
+
+	sorter.Record(peer1URL, 400*time.Millisecond)
+	sorter.Record(peer2URL, 100*time.Millisecond)
+	sorter.Record(peer3URL, 300*time.Millisecond)
+	sorter.Record(peer4URL, 200*time.Millisecond)
+
+	peers := sorter.Sort(allPeers)
+	assert.Contains(t, peers[:2], peer2)
+	assert.Contains(t, peers[:2], peer4)
+	assert.Equal(t, []fab.Peer{peer3, peer1}, peers[2:])
+}
@@ -0,0 +1,90 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package latencysorter
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/balancer"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+)
+
+const (
+	defaultSmoothingFactor   = 0.3
+	defaultLatencyPercentile = 0.5
+)
+
+type params struct {
+	smoothingFactor   float64
+	latencyPercentile float64
+	balancer          balancer.Balancer
+}
+
+func defaultParams() *params {
+	return &params{
+		smoothingFactor:   defaultSmoothingFactor,
+		latencyPercentile: defaultLatencyPercentile,
+		balancer:          balancer.Random(),
+	}
+}
+
+// WithSmoothingFactor sets the weight (between 0 and 1) of the most recent latency in the
+// exponentially weighted moving average of a peer's latency. A higher value discounts older latencies faster.
+func WithSmoothingFactor(value float64) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(smoothingFactorSetter); ok {
+			setter.SetSmoothingFactor(value)
+		}
+	}
+}
+
+// WithLatencyPercentile sets the latency percentile (between 0 and 1) of a group of peers below which
+// a peer is preferred. These peers are sorted using the given Balancer. Peers with a higher latency
+// are demoted to a lower priority list of peers which is sorted according to latency.
+//
+// If set to 1 then all peers with a known latency are load-balanced using the provided balancer.
+func WithLatencyPercentile(value float64) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(latencyPercentileSetter); ok {
+			setter.SetLatencyPercentile(value)
+		}
+	}
+}
+
+// WithBalancer sets the balancing strategy to load balance (sort) the preferred peers.
+func WithBalancer(value balancer.Balancer) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(balancerSetter); ok {
+			setter.SetBalancer(value)
+		}
+	}
+}
+
+type smoothingFactorSetter interface {
+	SetSmoothingFactor(value float64)
+}
+
+func (p *params) SetSmoothingFactor(value float64) {
+	logger.Debugf("SmoothingFactor: %f", value)
+	p.smoothingFactor = value
+}
+
+type latencyPercentileSetter interface {
+	SetLatencyPercentile(value float64)
+}
+
+func (p *params) SetLatencyPercentile(value float64) {
+	logger.Debugf("LatencyPercentile: %f", value)
+	p.latencyPercentile = value
+}
+
+type balancerSetter interface {
+	SetBalancer(value balancer.Balancer)
+}
+
+func (p *params) SetBalancer(value balancer.Balancer) {
+	logger.Debugf("Balancer: %#v", value)
+	p.balancer = value
+}
@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localitysorter
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	coptions "github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+// LocalityProperty is the name of the peer config property that holds the locality (region) of the peer
+const LocalityProperty = "locality"
+
+type peerConfig interface {
+	PeerConfig(nameOrURL string) (*fab.PeerConfig, bool)
+}
+
+// New returns a peer sorter that prefers the peers in the given locality. The locality of a peer is
+// read from the "locality" property of the peer's config. The peers are first sorted using the
+// provided balancer, and the peers in the given locality are then moved ahead of the other peers.
+// Since the other peers are still included, endorsers in other localities are chosen if the peers
+// in the given locality do not satisfy the endorsement policy.
+func New(config peerConfig, locality string, opts ...coptions.Opt) options.PeerSorter {
+	params := defaultParams()
+	coptions.Apply(params, opts)
+
+	sorter := &sorter{
+		params:   params,
+		config:   config,
+		locality: locality,
+	}
+
+	return func(peers []fab.Peer) []fab.Peer {
+		return sorter.Sort(peers)
+	}
+}
+
+type sorter struct {
+	*params
+	config   peerConfig
+	locality string
+}
+
+// Sort sorts the given peers so that the peers in the locality of the sorter come first.
+func (s *sorter) Sort(peers []fab.Peer) []fab.Peer {
+	if len(peers) <= 1 {
+		return peers
+	}
+
+	var localPeers, otherPeers []fab.Peer
+	for _, p := range s.balancer(peers) {
+		if s.isLocal(p) {
+			localPeers = append(localPeers, p)
+		} else {
+			otherPeers = append(otherPeers, p)
+		}
+	}
+
+	logger.Debugf("Found %d peers in locality [%s] out of %d peers", len(localPeers), s.locality, len(peers))
+	return append(localPeers, otherPeers...)
+}
+
+func (s *sorter) isLocal(peer fab.Peer) bool {
+	peerConfig, ok := s.config.PeerConfig(peer.URL())
+	if !ok {
+		return false
+	}
+	locality, ok := peerConfig.Properties[LocalityProperty].(string)
+	return ok && locality == s.locality
+}
@@ -0,0 +1,67 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package localitysorter
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/selection/balancer"
+	fab "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	peer1URL = "peer1.org1.com:9999"
+	peer2URL = "peer2.org1.com:9999"
+	peer3URL = "peer3.org1.com:9999"
+	peer4URL = "peer4.org1.com:9999"
+)
+
+var (
+	peer1 = mocks.NewMockPeer("p1", peer1URL)
+	peer2 = mocks.NewMockPeer("p2", peer2URL)
+	peer3 = mocks.NewMockPeer("p3", peer3URL)
+	peer4 = mocks.NewMockPeer("p4", peer4URL)
+
+	allPeers = []fab.Peer{peer1, peer2, peer3, peer4}
+)
+
+type mockPeerConfig map[string]*fab.PeerConfig
+
+func (c mockPeerConfig) PeerConfig(nameOrURL string) (*fab.PeerConfig, bool) {
+	peerConfig, ok := c[nameOrURL]
+	return peerConfig, ok
+}
+
+func newMockPeerConfig() mockPeerConfig {
+	return mockPeerConfig{
+		peer1URL: {URL: peer1URL, Properties: map[string]interface{}{LocalityProperty: "eu-west-1"}},
+		peer2URL: {URL: peer2URL, Properties: map[string]interface{}{LocalityProperty: "us-east-1"}},
+		peer3URL: {URL: peer3URL},
+		peer4URL: {URL: peer4URL, Properties: map[string]interface{}{LocalityProperty: "us-east-1"}},
+	}
+}
+
+func TestSortLocalPeersFirst(t *testing.T) {
+	sort := New(newMockPeerConfig(), "us-east-1", WithBalancer(balancer.RoundRobin()))
+
+	for i := 0; i < 4; i++ {
+		peers := sort(allPeers)
+		assert.Len(t, peers, len(allPeers))
+		assert.Contains(t, peers[:2], peer2)
+		assert.Contains(t, peers[:2], peer4)
+	}
+}
+
+func TestSortNoLocalPeers(t *testing.T) {
+	sort := New(newMockPeerConfig(), "ap-south-1")
+
+	peers := sort(allPeers)
+	assert.Len(t, peers, len(allPeers), "expected all peers when no peers are in the locality")
+	assert.ElementsMatch(t, allPeers, peers)
+}
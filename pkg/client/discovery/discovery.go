@@ -0,0 +1,391 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package discovery enables queries of the peer topology of a channel using Fabric's discovery service.
+// The discovery client supports the following queries: GetPeers, GetEndorsers and GetConfig.
+// Query results are cached for a configurable time (see WithCacheTTL).
+//
+//  Basic Flow:
+//  1) Prepare client context
+//  2) Create discovery client
+//  3) Query peers, endorsers or config of a channel
+package discovery
+
+import (
+	reqContext "context"
+	"fmt"
+	"sort"
+	"time"
+
+	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
+	discpb "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/random"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazycache"
+	"github.com/hyperledger/fabric-sdk-go/pkg/util/concurrent/lazyref"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+// DiscoveryClient is the client to the discovery service
+type DiscoveryClient interface {
+	Send(ctx reqContext.Context, req *discclient.Request, targets ...fab.PeerConfig) ([]fabdiscovery.Response, error)
+}
+
+// clientProvider is overridden by unit tests
+var clientProvider = func(ctx context.Client) (DiscoveryClient, error) {
+	return fabdiscovery.New(ctx)
+}
+
+type queryType string
+
+const (
+	peersQuery     queryType = "peers"
+	endorsersQuery queryType = "endorsers"
+	configQuery    queryType = "config"
+)
+
+// Client enables queries of Fabric's discovery service.
+type Client struct {
+	ctx        context.Client
+	discClient DiscoveryClient
+	cacheTTL   time.Duration
+	cache      *lazycache.Cache
+}
+
+// DiscoveredPeer contains the membership and state information of a peer returned by the discovery service
+type DiscoveredPeer struct {
+	MSPID        string
+	Endpoint     string
+	LedgerHeight uint64
+	Chaincodes   []string
+}
+
+// EndorserGroup contains the endorsers of one organization in a set of endorsers that satisfies
+// the endorsement policy of a chaincode
+type EndorserGroup struct {
+	MSPID string
+	Peers []*DiscoveredPeer
+}
+
+// DiscoveryConfig contains the channel configuration returned by the discovery service
+type DiscoveryConfig struct {
+	// MSPs holds the MSP configs of the channel by MSP ID
+	MSPs map[string]*mb.FabricMSPConfig
+
+	// Orderers holds the orderer endpoints (host:port) of the channel by MSP ID
+	Orderers map[string][]string
+}
+
+// New returns a discovery client instance.
+func New(ctxProvider context.ClientProvider, opts ...ClientOption) (*Client, error) {
+	ctx, err := ctxProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create client context")
+	}
+
+	c := &Client{ctx: ctx}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.cacheTTL == 0 {
+		c.cacheTTL = ctx.EndpointConfig().Timeout(fab.DiscoveryServiceRefresh)
+	}
+
+	c.discClient, err = clientProvider(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create discovery client")
+	}
+
+	c.cache = lazycache.New(
+		"Discovery_Client_Cache",
+		func(key lazycache.Key) (interface{}, error) {
+			return c.query(key.(*cacheKey))
+		},
+		lazyref.WithAbsoluteExpiration(c.cacheTTL),
+	)
+
+	return c, nil
+}
+
+// Close releases the cached query results
+func (c *Client) Close() {
+	c.cache.Close()
+}
+
+// GetPeers returns the peers that are joined to the given channel
+//  Parameters:
+//  channel is the name of the channel
+//
+//  Returns:
+//  the peers of the channel
+func (c *Client) GetPeers(channel string) ([]*DiscoveredPeer, error) {
+	if channel == "" {
+		return nil, errors.New("channel is required")
+	}
+	value, err := c.cache.Get(newCacheKey(peersQuery, channel, ""))
+	if err != nil {
+		return nil, err
+	}
+	return value.([]*DiscoveredPeer), nil
+}
+
+// GetEndorsers returns a set of endorsers that satisfies the endorsement policy of the given chaincode,
+// grouped by organization
+//  Parameters:
+//  channel is the name of the channel
+//  chaincode is the name of the chaincode
+//
+//  Returns:
+//  the endorser groups, sorted by MSP ID
+func (c *Client) GetEndorsers(channel, chaincode string) ([]*EndorserGroup, error) {
+	if channel == "" {
+		return nil, errors.New("channel is required")
+	}
+	if chaincode == "" {
+		return nil, errors.New("chaincode is required")
+	}
+	value, err := c.cache.Get(newCacheKey(endorsersQuery, channel, chaincode))
+	if err != nil {
+		return nil, err
+	}
+	return value.([]*EndorserGroup), nil
+}
+
+// GetConfig returns the MSP and orderer configuration of the given channel
+//  Parameters:
+//  channel is the name of the channel
+//
+//  Returns:
+//  the channel configuration
+func (c *Client) GetConfig(channel string) (*DiscoveryConfig, error) {
+	if channel == "" {
+		return nil, errors.New("channel is required")
+	}
+	value, err := c.cache.Get(newCacheKey(configQuery, channel, ""))
+	if err != nil {
+		return nil, err
+	}
+	return value.(*DiscoveryConfig), nil
+}
+
+func (c *Client) query(key *cacheKey) (interface{}, error) {
+	switch key.queryType {
+	case peersQuery:
+		return c.queryPeers(key.channelID)
+	case endorsersQuery:
+		return c.queryEndorsers(key.channelID, key.chaincode)
+	case configQuery:
+		return c.queryConfig(key.channelID)
+	default:
+		return nil, errors.Errorf("unsupported query type [%s]", key.queryType)
+	}
+}
+
+func (c *Client) queryPeers(channelID string) ([]*DiscoveredPeer, error) {
+	req := discclient.NewRequest().OfChannel(channelID).AddPeersQuery()
+
+	var peers []*DiscoveredPeer
+	err := c.send(channelID, req, func(response discclient.ChannelResponse) error {
+		endpoints, err := response.Peers()
+		if err != nil {
+			return err
+		}
+		peers = asDiscoveredPeers(endpoints)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to query peers")
+	}
+	return peers, nil
+}
+
+func (c *Client) queryEndorsers(channelID, chaincode string) ([]*EndorserGroup, error) {
+	req, err := discclient.NewRequest().OfChannel(channelID).AddEndorsersQuery(
+		&discpb.ChaincodeInterest{Chaincodes: []*discpb.ChaincodeCall{{Name: chaincode}}},
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create endorsers query")
+	}
+
+	var groups []*EndorserGroup
+	err = c.send(channelID, req, func(response discclient.ChannelResponse) error {
+		endorsers, err := response.Endorsers(discclient.InvocationChain{{Name: chaincode}}, discclient.NoFilter)
+		if err != nil {
+			return err
+		}
+		groups = asEndorserGroups(endorsers)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to query endorsers")
+	}
+	return groups, nil
+}
+
+func (c *Client) queryConfig(channelID string) (*DiscoveryConfig, error) {
+	req := discclient.NewRequest().OfChannel(channelID).AddConfigQuery()
+
+	var config *DiscoveryConfig
+	err := c.send(channelID, req, func(response discclient.ChannelResponse) error {
+		result, err := response.Config()
+		if err != nil {
+			return err
+		}
+		config = asDiscoveryConfig(result)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to query config")
+	}
+	return config, nil
+}
+
+// send sends the request to the discovery service of the channel peers and evaluates
+// the responses until one is successful
+func (c *Client) send(channelID string, req *discclient.Request, evaluate func(discclient.ChannelResponse) error) error {
+	targets, err := c.getTargets(channelID)
+	if err != nil {
+		return err
+	}
+
+	reqCtx, cancel := contextImpl.NewRequest(c.ctx, contextImpl.WithTimeoutType(fab.DiscoveryResponse))
+	defer cancel()
+
+	responses, err := c.discClient.Send(reqCtx, req, targets...)
+	if err != nil && len(responses) == 0 {
+		return errors.Wrap(err, "error calling discover service send")
+	}
+	if len(responses) == 0 {
+		return errors.New("no successful response received from any peer")
+	}
+
+	// TODO: validate the signatures in the responses
+	// For now just pick the first successful response
+	var lastErr error
+	for _, response := range responses {
+		if lastErr = evaluate(response.ForChannel(channelID)); lastErr == nil {
+			return nil
+		}
+	}
+	return lastErr
+}
+
+func (c *Client) getTargets(channelID string) ([]fab.PeerConfig, error) {
+	chPeers := c.ctx.EndpointConfig().ChannelPeers(channelID)
+	if len(chPeers) == 0 {
+		return nil, errors.Errorf("no channel peers configured for channel [%s]", channelID)
+	}
+
+	chConfig := c.ctx.EndpointConfig().ChannelConfig(channelID)
+
+	//pick number of peers given in channel policy
+	return random.PickRandomNPeerConfigs(chPeers, chConfig.Policies.Discovery.MaxTargets), nil
+}
+
+func asDiscoveredPeers(endpoints []*discclient.Peer) []*DiscoveredPeer {
+	var peers []*DiscoveredPeer
+	for _, endpoint := range endpoints {
+		if peer, ok := asDiscoveredPeer(endpoint); ok {
+			peers = append(peers, peer)
+		}
+	}
+	return peers
+}
+
+func asDiscoveredPeer(endpoint *discclient.Peer) (*DiscoveredPeer, bool) {
+	if endpoint.AliveMessage == nil {
+		return nil, false
+	}
+	aliveMsg := endpoint.AliveMessage.GetAliveMsg()
+	if aliveMsg == nil || aliveMsg.Membership == nil {
+		return nil, false
+	}
+
+	peer := &DiscoveredPeer{
+		MSPID:    endpoint.MSPID,
+		Endpoint: aliveMsg.Membership.Endpoint,
+	}
+
+	var properties *gossip.Properties
+	if endpoint.StateInfoMessage != nil {
+		properties = endpoint.StateInfoMessage.GetStateInfo().GetProperties()
+	}
+	if properties != nil {
+		peer.LedgerHeight = properties.LedgerHeight
+		for _, chaincode := range properties.Chaincodes {
+			peer.Chaincodes = append(peer.Chaincodes, chaincode.Name)
+		}
+	}
+	return peer, true
+}
+
+func asEndorserGroups(endorsers discclient.Endorsers) []*EndorserGroup {
+	groupsByMSP := make(map[string]*EndorserGroup)
+	for _, endorser := range endorsers {
+		peer, ok := asDiscoveredPeer(endorser)
+		if !ok {
+			continue
+		}
+		group, ok := groupsByMSP[peer.MSPID]
+		if !ok {
+			group = &EndorserGroup{MSPID: peer.MSPID}
+			groupsByMSP[peer.MSPID] = group
+		}
+		group.Peers = append(group.Peers, peer)
+	}
+
+	var groups []*EndorserGroup
+	for _, group := range groupsByMSP {
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].MSPID < groups[j].MSPID })
+	return groups
+}
+
+func asDiscoveryConfig(result *discpb.ConfigResult) *DiscoveryConfig {
+	config := &DiscoveryConfig{
+		MSPs:     make(map[string]*mb.FabricMSPConfig),
+		Orderers: make(map[string][]string),
+	}
+	for mspID, mspConfig := range result.Msps {
+		config.MSPs[mspID] = mspConfig
+	}
+	for mspID, endpoints := range result.Orderers {
+		for _, endpoint := range endpoints.Endpoint {
+			config.Orderers[mspID] = append(config.Orderers[mspID], fmt.Sprintf("%s:%d", endpoint.Host, endpoint.Port))
+		}
+	}
+	return config
+}
+
+type cacheKey struct {
+	queryType queryType
+	channelID string
+	chaincode string
+	key       string
+}
+
+func newCacheKey(t queryType, channelID, chaincode string) *cacheKey {
+	return &cacheKey{
+		queryType: t,
+		channelID: channelID,
+		chaincode: chaincode,
+		key:       string(t) + "_" + channelID + "_" + chaincode,
+	}
+}
+
+// String returns the key as a string
+func (k *cacheKey) String() string {
+	return k.key
+}
@@ -0,0 +1,148 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	reqContext "context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
+	discpb "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/protos/discovery"
+	clientmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
+	discmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery/mocks"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+)
+
+const channelID = "mychannel"
+
+// countingDiscoveryClient counts the requests sent to the mock discovery client
+type countingDiscoveryClient struct {
+	*clientmocks.MockDiscoveryClient
+	sent int32
+}
+
+func (c *countingDiscoveryClient) Send(ctx reqContext.Context, req *discclient.Request, targets ...fab.PeerConfig) ([]fabdiscovery.Response, error) {
+	atomic.AddInt32(&c.sent, 1)
+	return c.MockDiscoveryClient.Send(ctx, req, targets...)
+}
+
+func setupClient(t *testing.T, opts ...ClientOption) (*Client, *countingDiscoveryClient) {
+	discClient := &countingDiscoveryClient{MockDiscoveryClient: clientmocks.NewMockDiscoveryClient()}
+	clientProvider = func(ctx context.Client) (DiscoveryClient, error) {
+		return discClient, nil
+	}
+
+	ctx := fcmocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP"))
+	c, err := New(func() (context.Client, error) { return ctx, nil }, opts...)
+	require.NoError(t, err)
+	return c, discClient
+}
+
+func TestNew(t *testing.T) {
+	ctx := fcmocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP"))
+	_, err := New(func() (context.Client, error) { return ctx, nil }, WithCacheTTL(0))
+	assert.Error(t, err, "expected error for invalid cache TTL")
+
+	c, _ := setupClient(t)
+	defer c.Close()
+	assert.NotZero(t, c.cacheTTL)
+}
+
+func TestGetPeers(t *testing.T) {
+	c, discClient := setupClient(t, WithCacheTTL(50*time.Millisecond))
+	defer c.Close()
+
+	discClient.SetResponses(
+		&clientmocks.MockDiscoverEndpointResponse{
+			PeerEndpoints: []*discmocks.MockDiscoveryPeerEndpoint{
+				{MSPID: "Org1MSP", Endpoint: "peer1.org1.com:7051", LedgerHeight: 5},
+				{MSPID: "Org2MSP", Endpoint: "peer1.org2.com:7051", LedgerHeight: 6},
+			},
+		},
+	)
+
+	_, err := c.GetPeers("")
+	assert.Error(t, err, "expected error for empty channel")
+
+	peers, err := c.GetPeers(channelID)
+	require.NoError(t, err)
+	require.Len(t, peers, 2)
+	assert.Equal(t, "Org1MSP", peers[0].MSPID)
+	assert.Equal(t, "peer1.org1.com:7051", peers[0].Endpoint)
+	assert.Equal(t, uint64(5), peers[0].LedgerHeight)
+
+	_, err = c.GetPeers(channelID)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&discClient.sent), "expected cached result")
+
+	time.Sleep(100 * time.Millisecond)
+	_, err = c.GetPeers(channelID)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&discClient.sent), "expected cached result to expire")
+}
+
+func TestGetEndorsers(t *testing.T) {
+	c, discClient := setupClient(t)
+	defer c.Close()
+
+	discClient.SetResponses(
+		&clientmocks.MockDiscoverEndpointResponse{
+			PeerEndpoints: []*discmocks.MockDiscoveryPeerEndpoint{
+				{MSPID: "Org2MSP", Endpoint: "peer1.org2.com:7051"},
+				{MSPID: "Org1MSP", Endpoint: "peer1.org1.com:7051"},
+				{MSPID: "Org1MSP", Endpoint: "peer2.org1.com:7051"},
+			},
+		},
+	)
+
+	_, err := c.GetEndorsers(channelID, "")
+	assert.Error(t, err, "expected error for empty chaincode")
+
+	groups, err := c.GetEndorsers(channelID, "testCC")
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+	assert.Equal(t, "Org1MSP", groups[0].MSPID)
+	assert.Len(t, groups[0].Peers, 2)
+	assert.Equal(t, "Org2MSP", groups[1].MSPID)
+	assert.Len(t, groups[1].Peers, 1)
+}
+
+func TestGetConfig(t *testing.T) {
+	c, discClient := setupClient(t)
+	defer c.Close()
+
+	discClient.SetResponses(&clientmocks.MockDiscoverEndpointResponse{})
+	_, err := c.GetConfig(channelID)
+	assert.Error(t, err, "expected error for missing config")
+	c.cache.DeleteAll()
+
+	discClient.SetResponses(
+		&clientmocks.MockDiscoverEndpointResponse{
+			Config: &discpb.ConfigResult{
+				Msps: map[string]*mb.FabricMSPConfig{"Org1MSP": {Name: "Org1MSP"}},
+				Orderers: map[string]*discpb.Endpoints{
+					"OrdererMSP": {Endpoint: []*discpb.Endpoint{{Host: "orderer.example.com", Port: 7050}}},
+				},
+			},
+		},
+	)
+
+	config, err := c.GetConfig(channelID)
+	require.NoError(t, err)
+	assert.Equal(t, "Org1MSP", config.MSPs["Org1MSP"].Name)
+	assert.Equal(t, []string{"orderer.example.com:7050"}, config.Orderers["OrdererMSP"])
+}
@@ -0,0 +1,28 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ClientOption describes a functional parameter for the New constructor
+type ClientOption func(*Client) error
+
+// WithCacheTTL sets the time for which the results of discovery queries are cached.
+// If not set, the discovery service refresh interval of the endpoint config is used.
+func WithCacheTTL(ttl time.Duration) ClientOption {
+	return func(c *Client) error {
+		if ttl <= 0 {
+			return errors.New("cache TTL must be greater than zero")
+		}
+		c.cacheTTL = ttl
+		return nil
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/pkg/errors"
+)
+
+const defaultBlockHeightInterval = 5 * time.Second
+
+// blockHeightEventClient is the event client used to query the block height of a channel
+type blockHeightEventClient interface {
+	fab.EventService
+	Connect() error
+	Close()
+}
+
+// blockHeightClientProvider is overridden by unit tests
+var blockHeightClientProvider = func(ctx context.Client, chConfig fab.ChannelCfg, discovery fab.DiscoveryService, opts ...options.Opt) (blockHeightEventClient, error) {
+	return deliverclient.New(ctx, chConfig, discovery, opts...)
+}
+
+type blockHeight struct {
+	height    uint64
+	queriedAt time.Time
+}
+
+// GetBlockHeight returns the current block height of the given channel. The height is obtained by seeking a
+// deliver stream to the newest block; the stream is closed as soon as the block is received. The result is
+// cached for the interval given by WithBlockHeightInterval.
+//  Parameters:
+//  channelID is the ID of the channel; if empty the channel of the client is used
+//
+//  Returns:
+//  the block height, i.e. the number of the newest block plus one
+func (c *Client) GetBlockHeight(channelID string) (uint64, error) {
+	if channelID == "" {
+		channelID = c.channelContext.ChannelID()
+	}
+
+	c.blockHeightsLock.Lock()
+	cached, ok := c.blockHeights[channelID]
+	c.blockHeightsLock.Unlock()
+
+	if ok && time.Since(cached.queriedAt) < c.blockHeightInterval {
+		return cached.height, nil
+	}
+
+	// The lock isn't held while querying so that a slow peer doesn't block queries for other channels
+	height, err := c.queryBlockHeight(channelID)
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to query block height")
+	}
+
+	c.blockHeightsLock.Lock()
+	c.blockHeights[channelID] = &blockHeight{height: height, queriedAt: time.Now()}
+	c.blockHeightsLock.Unlock()
+
+	return height, nil
+}
+
+func (c *Client) queryBlockHeight(channelID string) (uint64, error) {
+	chService := c.channelContext.ChannelService()
+	if channelID != c.channelContext.ChannelID() {
+		var err error
+		chService, err = c.channelContext.ChannelProvider().ChannelService(c.channelContext, channelID)
+		if err != nil {
+			return 0, errors.WithMessage(err, "failed to get channel service")
+		}
+	}
+
+	chConfig, err := chService.ChannelConfig()
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to get channel config")
+	}
+	discovery, err := chService.Discovery()
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to get discovery service")
+	}
+
+	eventClient, err := blockHeightClientProvider(c.channelContext, chConfig, discovery, deliverclient.WithSeekType(seek.Newest))
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to create event client")
+	}
+	defer eventClient.Close()
+
+	// Register before connecting since the seek to the newest block is sent on connect
+	reg, eventch, err := eventClient.RegisterFilteredBlockEvent()
+	if err != nil {
+		return 0, err
+	}
+	defer eventClient.Unregister(reg)
+
+	if err := eventClient.Connect(); err != nil {
+		return 0, errors.WithMessage(err, "failed to connect event client")
+	}
+
+	select {
+	case event, ok := <-eventch:
+		if !ok || event.FilteredBlock == nil {
+			return 0, errors.New("event client closed before the newest block was received")
+		}
+		return event.FilteredBlock.Number + 1, nil
+	case <-time.After(c.channelContext.EndpointConfig().Timeout(fab.EventReg)):
+		return 0, errors.New("timed out waiting for the newest block")
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service"
+	servicemocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/service/mocks"
+)
+
+// mockBlockHeightClient produces the newest filtered block on connect, as the deliver client does
+// after sending the seek request. Connect fails if there's no registration to receive the block.
+type mockBlockHeightClient struct {
+	*service.Service
+	producer   *servicemocks.MockProducer
+	registered bool
+}
+
+func (c *mockBlockHeightClient) Connect() error {
+	if !c.registered {
+		return errors.New("connected before registering for filtered block events")
+	}
+	c.producer.Ledger().NewFilteredBlock(channelID)
+	return nil
+}
+
+func (c *mockBlockHeightClient) Close() {
+}
+
+func (c *mockBlockHeightClient) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	reg, eventch, err := c.Service.RegisterFilteredBlockEvent()
+	c.registered = err == nil
+	return reg, eventch, err
+}
+
+func TestGetBlockHeight(t *testing.T) {
+	eventService, eventProducer, err := newServiceWithMockProducer(defaultOpts, withFilteredBlockLedger(sourceURL))
+	require.NoError(t, err)
+	defer eventProducer.Close()
+	defer eventService.Stop()
+
+	queries := 0
+	blockHeightClientProvider = func(ctx context.Client, chConfig fab.ChannelCfg, discovery fab.DiscoveryService, opts ...options.Opt) (blockHeightEventClient, error) {
+		queries++
+		return &mockBlockHeightClient{Service: eventService, producer: eventProducer}, nil
+	}
+
+	fabCtx := setupCustomTestContext(t, nil)
+	ctx := createChannelContext(fabCtx, channelID)
+
+	_, err = New(ctx, WithBlockHeightInterval(-1))
+	assert.Error(t, err, "expected error for negative block height interval")
+
+	client, err := New(ctx)
+	require.NoError(t, err)
+
+	height, err := client.GetBlockHeight("")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), height)
+
+	height, err = client.GetBlockHeight(channelID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), height)
+	assert.Equal(t, 1, queries, "expected cached block height to be returned")
+
+	client.blockHeightInterval = 0
+	height, err = client.GetBlockHeight(channelID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), height)
+	assert.Equal(t, 2, queries)
+}
@@ -15,6 +15,9 @@ SPDX-License-Identifier: Apache-2.0
 package event
 
 import (
+	"sync"
+	"time"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -24,12 +27,26 @@ import (
 	"github.com/pkg/errors"
 )
 
+// ErrReplayInProgress is returned by RegisterBlockEvent while block events are replayed from the oldest block, and
+// by RegisterBlockEventFromOldest while block events are registered, since both may not be used on the same client
+var ErrReplayInProgress = errors.New("block event replay and block event registration are mutually exclusive")
+
 // Client enables access to a channel events on a Fabric network.
 type Client struct {
 	eventService      fab.EventService
 	permitBlockEvents bool
 	fromBlock         uint64
 	seekType          seek.Type
+	connOpts          []options.Opt // options of the connection to the event server, e.g. reconnect delay
+
+	channelContext context.Channel
+	blockRegsLock  sync.Mutex
+	blockRegs      map[fab.Registration]bool // block event registrations; true if replayed from the oldest block
+	replayService  fab.EventService          // event service seeked to the oldest block, created on first replay
+
+	blockHeightInterval time.Duration
+	blockHeightsLock    sync.Mutex
+	blockHeights        map[string]*blockHeight // cached block heights by channel
 }
 
 // New returns a Client instance. Client receives events such as block, filtered block,
@@ -41,12 +58,18 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 		return nil, errors.WithMessage(err, "failed to create channel context")
 	}
 
-	eventClient := Client{}
+	eventClient := Client{
+		channelContext: channelContext,
+		blockRegs:      make(map[fab.Registration]bool),
+
+		blockHeightInterval: defaultBlockHeightInterval,
+		blockHeights:        make(map[string]*blockHeight),
+	}
 
 	for _, param := range opts {
 		err1 := param(&eventClient)
 		if err1 != nil {
-			return nil, errors.WithMessage(err, "option failed")
+			return nil, errors.WithMessage(err1, "option failed")
 		}
 	}
 
@@ -64,9 +87,9 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 				opts = append(opts, deliverclient.WithBlockNum(eventClient.fromBlock))
 			}
 		}
-		es, err = channelContext.ChannelService().EventService(opts...)
+		es, err = channelContext.ChannelService().EventService(append(opts, eventClient.connOpts...)...)
 	} else {
-		es, err = channelContext.ChannelService().EventService()
+		es, err = channelContext.ChannelService().EventService(eventClient.connOpts...)
 	}
 
 	if err != nil {
@@ -86,7 +109,64 @@ func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client
 //  Returns:
 //  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
 func (c *Client) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
-	return c.eventService.RegisterBlockEvent(filter...)
+	c.blockRegsLock.Lock()
+	defer c.blockRegsLock.Unlock()
+
+	if c.hasBlockRegs(true) {
+		return nil, nil, ErrReplayInProgress
+	}
+
+	reg, eventch, err := c.eventService.RegisterBlockEvent(filter...)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.blockRegs[reg] = false
+	return reg, eventch, nil
+}
+
+// RegisterBlockEventFromOldest registers for block events, starting with the oldest block of the channel, so that callers
+// rebuilding state from genesis receive all historical blocks. The caller must have permission to receive block events.
+// While a replay registration exists RegisterBlockEvent returns ErrReplayInProgress, and vice versa.
+// Unregister must be called when the registration is no longer needed.
+//  Parameters:
+//  filter is an optional filter that filters out unwanted events. (Note: Only one filter may be specified.)
+//
+//  Returns:
+//  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
+func (c *Client) RegisterBlockEventFromOldest(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	c.blockRegsLock.Lock()
+	defer c.blockRegsLock.Unlock()
+
+	if c.hasBlockRegs(false) {
+		return nil, nil, ErrReplayInProgress
+	}
+
+	if c.replayService == nil {
+		// The deliver stream of the event service is seeked to the oldest block when it connects
+		opts := []options.Opt{client.WithBlockEvents(), deliverclient.WithSeekType(seek.Oldest)}
+		es, err := c.channelContext.ChannelService().EventService(append(opts, c.connOpts...)...)
+		if err != nil {
+			return nil, nil, errors.WithMessage(err, "event service creation failed")
+		}
+		c.replayService = es
+	}
+
+	reg, eventch, err := c.replayService.RegisterBlockEvent(filter...)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.blockRegs[reg] = true
+	return reg, eventch, nil
+}
+
+// hasBlockRegs returns true if there are block event registrations of the given kind
+func (c *Client) hasBlockRegs(replay bool) bool {
+	for _, r := range c.blockRegs {
+		if r == replay {
+			return true
+		}
+	}
+	return false
 }
 
 // RegisterFilteredBlockEvent registers for filtered block events. Unregister must be called when the registration is no longer needed.
@@ -117,9 +197,39 @@ func (c *Client) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *f
 	return c.eventService.RegisterTxStatusEvent(txID)
 }
 
-// Unregister removes the given registration and closes the event channel.
+// Unregister removes the given registration and closes the event channel. When the last registration of
+// RegisterBlockEventFromOldest is removed, the connection of the event service used for the replay is closed.
 //  Parameters:
 //  reg is the registration handle that was returned from one of the Register functions
 func (c *Client) Unregister(reg fab.Registration) {
+	c.blockRegsLock.Lock()
+	replay, ok := c.blockRegs[reg]
+	delete(c.blockRegs, reg)
+
+	if ok && replay {
+		defer c.blockRegsLock.Unlock()
+		c.replayService.Unregister(reg)
+		if !c.hasBlockRegs(true) {
+			c.closeReplayService()
+		}
+		return
+	}
+	c.blockRegsLock.Unlock()
 	c.eventService.Unregister(reg)
 }
+
+// closeReplayService closes the connection of the event service used for the replay, if it has no registrations left
+func (c *Client) closeReplayService() {
+	closer, ok := c.replayService.(idleCloser)
+	if !ok {
+		return
+	}
+	if closer.CloseIfIdle() {
+		c.replayService = nil
+	}
+}
+
+// idleCloser is implemented by event services whose connection may be closed when they have no registrations
+type idleCloser interface {
+	CloseIfIdle() bool
+}
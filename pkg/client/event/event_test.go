@@ -48,6 +48,23 @@ func TestNewEventClient(t *testing.T) {
 		t.Fatalf("Failed to create new event client: %s", err)
 	}
 
+	_, err = New(ctx, WithReconnectDelay(time.Second, time.Millisecond, 2))
+	assert.Error(t, err, "expected error for max reconnect delay less than initial delay")
+
+	_, err = New(ctx, WithReconnectDelay(0, time.Second, 2))
+	assert.Error(t, err, "expected error for zero initial reconnect delay")
+
+	_, err = New(ctx, WithReconnectDelay(time.Millisecond, time.Second, 0.5))
+	assert.Error(t, err, "expected error for backoff factor less than 1")
+
+	_, err = New(ctx, WithReconnectEvents(nil))
+	assert.Error(t, err, "expected error for nil reconnect event channel")
+
+	_, err = New(ctx, WithReconnectDelay(time.Millisecond, time.Second, 2), WithReconnectEvents(make(chan *fab.ReconnectEvent, 1)))
+	if err != nil {
+		t.Fatalf("Failed to create new event client: %s", err)
+	}
+
 	ctxErr := createChannelContextWithError(fabCtx, channelID)
 	_, err = New(ctxErr)
 	if err == nil {
@@ -92,6 +109,70 @@ func TestBlockEvents(t *testing.T) {
 	}
 }
 
+func TestBlockEventsFromOldest(t *testing.T) {
+	eventService, eventProducer, err := newServiceWithMockProducer(defaultOpts, withBlockLedger(sourceURL))
+	if err != nil {
+		t.Fatalf("error creating channel event client: %s", err)
+	}
+	defer eventProducer.Close()
+	defer eventService.Stop()
+
+	fabCtx := setupCustomTestContext(t, nil)
+	ctx := createChannelContext(fabCtx, channelID)
+
+	client, err := New(ctx)
+	if err != nil {
+		t.Fatalf("Failed to create new event client: %s", err)
+	}
+
+	replayService := &idleClosingEventService{EventService: eventService}
+	client.eventService = eventService
+	client.replayService = replayService
+
+	registration, eventch, err := client.RegisterBlockEventFromOldest()
+	if err != nil {
+		t.Fatalf("error registering for block events from oldest: %s", err)
+	}
+
+	_, _, err = client.RegisterBlockEvent()
+	assert.Equal(t, ErrReplayInProgress, err)
+
+	eventProducer.Ledger().NewBlock(channelID)
+
+	select {
+	case _, ok := <-eventch:
+		if !ok {
+			t.Fatalf("unexpected closed channel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for block event")
+	}
+
+	client.Unregister(registration)
+	assert.True(t, replayService.closed, "expecting the replay event service to be closed after its last registration is removed")
+	assert.Nil(t, client.replayService)
+
+	registration, _, err = client.RegisterBlockEvent()
+	if err != nil {
+		t.Fatalf("error registering for block events: %s", err)
+	}
+	defer client.Unregister(registration)
+
+	_, _, err = client.RegisterBlockEventFromOldest()
+	assert.Equal(t, ErrReplayInProgress, err)
+}
+
+// idleClosingEventService records whether CloseIfIdle was called
+type idleClosingEventService struct {
+	fab.EventService
+	closed bool
+}
+
+func (s *idleClosingEventService) CloseIfIdle() bool {
+	s.closed = true
+	return true
+}
+
 func TestFilteredBlockEvents(t *testing.T) {
 
 	eventService, eventProducer, err := newServiceWithMockProducer(defaultOpts, withFilteredBlockLedger(sourceURL))
@@ -0,0 +1,101 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+// MultiChannelEventClient receives events of multiple channels. The event service of each channel is
+// created on first use. All event services use the connections of the same client context, so the
+// deliver streams of the channels share the gRPC connections to a peer.
+type MultiChannelEventClient struct {
+	ctx           context.Client
+	lock          sync.Mutex
+	eventServices map[string]fab.EventService           // event service by channel ID
+	regs          map[fab.Registration]fab.EventService // event service of each registration
+}
+
+// NewMultiChannelEventClient returns a MultiChannelEventClient instance.
+func NewMultiChannelEventClient(clientProvider context.ClientProvider) (*MultiChannelEventClient, error) {
+	ctx, err := clientProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create client context")
+	}
+
+	return &MultiChannelEventClient{
+		ctx:           ctx,
+		eventServices: make(map[string]fab.EventService),
+		regs:          make(map[fab.Registration]fab.EventService),
+	}, nil
+}
+
+// RegisterChaincodeEvent registers for chaincode events of the given channel. Unregister must be called when the registration is no longer needed.
+//  Parameters:
+//  channelID is the ID of the channel
+//  ccID is the chaincode ID for which events are to be received
+//  eventFilter is the chaincode event filter (regular expression) for which events are to be received
+//
+//  Returns:
+//  the registration and a channel that is used to receive events. The channel is closed when Unregister is called.
+func (c *MultiChannelEventClient) RegisterChaincodeEvent(channelID, ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	eventService, err := c.eventService(channelID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reg, eventch, err := eventService.RegisterChaincodeEvent(ccID, eventFilter)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.regs[reg] = eventService
+	return reg, eventch, nil
+}
+
+// Unregister removes the given registration and closes the event channel.
+//  Parameters:
+//  reg is the registration handle that was returned from RegisterChaincodeEvent
+func (c *MultiChannelEventClient) Unregister(reg fab.Registration) {
+	c.lock.Lock()
+	eventService, ok := c.regs[reg]
+	delete(c.regs, reg)
+	c.lock.Unlock()
+
+	if ok {
+		eventService.Unregister(reg)
+	}
+}
+
+// eventService returns the event service of the given channel, creating it on first use
+func (c *MultiChannelEventClient) eventService(channelID string) (fab.EventService, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+
+	if eventService, ok := c.eventServices[channelID]; ok {
+		return eventService, nil
+	}
+
+	chService, err := c.ctx.ChannelProvider().ChannelService(c.ctx, channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get channel service")
+	}
+	eventService, err := chService.EventService()
+	if err != nil {
+		return nil, errors.WithMessage(err, "event service creation failed")
+	}
+
+	c.eventServices[channelID] = eventService
+	return eventService, nil
+}
@@ -0,0 +1,39 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package event
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiChannelRegisterChaincodeEvent(t *testing.T) {
+	client, err := NewMultiChannelEventClient(setupCustomTestContext(t, nil))
+	require.NoError(t, err)
+
+	_, _, err = client.RegisterChaincodeEvent("", "mycc", "event1")
+	assert.Error(t, err, "expected error for empty channel ID")
+
+	reg1, _, err := client.RegisterChaincodeEvent("channel1", "mycc", "event1")
+	require.NoError(t, err)
+	reg2, _, err := client.RegisterChaincodeEvent("channel2", "mycc", "event1")
+	require.NoError(t, err)
+	reg3, _, err := client.RegisterChaincodeEvent("channel1", "othercc", "event2")
+	require.NoError(t, err)
+
+	assert.Len(t, client.eventServices, 2, "expected one event service per channel")
+	assert.True(t, client.regs[reg1] == client.eventServices["channel1"])
+	assert.True(t, client.regs[reg2] == client.eventServices["channel2"])
+	assert.True(t, client.regs[reg3] == client.eventServices["channel1"])
+
+	client.Unregister(reg1)
+	client.Unregister(reg2)
+	client.Unregister(reg3)
+	assert.Empty(t, client.regs)
+}
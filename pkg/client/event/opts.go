@@ -6,7 +6,14 @@ SPDX-License-Identifier: Apache-2.0
 
 package event
 
-import "github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+import (
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
+	"github.com/pkg/errors"
+)
 
 // ClientOption describes a functional parameter for the New constructor
 type ClientOption func(*Client) error
@@ -37,3 +44,47 @@ func WithSeekType(seek seek.Type) ClientOption {
 		return nil
 	}
 }
+
+// WithReconnectDelay sets the backoff of the attempts to reconnect to the event server after the connection
+// has been lost. The first attempt is made after the initial delay, which is multiplied by factor after each
+// failed attempt, up to the max delay. Registrations remain active across reconnects, and events are
+// delivered from the block following the last block received. The initial delay must be greater than zero.
+func WithReconnectDelay(initial, max time.Duration, factor float64) ClientOption {
+	return func(c *Client) error {
+		if initial <= 0 || max < initial {
+			return errors.Errorf("invalid reconnect delay: initial [%s], max [%s]", initial, max)
+		}
+		if factor < 1 {
+			return errors.Errorf("reconnect backoff factor must be at least 1: %f", factor)
+		}
+		c.connOpts = append(c.connOpts, client.WithReconnectDelay(initial, max, factor))
+		return nil
+	}
+}
+
+// WithReconnectEvents sets the channel that receives a ReconnectEvent each time the client has reconnected
+// to the event server. The event contains the number of the last block received before the reconnect,
+// so that the receiver can detect potential gaps. Note that the client uses a dedicated connection to
+// the event server if this option is given. The channel is closed when the connection is closed.
+func WithReconnectEvents(eventch chan *fab.ReconnectEvent) ClientOption {
+	return func(c *Client) error {
+		if eventch == nil {
+			return errors.New("reconnect event channel is nil")
+		}
+		c.connOpts = append(c.connOpts, client.WithReconnectEvent(eventch))
+		return nil
+	}
+}
+
+// WithBlockHeightInterval sets the minimum interval between the block height queries of GetBlockHeight
+// for a channel. Within the interval the cached block height is returned. If zero, the block height
+// is queried on each call.
+func WithBlockHeightInterval(interval time.Duration) ClientOption {
+	return func(c *Client) error {
+		if interval < 0 {
+			return errors.Errorf("invalid block height interval: %s", interval)
+		}
+		c.blockHeightInterval = interval
+		return nil
+	}
+}
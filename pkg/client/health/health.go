@@ -0,0 +1,207 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package health enables health checks of the peers in the SDK's configuration.
+// A health check sends a local peers query to the discovery service of the peer, which
+// measures the round-trip latency and reveals whether the peer participates in gossip.
+// The discovery service only answers local peers queries of admins of the peer, so the
+// gossip membership of a peer is only known if the client context has an admin identity.
+//
+//  Basic Flow:
+//  1) Prepare client context
+//  2) Create health client
+//  3) Check the health of a peer
+package health
+
+import (
+	reqContext "context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"net"
+	"time"
+
+	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/client")
+
+type discoveryClient interface {
+	Send(ctx reqContext.Context, req *discclient.Request, targets ...fab.PeerConfig) ([]fabdiscovery.Response, error)
+}
+
+// clientProvider is overridden by unit tests
+var clientProvider = func(ctx context.Client) (discoveryClient, error) {
+	return fabdiscovery.New(ctx)
+}
+
+// Client checks the health of peers.
+type Client struct {
+	ctx        context.Client
+	discClient discoveryClient
+}
+
+// PeerHealthResult contains the result of a peer health check
+type PeerHealthResult struct {
+	URL string
+	// Connected is true if the peer responded to the health check
+	Connected bool
+	// GossipMember is true if the peer is an alive member of the gossip network of its organization.
+	// It is only meaningful if Error is nil: if the local peers query is denied, e.g. because the
+	// identity of the client is not an admin of the peer, the membership is unknown and Error is set.
+	GossipMember bool
+	// Latency is the round-trip time of the health check
+	Latency time.Duration
+	// LedgerHeight is the ledger height reported by the peer, if known (see fab.PeerState)
+	LedgerHeight uint64
+	// TLSFingerprint is the hex encoded SHA-256 hash of the TLS certificate of the peer
+	// (empty if the connection to the peer is not secured)
+	TLSFingerprint string
+	// Error holds the reason why the peer is not connected or its gossip membership is unknown
+	Error error
+}
+
+// New returns a health client instance.
+func New(ctxProvider context.ClientProvider) (*Client, error) {
+	ctx, err := ctxProvider()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create client context")
+	}
+
+	discClient, err := clientProvider(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create discovery client")
+	}
+
+	return &Client{
+		ctx:        ctx,
+		discClient: discClient,
+	}, nil
+}
+
+// CheckPeerHealth checks whether the given peer is alive and participates in gossip.
+// A peer that cannot be reached is reported in the result and not as an error. The gossip
+// membership can only be determined if the identity of the client is an admin of the peer.
+//  Parameters:
+//  peer is the peer to check
+//  timeout is the maximum time to wait for the peer to respond
+//
+//  Returns:
+//  the health check result
+func (c *Client) CheckPeerHealth(peer fab.Peer, timeout time.Duration) (*PeerHealthResult, error) {
+	if peer == nil {
+		return nil, errors.New("peer is required")
+	}
+	if timeout <= 0 {
+		return nil, errors.New("timeout must be greater than zero")
+	}
+
+	result := &PeerHealthResult{URL: peer.URL()}
+	if peerState, ok := peer.(fab.PeerState); ok {
+		result.LedgerHeight = peerState.BlockHeight()
+	}
+
+	peerConfig := c.peerConfig(peer)
+
+	reqCtx, cancel := contextImpl.NewRequest(c.ctx, contextImpl.WithTimeout(timeout))
+	defer cancel()
+
+	start := time.Now()
+	responses, err := c.discClient.Send(reqCtx, discclient.NewRequest().AddLocalPeersQuery(), *peerConfig)
+	if err == nil && len(responses) == 0 {
+		err = errors.New("no response from discovery service")
+	}
+	if err != nil {
+		logger.Debugf("Peer [%s] did not respond to health check: %s", peer.URL(), err)
+		result.Error = errors.WithMessage(err, "peer did not respond")
+		return result, nil
+	}
+	result.Latency = time.Since(start)
+	result.Connected = true
+
+	endpoints, err := responses[0].ForLocal().Peers()
+	if err != nil {
+		result.Error = errors.WithMessage(err, "local peers query failed")
+	} else {
+		result.GossipMember = isMember(peer.URL(), endpoints)
+	}
+
+	if endpoint.AttemptSecured(peerConfig.URL, isInsecureAllowed(peerConfig)) {
+		fingerprint, err := c.tlsFingerprint(peerConfig, timeout)
+		if err != nil {
+			logger.Warnf("Failed to get TLS fingerprint of peer [%s]: %s", peer.URL(), err)
+		}
+		result.TLSFingerprint = fingerprint
+	}
+
+	return result, nil
+}
+
+func (c *Client) peerConfig(peer fab.Peer) *fab.PeerConfig {
+	if peerConfig, ok := c.ctx.EndpointConfig().PeerConfig(peer.URL()); ok {
+		return peerConfig
+	}
+	return &fab.PeerConfig{URL: peer.URL()}
+}
+
+// tlsFingerprint returns the fingerprint of the TLS certificate presented by the peer
+func (c *Client) tlsFingerprint(peerConfig *fab.PeerConfig, timeout time.Duration) (string, error) {
+	tlsConfig, err := comm.TLSConfig(peerConfig.TLSCACert, serverNameOverride(peerConfig), c.ctx.EndpointConfig())
+	if err != nil {
+		return "", err
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", endpoint.ToAddress(peerConfig.URL), tlsConfig)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return "", errors.New("peer did not present a TLS certificate")
+	}
+	return fingerprint(certs[0].Raw), nil
+}
+
+func fingerprint(raw []byte) string {
+	hash := sha256.Sum256(raw)
+	return hex.EncodeToString(hash[:])
+}
+
+func isMember(url string, endpoints []*discclient.Peer) bool {
+	address := endpoint.ToAddress(url)
+	for _, e := range endpoints {
+		if e.AliveMessage == nil {
+			continue
+		}
+		aliveMsg := e.AliveMessage.GetAliveMsg()
+		if aliveMsg != nil && aliveMsg.Membership != nil && aliveMsg.Membership.Endpoint == address {
+			return true
+		}
+	}
+	return false
+}
+
+func serverNameOverride(peerConfig *fab.PeerConfig) string {
+	if str, ok := peerConfig.GRPCOptions["ssl-target-name-override"].(string); ok {
+		return str
+	}
+	return ""
+}
+
+func isInsecureAllowed(peerConfig *fab.PeerConfig) bool {
+	allowInsecure, ok := peerConfig.GRPCOptions["allow-insecure"].(bool)
+	return ok && allowInsecure
+}
@@ -0,0 +1,97 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package health
+
+import (
+	reqContext "context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	discclient "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/discovery/client"
+	clientmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	fabdiscovery "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery"
+	discmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery/mocks"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+)
+
+const peerURL = "grpc://peer1.org1.com:7051"
+
+type statefulPeer struct {
+	fab.Peer
+	blockHeight uint64
+}
+
+func (p *statefulPeer) BlockHeight() uint64 {
+	return p.blockHeight
+}
+
+type unreachableDiscoveryClient struct{}
+
+func (c *unreachableDiscoveryClient) Send(ctx reqContext.Context, req *discclient.Request, targets ...fab.PeerConfig) ([]fabdiscovery.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func setupClient(t *testing.T, discClient discoveryClient) *Client {
+	clientProvider = func(ctx context.Client) (discoveryClient, error) {
+		return discClient, nil
+	}
+
+	ctx := fcmocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP"))
+	c, err := New(func() (context.Client, error) { return ctx, nil })
+	require.NoError(t, err)
+	return c
+}
+
+func TestCheckPeerHealth(t *testing.T) {
+	discClient := clientmocks.NewMockDiscoveryClient()
+	discClient.SetResponses(
+		&clientmocks.MockDiscoverEndpointResponse{
+			PeerEndpoints: []*discmocks.MockDiscoveryPeerEndpoint{
+				{MSPID: "Org1MSP", Endpoint: "peer1.org1.com:7051"},
+				{MSPID: "Org1MSP", Endpoint: "peer2.org1.com:7051"},
+			},
+		},
+	)
+	c := setupClient(t, discClient)
+	peer := &statefulPeer{Peer: fcmocks.NewMockPeer("peer1", peerURL), blockHeight: 10}
+
+	_, err := c.CheckPeerHealth(nil, time.Second)
+	assert.Error(t, err, "expected error for nil peer")
+	_, err = c.CheckPeerHealth(peer, 0)
+	assert.Error(t, err, "expected error for invalid timeout")
+
+	result, err := c.CheckPeerHealth(peer, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, peerURL, result.URL)
+	assert.True(t, result.Connected)
+	assert.True(t, result.GossipMember)
+	assert.Equal(t, uint64(10), result.LedgerHeight)
+	assert.Empty(t, result.TLSFingerprint, "expected no TLS fingerprint for insecure connection")
+	assert.NoError(t, result.Error)
+
+	result, err = c.CheckPeerHealth(fcmocks.NewMockPeer("peer3", "grpc://peer3.org1.com:7051"), time.Second)
+	require.NoError(t, err)
+	assert.True(t, result.Connected)
+	assert.False(t, result.GossipMember)
+}
+
+func TestCheckPeerHealthUnreachable(t *testing.T) {
+	c := setupClient(t, &unreachableDiscoveryClient{})
+
+	result, err := c.CheckPeerHealth(fcmocks.NewMockPeer("peer1", peerURL), time.Second)
+	require.NoError(t, err)
+	assert.False(t, result.Connected)
+	assert.False(t, result.GossipMember)
+	assert.Error(t, result.Error)
+}
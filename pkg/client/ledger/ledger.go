@@ -7,7 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 // Package ledger enables ledger queries on specified channel on a Fabric network.
 // An application that requires ledger queries from multiple channels should create a separate
 // instance of the ledger client for each channel. Ledger client supports the following queries:
-// QueryInfo, QueryBlock, QueryBlockByHash,  QueryBlockByTxID, QueryTransaction and QueryConfig.
+// QueryInfo, QueryBlock, QueryBlockByHash,  QueryBlockByTxID, QueryBlockRange, QueryTransaction, QueryTransactionDetail and QueryConfig.
 //
 //  Basic Flow:
 //  1) Prepare channel context
@@ -18,6 +18,7 @@ package ledger
 import (
 	reqContext "context"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -25,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/discovery"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/filter"
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
@@ -35,9 +37,16 @@ import (
 
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
 	"github.com/pkg/errors"
 )
 
+// ErrBlockNotFound is returned by QueryBlockByHash if none of the targets has a block with the given hash
+var ErrBlockNotFound = errors.New("block not found")
+
+// blockNotFoundMsg is the message of the error returned by the peer's block storage for an unknown block hash
+const blockNotFoundMsg = "Entry not found in index"
+
 // Client enables ledger queries on a Fabric network.
 type Client struct {
 	ctx       context.Channel
@@ -47,6 +56,19 @@ type Client struct {
 	discovery fab.DiscoveryService
 }
 
+// BlockResult holds a block returned by QueryBlockRange, or the error that ended the query
+type BlockResult struct {
+	Block *common.Block
+	Err   error
+}
+
+// ChainInfo holds the height and tip block hashes of the ledger of a channel
+type ChainInfo struct {
+	Height            uint64
+	CurrentBlockHash  []byte
+	PreviousBlockHash []byte
+}
+
 // mspFilter is default filter
 type mspFilter struct {
 	mspID string
@@ -160,13 +182,38 @@ func (c *Client) QueryInfo(options ...RequestOption) (*fab.BlockchainInfoRespons
 	return response, nil
 }
 
+// QueryChainInfo queries the height and tip block hashes of the ledger of the channel, as QueryInfo does,
+// without exposing protobuf types.
+//  Parameters:
+//  channelID is the ID of the channel of the client
+//  options are optional request options
+//
+//  Returns:
+//  chain information of the peer with the highest block height
+func (c *Client) QueryChainInfo(channelID string, options ...RequestOption) (*ChainInfo, error) {
+	if channelID != c.ctx.ChannelID() {
+		return nil, errors.Errorf("ledger client is bound to channel [%s], not [%s]", c.ctx.ChannelID(), channelID)
+	}
+
+	response, err := c.QueryInfo(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChainInfo{
+		Height:            response.BCI.Height,
+		CurrentBlockHash:  response.BCI.CurrentBlockHash,
+		PreviousBlockHash: response.BCI.PreviousBlockHash,
+	}, nil
+}
+
 // QueryBlockByHash queries the ledger for block by block hash.
 //  Parameters:
 //  blockHash is required block hash
 //  options hold optional request options
 //
 //  Returns:
-//  block information
+//  block information, or ErrBlockNotFound if no target has a block with the given hash
 func (c *Client) QueryBlockByHash(blockHash []byte, options ...RequestOption) (*common.Block, error) {
 
 	targets, opts, err := c.prepareRequestParams(options...)
@@ -178,6 +225,9 @@ func (c *Client) QueryBlockByHash(blockHash []byte, options ...RequestOption) (*
 
 	responses, err := c.ledger.QueryBlockByHash(reqCtx, blockHash, peersToTxnProcessors(targets), c.verifier)
 	if err != nil && len(responses) == 0 {
+		if isBlockNotFound(err) {
+			return nil, ErrBlockNotFound
+		}
 		return nil, errors.WithMessage(err, "QueryBlockByHash failed")
 	}
 
@@ -232,6 +282,134 @@ func (c *Client) QueryBlock(blockNumber uint64, options ...RequestOption) (*comm
 	return matchBlockData(responses, opts.MinTargets)
 }
 
+// QueryBlockRange retrieves the blocks from startNum to endNum (inclusive) over a single deliver stream
+// to the orderer. If the orderer is not provided using options it will be defaulted to the channel
+// orderer (if configured) or a random orderer from configuration. The OrdererResponse timeout applies
+// to each block rather than to the whole range, which is only bounded by the parent context (if any).
+//  Parameters:
+//  startNum is the number of the first block
+//  endNum is the number of the last block
+//  options hold optional request options
+//
+//  Returns:
+//  a channel on which the blocks are sent in order. The channel is closed once all blocks have been sent
+//  or after a result with an error has been sent.
+func (c *Client) QueryBlockRange(startNum, endNum uint64, options ...RequestOption) (<-chan BlockResult, error) {
+	if startNum > endNum {
+		return nil, errors.Errorf("start block number %d is greater than end block number %d", startNum, endNum)
+	}
+
+	opts, err := c.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "QueryBlockRange failed to prepare request options")
+	}
+
+	orderer, err := c.requestOrderer(&opts)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to find orderer for request")
+	}
+
+	if opts.Timeouts == nil {
+		opts.Timeouts = make(map[fab.TimeoutType]time.Duration)
+	}
+	if opts.Timeouts[fab.OrdererResponse] == 0 {
+		opts.Timeouts[fab.OrdererResponse] = c.ctx.EndpointConfig().Timeout(fab.OrdererResponse)
+	}
+	reqCtx, cancel := contextImpl.NewRequest(c.ctx, contextImpl.WithNoTimeout(), contextImpl.WithParent(opts.ParentContext))
+
+	blocks, errs, err := resource.BlocksFromOrderer(reqCtx, c.ctx.ChannelID(), orderer, startNum, endNum)
+	if err != nil {
+		cancel()
+		return nil, errors.WithMessage(err, "QueryBlockRange failed")
+	}
+
+	results := make(chan BlockResult)
+	go func() {
+		defer cancel()
+		defer close(results)
+		forwardBlocks(reqCtx, blocks, errs, startNum, endNum, opts.Timeouts[fab.OrdererResponse], results)
+	}()
+	return results, nil
+}
+
+// forwardBlocks sends the blocks received from the deliver stream to results until all blocks from
+// startNum to endNum have been sent or an error occurs. Each block must be received within the timeout.
+func forwardBlocks(reqCtx reqContext.Context, blocks chan *common.Block, errs chan error, startNum, endNum uint64, timeout time.Duration, results chan<- BlockResult) {
+	send := func(result BlockResult) bool {
+		select {
+		case results <- result:
+			return true
+		case <-reqCtx.Done():
+			return false
+		}
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	next := startNum
+	for {
+		select {
+		case block, ok := <-blocks:
+			if !ok {
+				// The stream may have ended because of an error
+				select {
+				case err := <-errs:
+					send(BlockResult{Err: errors.WithMessage(err, "deliver stream failed")})
+				default:
+					if next <= endNum {
+						send(BlockResult{Err: errors.Errorf("deliver stream ended before block %d", next)})
+					}
+				}
+				return
+			}
+			if next > endNum || block.Header == nil || block.Header.Number != next {
+				send(BlockResult{Err: errors.Errorf("unexpected block from deliver stream, expected block %d", next)})
+				return
+			}
+			if !send(BlockResult{Block: block}) {
+				return
+			}
+			next++
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(timeout)
+		case err := <-errs:
+			send(BlockResult{Err: errors.WithMessage(err, "deliver stream failed")})
+			return
+		case <-timer.C:
+			send(BlockResult{Err: errors.Errorf("timed out waiting for block %d", next)})
+			return
+		case <-reqCtx.Done():
+			send(BlockResult{Err: errors.WithMessage(reqCtx.Err(), "QueryBlockRange timed out or was cancelled")})
+			return
+		}
+	}
+}
+
+func (c *Client) requestOrderer(opts *requestOptions) (fab.Orderer, error) {
+	if opts.Orderer != nil {
+		return opts.Orderer, nil
+	}
+
+	orderers := c.ctx.EndpointConfig().ChannelOrderers(c.ctx.ChannelID())
+	if len(orderers) == 0 {
+		orderers = c.ctx.EndpointConfig().OrderersConfig()
+	}
+	if len(orderers) == 0 {
+		return nil, errors.New("no orderers found")
+	}
+
+	// random orderer
+	ordererCfg := orderers[rand.Intn(len(orderers))]
+	orderer, err := c.ctx.InfraProvider().CreateOrdererFromConfig(&ordererCfg)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create orderer from config")
+	}
+	return orderer, nil
+}
+
 func (c *Client) prepareRequestParams(options ...RequestOption) ([]fab.Peer, *requestOptions, error) {
 	opts, err := c.prepareRequestOpts(options...)
 	if err != nil {
@@ -246,6 +424,25 @@ func (c *Client) prepareRequestParams(options ...RequestOption) ([]fab.Peer, *re
 	return targets, &opts, nil
 }
 
+// isBlockNotFound returns true if all targets responded that the block does not exist
+func isBlockNotFound(err error) bool {
+	errs, ok := err.(multi.Errors)
+	if !ok {
+		errs = multi.Errors{err}
+	}
+	notFound := false
+	for _, e := range errs {
+		if e == nil {
+			continue
+		}
+		if !strings.Contains(e.Error(), blockNotFoundMsg) {
+			return false
+		}
+		notFound = true
+	}
+	return notFound
+}
+
 func matchBlockData(responses []*common.Block, minTargets int) (*common.Block, error) {
 	if len(responses) < minTargets {
 		return nil, errors.Errorf("Number of responses %d is less than MinTargets %d", len(responses), minTargets)
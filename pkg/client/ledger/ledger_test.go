@@ -8,16 +8,21 @@ package ledger
 import (
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang/protobuf/proto"
 	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const (
@@ -152,6 +157,88 @@ func TestQueryBlockByHash(t *testing.T) {
 	}
 }
 
+func TestQueryBlockByHashNotFound(t *testing.T) {
+	notFound := status.New(status.ChaincodeStatus, 500, "Failed to get block hash 68617368, error Entry not found in index", nil)
+	peer1 := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", Status: 500, MockMSP: "test", Error: notFound}
+	peer2 := mocks.MockPeer{MockName: "Peer2", MockURL: "http://peer2.com", Status: 500, MockMSP: "test", Error: notFound}
+	lc := setupLedgerClient([]fab.Peer{&peer1, &peer2}, t)
+
+	_, err := lc.QueryBlockByHash([]byte("hash"), WithTargets(&peer1, &peer2), WithMaxTargets(2))
+	assert.Equal(t, ErrBlockNotFound, err)
+
+	peer2.Error = status.New(status.EndorserClientStatus, status.ConnectionFailed.ToInt32(), "connection failed", nil)
+	_, err = lc.QueryBlockByHash([]byte("hash"), WithTargets(&peer1, &peer2), WithMaxTargets(2))
+	assert.Error(t, err)
+	assert.NotEqual(t, ErrBlockNotFound, err, "block should only be reported as not found if all targets report it")
+}
+
+func TestQueryBlockRange(t *testing.T) {
+	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", Status: 200, MockMSP: "test"}
+	lc := setupLedgerClient([]fab.Peer{&peer}, t)
+
+	_, err := lc.QueryBlockRange(5, 4)
+	assert.Error(t, err, "expected error for start block after end block")
+
+	orderer := mocks.NewMockOrderer("", nil)
+	for i := uint64(2); i <= 4; i++ {
+		orderer.EnqueueForSendDeliver(newTestBlock(i))
+	}
+	orderer.CloseQueue()
+
+	results, err := lc.QueryBlockRange(2, 4, WithOrderer(orderer))
+	require.NoError(t, err)
+	var numbers []uint64
+	for result := range results {
+		require.NoError(t, result.Err)
+		numbers = append(numbers, result.Block.Header.Number)
+	}
+	assert.Equal(t, []uint64{2, 3, 4}, numbers)
+
+	// Stream ends before the last block
+	orderer = mocks.NewMockOrderer("", nil)
+	orderer.EnqueueForSendDeliver(newTestBlock(2))
+	orderer.CloseQueue()
+
+	results, err = lc.QueryBlockRange(2, 4, WithOrderer(orderer))
+	require.NoError(t, err)
+	result := <-results
+	require.NoError(t, result.Err)
+	result = <-results
+	assert.Error(t, result.Err, "expected error for incomplete block range")
+	_, ok := <-results
+	assert.False(t, ok, "results should be closed after error")
+
+	// Stream skips a block
+	orderer = mocks.NewMockOrderer("", nil)
+	orderer.EnqueueForSendDeliver(newTestBlock(3))
+	orderer.CloseQueue()
+
+	results, err = lc.QueryBlockRange(2, 4, WithOrderer(orderer))
+	require.NoError(t, err)
+	result = <-results
+	assert.Error(t, result.Err, "expected error for unexpected block")
+
+	// The timeout applies to each block rather than to the whole range
+	orderer = mocks.NewMockOrderer("", nil)
+	results, err = lc.QueryBlockRange(2, 4, WithOrderer(orderer), WithTimeout(fab.OrdererResponse, 100*time.Millisecond))
+	require.NoError(t, err)
+	for i := uint64(2); i <= 3; i++ {
+		time.Sleep(60 * time.Millisecond)
+		orderer.EnqueueForSendDeliver(newTestBlock(i))
+		result = <-results
+		require.NoError(t, result.Err)
+		assert.Equal(t, i, result.Block.Header.Number)
+	}
+	result = <-results
+	assert.EqualError(t, result.Err, "timed out waiting for block 4")
+	_, ok = <-results
+	assert.False(t, ok, "results should be closed after timeout")
+}
+
+func newTestBlock(number uint64) *common.Block {
+	return &common.Block{Header: &common.BlockHeader{Number: number}, Data: &common.BlockData{}}
+}
+
 func TestQueryBlockByTxID(t *testing.T) {
 
 	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Status: 200, MockMSP: "test"}
@@ -219,6 +306,23 @@ func TestQueryInfo(t *testing.T) {
 	}
 }
 
+func TestQueryChainInfo(t *testing.T) {
+	bci1, err := proto.Marshal(&common.BlockchainInfo{Height: 10, CurrentBlockHash: []byte("hash9"), PreviousBlockHash: []byte("hash8")})
+	require.NoError(t, err)
+	bci2, err := proto.Marshal(&common.BlockchainInfo{Height: 11, CurrentBlockHash: []byte("hash10"), PreviousBlockHash: []byte("hash9")})
+	require.NoError(t, err)
+	peer1 := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Status: 200, MockMSP: "test", Payload: bci1}
+	peer2 := mocks.MockPeer{MockName: "Peer2", MockURL: "http://peer2.com", MockRoles: []string{}, MockCert: nil, Status: 200, MockMSP: "test", Payload: bci2}
+	lc := setupLedgerClient([]fab.Peer{&peer1, &peer2}, t)
+
+	_, err = lc.QueryChainInfo("otherChannel")
+	assert.EqualError(t, err, "ledger client is bound to channel [testChannel], not [otherChannel]")
+
+	info, err := lc.QueryChainInfo(channelID, WithTargets(&peer1, &peer2), WithMaxTargets(2))
+	require.NoError(t, err)
+	assert.Equal(t, &ChainInfo{Height: 11, CurrentBlockHash: []byte("hash10"), PreviousBlockHash: []byte("hash9")}, info)
+}
+
 func TestQueryTransaction(t *testing.T) {
 
 	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Status: 200, MockMSP: "test"}
@@ -43,6 +43,7 @@ type requestOptions struct {
 	MinTargets    int                               // min number of targets that have to respond with no error (or agree on result)
 	Timeouts      map[fab.TimeoutType]time.Duration //timeout options for ledger query operations
 	ParentContext reqContext.Context                //parent grpc context for ledger operations
+	Orderer       fab.Orderer                       // orderer used by QueryBlockRange
 }
 
 //WithTargets allows for overriding of the target peers per request.
@@ -113,6 +114,14 @@ func WithMinTargets(minTargets int) RequestOption {
 	}
 }
 
+// WithOrderer allows an orderer to be specified for QueryBlockRange.
+func WithOrderer(orderer fab.Orderer) RequestOption {
+	return func(ctx context.Client, opts *requestOptions) error {
+		opts.Orderer = orderer
+		return nil
+	}
+}
+
 //WithTimeout encapsulates key value pairs of timeout type, timeout duration to Options
 //for QueryInfo, QueryBlock, QueryBlockByHash,  QueryBlockByTxID, QueryTransaction, QueryConfig functions
 func WithTimeout(timeoutType fab.TimeoutType, timeout time.Duration) RequestOption {
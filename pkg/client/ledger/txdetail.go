@@ -0,0 +1,121 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// TransactionDetail is a processed transaction along with its parsed envelope
+type TransactionDetail struct {
+	*pb.ProcessedTransaction
+
+	Payload         *common.Payload
+	Header          *common.Header
+	ChannelHeader   *common.ChannelHeader
+	SignatureHeader *common.SignatureHeader
+
+	// Transaction and ChaincodeInvocationSpecs are only set for endorser transactions.
+	// ChaincodeInvocationSpecs holds the invocation spec of each transaction action.
+	Transaction              *pb.Transaction
+	ChaincodeInvocationSpecs []*pb.ChaincodeInvocationSpec
+}
+
+// QueryTransactionDetail queries the ledger for processed transaction by transaction ID and parses its envelope.
+//  Parameters:
+//  txID is required transaction ID
+//  options hold optional request options
+//
+//  Returns:
+//  processed transaction along with its parsed headers and chaincode invocation specs
+func (c *Client) QueryTransactionDetail(transactionID fab.TransactionID, options ...RequestOption) (*TransactionDetail, error) {
+	processedTx, err := c.QueryTransaction(transactionID, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	detail, err := newTransactionDetail(processedTx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "QueryTransactionDetail failed to parse transaction")
+	}
+	return detail, nil
+}
+
+func newTransactionDetail(processedTx *pb.ProcessedTransaction) (*TransactionDetail, error) {
+	if processedTx.TransactionEnvelope == nil {
+		return nil, errors.New("transaction envelope is missing")
+	}
+
+	payload, err := protos_utils.GetPayload(processedTx.TransactionEnvelope)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("payload header is missing")
+	}
+
+	channelHeader, err := protos_utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	signatureHeader, err := protos_utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &TransactionDetail{
+		ProcessedTransaction: processedTx,
+		Payload:              payload,
+		Header:               payload.Header,
+		ChannelHeader:        channelHeader,
+		SignatureHeader:      signatureHeader,
+	}
+
+	if common.HeaderType(channelHeader.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+		return detail, nil
+	}
+
+	detail.Transaction, err = protos_utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, action := range detail.Transaction.Actions {
+		cis, err := chaincodeInvocationSpec(action)
+		if err != nil {
+			return nil, err
+		}
+		detail.ChaincodeInvocationSpecs = append(detail.ChaincodeInvocationSpecs, cis)
+	}
+
+	return detail, nil
+}
+
+// chaincodeInvocationSpec extracts the chaincode invocation spec from the proposal payload of the action
+func chaincodeInvocationSpec(action *pb.TransactionAction) (*pb.ChaincodeInvocationSpec, error) {
+	actionPayload, err := protos_utils.GetChaincodeActionPayload(action.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	proposalPayload, err := protos_utils.GetChaincodeProposalPayload(actionPayload.ChaincodeProposalPayload)
+	if err != nil {
+		return nil, err
+	}
+
+	cis := &pb.ChaincodeInvocationSpec{}
+	if err := proto.Unmarshal(proposalPayload.Input, cis); err != nil {
+		return nil, errors.Wrap(err, "error unmarshaling ChaincodeInvocationSpec")
+	}
+	return cis, nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package ledger
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryTransactionDetail(t *testing.T) {
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: "testCC"},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("move"), []byte("a"), []byte("b")}},
+		},
+	}
+	processedTx := newTestProcessedTransaction(t, common.HeaderType_ENDORSER_TRANSACTION, cis)
+
+	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", Status: 200, MockMSP: "test", Payload: protos_utils.MarshalOrPanic(processedTx)}
+	lc := setupLedgerClient([]fab.Peer{&peer}, t)
+
+	detail, err := lc.QueryTransactionDetail("txid")
+	require.NoError(t, err)
+	assert.Equal(t, int32(pb.TxValidationCode_VALID), detail.ValidationCode)
+	assert.Equal(t, "txid", detail.ChannelHeader.TxId)
+	assert.Equal(t, []byte("creator"), detail.SignatureHeader.Creator)
+	assert.Equal(t, detail.Payload.Header, detail.Header)
+	require.NotNil(t, detail.Transaction)
+	require.Len(t, detail.ChaincodeInvocationSpecs, 1)
+	assert.True(t, proto.Equal(cis, detail.ChaincodeInvocationSpecs[0]))
+
+	// Config transactions have no chaincode invocations
+	detail, err = newTransactionDetail(newTestProcessedTransaction(t, common.HeaderType_CONFIG, nil))
+	require.NoError(t, err)
+	assert.Nil(t, detail.Transaction)
+	assert.Empty(t, detail.ChaincodeInvocationSpecs)
+
+	_, err = newTransactionDetail(&pb.ProcessedTransaction{})
+	assert.Error(t, err, "expected error for missing envelope")
+}
+
+func newTestProcessedTransaction(t *testing.T, headerType common.HeaderType, cis *pb.ChaincodeInvocationSpec) *pb.ProcessedTransaction {
+	channelHeader := protos_utils.MakeChannelHeader(headerType, 0, channelID, 0)
+	channelHeader.TxId = "txid"
+	signatureHeader := &common.SignatureHeader{Creator: []byte("creator"), Nonce: []byte("nonce")}
+
+	var data []byte
+	if cis != nil {
+		proposalPayload := protos_utils.MarshalOrPanic(&pb.ChaincodeProposalPayload{Input: protos_utils.MarshalOrPanic(cis)})
+		actionPayload := protos_utils.MarshalOrPanic(&pb.ChaincodeActionPayload{ChaincodeProposalPayload: proposalPayload})
+		data = protos_utils.MarshalOrPanic(&pb.Transaction{Actions: []*pb.TransactionAction{{Payload: actionPayload}}})
+	}
+
+	payload := &common.Payload{
+		Header: protos_utils.MakePayloadHeader(channelHeader, signatureHeader),
+		Data:   data,
+	}
+	payloadBytes, err := proto.Marshal(payload)
+	require.NoError(t, err)
+
+	return &pb.ProcessedTransaction{
+		TransactionEnvelope: &common.Envelope{Payload: payloadBytes},
+		ValidationCode:      int32(pb.TxValidationCode_VALID),
+	}
+}
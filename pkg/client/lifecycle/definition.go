@@ -0,0 +1,188 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric-protos-go/peer"
+	lb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// ChaincodeDefinition contains the parameters of a chaincode definition that are agreed upon by the channel members
+type ChaincodeDefinition struct {
+	Name     string
+	Version  string
+	Sequence int64
+	// EndorsementPlugin and ValidationPlugin default to the peer's built-in plugins if not set
+	EndorsementPlugin string
+	ValidationPlugin  string
+	// SignaturePolicy is the endorsement policy of the chaincode. ChannelConfigPolicy may be set instead
+	// to use a policy of the channel configuration (e.g. /Channel/Application/Endorsement).
+	SignaturePolicy     *common.SignaturePolicyEnvelope
+	ChannelConfigPolicy string
+	CollectionConfig    []*common.CollectionConfig
+	InitRequired        bool
+}
+
+// ApprovedChaincodeDefinition is a chaincode definition approved by the client's organization
+type ApprovedChaincodeDefinition struct {
+	ChaincodeDefinition
+	// PackageID is the ID of the installed chaincode package the approval refers to (empty if not available)
+	PackageID string
+}
+
+// MarshalApplicationPolicy serializes an endorsement policy into the validation parameter of a chaincode definition.
+// Exactly one of signaturePolicy and channelConfigPolicy must be set.
+func MarshalApplicationPolicy(signaturePolicy *common.SignaturePolicyEnvelope, channelConfigPolicy string) ([]byte, error) {
+	if signaturePolicy != nil && channelConfigPolicy != "" {
+		return nil, errors.New("signature policy and channel config policy cannot both be set")
+	}
+
+	var policy *pb.ApplicationPolicy
+	switch {
+	case signaturePolicy != nil:
+		policy = &pb.ApplicationPolicy{Type: &pb.ApplicationPolicy_SignaturePolicy{SignaturePolicy: signaturePolicy}}
+	case channelConfigPolicy != "":
+		policy = &pb.ApplicationPolicy{Type: &pb.ApplicationPolicy_ChannelConfigPolicyReference{ChannelConfigPolicyReference: channelConfigPolicy}}
+	default:
+		return nil, errors.New("an endorsement policy is required")
+	}
+
+	policyBytes, err := proto.Marshal(policy)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of application policy failed")
+	}
+	return policyBytes, nil
+}
+
+// UnmarshalApplicationPolicy returns the endorsement policy of the given validation parameter, which is
+// either a signature policy or a reference to a channel config policy
+func UnmarshalApplicationPolicy(policyBytes []byte) (*common.SignaturePolicyEnvelope, string, error) {
+	policy := &pb.ApplicationPolicy{}
+	if err := proto.Unmarshal(policyBytes, policy); err != nil {
+		return nil, "", errors.Wrap(err, "unmarshal of application policy failed")
+	}
+
+	switch p := policy.Type.(type) {
+	case *pb.ApplicationPolicy_SignaturePolicy:
+		return p.SignaturePolicy, "", nil
+	case *pb.ApplicationPolicy_ChannelConfigPolicyReference:
+		return nil, p.ChannelConfigPolicyReference, nil
+	default:
+		return nil, "", errors.Errorf("unsupported application policy type %T", p)
+	}
+}
+
+// collectionConfigPackage returns the collections of a chaincode definition (nil if there are none)
+func collectionConfigPackage(collConfig []*common.CollectionConfig) *common.CollectionConfigPackage {
+	if len(collConfig) == 0 {
+		return nil
+	}
+	return &common.CollectionConfigPackage{Config: collConfig}
+}
+
+func validateDefinition(def *ChaincodeDefinition) error {
+	if def == nil {
+		return errors.New("chaincode definition is required")
+	}
+	if def.Name == "" || def.Version == "" {
+		return errors.New("chaincode name and version are required")
+	}
+	if def.Sequence <= 0 {
+		return errors.New("chaincode sequence must be greater than zero")
+	}
+	return nil
+}
+
+func newApproveArgs(def *ChaincodeDefinition, packageID string) (*lb.ApproveChaincodeDefinitionForMyOrgArgs, error) {
+	policyBytes, err := MarshalApplicationPolicy(def.SignaturePolicy, def.ChannelConfigPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	source := &lb.ChaincodeSource{Type: &lb.ChaincodeSource_Unavailable_{Unavailable: &lb.ChaincodeSource_Unavailable{}}}
+	if packageID != "" {
+		source = &lb.ChaincodeSource{Type: &lb.ChaincodeSource_LocalPackage{LocalPackage: &lb.ChaincodeSource_Local{PackageId: packageID}}}
+	}
+
+	return &lb.ApproveChaincodeDefinitionForMyOrgArgs{
+		Name:                def.Name,
+		Version:             def.Version,
+		Sequence:            def.Sequence,
+		EndorsementPlugin:   def.EndorsementPlugin,
+		ValidationPlugin:    def.ValidationPlugin,
+		ValidationParameter: policyBytes,
+		Collections:         collectionConfigPackage(def.CollectionConfig),
+		InitRequired:        def.InitRequired,
+		Source:              source,
+	}, nil
+}
+
+func newCommitArgs(def *ChaincodeDefinition) (*lb.CommitChaincodeDefinitionArgs, error) {
+	policyBytes, err := MarshalApplicationPolicy(def.SignaturePolicy, def.ChannelConfigPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lb.CommitChaincodeDefinitionArgs{
+		Name:                def.Name,
+		Version:             def.Version,
+		Sequence:            def.Sequence,
+		EndorsementPlugin:   def.EndorsementPlugin,
+		ValidationPlugin:    def.ValidationPlugin,
+		ValidationParameter: policyBytes,
+		Collections:         collectionConfigPackage(def.CollectionConfig),
+		InitRequired:        def.InitRequired,
+	}, nil
+}
+
+func newCheckCommitReadinessArgs(def *ChaincodeDefinition) (*lb.CheckCommitReadinessArgs, error) {
+	args, err := newCommitArgs(def)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lb.CheckCommitReadinessArgs{
+		Name:                args.Name,
+		Version:             args.Version,
+		Sequence:            args.Sequence,
+		EndorsementPlugin:   args.EndorsementPlugin,
+		ValidationPlugin:    args.ValidationPlugin,
+		ValidationParameter: args.ValidationParameter,
+		Collections:         args.Collections,
+		InitRequired:        args.InitRequired,
+	}, nil
+}
+
+func newApprovedChaincodeDefinition(name string, result *lb.QueryApprovedChaincodeDefinitionResult) (*ApprovedChaincodeDefinition, error) {
+	signaturePolicy, channelConfigPolicy, err := UnmarshalApplicationPolicy(result.ValidationParameter)
+	if err != nil {
+		return nil, err
+	}
+
+	def := &ApprovedChaincodeDefinition{
+		ChaincodeDefinition: ChaincodeDefinition{
+			Name:                name,
+			Version:             result.Version,
+			Sequence:            result.Sequence,
+			EndorsementPlugin:   result.EndorsementPlugin,
+			ValidationPlugin:    result.ValidationPlugin,
+			SignaturePolicy:     signaturePolicy,
+			ChannelConfigPolicy: channelConfigPolicy,
+			InitRequired:        result.InitRequired,
+		},
+	}
+	if result.Collections != nil {
+		def.CollectionConfig = result.Collections.Config
+	}
+	if local := result.GetSource().GetLocalPackage(); local != nil {
+		def.PackageID = local.PackageId
+	}
+	return def, nil
+}
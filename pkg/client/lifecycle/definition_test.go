@@ -0,0 +1,66 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package lifecycle
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	lb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplicationPolicy(t *testing.T) {
+	signaturePolicy := cauthdsl.SignedByMspMember("Org1MSP")
+
+	policyBytes, err := MarshalApplicationPolicy(signaturePolicy, "")
+	require.NoError(t, err)
+	policy, ref, err := UnmarshalApplicationPolicy(policyBytes)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(signaturePolicy, policy))
+	assert.Empty(t, ref)
+
+	policyBytes, err = MarshalApplicationPolicy(nil, "/Channel/Application/Endorsement")
+	require.NoError(t, err)
+	policy, ref, err = UnmarshalApplicationPolicy(policyBytes)
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+	assert.Equal(t, "/Channel/Application/Endorsement", ref)
+
+	_, err = MarshalApplicationPolicy(signaturePolicy, "/Channel/Application/Endorsement")
+	assert.EqualError(t, err, "signature policy and channel config policy cannot both be set")
+
+	_, _, err = UnmarshalApplicationPolicy([]byte("invalid"))
+	assert.Error(t, err)
+}
+
+func TestApproveArgs(t *testing.T) {
+	def := newTestDefinition()
+	def.CollectionConfig = []*common.CollectionConfig{{
+		Payload: &common.CollectionConfig_StaticCollectionConfig{
+			StaticCollectionConfig: &common.StaticCollectionConfig{Name: "coll1", RequiredPeerCount: 1, MaximumPeerCount: 2},
+		},
+	}}
+
+	args, err := newApproveArgs(def, "")
+	require.NoError(t, err)
+	assert.NotNil(t, args.Source.GetUnavailable())
+	assert.Equal(t, "coll1", args.Collections.Config[0].GetStaticCollectionConfig().Name)
+
+	args, err = newApproveArgs(def, "mycc_1:hash")
+	require.NoError(t, err)
+	assert.Equal(t, "mycc_1:hash", args.Source.GetLocalPackage().PackageId)
+
+	// The arguments are unmarshaled by the lifecycle system chaincode
+	argsBytes, err := proto.Marshal(args)
+	require.NoError(t, err)
+	unmarshaled := &lb.ApproveChaincodeDefinitionForMyOrgArgs{}
+	require.NoError(t, proto.Unmarshal(argsBytes, unmarshaled))
+	assert.True(t, proto.Equal(args, unmarshaled))
+}
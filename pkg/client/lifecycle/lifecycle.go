@@ -0,0 +1,536 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package lifecycle enables management of chaincode definitions on a channel using the
+// Fabric 2.x chaincode lifecycle (the _lifecycle system chaincode).
+// Each organization approves a chaincode definition for itself, and once enough organizations
+// have approved the definition it is committed to the channel.
+// An application that manages chaincode on multiple channels should create a separate
+// instance of the lifecycle client for each channel.
+//
+//  Basic Flow:
+//  1) Prepare channel context
+//  2) Create lifecycle client
+//  3) Install chaincode package onto the organization's peer(s)
+//  4) Approve chaincode definition for the organization
+//  5) Check commit readiness of the chaincode definition
+//  6) Commit chaincode definition
+package lifecycle
+
+import (
+	reqContext "context"
+	"math/rand"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/common/verifier"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	lb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	lifecycleCC = "_lifecycle"
+
+	installFuncName              = "InstallChaincode"
+	queryInstalledFuncName       = "QueryInstalledChaincodes"
+	approveFuncName              = "ApproveChaincodeDefinitionForMyOrg"
+	commitFuncName               = "CommitChaincodeDefinition"
+	checkCommitReadinessFuncName = "CheckCommitReadiness"
+	queryApprovedFuncName        = "QueryApprovedChaincodeDefinition"
+)
+
+// Client enables management of chaincode definitions on a channel.
+type Client struct {
+	ctx       context.Channel
+	filter    fab.TargetFilter
+	verifier  channel.ResponseVerifier
+	discovery fab.DiscoveryService
+}
+
+// mspFilter is default filter
+type mspFilter struct {
+	mspID string
+}
+
+// Accept returns true if this peer is to be included in the target list
+func (f *mspFilter) Accept(peer fab.Peer) bool {
+	return peer.MSPID() == f.mspID
+}
+
+// New returns a lifecycle client instance for the channel of the given channel context.
+func New(channelProvider context.ChannelProvider, opts ...ClientOption) (*Client, error) {
+
+	channelContext, err := channelProvider()
+	if err != nil {
+		return nil, err
+	}
+
+	if channelContext.ChannelService() == nil {
+		return nil, errors.New("channel service not initialized")
+	}
+
+	membership, err := channelContext.ChannelService().Membership()
+	if err != nil {
+		return nil, errors.WithMessage(err, "membership creation failed")
+	}
+
+	discovery, err := channelContext.ChannelService().Discovery()
+	if err != nil {
+		return nil, err
+	}
+
+	lifecycleClient := Client{
+		ctx:       channelContext,
+		verifier:  &verifier.Signature{Membership: membership},
+		discovery: discovery,
+	}
+
+	for _, opt := range opts {
+		err := opt(&lifecycleClient)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// check if target filter was set - if not set the default
+	if lifecycleClient.filter == nil {
+		// Default target filter is based on user msp
+		if channelContext.Identifier().MSPID == "" {
+			return nil, errors.New("mspID not available in user context")
+		}
+		lifecycleClient.filter = &mspFilter{mspID: channelContext.Identifier().MSPID}
+	}
+
+	return &lifecycleClient, nil
+}
+
+// InstallChaincodePackage installs a chaincode package on a peer. Unlike chaincode definitions, packages are
+// installed on each peer independently of the channel.
+//  Parameters:
+//  peer is the peer to install the package on
+//  pkg is the chaincode package
+//  options holds optional request options
+//
+//  Returns:
+//  the package ID (the package label and the hash of the package), which is used to approve a chaincode definition
+func (c *Client) InstallChaincodePackage(peer fab.Peer, pkg *ChaincodePackage, options ...RequestOption) (string, error) {
+	if peer == nil {
+		return "", errors.New("peer is required")
+	}
+	if pkg == nil {
+		return "", errors.New("chaincode package is required")
+	}
+
+	pkgBytes, err := pkg.Bytes()
+	if err != nil {
+		return "", errors.WithMessage(err, "creating install package failed")
+	}
+
+	payload, err := c.queryPeer(peer, installFuncName, &lb.InstallChaincodeArgs{ChaincodeInstallPackage: pkgBytes}, fab.ResMgmt, options...)
+	if err != nil {
+		return "", errors.WithMessage(err, "install chaincode package failed")
+	}
+
+	result := &lb.InstallChaincodeResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return "", errors.Wrap(err, "unmarshal of install chaincode result failed")
+	}
+
+	id := packageID(pkg.Label, pkgBytes)
+	if result.PackageId != id {
+		return "", errors.Errorf("package ID [%s] returned by peer does not match package ID [%s]", result.PackageId, id)
+	}
+	return id, nil
+}
+
+// QueryInstalledChaincodes returns the chaincode packages installed on a peer.
+//  Parameters:
+//  peer is the peer to query
+//  options holds optional request options
+//
+//  Returns:
+//  the installed chaincode packages, with the chaincode definitions that use them on each channel
+func (c *Client) QueryInstalledChaincodes(peer fab.Peer, options ...RequestOption) ([]*InstalledChaincode, error) {
+	if peer == nil {
+		return nil, errors.New("peer is required")
+	}
+
+	payload, err := c.queryPeer(peer, queryInstalledFuncName, &lb.QueryInstalledChaincodesArgs{}, fab.PeerResponse, options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "query installed chaincodes failed")
+	}
+
+	result := &lb.QueryInstalledChaincodesResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of installed chaincodes failed")
+	}
+
+	installed := make([]*InstalledChaincode, len(result.InstalledChaincodes))
+	for i, cc := range result.InstalledChaincodes {
+		installed[i] = newInstalledChaincode(cc)
+	}
+	return installed, nil
+}
+
+// ApproveChaincodeDefinition approves a chaincode definition for the client's organization. If targets are
+// not specified in options, the definition is endorsed by all peers that belong to client's MSP.
+//  Parameters:
+//  def is the chaincode definition
+//  packageID is the ID of the chaincode package installed on the organization's peers (empty if not installed yet)
+//  options holds optional request options
+//
+//  Returns:
+//  the ID of the approval transaction
+func (c *Client) ApproveChaincodeDefinition(def *ChaincodeDefinition, packageID string, options ...RequestOption) (fab.TransactionID, error) {
+	if err := validateDefinition(def); err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	args, err := newApproveArgs(def, packageID)
+	if err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	txID, err := c.submit(approveFuncName, args, c.filter, options...)
+	if err != nil {
+		return txID, errors.WithMessage(err, "approve chaincode definition failed")
+	}
+	return txID, nil
+}
+
+// CommitChaincodeDefinition commits a chaincode definition that has been approved by the channel members.
+// The lifecycle endorsement policy of the channel (by default a majority of the organizations) must be
+// satisfied by the targets, so if targets are not specified in options all peers of the channel are used.
+//  Parameters:
+//  def is the chaincode definition
+//  options holds optional request options
+//
+//  Returns:
+//  the ID of the commit transaction
+func (c *Client) CommitChaincodeDefinition(def *ChaincodeDefinition, options ...RequestOption) (fab.TransactionID, error) {
+	if err := validateDefinition(def); err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	args, err := newCommitArgs(def)
+	if err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	txID, err := c.submit(commitFuncName, args, nil, options...)
+	if err != nil {
+		return txID, errors.WithMessage(err, "commit chaincode definition failed")
+	}
+	return txID, nil
+}
+
+// CheckCommitReadiness returns the organizations of the channel that have approved the given chaincode definition.
+//  Parameters:
+//  def is the chaincode definition
+//  options holds optional request options
+//
+//  Returns:
+//  the approval status of the definition by MSP ID
+func (c *Client) CheckCommitReadiness(def *ChaincodeDefinition, options ...RequestOption) (map[string]bool, error) {
+	if err := validateDefinition(def); err != nil {
+		return nil, err
+	}
+
+	args, err := newCheckCommitReadinessArgs(def)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := c.query(checkCommitReadinessFuncName, args, options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "check commit readiness failed")
+	}
+
+	result := &lb.CheckCommitReadinessResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of check commit readiness result failed")
+	}
+	return result.Approvals, nil
+}
+
+// QueryApprovedChaincodeDefinition returns the chaincode definition approved by the client's organization.
+//  Parameters:
+//  name is the chaincode name
+//  sequence is the sequence of the definition (0 for the latest approved definition)
+//  options holds optional request options
+//
+//  Returns:
+//  the approved chaincode definition
+func (c *Client) QueryApprovedChaincodeDefinition(name string, sequence int64, options ...RequestOption) (*ApprovedChaincodeDefinition, error) {
+	if name == "" {
+		return nil, errors.New("chaincode name is required")
+	}
+
+	args := &lb.QueryApprovedChaincodeDefinitionArgs{Name: name, Sequence: sequence}
+	payload, err := c.query(queryApprovedFuncName, args, options...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "query approved chaincode definition failed")
+	}
+
+	result := &lb.QueryApprovedChaincodeDefinitionResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of approved chaincode definition failed")
+	}
+	return newApprovedChaincodeDefinition(name, result)
+}
+
+// submit endorses a lifecycle transaction with the targets, sends it to the orderer and waits for it to be committed
+func (c *Client) submit(fcn string, args proto.Message, defaultFilter fab.TargetFilter, options ...RequestOption) (fab.TransactionID, error) {
+	opts, err := c.prepareRequestOpts(options...)
+	if err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	targets, err := c.calculateTargets(opts, defaultFilter)
+	if err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	reqCtx, cancel := c.createRequestContext(&opts, fab.ResMgmt)
+	defer cancel()
+
+	transactor, err := c.ctx.ChannelService().Transactor(reqCtx)
+	if err != nil {
+		return fab.EmptyTransactionID, errors.WithMessage(err, "get channel transactor failed")
+	}
+
+	txh, err := transactor.CreateTransactionHeader()
+	if err != nil {
+		return fab.EmptyTransactionID, errors.WithMessage(err, "create transaction ID failed")
+	}
+
+	tp, err := createProposal(txh, fcn, args)
+	if err != nil {
+		return fab.EmptyTransactionID, err
+	}
+
+	responses, err := c.sendProposal(transactor, tp, targets)
+	if err != nil {
+		return tp.TxnID, err
+	}
+
+	eventService, err := c.ctx.ChannelService().EventService()
+	if err != nil {
+		return tp.TxnID, errors.WithMessage(err, "unable to get event service")
+	}
+
+	reg, statusNotifier, err := eventService.RegisterTxStatusEvent(string(tp.TxnID))
+	if err != nil {
+		return tp.TxnID, errors.WithMessage(err, "error registering for TxStatus event")
+	}
+	defer eventService.Unregister(reg)
+
+	tx, err := transactor.CreateTransaction(fab.TransactionRequest{Proposal: tp, ProposalResponses: responses})
+	if err != nil {
+		return tp.TxnID, errors.WithMessage(err, "CreateTransaction failed")
+	}
+
+	if _, err := transactor.SendTransaction(tx); err != nil {
+		return tp.TxnID, errors.WithMessage(err, "SendTransaction failed")
+	}
+
+	select {
+	case txStatus := <-statusNotifier:
+		if txStatus.TxValidationCode == pb.TxValidationCode_VALID {
+			return fab.TransactionID(txStatus.TxID), nil
+		}
+		return fab.TransactionID(txStatus.TxID), status.New(status.EventServerStatus, int32(txStatus.TxValidationCode), "lifecycle transaction failed", nil)
+	case <-reqCtx.Done():
+		return tp.TxnID, errors.New("lifecycle transaction timed out or cancelled")
+	}
+}
+
+// query sends a lifecycle query to one of the targets and returns the response payload
+func (c *Client) query(fcn string, args proto.Message, options ...RequestOption) ([]byte, error) {
+	opts, err := c.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	targets, err := c.calculateTargets(opts, c.filter)
+	if err != nil {
+		return nil, err
+	}
+	target := targets[rand.Intn(len(targets))]
+
+	reqCtx, cancel := c.createRequestContext(&opts, fab.PeerResponse)
+	defer cancel()
+
+	transactor, err := c.ctx.ChannelService().Transactor(reqCtx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "get channel transactor failed")
+	}
+
+	txh, err := transactor.CreateTransactionHeader()
+	if err != nil {
+		return nil, errors.WithMessage(err, "create transaction ID failed")
+	}
+
+	tp, err := createProposal(txh, fcn, args)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := c.sendProposal(transactor, tp, []fab.Peer{target})
+	if err != nil {
+		return nil, err
+	}
+	return responses[0].ProposalResponse.GetResponse().GetPayload(), nil
+}
+
+// queryPeer sends a lifecycle proposal that is not bound to a channel (e.g. install) to the given peer and returns the response payload
+func (c *Client) queryPeer(peer fab.Peer, fcn string, args proto.Message, timeoutType fab.TimeoutType, options ...RequestOption) ([]byte, error) {
+	opts, err := c.prepareRequestOpts(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	reqCtx, cancel := c.createRequestContext(&opts, timeoutType)
+	defer cancel()
+
+	txh, err := txn.NewHeader(c.ctx, fab.SystemChannel)
+	if err != nil {
+		return nil, errors.WithMessage(err, "create transaction ID failed")
+	}
+
+	tp, err := createProposal(txh, fcn, args)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := txn.SendProposal(reqCtx, tp, []fab.ProposalProcessor{peer})
+	if err != nil {
+		return nil, errors.WithMessage(err, "sending lifecycle proposal failed")
+	}
+	if err := c.verifier.Verify(responses[0]); err != nil {
+		return nil, errors.WithMessage(err, "failed to verify lifecycle proposal response")
+	}
+	return responses[0].ProposalResponse.GetResponse().GetPayload(), nil
+}
+
+func createProposal(txh fab.TransactionHeader, fcn string, args proto.Message) (*fab.TransactionProposal, error) {
+	argsBytes, err := proto.Marshal(args)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of lifecycle arguments failed")
+	}
+
+	cir := fab.ChaincodeInvokeRequest{
+		ChaincodeID: lifecycleCC,
+		Fcn:         fcn,
+		Args:        [][]byte{argsBytes},
+	}
+	tp, err := txn.CreateChaincodeInvokeProposal(txh, cir)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating lifecycle transaction proposal failed")
+	}
+	return tp, nil
+}
+
+func (c *Client) sendProposal(transactor fab.Transactor, tp *fab.TransactionProposal, targets []fab.Peer) ([]*fab.TransactionProposalResponse, error) {
+	responses, err := transactor.SendTransactionProposal(tp, peersToTxnProcessors(targets))
+	if err != nil {
+		return nil, errors.WithMessage(err, "sending lifecycle transaction proposal failed")
+	}
+
+	for _, r := range responses {
+		if err := c.verifier.Verify(r); err != nil {
+			return nil, errors.WithMessage(err, "failed to verify lifecycle proposal response")
+		}
+	}
+	return responses, nil
+}
+
+// calculateTargets returns the targets of the request, or the discovered peers accepted by the given filter
+func (c *Client) calculateTargets(opts requestOptions, defaultFilter fab.TargetFilter) ([]fab.Peer, error) {
+
+	if opts.Targets != nil && opts.TargetFilter != nil {
+		return nil, errors.New("If targets are provided, filter cannot be provided")
+	}
+
+	targets := opts.Targets
+	targetFilter := opts.TargetFilter
+
+	var err error
+	if targets == nil {
+		// Retrieve targets from discovery
+		targets, err = c.discovery.GetPeers()
+		if err != nil {
+			return nil, err
+		}
+
+		if targetFilter == nil {
+			targetFilter = defaultFilter
+		}
+	}
+
+	if targetFilter != nil {
+		targets = filterTargets(targets, targetFilter)
+	}
+
+	if len(targets) == 0 {
+		return nil, errors.WithStack(status.New(status.ClientStatus, status.NoPeersFound.ToInt32(), "no targets available", nil))
+	}
+
+	return targets, nil
+}
+
+func (c *Client) prepareRequestOpts(options ...RequestOption) (requestOptions, error) {
+	opts := requestOptions{}
+	for _, option := range options {
+		err := option(c.ctx, &opts)
+		if err != nil {
+			return opts, errors.WithMessage(err, "Failed to read request opts")
+		}
+	}
+	return opts, nil
+}
+
+func (c *Client) createRequestContext(opts *requestOptions, timeoutType fab.TimeoutType) (reqContext.Context, reqContext.CancelFunc) {
+
+	if opts.Timeouts == nil {
+		opts.Timeouts = make(map[fab.TimeoutType]time.Duration)
+	}
+
+	if opts.Timeouts[timeoutType] == 0 {
+		opts.Timeouts[timeoutType] = c.ctx.EndpointConfig().Timeout(timeoutType)
+	}
+
+	return contextImpl.NewRequest(c.ctx, contextImpl.WithTimeout(opts.Timeouts[timeoutType]), contextImpl.WithParent(opts.ParentContext))
+}
+
+func filterTargets(peers []fab.Peer, filter fab.TargetFilter) []fab.Peer {
+
+	filteredPeers := []fab.Peer{}
+	for _, peer := range peers {
+		if filter.Accept(peer) {
+			filteredPeers = append(filteredPeers, peer)
+		}
+	}
+
+	return filteredPeers
+}
+
+// peersToTxnProcessors converts a slice of Peers to a slice of ProposalProcessors
+func peersToTxnProcessors(peers []fab.Peer) []fab.ProposalProcessor {
+	tpp := make([]fab.ProposalProcessor, len(peers))
+
+	for i := range peers {
+		tpp[i] = peers[i]
+	}
+	return tpp
+}
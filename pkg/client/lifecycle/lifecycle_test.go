@@ -0,0 +1,248 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package lifecycle
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	txnmocks "github.com/hyperledger/fabric-sdk-go/pkg/client/common/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	lb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	channelID = "testChannel"
+)
+
+func TestInstallChaincodePackage(t *testing.T) {
+	pkg, err := NewExternalChaincodePackage("mycc_1", &ExternalConnection{Address: "mycc:9999"})
+	require.NoError(t, err)
+	pkgBytes, err := pkg.Bytes()
+	require.NoError(t, err)
+	expectedID := packageID("mycc_1", pkgBytes)
+
+	payload, err := proto.Marshal(&lb.InstallChaincodeResult{PackageId: expectedID, Label: "mycc_1"})
+	require.NoError(t, err)
+
+	peer1 := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", Status: 200, MockMSP: "test", Payload: payload}
+	lc := setupLifecycleClient([]fab.Peer{peer1}, t)
+
+	id, err := lc.InstallChaincodePackage(peer1, pkg)
+	require.NoError(t, err)
+	assert.Equal(t, expectedID, id)
+
+	// The peer computed a different package ID
+	peer1.Payload, err = proto.Marshal(&lb.InstallChaincodeResult{PackageId: "mycc_1:other", Label: "mycc_1"})
+	require.NoError(t, err)
+	_, err = lc.InstallChaincodePackage(peer1, pkg)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "does not match package ID")
+
+	_, err = lc.InstallChaincodePackage(nil, pkg)
+	assert.EqualError(t, err, "peer is required")
+
+	_, err = lc.InstallChaincodePackage(peer1, &ChaincodePackage{Label: "my cc", Type: "golang"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid package label")
+}
+
+func TestQueryInstalledChaincodes(t *testing.T) {
+	payload, err := proto.Marshal(&lb.QueryInstalledChaincodesResult{
+		InstalledChaincodes: []*lb.QueryInstalledChaincodesResult_InstalledChaincode{
+			{
+				PackageId: "mycc_1:hash1",
+				Label:     "mycc_1",
+				References: map[string]*lb.QueryInstalledChaincodesResult_References{
+					"ch1": {Chaincodes: []*lb.QueryInstalledChaincodesResult_Chaincode{{Name: "mycc", Version: "v1"}}},
+				},
+			},
+			{PackageId: "mycc_2:hash2", Label: "mycc_2"},
+		},
+	})
+	require.NoError(t, err)
+
+	peer1 := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", Status: 200, MockMSP: "test", Payload: payload}
+	lc := setupLifecycleClient([]fab.Peer{peer1}, t)
+
+	installed, err := lc.QueryInstalledChaincodes(peer1)
+	require.NoError(t, err)
+	require.Len(t, installed, 2)
+	assert.Equal(t, "mycc_1:hash1", installed[0].PackageID)
+	assert.Equal(t, "mycc_1", installed[0].Label)
+	assert.Equal(t, map[string][]ChaincodeReference{"ch1": {{Name: "mycc", Version: "v1"}}}, installed[0].References)
+	assert.Equal(t, "mycc_2:hash2", installed[1].PackageID)
+	assert.Empty(t, installed[1].References)
+
+	_, err = lc.QueryInstalledChaincodes(nil)
+	assert.EqualError(t, err, "peer is required")
+}
+
+func TestApproveChaincodeDefinition(t *testing.T) {
+	peer1 := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", Status: 200, MockMSP: "test"}
+	peer2 := &fcmocks.MockPeer{MockName: "Peer2", MockURL: "http://peer2.com", Status: 200, MockMSP: "other"}
+
+	lc := setupLifecycleClient([]fab.Peer{peer1, peer2}, t)
+
+	def := newTestDefinition()
+	txID, err := lc.ApproveChaincodeDefinition(def, "mycc_1:hash")
+	require.NoError(t, err)
+	assert.NotEmpty(t, txID)
+
+	// Only the peers of the client's organization approve the definition
+	assert.Equal(t, 1, peer1.ProcessProposalCalls)
+	assert.Equal(t, 0, peer2.ProcessProposalCalls)
+
+	_, err = lc.ApproveChaincodeDefinition(&ChaincodeDefinition{Name: "mycc", Version: "v1"}, "")
+	assert.EqualError(t, err, "chaincode sequence must be greater than zero")
+
+	_, err = lc.ApproveChaincodeDefinition(&ChaincodeDefinition{Name: "mycc", Version: "v1", Sequence: 1}, "")
+	assert.EqualError(t, err, "an endorsement policy is required")
+}
+
+func TestCommitChaincodeDefinition(t *testing.T) {
+	peer1 := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", Status: 200, MockMSP: "test"}
+	peer2 := &fcmocks.MockPeer{MockName: "Peer2", MockURL: "http://peer2.com", Status: 200, MockMSP: "other"}
+
+	lc := setupLifecycleClient([]fab.Peer{peer1, peer2}, t)
+
+	// The commit is endorsed by the peers of all organizations
+	_, err := lc.CommitChaincodeDefinition(newTestDefinition())
+	require.NoError(t, err)
+	assert.Equal(t, 1, peer1.ProcessProposalCalls)
+	assert.Equal(t, 1, peer2.ProcessProposalCalls)
+
+	_, err = lc.CommitChaincodeDefinition(newTestDefinition(), WithTargets(peer2))
+	require.NoError(t, err)
+	assert.Equal(t, 1, peer1.ProcessProposalCalls)
+	assert.Equal(t, 2, peer2.ProcessProposalCalls)
+
+	peer2.Error = errors.New("endorsement failure")
+	_, err = lc.CommitChaincodeDefinition(newTestDefinition(), WithTargets(peer2))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "endorsement failure")
+}
+
+func TestCheckCommitReadiness(t *testing.T) {
+	payload, err := proto.Marshal(&lb.CheckCommitReadinessResult{Approvals: map[string]bool{"test": true, "other": false}})
+	require.NoError(t, err)
+
+	peer1 := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", Status: 200, MockMSP: "test", Payload: payload}
+	lc := setupLifecycleClient([]fab.Peer{peer1}, t)
+
+	approvals, err := lc.CheckCommitReadiness(newTestDefinition())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"test": true, "other": false}, approvals)
+}
+
+func TestQueryApprovedChaincodeDefinition(t *testing.T) {
+	def := newTestDefinition()
+	policyBytes, err := MarshalApplicationPolicy(def.SignaturePolicy, "")
+	require.NoError(t, err)
+
+	payload, err := proto.Marshal(&lb.QueryApprovedChaincodeDefinitionResult{
+		Sequence:            def.Sequence,
+		Version:             def.Version,
+		ValidationParameter: policyBytes,
+		InitRequired:        true,
+		Source:              &lb.ChaincodeSource{Type: &lb.ChaincodeSource_LocalPackage{LocalPackage: &lb.ChaincodeSource_Local{PackageId: "mycc_1:hash"}}},
+	})
+	require.NoError(t, err)
+
+	peer1 := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", Status: 200, MockMSP: "test", Payload: payload}
+	lc := setupLifecycleClient([]fab.Peer{peer1}, t)
+
+	approved, err := lc.QueryApprovedChaincodeDefinition("mycc", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "mycc", approved.Name)
+	assert.Equal(t, "v1", approved.Version)
+	assert.Equal(t, int64(1), approved.Sequence)
+	assert.True(t, approved.InitRequired)
+	assert.True(t, proto.Equal(def.SignaturePolicy, approved.SignaturePolicy))
+	assert.Equal(t, "mycc_1:hash", approved.PackageID)
+
+	_, err = lc.QueryApprovedChaincodeDefinition("", 0)
+	assert.EqualError(t, err, "chaincode name is required")
+}
+
+func TestNoTargets(t *testing.T) {
+	peer1 := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", Status: 200, MockMSP: "other"}
+	lc := setupLifecycleClient([]fab.Peer{peer1}, t)
+
+	_, err := lc.ApproveChaincodeDefinition(newTestDefinition(), "")
+	s, ok := status.FromError(err)
+	assert.True(t, ok, "expected status error")
+	assert.EqualValues(t, status.NoPeersFound.ToInt32(), s.Code)
+
+	_, err = lc.ApproveChaincodeDefinition(newTestDefinition(), "", WithTargets(peer1, nil))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "target is nil")
+}
+
+func newTestDefinition() *ChaincodeDefinition {
+	return &ChaincodeDefinition{
+		Name:            "mycc",
+		Version:         "v1",
+		Sequence:        1,
+		SignaturePolicy: cauthdsl.SignedByAnyMember([]string{"test", "other"}),
+	}
+}
+
+func setupLifecycleClient(peers []fab.Peer, t *testing.T) *Client {
+	user := mspmocks.NewMockSigningIdentity("test", "test")
+	ctx := fcmocks.NewMockContext(user)
+
+	orderer := fcmocks.NewMockOrderer("", nil)
+	transactor := txnmocks.MockTransactor{
+		Ctx:       ctx,
+		ChannelID: channelID,
+		Orderers:  []fab.Orderer{orderer},
+	}
+
+	chProvider, err := fcmocks.NewMockChannelProvider(ctx)
+	require.NoError(t, err)
+	chService, err := chProvider.ChannelService(ctx, channelID)
+	require.NoError(t, err)
+	chService.(*fcmocks.MockChannelService).SetTransactor(&transactor)
+	chService.(*fcmocks.MockChannelService).SetDiscovery(txnmocks.NewMockDiscoveryService(nil, peers...))
+	ctx.MockProviderContext.ChannelProvider().(*fcmocks.MockChannelProvider).SetCustomChannelService(chService)
+
+	clientProvider := func() (context.Client, error) {
+		return ctx, nil
+	}
+	channelProvider := func() (context.Channel, error) {
+		return contextImpl.NewChannel(clientProvider, channelID)
+	}
+
+	lc, err := New(channelProvider)
+	require.NoError(t, err)
+
+	lc.verifier = &testVerifier{}
+	return lc
+}
+
+type testVerifier struct {
+	verifyErr error
+}
+
+// Verify checks transaction proposal response
+func (tv *testVerifier) Verify(response *fab.TransactionProposalResponse) error {
+	return tv.verifyErr
+}
+
+// Match matches transaction proposal responses
+func (tv *testVerifier) Match(response []*fab.TransactionProposalResponse) error {
+	return nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"time"
+
+	lb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ExternalChaincodeType is the package type of chaincode that runs outside of the peer and
+	// is launched by an external builder using the package's connection.json
+	ExternalChaincodeType = "external"
+	// CCaaSChaincodeType is the package type of chaincode-as-a-service, which is launched by the
+	// ccaas builder that is built into the peer
+	CCaaSChaincodeType = "ccaas"
+
+	metadataFile       = "metadata.json"
+	codeFile           = "code.tar.gz"
+	connectionFile     = "connection.json"
+	packageFileMode    = 0100644
+	packageIDSeparator = ":"
+)
+
+// labelRegexp is the format of package labels accepted by the peer
+var labelRegexp = regexp.MustCompile(`^[[:alnum:]][[:alnum:]_.+-]*$`)
+
+// ChaincodePackage is a Fabric 2.x chaincode install package
+type ChaincodePackage struct {
+	// Label identifies the package (e.g. mycc_1), and is the prefix of the package ID
+	Label string
+	// Type is the chaincode type (e.g. golang, node, java, or ExternalChaincodeType)
+	Type string
+	// Path is the path of the chaincode source (e.g. the Go import path)
+	Path string
+	// Code is the .tar.gz of the chaincode source, or of the connection.json of external chaincode
+	Code []byte
+}
+
+// ExternalConnection contains the connection.json parameters used by the peer to connect to external chaincode
+type ExternalConnection struct {
+	Address            string `json:"address"`
+	DialTimeout        string `json:"dial_timeout,omitempty"`
+	TLSRequired        bool   `json:"tls_required"`
+	ClientAuthRequired bool   `json:"client_auth_required,omitempty"`
+	ClientKey          string `json:"client_key,omitempty"`
+	ClientCert         string `json:"client_cert,omitempty"`
+	RootCert           string `json:"root_cert,omitempty"`
+}
+
+// InstalledChaincode is a chaincode package installed on a peer
+type InstalledChaincode struct {
+	PackageID string
+	Label     string
+	// References are the chaincode definitions that use the package, by channel
+	References map[string][]ChaincodeReference
+}
+
+// ChaincodeReference identifies a chaincode definition that uses an installed package
+type ChaincodeReference struct {
+	Name    string
+	Version string
+}
+
+// packageMetadata is the content of a package's metadata.json
+type packageMetadata struct {
+	Path  string `json:"path"`
+	Type  string `json:"type"`
+	Label string `json:"label"`
+}
+
+// NewExternalChaincodePackage returns the package of chaincode that runs as an external service
+//  Parameters:
+//  label is the package label
+//  connection holds the parameters used by the peer to connect to the chaincode
+//
+//  Returns:
+//  a package of ExternalChaincodeType
+func NewExternalChaincodePackage(label string, connection *ExternalConnection) (*ChaincodePackage, error) {
+	if connection == nil || connection.Address == "" {
+		return nil, errors.New("chaincode address is required")
+	}
+
+	connectionBytes, err := json.Marshal(connection)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of connection.json failed")
+	}
+
+	code, err := tarGz(map[string][]byte{connectionFile: connectionBytes}, connectionFile)
+	if err != nil {
+		return nil, errors.WithMessage(err, "packaging connection.json failed")
+	}
+
+	return &ChaincodePackage{Label: label, Type: ExternalChaincodeType, Code: code}, nil
+}
+
+// BuildExternalChaincodePackage returns a chaincode-as-a-service install package
+//  Parameters:
+//  label is the package label
+//  address is the host:port on which the chaincode service listens
+//  tlsRequired indicates whether the peer connects to the chaincode service using TLS
+//
+//  Returns:
+//  the .tar.gz install package
+func BuildExternalChaincodePackage(label, address string, tlsRequired bool) ([]byte, error) {
+	pkg, err := NewExternalChaincodePackage(label, &ExternalConnection{Address: address, TLSRequired: tlsRequired})
+	if err != nil {
+		return nil, err
+	}
+	pkg.Type = CCaaSChaincodeType
+	return pkg.Bytes()
+}
+
+// Bytes returns the install package (a .tar.gz containing metadata.json and code.tar.gz)
+func (p *ChaincodePackage) Bytes() ([]byte, error) {
+	if !labelRegexp.MatchString(p.Label) {
+		return nil, errors.Errorf("invalid package label '%s'", p.Label)
+	}
+	if p.Type == "" {
+		return nil, errors.New("chaincode type is required")
+	}
+
+	metadata, err := json.Marshal(&packageMetadata{Path: p.Path, Type: p.Type, Label: p.Label})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of metadata.json failed")
+	}
+
+	return tarGz(map[string][]byte{metadataFile: metadata, codeFile: p.Code}, metadataFile, codeFile)
+}
+
+func newInstalledChaincode(cc *lb.QueryInstalledChaincodesResult_InstalledChaincode) *InstalledChaincode {
+	installed := &InstalledChaincode{
+		PackageID:  cc.PackageId,
+		Label:      cc.Label,
+		References: make(map[string][]ChaincodeReference),
+	}
+	for channelID, refs := range cc.References {
+		for _, ref := range refs.GetChaincodes() {
+			installed.References[channelID] = append(installed.References[channelID], ChaincodeReference{Name: ref.Name, Version: ref.Version})
+		}
+	}
+	return installed
+}
+
+// packageID returns the ID that the peer assigns to the given install package
+func packageID(label string, pkgBytes []byte) string {
+	hash := sha256.Sum256(pkgBytes)
+	return label + packageIDSeparator + hex.EncodeToString(hash[:])
+}
+
+// tarGz returns a .tar.gz of the given files in the given order (so that the package ID is deterministic)
+func tarGz(files map[string][]byte, names ...string) ([]byte, error) {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	for _, name := range names {
+		content := files[name]
+		header := &tar.Header{
+			Name:    name,
+			Size:    int64(len(content)),
+			Mode:    packageFileMode,
+			ModTime: time.Time{},
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return nil, errors.Wrapf(err, "writing header of %s failed", name)
+		}
+		if _, err := tw.Write(content); err != nil {
+			return nil, errors.Wrapf(err, "writing %s failed", name)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, errors.Wrap(err, "closing tar failed")
+	}
+	if err := gw.Close(); err != nil {
+		return nil, errors.Wrap(err, "closing gzip failed")
+	}
+	return buf.Bytes(), nil
+}
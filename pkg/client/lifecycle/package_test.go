@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package lifecycle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExternalChaincodePackage(t *testing.T) {
+	pkg, err := NewExternalChaincodePackage("mycc_1", &ExternalConnection{Address: "mycc:9999", DialTimeout: "10s"})
+	require.NoError(t, err)
+	assert.Equal(t, ExternalChaincodeType, pkg.Type)
+
+	pkgBytes, err := pkg.Bytes()
+	require.NoError(t, err)
+
+	files := untarGz(t, pkgBytes)
+	metadata := packageMetadata{}
+	require.NoError(t, json.Unmarshal(files[metadataFile], &metadata))
+	assert.Equal(t, packageMetadata{Type: ExternalChaincodeType, Label: "mycc_1"}, metadata)
+
+	code := untarGz(t, files[codeFile])
+	connection := ExternalConnection{}
+	require.NoError(t, json.Unmarshal(code[connectionFile], &connection))
+	assert.Equal(t, "mycc:9999", connection.Address)
+	assert.Equal(t, "10s", connection.DialTimeout)
+
+	// The package ID only depends on the package content
+	pkgBytes2, err := pkg.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, packageID(pkg.Label, pkgBytes), packageID(pkg.Label, pkgBytes2))
+
+	_, err = NewExternalChaincodePackage("mycc_1", &ExternalConnection{})
+	assert.EqualError(t, err, "chaincode address is required")
+}
+
+func TestBuildExternalChaincodePackage(t *testing.T) {
+	pkgBytes, err := BuildExternalChaincodePackage("mycc_1", "mycc:9999", true)
+	require.NoError(t, err)
+
+	files := untarGz(t, pkgBytes)
+	metadata := packageMetadata{}
+	require.NoError(t, json.Unmarshal(files[metadataFile], &metadata))
+	assert.Equal(t, packageMetadata{Type: CCaaSChaincodeType, Label: "mycc_1"}, metadata)
+
+	code := untarGz(t, files[codeFile])
+	connection := ExternalConnection{}
+	require.NoError(t, json.Unmarshal(code[connectionFile], &connection))
+	assert.Equal(t, ExternalConnection{Address: "mycc:9999", TLSRequired: true}, connection)
+
+	_, err = BuildExternalChaincodePackage("mycc_1", "", false)
+	assert.EqualError(t, err, "chaincode address is required")
+
+	_, err = BuildExternalChaincodePackage("my cc", "mycc:9999", false)
+	assert.EqualError(t, err, "invalid package label 'my cc'")
+}
+
+func TestChaincodePackageBytes(t *testing.T) {
+	_, err := (&ChaincodePackage{Label: "", Type: "golang"}).Bytes()
+	assert.EqualError(t, err, "invalid package label ''")
+
+	_, err = (&ChaincodePackage{Label: "mycc_1"}).Bytes()
+	assert.EqualError(t, err, "chaincode type is required")
+
+	pkgBytes, err := (&ChaincodePackage{Label: "mycc_1", Type: "golang", Path: "github.com/example/mycc", Code: []byte("code")}).Bytes()
+	require.NoError(t, err)
+	files := untarGz(t, pkgBytes)
+	assert.Equal(t, []byte("code"), files[codeFile])
+}
+
+func untarGz(t *testing.T, b []byte) map[string][]byte {
+	gr, err := gzip.NewReader(bytes.NewReader(b))
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+
+	files := make(map[string][]byte)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		require.NoError(t, err)
+		files[header.Name], err = ioutil.ReadAll(tr)
+		require.NoError(t, err)
+	}
+}
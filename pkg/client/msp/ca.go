@@ -6,12 +6,32 @@ SPDX-License-Identifier: Apache-2.0
 
 package msp
 
+import (
+	"github.com/pkg/errors"
+)
+
 // AttributeRequest is a request for an attribute.
 type AttributeRequest struct {
 	Name     string
 	Optional bool
 }
 
+// EnrollmentRequest defines a single enrollment submitted through BatchEnroll
+type EnrollmentRequest struct {
+	// EnrollmentID is the enrollment ID of a registered user
+	EnrollmentID string
+	// Options are the enrollment options (secret, profile, etc.) for this user
+	Options []EnrollmentOption
+}
+
+// EnrollmentResult holds the outcome of a single enrollment submitted through BatchEnroll
+type EnrollmentResult struct {
+	// EnrollmentID is the enrollment ID of the user
+	EnrollmentID string
+	// Err is the error returned by the enrollment, nil if it succeeded
+	Err error
+}
+
 // RegistrationRequest defines the attributes required to register a user with the CA
 type RegistrationRequest struct {
 	// Name is the unique name of the identity
@@ -55,6 +75,24 @@ type RevocationRequest struct {
 	Reason string
 	// CAName is the name of the CA to connect to
 	CAName string
+	// GenCRL specifies whether to include the updated CRL in the response
+	GenCRL bool
+}
+
+// NewUserRevocationRequest returns a request that revokes all certificates of the given identity
+func NewUserRevocationRequest(enrollmentID, reason string) (*RevocationRequest, error) {
+	if enrollmentID == "" {
+		return nil, errors.New("enrollment ID is required")
+	}
+	return &RevocationRequest{Name: enrollmentID, Reason: reason}, nil
+}
+
+// NewCertRevocationRequest returns a request that revokes the certificate with the given serial number and AKI
+func NewCertRevocationRequest(serial, aki, reason string) (*RevocationRequest, error) {
+	if serial == "" || aki == "" {
+		return nil, errors.New("serial and AKI are required")
+	}
+	return &RevocationRequest{Serial: serial, AKI: aki, Reason: reason}, nil
 }
 
 // RevocationResponse represents response from the server for a revocation request
@@ -73,6 +111,26 @@ type RevokedCert struct {
 	AKI string
 }
 
+// BulkRevocationResponse holds the outcome of RevokeByAffiliation
+type BulkRevocationResponse struct {
+	// Results holds the outcome of the revocation of each identity of the affiliation
+	Results []IdentityRevocationResult
+	// CRL is PEM-encoded certificate revocation list (CRL) that contains all unexpired revoked certificates
+	CRL []byte
+}
+
+// IdentityRevocationResult holds the outcome of the revocation of a single identity by RevokeByAffiliation
+type IdentityRevocationResult struct {
+	// ID is the enrollment ID of the identity
+	ID string
+	// RevokedCerts holds the certificates of the identity that were revoked
+	RevokedCerts []RevokedCert
+	// AlreadyRevoked is true if the identity had no certificates left to revoke
+	AlreadyRevoked bool
+	// Err is the error returned by the revocation, nil if it succeeded
+	Err error
+}
+
 // IdentityRequest represents the request to add/update identity to the fabric-ca-server
 type IdentityRequest struct {
 
@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+
+	mspctx "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	mspapi "github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
+)
+
+// caClientCache holds the CA clients created by the MSP client, keyed by organization and CA name.
+// Entries created for another CA config (see caConfigHash) or for an older config version are
+// evicted when they are next accessed.
+type caClientCache struct {
+	mutex   sync.RWMutex
+	version uint64
+	clients map[string]caClientCacheEntry
+}
+
+type caClientCacheEntry struct {
+	client     mspapi.CAClient
+	configHash string
+	version    uint64
+}
+
+func newCAClientCache() *caClientCache {
+	return &caClientCache{clients: make(map[string]caClientCacheEntry)}
+}
+
+// get returns the cached CA client for the given organization and CA, or creates and caches one
+// if there is none for the given CA config hash and the current config version
+func (c *caClientCache) get(orgName, caName, configHash string, create func() (mspapi.CAClient, error)) (mspapi.CAClient, error) {
+	key := orgName + "/" + caName
+
+	c.mutex.RLock()
+	entry, ok := c.clients[key]
+	current := ok && c.isCurrent(entry, configHash)
+	c.mutex.RUnlock()
+	if current {
+		return entry.client, nil
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok = c.clients[key]
+	if ok && c.isCurrent(entry, configHash) {
+		return entry.client, nil
+	}
+	if ok {
+		logger.Debugf("Evicting CA client for [%s] created for config version %d or another CA config", key, entry.version)
+		delete(c.clients, key)
+	}
+
+	client, err := create()
+	if err != nil {
+		return nil, err
+	}
+	c.clients[key] = caClientCacheEntry{client: client, configHash: configHash, version: c.version}
+	return client, nil
+}
+
+func (c *caClientCache) isCurrent(entry caClientCacheEntry, configHash string) bool {
+	return entry.version == c.version && entry.configHash == configHash
+}
+
+// invalidate increments the config version so that all cached CA clients are recreated
+func (c *caClientCache) invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.version++
+}
+
+// caConfigHash returns a hash of the CA config settings that the CA client is created from:
+// the URL and name of the CA, the TLS settings and the registrar
+func caConfigHash(caConfig *mspctx.CAConfig) string {
+	if caConfig == nil {
+		return ""
+	}
+	h := sha256.New()
+	fmt.Fprintf(h, "%q %q %q %q %q %q %q", caConfig.URL, caConfig.CAName,
+		caConfig.TLSCAServerCerts, caConfig.TLSCAClientCert, caConfig.TLSCAClientKey,
+		caConfig.Registrar.EnrollID, caConfig.Registrar.EnrollSecret)
+
+	// Map iteration order is random, so the gRPC options are hashed in the order of their keys
+	keys := make([]string, 0, len(caConfig.GRPCOptions))
+	for k := range caConfig.GRPCOptions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(h, " %q=%v", k, caConfig.GRPCOptions[k])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
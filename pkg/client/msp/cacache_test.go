@@ -0,0 +1,153 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mspctx "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	fcmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspapi "github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmspapi"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCAClientCache(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	created := 0
+	create := func() (mspapi.CAClient, error) {
+		created++
+		return mockmspapi.NewMockCAClient(mockCtrl), nil
+	}
+
+	cache := newCAClientCache()
+
+	ca1, err := cache.get("org1", "ca1", "hash1", create)
+	require.NoError(t, err)
+	ca2, err := cache.get("org1", "ca1", "hash1", create)
+	require.NoError(t, err)
+	assert.True(t, ca1 == ca2, "expecting cached CA client")
+	assert.Equal(t, 1, created)
+
+	ca3, err := cache.get("org1", "ca2", "hash1", create)
+	require.NoError(t, err)
+	assert.False(t, ca1 == ca3, "expecting a CA client per CA")
+	assert.Equal(t, 2, created)
+
+	// Clients are recreated after the config version changes
+	cache.invalidate()
+	ca4, err := cache.get("org1", "ca1", "hash1", create)
+	require.NoError(t, err)
+	assert.False(t, ca1 == ca4, "expecting stale CA client to be evicted")
+	assert.Equal(t, 3, created)
+
+	// Clients are recreated after the CA config changes
+	ca5, err := cache.get("org1", "ca1", "hash2", create)
+	require.NoError(t, err)
+	assert.False(t, ca4 == ca5, "expecting CA client of the previous CA config to be evicted")
+	assert.Equal(t, 4, created)
+
+	// Failures are not cached
+	_, err = cache.get("org2", "ca1", "hash1", func() (mspapi.CAClient, error) { return nil, errors.New("create failed") })
+	assert.Error(t, err)
+	_, err = cache.get("org2", "ca1", "hash1", create)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, created)
+}
+
+func TestCAConfigHash(t *testing.T) {
+	caConfig := &mspctx.CAConfig{URL: "https://ca.org1.example.com:7054", CAName: "ca.org1.example.com"}
+	caConfig.Registrar.EnrollID = "admin"
+	hash := caConfigHash(caConfig)
+	assert.Equal(t, hash, caConfigHash(caConfig))
+
+	updated := *caConfig
+	updated.URL = "https://ca2.org1.example.com:7054"
+	assert.NotEqual(t, hash, caConfigHash(&updated))
+
+	updated = *caConfig
+	updated.TLSCAServerCerts = [][]byte{[]byte("cert")}
+	assert.NotEqual(t, hash, caConfigHash(&updated))
+
+	updated = *caConfig
+	updated.Registrar.EnrollSecret = "adminpw"
+	assert.NotEqual(t, hash, caConfigHash(&updated))
+
+	// The hash of the gRPC options does not depend on the order of the map entries
+	updated = *caConfig
+	updated.GRPCOptions = make(map[string]interface{})
+	other := *caConfig
+	other.GRPCOptions = make(map[string]interface{})
+	for i := 0; i < 20; i++ {
+		updated.GRPCOptions[fmt.Sprintf("option%d", i)] = i
+		other.GRPCOptions[fmt.Sprintf("option%d", 19-i)] = 19 - i
+	}
+	grpcHash := caConfigHash(&updated)
+	assert.NotEqual(t, hash, grpcHash)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, grpcHash, caConfigHash(&other))
+	}
+	other.GRPCOptions["option0"] = "changed"
+	assert.NotEqual(t, grpcHash, caConfigHash(&other))
+}
+
+func TestCAClientCacheConcurrency(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var mutex sync.Mutex
+	created := 0
+	create := func() (mspapi.CAClient, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		created++
+		return mockmspapi.NewMockCAClient(mockCtrl), nil
+	}
+
+	cache := newCAClientCache()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cache.get("org1", "ca1", "hash1", create)
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, created)
+}
+
+// newClientWithCAClient returns a client that uses the given CA client
+func newClientWithCAClient(ca mspapi.CAClient) *Client {
+	c := &Client{
+		ctx:         fcmocks.NewMockContext(mockmsp.NewMockSigningIdentity("user", "Org1MSP")),
+		orgName:     "org1",
+		caName:      "ca1",
+		metrics:     noopMetrics{},
+		retryPolicy: noRetryPolicy,
+		caClients:   newCAClientCache(),
+	}
+	addCAClient(c, "org1", "ca1", ca)
+	return c
+}
+
+// addCAClient adds the CA client for the given organization and CA to the CA clients of the client
+func addCAClient(c *Client, orgName, caName string, ca mspapi.CAClient) {
+	caConfig, _ := c.ctx.IdentityConfig().CAConfig(orgName)
+	c.caClients.clients[orgName+"/"+caName] = caClientCacheEntry{client: ca, configHash: caConfigHash(caConfig)}
+}
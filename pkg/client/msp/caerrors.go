@@ -0,0 +1,189 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	reqContext "context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	mspapi "github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrCAAuthenticationFailure indicates the CA could not authenticate the caller,
+	// for example because of an invalid enrollment secret
+	ErrCAAuthenticationFailure = &CAError{Code: 20, Message: "Authentication failure"}
+
+	// ErrCAIdentityNotFound indicates the CA could not find the identity
+	ErrCAIdentityNotFound = &CAError{Code: 63, Message: "Failed to get user"}
+
+	// ErrCAAuthorizationFailure indicates the caller is not authorized to perform the request
+	ErrCAAuthorizationFailure = &CAError{Code: 71, Message: "Authorization failure"}
+
+	// ErrCAIdentityAlreadyExists indicates the identity is already registered with the CA
+	ErrCAIdentityAlreadyExists = &CAError{Code: 74, Message: "Identity is already registered"}
+)
+
+// caErrorPattern matches the error code and message of a Fabric CA server error
+var caErrorPattern = regexp.MustCompile(`Error Code: (\d+) - ([^\n]*)`)
+
+// CAError is an error reported by the Fabric CA server. The error code of an
+// error returned by the client, wrapped or not, is checked with IsCAError, e.g.
+//  IsCAError(err, ErrCAIdentityAlreadyExists.Code)
+type CAError struct {
+	// Code is the Fabric CA error code
+	Code int
+	// Message is the error message reported by the CA
+	Message string
+
+	err error
+}
+
+// Error returns the original error message, including any context added by the SDK
+func (e *CAError) Error() string {
+	if e.err != nil {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("Error Code: %d - %s", e.Code, e.Message)
+}
+
+// Is returns true if target is a CAError with the same code
+func (e *CAError) Is(target error) bool {
+	t, ok := target.(*CAError)
+	return ok && t.Code == e.Code
+}
+
+// IsCAError returns true if the cause of err is a CAError with the given Fabric CA error code
+func IsCAError(err error, code int) bool {
+	caErr, ok := errors.Cause(err).(*CAError)
+	return ok && caErr.Code == code
+}
+
+// toCAError returns a CAError if err was reported by the CA server, otherwise err itself.
+// The Fabric CA client only reports server errors as text, so the first error code
+// in the message is used.
+func toCAError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*CAError); ok {
+		return err
+	}
+	m := caErrorPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	code, convErr := strconv.Atoi(m[1])
+	if convErr != nil {
+		return err
+	}
+	return &CAError{Code: code, Message: m[2], err: err}
+}
+
+// caErrorClient converts the errors returned by a CA client to CAErrors
+type caErrorClient struct {
+	ca mspapi.CAClient
+}
+
+// WithContext binds the requests of the underlying CA client to the given context
+func (c *caErrorClient) WithContext(ctx reqContext.Context) (mspapi.CAClient, error) {
+	cc, ok := c.ca.(mspapi.ContextCAClient)
+	if !ok {
+		return nil, errors.New("CA client does not support request contexts")
+	}
+	ca, err := cc.WithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &caErrorClient{ca: ca}, nil
+}
+
+func (c *caErrorClient) Enroll(request *mspapi.EnrollmentRequest) error {
+	return toCAError(c.ca.Enroll(request))
+}
+
+func (c *caErrorClient) Reenroll(request *mspapi.ReenrollmentRequest) error {
+	return toCAError(c.ca.Reenroll(request))
+}
+
+func (c *caErrorClient) Register(request *mspapi.RegistrationRequest) (string, error) {
+	secret, err := c.ca.Register(request)
+	return secret, toCAError(err)
+}
+
+func (c *caErrorClient) Revoke(request *mspapi.RevocationRequest) (*mspapi.RevocationResponse, error) {
+	resp, err := c.ca.Revoke(request)
+	return resp, toCAError(err)
+}
+
+func (c *caErrorClient) GetCAInfo() (*mspapi.GetCAInfoResponse, error) {
+	resp, err := c.ca.GetCAInfo()
+	return resp, toCAError(err)
+}
+
+func (c *caErrorClient) GetCRL(caname string) ([]byte, error) {
+	crl, err := c.ca.GetCRL(caname)
+	return crl, toCAError(err)
+}
+
+func (c *caErrorClient) GetIdemixCredential(enrollmentID, caname string, provider mspapi.IdemixProvider) (*mspapi.IdemixCredentialResponse, error) {
+	resp, err := c.ca.GetIdemixCredential(enrollmentID, caname, provider)
+	return resp, toCAError(err)
+}
+
+func (c *caErrorClient) CreateIdentity(request *mspapi.IdentityRequest) (*mspapi.IdentityResponse, error) {
+	resp, err := c.ca.CreateIdentity(request)
+	return resp, toCAError(err)
+}
+
+func (c *caErrorClient) GetIdentity(id, caname string) (*mspapi.IdentityResponse, error) {
+	resp, err := c.ca.GetIdentity(id, caname)
+	return resp, toCAError(err)
+}
+
+func (c *caErrorClient) ModifyIdentity(request *mspapi.IdentityRequest) (*mspapi.IdentityResponse, error) {
+	resp, err := c.ca.ModifyIdentity(request)
+	return resp, toCAError(err)
+}
+
+func (c *caErrorClient) RemoveIdentity(request *mspapi.RemoveIdentityRequest) (*mspapi.IdentityResponse, error) {
+	resp, err := c.ca.RemoveIdentity(request)
+	return resp, toCAError(err)
+}
+
+func (c *caErrorClient) GetAllIdentities(caname string) ([]*mspapi.IdentityResponse, error) {
+	resp, err := c.ca.GetAllIdentities(caname)
+	return resp, toCAError(err)
+}
+
+func (c *caErrorClient) GetAffiliation(affiliation, caname string) (*mspapi.AffiliationResponse, error) {
+	resp, err := c.ca.GetAffiliation(affiliation, caname)
+	return resp, toCAError(err)
+}
+
+func (c *caErrorClient) GetAllAffiliations(caname string) (*mspapi.AffiliationResponse, error) {
+	resp, err := c.ca.GetAllAffiliations(caname)
+	return resp, toCAError(err)
+}
+
+func (c *caErrorClient) AddAffiliation(request *mspapi.AffiliationRequest) (*mspapi.AffiliationResponse, error) {
+	resp, err := c.ca.AddAffiliation(request)
+	return resp, toCAError(err)
+}
+
+func (c *caErrorClient) ModifyAffiliation(request *mspapi.ModifyAffiliationRequest) (*mspapi.AffiliationResponse, error) {
+	resp, err := c.ca.ModifyAffiliation(request)
+	return resp, toCAError(err)
+}
+
+func (c *caErrorClient) RemoveAffiliation(request *mspapi.AffiliationRequest) (*mspapi.AffiliationResponse, error) {
+	resp, err := c.ca.RemoveAffiliation(request)
+	return resp, toCAError(err)
+}
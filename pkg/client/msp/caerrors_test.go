@@ -0,0 +1,81 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mspapi "github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmspapi"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToCAError(t *testing.T) {
+	assert.Nil(t, toCAError(nil))
+
+	plain := errors.New("connection refused")
+	assert.Equal(t, plain, toCAError(plain))
+
+	err := toCAError(errors.Wrap(errors.New("Response from server: Error Code: 20 - Authentication failure\n"), "enroll failed"))
+	caErr, ok := err.(*CAError)
+	require.True(t, ok)
+	assert.Equal(t, 20, caErr.Code)
+	assert.Equal(t, "Authentication failure", caErr.Message)
+	assert.Equal(t, "enroll failed: Response from server: Error Code: 20 - Authentication failure\n", caErr.Error())
+	assert.True(t, caErr.Is(ErrCAAuthenticationFailure))
+	assert.False(t, caErr.Is(ErrCAAuthorizationFailure))
+	assert.False(t, caErr.Is(plain))
+
+	// Converting again has no effect
+	assert.Equal(t, err, toCAError(err))
+}
+
+func TestCAErrorFromClient(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ca := mockmspapi.NewMockCAClient(mockCtrl)
+	c := newClientWithCAClient(ca)
+
+	ca.EXPECT().Register(gomock.Any()).Return("", errors.New("Response from server: Error Code: 74 - Identity 'user1' is already registered"))
+	_, err := c.Register(&RegistrationRequest{Name: "user1"})
+	caErr, ok := err.(*CAError)
+	require.True(t, ok)
+	assert.True(t, caErr.Is(ErrCAIdentityAlreadyExists))
+
+	// Wrapped CA errors can be retrieved with errors.Cause
+	ca.EXPECT().Revoke(gomock.Any()).Return(nil, errors.New("Response from server: Error Code: 71 - Authorization failure"))
+	ca.EXPECT().RemoveIdentity(gomock.Any()).Return(&mspapi.IdentityResponse{ID: "user1"}, nil)
+	err = c.RevokeAndRemoveIdentity("user1")
+	caErr, ok = errors.Cause(err).(*CAError)
+	require.True(t, ok)
+	assert.True(t, caErr.Is(ErrCAAuthorizationFailure))
+}
+
+func TestIsCAError(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ca := mockmspapi.NewMockCAClient(mockCtrl)
+	c := newClientWithCAClient(ca)
+
+	assert.False(t, IsCAError(nil, ErrCAIdentityAlreadyExists.Code))
+	assert.False(t, IsCAError(errors.New("Error Code: 74 - not converted"), ErrCAIdentityAlreadyExists.Code))
+
+	// RegisterAndEnroll wraps the error of the registration
+	ca.EXPECT().Register(gomock.Any()).Return("", errors.New("Response from server: Error Code: 74 - Identity 'user1' is already registered"))
+	_, err := c.RegisterAndEnroll(&RegistrationRequest{Name: "user1"})
+	require.Error(t, err)
+	_, ok := err.(*CAError)
+	require.False(t, ok, "expecting a wrapped CA error")
+	assert.True(t, IsCAError(err, ErrCAIdentityAlreadyExists.Code))
+	assert.False(t, IsCAError(err, ErrCAAuthorizationFailure.Code))
+	assert.True(t, IsCAError(errors.Wrap(err, "onboarding failed"), ErrCAIdentityAlreadyExists.Code))
+}
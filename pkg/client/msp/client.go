@@ -6,7 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 
 // Package msp enables creation and update of users on a Fabric network.
 // Msp client supports the following actions:
-// Enroll, Reenroll, Register,  Revoke and GetSigningIdentity.
+// Enroll, BatchEnroll, Reenroll, Register,  Revoke and GetSigningIdentity.
 //
 //  Basic Flow:
 //  1) Prepare client context
@@ -16,22 +16,54 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"bytes"
+	reqContext "context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
 	"fmt"
-
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
+	fabricCaUtil "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
+	bccspUtils "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	mspctx "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/common/pkcs11"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp"
 	mspapi "github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
 	"github.com/pkg/errors"
 )
 
+var logger = logging.NewLogger("fabsdk/client")
+
+const defaultEnrollmentWorkers = 10
+
 // Client enables access to Client services
 type Client struct {
-	orgName string
-	caName  string
-	ctx     context.Client
+	orgName           string
+	caName            string
+	ctx               context.Client
+	enrollmentWorkers int
+	ocspResponderURL  string
+	ocspCacheTTL      time.Duration
+	ocsp              *ocspChecker
+	keyExportAllowed  bool
+	metrics           Metrics
+	retryPolicy       RetryPolicy
+	tlsClientCert     *tls.Certificate
+	caClients         *caClientCache
+	identityTypes     []string
+	idemixProvider    IdemixProvider
 }
 
 // ClientOption describes a functional parameter for the New constructor
@@ -45,9 +77,132 @@ func WithOrg(orgName string) ClientOption {
 	}
 }
 
+// WithCAName option sets the name of the CA that the client targets.
+// If not provided, the CA name configured for the organization is used.
+func WithCAName(caName string) ClientOption {
+	return func(msp *Client) error {
+		if caName == "" {
+			return errors.New("CA name is empty")
+		}
+		msp.caName = caName
+		return nil
+	}
+}
+
+// WithKeyExportAllowed option controls whether ExportSigningIdentity may export
+// private keys (default false)
+func WithKeyExportAllowed(allowed bool) ClientOption {
+	return func(msp *Client) error {
+		msp.keyExportAllowed = allowed
+		return nil
+	}
+}
+
+// WithMetrics option sets the metrics that record the outcome of enrollments,
+// registrations and revocations performed by the client
+func WithMetrics(metrics Metrics) ClientOption {
+	return func(msp *Client) error {
+		if metrics == nil {
+			return errors.New("metrics must not be nil")
+		}
+		msp.metrics = metrics
+		return nil
+	}
+}
+
+// WithRetry option sets the policy used to retry enrollments, re-enrollments,
+// registrations and revocations that failed with a transient error.
+// If ShouldRetry is not set, IsTransientError is used.
+func WithRetry(policy RetryPolicy) ClientOption {
+	return func(msp *Client) error {
+		if err := policy.validate(); err != nil {
+			return errors.WithMessage(err, "invalid retry policy")
+		}
+		if policy.ShouldRetry == nil {
+			policy.ShouldRetry = IsTransientError
+		}
+		msp.retryPolicy = policy
+		return nil
+	}
+}
+
+// WithIdentityTypes option sets the identity types accepted by the CA (e.g. "client", "peer").
+// Fabric CA does not report the types it accepts, so Register and ValidateRegistrationRequest
+// only reject unknown types if this option is provided.
+func WithIdentityTypes(types ...string) ClientOption {
+	return func(msp *Client) error {
+		if len(types) == 0 {
+			return errors.New("at least one identity type is required")
+		}
+		msp.identityTypes = types
+		return nil
+	}
+}
+
+// WithTLSClientCert option sets the certificate presented to the CA when it requires
+// mutual TLS, instead of the client key and certificate configured for the organization's CA.
+// The certificate and key of the SDK identity can be reused, e.g. with tls.X509KeyPair,
+// as long as the Fabric CA server trusts their issuer for TLS client authentication.
+func WithTLSClientCert(cert tls.Certificate) ClientOption {
+	return func(msp *Client) error {
+		if len(cert.Certificate) == 0 || cert.PrivateKey == nil {
+			return errors.New("TLS client certificate and private key are required")
+		}
+		msp.tlsClientCert = &cert
+		return nil
+	}
+}
+
+// WithEnrollmentWorkers option sets the maximum number of enrollments
+// that are submitted concurrently by BatchEnroll
+func WithEnrollmentWorkers(workers int) ClientOption {
+	return func(msp *Client) error {
+		if workers <= 0 {
+			return errors.New("number of enrollment workers must be greater than zero")
+		}
+		msp.enrollmentWorkers = workers
+		return nil
+	}
+}
+
+// WithIdemixProvider option sets the Idemix implementation used by GetIdemixCredential and VerifyIdemixProof
+func WithIdemixProvider(provider IdemixProvider) ClientOption {
+	return func(msp *Client) error {
+		if provider == nil {
+			return errors.New("Idemix provider is required")
+		}
+		msp.idemixProvider = provider
+		return nil
+	}
+}
+
+// WithOCSPCheck option enables an OCSP revocation check of the enrollment
+// certificate in GetSigningIdentity, using the given OCSP responder
+func WithOCSPCheck(responderURL string) ClientOption {
+	return func(msp *Client) error {
+		if responderURL == "" {
+			return errors.New("OCSP responder URL is required")
+		}
+		msp.ocspResponderURL = responderURL
+		return nil
+	}
+}
+
+// WithOCSPCacheTTL option sets how long OCSP responses are cached
+// per certificate serial number (default 5 minutes)
+func WithOCSPCacheTTL(ttl time.Duration) ClientOption {
+	return func(msp *Client) error {
+		msp.ocspCacheTTL = ttl
+		return nil
+	}
+}
+
 // opts allows the user to specify more advanced request options
 type requestOptions struct {
-	CA string
+	CA                string
+	ReplaceAttributes bool
+	ParentContext     reqContext.Context
+	Timeout           time.Duration
 }
 
 // RequestOption func for each Opts argument
@@ -61,6 +216,35 @@ func WithCA(caname string) RequestOption {
 	}
 }
 
+// WithParentContext encapsulates the parent context of the request. If the context is
+// cancelled or its deadline expires before the CA responds, the request to the CA is
+// cancelled and the call returns an error.
+func WithParentContext(parentContext reqContext.Context) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.ParentContext = parentContext
+		return nil
+	}
+}
+
+// WithTimeout sets the time after which the request to the CA is cancelled and the call returns
+// an error if the CA has not responded
+func WithTimeout(timeout time.Duration) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.Timeout = timeout
+		return nil
+	}
+}
+
+// WithReplaceAttributes controls how ModifyIdentity updates attributes. By default, attributes
+// in the request are added or updated and all other attributes are kept. If replace is true,
+// attributes of the identity that are not present in the request are removed.
+func WithReplaceAttributes(replace bool) RequestOption {
+	return func(ctx context.Client, o *requestOptions) error {
+		o.ReplaceAttributes = replace
+		return nil
+	}
+}
+
 // New creates a new Client instance
 func New(clientProvider context.ClientProvider, opts ...ClientOption) (*Client, error) {
 
@@ -70,7 +254,11 @@ func New(clientProvider context.ClientProvider, opts ...ClientOption) (*Client,
 	}
 
 	msp := Client{
-		ctx: ctx,
+		ctx:               ctx,
+		enrollmentWorkers: defaultEnrollmentWorkers,
+		metrics:           noopMetrics{},
+		retryPolicy:       noRetryPolicy,
+		caClients:         newCAClientCache(),
 	}
 
 	for _, param := range opts {
@@ -87,23 +275,72 @@ func New(clientProvider context.ClientProvider, opts ...ClientOption) (*Client,
 		return nil, errors.New("organization is not provided")
 	}
 
-	caConfig, ok := ctx.IdentityConfig().CAConfig(msp.orgName)
-	if ok {
-		msp.caName = caConfig.CAName
+	if msp.ocspResponderURL != "" {
+		ttl := msp.ocspCacheTTL
+		if ttl == 0 {
+			ttl = defaultOCSPCacheTTL
+		}
+		msp.ocsp = newOCSPChecker(msp.ocspResponderURL, ttl)
+	}
+
+	if msp.caName == "" {
+		caConfig, ok := ctx.IdentityConfig().CAConfig(msp.orgName)
+		if ok {
+			msp.caName = caConfig.CAName
+		}
 	}
 
 	networkConfig := ctx.EndpointConfig().NetworkConfig()
-	_, ok = networkConfig.Organizations[strings.ToLower(msp.orgName)]
+	_, ok := networkConfig.Organizations[strings.ToLower(msp.orgName)]
 	if !ok {
 		return nil, fmt.Errorf("non-existent organization: '%s'", msp.orgName)
 	}
+
+	logger.Debugf("Created MSP client for org [%s], CA [%s]", msp.orgName, msp.caName)
 	return &msp, nil
 }
 
-func newCAClient(ctx context.Client, orgName string) (mspapi.CAClient, error) {
+// caClient returns the CA client for the client's organization and CA, creating it on first use.
+// Errors reported by the CA server are returned as CAErrors.
+func (c *Client) caClient() (mspapi.CAClient, error) {
+	return c.caClientForOrg(c.orgName, c.caName)
+}
+
+// caClientForOrg returns the CA client for the given organization and CA, creating it on first use
+// and whenever the CA config of the organization changes
+func (c *Client) caClientForOrg(orgName, caName string) (mspapi.CAClient, error) {
+	caConfig, _ := c.ctx.IdentityConfig().CAConfig(orgName)
+	ca, err := c.caClients.get(orgName, caName, caConfigHash(caConfig), func() (mspapi.CAClient, error) {
+		return c.createCAClient(orgName, caName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &caErrorClient{ca: ca}, nil
+}
+
+// InvalidateCAClients discards the CA clients cached by the client, so that they are
+// recreated from the current config. CA clients are recreated automatically when the CA config
+// of their organization changes, so InvalidateCAClients is only needed to recreate them for
+// changes that are not part of the CA config.
+func (c *Client) InvalidateCAClients() {
+	c.caClients.invalidate()
+}
+
+// createCAClient creates a new CA client for the given organization and CA
+func (c *Client) createCAClient(orgName, caName string) (mspapi.CAClient, error) {
+
+	var opts []msp.CAClientOption
+	if caName != "" {
+		opts = append(opts, msp.WithCAName(caName))
+	}
+	if c.tlsClientCert != nil {
+		opts = append(opts, msp.WithTLSClientCert(*c.tlsClientCert))
+	}
 
-	caClient, err := msp.NewCAClient(orgName, ctx)
+	caClient, err := msp.NewCAClient(orgName, c.ctx, opts...)
 	if err != nil {
+		logger.Debugf("Failed to create CA client for org [%s], CA [%s]: %s", orgName, caName, err)
 		return nil, errors.WithMessage(err, "failed to create CA Client")
 	}
 
@@ -117,6 +354,18 @@ type enrollmentOptions struct {
 	label    string
 	typ      string
 	attrReqs []*AttributeRequest
+	key      core.Key
+	template *pkcs11.ObjectAttributes
+	csr      *mspapi.CSRInfo
+	reqOpts  []RequestOption
+}
+
+// csrInfo returns the CSR overrides, creating them on first use
+func (o *enrollmentOptions) csrInfo() *mspapi.CSRInfo {
+	if o.csr == nil {
+		o.csr = &mspapi.CSRInfo{}
+	}
+	return o.csr
 }
 
 // EnrollmentOption describes a functional parameter for Enroll
@@ -130,7 +379,9 @@ func WithSecret(secret string) EnrollmentOption {
 	}
 }
 
-// WithProfile enrollment option
+// WithProfile enrollment option. The profile selects the signing profile of the CA
+// used to issue the certificate, e.g. "tls" for a TLS certificate with the
+// corresponding key usages. It applies to both enrollment and re-enrollment.
 func WithProfile(profile string) EnrollmentOption {
 	return func(o *enrollmentOptions) error {
 		o.profile = profile
@@ -162,6 +413,101 @@ func WithAttributeRequests(attrReqs []*AttributeRequest) EnrollmentOption {
 	}
 }
 
+// WithCSRHosts enrollment option. The hosts are requested as subject alternative
+// names of the certificate, instead of the local host name. This is typically used
+// together with WithProfile("tls") to enroll TLS certificates of peers and orderers.
+func WithCSRHosts(hosts []string) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		o.csrInfo().Hosts = hosts
+		return nil
+	}
+}
+
+// WithCSRCommonName enrollment option. The common name is requested as the subject
+// of the certificate, instead of the enrollment ID.
+// Note that the Fabric CA server may reject a common name that differs from the enrollment ID.
+func WithCSRCommonName(cn string) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		if cn == "" {
+			return errors.New("common name is required")
+		}
+		o.csrInfo().CN = cn
+		return nil
+	}
+}
+
+// WithCSRKeyType enrollment option. It sets the type of the key pair generated for the
+// enrollment: the algorithm, optionally followed by the key size, i.e. "ecdsa" (P-256),
+// "ecdsa-384", "rsa" (2048 bits), "rsa-3072" or "rsa-4096".
+// It cannot be combined with WithExistingKey.
+func WithCSRKeyType(keyType string) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		kr, err := parseKeyType(keyType)
+		if err != nil {
+			return err
+		}
+		o.csrInfo().KeyRequest = kr
+		return nil
+	}
+}
+
+// defaultKeySizes are the key sizes used when a key type does not specify one
+var defaultKeySizes = map[string]int{
+	"ecdsa": 256,
+	"rsa":   2048,
+}
+
+func parseKeyType(keyType string) (*mspapi.KeyRequest, error) {
+	parts := strings.SplitN(strings.ToLower(keyType), "-", 2)
+	size, ok := defaultKeySizes[parts[0]]
+	if !ok {
+		return nil, errors.Errorf("unsupported key algorithm: '%s'", keyType)
+	}
+	if len(parts) == 2 {
+		var err error
+		size, err = strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, errors.Errorf("invalid key size: '%s'", keyType)
+		}
+	}
+	return &mspapi.KeyRequest{Algo: parts[0], Size: size}, nil
+}
+
+// WithHSMKeyTemplate enrollment option. The key pair is generated in the HSM by the
+// PKCS#11 crypto suite, which must be configured for the SDK, and its objects are
+// created with the attributes of the template. The private key remains in the HSM;
+// only the public key is included in the CSR.
+func WithHSMKeyTemplate(template pkcs11.ObjectAttributes) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		o.template = &template
+		return nil
+	}
+}
+
+// WithExistingKey enrollment option. The given private key is used to sign
+// the CSR instead of generating a new key pair. The key must be available
+// in the SDK's crypto suite key store (for example, imported with a non
+// ephemeral option or held by an HSM) so that the enrolled identity can sign.
+func WithExistingKey(key core.Key) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		if key == nil {
+			return errors.New("key is required")
+		}
+		o.key = key
+		return nil
+	}
+}
+
+// WithRequestOptions sets request options of the enrollment or re-enrollment, such as WithParentContext
+// and WithTimeout, so that the requests to the CA can be cancelled. Retries of failed requests stop when
+// the request context is done.
+func WithRequestOptions(options ...RequestOption) EnrollmentOption {
+	return func(o *enrollmentOptions) error {
+		o.reqOpts = append(o.reqOpts, options...)
+		return nil
+	}
+}
+
 // CreateIdentity creates a new identity with the Fabric CA server. An enrollment secret is returned which can then be used,
 // along with the enrollment ID, to enroll a new identity.
 //  Parameters:
@@ -171,7 +517,7 @@ func WithAttributeRequests(attrReqs []*AttributeRequest) EnrollmentOption {
 //  Return identity info including the secret
 func (c *Client) CreateIdentity(request *IdentityRequest) (*IdentityResponse, error) {
 
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := c.caClient()
 	if err != nil {
 		return nil, err
 	}
@@ -200,14 +546,23 @@ func (c *Client) CreateIdentity(request *IdentityRequest) (*IdentityResponse, er
 }
 
 // ModifyIdentity modifies identity with the Fabric CA server.
+// Attributes that are not present in the request are kept, unless
+// WithReplaceAttributes(true) is passed.
 //  Parameters:
 //  request holds info about identity
+//  options holds optional request options
 //
 //  Returns:
 //  Return updated identity info
-func (c *Client) ModifyIdentity(request *IdentityRequest) (*IdentityResponse, error) {
+func (c *Client) ModifyIdentity(request *IdentityRequest, options ...RequestOption) (*IdentityResponse, error) {
+
+	// Read request options
+	opts, err := c.prepareOptsFromOptions(c.ctx, options...)
+	if err != nil {
+		return nil, err
+	}
 
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := c.caClient()
 	if err != nil {
 		return nil, err
 	}
@@ -217,22 +572,53 @@ func (c *Client) ModifyIdentity(request *IdentityRequest) (*IdentityResponse, er
 		attrs = append(attrs, mspapi.Attribute{Name: request.Attributes[i].Name, Value: request.Attributes[i].Value, ECert: request.Attributes[i].ECert})
 	}
 
+	caName := c.caNameOrDefault(request.CAName)
+	if opts.ReplaceAttributes {
+		result, err := opts.invoke(ca, func(ca mspapi.CAClient) (interface{}, error) {
+			return ca.GetIdentity(request.ID, caName)
+		})
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to retrieve current attributes")
+		}
+		current := result.(*mspapi.IdentityResponse)
+		attrs = append(attrs, removedAttributes(current.Attributes, attrs)...)
+	}
+
 	req := &mspapi.IdentityRequest{
 		ID:             request.ID,
 		Type:           request.Type,
 		MaxEnrollments: request.MaxEnrollments,
 		Affiliation:    request.Affiliation,
 		Attributes:     attrs,
-		CAName:         request.CAName,
+		CAName:         caName,
 		Secret:         request.Secret,
 	}
 
-	response, err := ca.ModifyIdentity(req)
+	result, err := opts.invoke(ca, func(ca mspapi.CAClient) (interface{}, error) {
+		return ca.ModifyIdentity(req)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return getIdentityResponse(response), nil
+	return getIdentityResponse(result.(*mspapi.IdentityResponse)), nil
+}
+
+// removedAttributes returns the attributes in current that are not in requested, with empty values.
+// Fabric CA removes attributes of an identity that are modified to an empty value.
+func removedAttributes(current []mspapi.Attribute, requested []mspapi.Attribute) []mspapi.Attribute {
+	names := make(map[string]bool)
+	for _, a := range requested {
+		names[a.Name] = true
+	}
+
+	var removed []mspapi.Attribute
+	for _, a := range current {
+		if !names[a.Name] {
+			removed = append(removed, mspapi.Attribute{Name: a.Name, Value: ""})
+		}
+	}
+	return removed
 }
 
 // RemoveIdentity removes identity with the Fabric CA server.
@@ -243,7 +629,7 @@ func (c *Client) ModifyIdentity(request *IdentityRequest) (*IdentityResponse, er
 //  Return removed identity info
 func (c *Client) RemoveIdentity(request *RemoveIdentityRequest) (*IdentityResponse, error) {
 
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := c.caClient()
 	if err != nil {
 		return nil, err
 	}
@@ -262,6 +648,29 @@ func (c *Client) RemoveIdentity(request *RemoveIdentityRequest) (*IdentityRespon
 	return getIdentityResponse(response), nil
 }
 
+// RevokeAndRemoveIdentity revokes all certificates of an identity and removes
+// the identity from the Fabric CA server. The identity is removed even if the
+// revocation fails.
+//  Parameters:
+//  enrollmentID is the enrollment ID of the identity
+//
+//  Returns:
+//  a combined error if the revocation or the removal failed
+func (c *Client) RevokeAndRemoveIdentity(enrollmentID string) error {
+	if enrollmentID == "" {
+		return errors.New("enrollment ID is required")
+	}
+
+	var errs error
+	if _, err := c.Revoke(&RevocationRequest{Name: enrollmentID}); err != nil {
+		errs = multi.Append(errs, errors.WithMessage(err, fmt.Sprintf("failed to revoke [%s]", enrollmentID)))
+	}
+	if _, err := c.RemoveIdentity(&RemoveIdentityRequest{ID: enrollmentID}); err != nil {
+		errs = multi.Append(errs, errors.WithMessage(err, fmt.Sprintf("failed to remove [%s]", enrollmentID)))
+	}
+	return errs
+}
+
 // GetAllIdentities returns all identities that the caller is authorized to see
 //  Parameters:
 //  options holds optional request options
@@ -275,18 +684,95 @@ func (c *Client) GetAllIdentities(options ...RequestOption) ([]*IdentityResponse
 		return nil, err
 	}
 
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := c.caClient()
 	if err != nil {
 		return nil, err
 	}
 
-	responses, err := ca.GetAllIdentities(opts.CA)
+	result, err := opts.invoke(ca, func(ca mspapi.CAClient) (interface{}, error) {
+		return ca.GetAllIdentities(opts.CA)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	return getIdentityResponses(responses), nil
+	return getIdentityResponses(result.([]*mspapi.IdentityResponse)), nil
+
+}
 
+// identityListOptions represent ListIdentities options
+type identityListOptions struct {
+	typ         string
+	affiliation string
+	caName      string
+}
+
+// IdentityListOption describes a functional parameter for ListIdentities
+type IdentityListOption func(*identityListOptions) error
+
+// WithIdentityType option limits the listed identities to the given type (e.g. 'peer, app, user')
+func WithIdentityType(typ string) IdentityListOption {
+	return func(o *identityListOptions) error {
+		o.typ = typ
+		return nil
+	}
+}
+
+// WithIdentityAffiliation option limits the listed identities to the given affiliation
+// and its sub-affiliations
+func WithIdentityAffiliation(affiliation string) IdentityListOption {
+	return func(o *identityListOptions) error {
+		o.affiliation = affiliation
+		return nil
+	}
+}
+
+// WithIdentityCA option sets the name of the CA to list identities from
+func WithIdentityCA(caName string) IdentityListOption {
+	return func(o *identityListOptions) error {
+		o.caName = caName
+		return nil
+	}
+}
+
+// ListIdentities returns the identities that the caller is authorized to see,
+// optionally filtered by type and affiliation. All identities are retrieved from the CA
+// in a single call; filtering is applied on the client side.
+//  Parameters:
+//  opts holds optional filters
+//
+//  Returns:
+//  Response containing identities
+func (c *Client) ListIdentities(opts ...IdentityListOption) ([]*IdentityResponse, error) {
+	lo := identityListOptions{}
+	for _, param := range opts {
+		err := param(&lo)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to list identities")
+		}
+	}
+
+	ca, err := c.caClient()
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := ca.GetAllIdentities(c.caNameOrDefault(lo.caName))
+	if err != nil {
+		return nil, err
+	}
+
+	var identities []*IdentityResponse
+	for _, r := range responses {
+		if lo.typ != "" && r.Type != lo.typ {
+			continue
+		}
+		if lo.affiliation != "" && r.Affiliation != lo.affiliation && !strings.HasPrefix(r.Affiliation, lo.affiliation+".") {
+			continue
+		}
+		identities = append(identities, getIdentityResponse(r))
+	}
+	return identities, nil
 }
 
 // GetIdentity retrieves identity information.
@@ -295,7 +781,7 @@ func (c *Client) GetAllIdentities(options ...RequestOption) ([]*IdentityResponse
 //  options holds optional request options
 //
 //  Returns:
-//  Response containing identity information
+//  Response containing identity information, or ErrUserNotFound if the CA does not know the identity
 func (c *Client) GetIdentity(ID string, options ...RequestOption) (*IdentityResponse, error) {
 
 	// Read request options
@@ -304,17 +790,22 @@ func (c *Client) GetIdentity(ID string, options ...RequestOption) (*IdentityResp
 		return nil, err
 	}
 
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := c.caClient()
 	if err != nil {
 		return nil, err
 	}
 
-	response, err := ca.GetIdentity(ID, opts.CA)
+	result, err := opts.invoke(ca, func(ca mspapi.CAClient) (interface{}, error) {
+		return ca.GetIdentity(ID, opts.CA)
+	})
 	if err != nil {
+		if err == mspctx.ErrUserNotFound {
+			return nil, ErrUserNotFound
+		}
 		return nil, err
 	}
 
-	return getIdentityResponse(response), nil
+	return getIdentityResponse(result.(*mspapi.IdentityResponse)), nil
 
 }
 
@@ -359,6 +850,102 @@ func getIdentityResponses(responses []*mspapi.IdentityResponse) []*IdentityRespo
 //  an error if enrollment fails
 func (c *Client) Enroll(enrollmentID string, opts ...EnrollmentOption) error {
 
+	ca, err := c.caClient()
+	if err != nil {
+		return err
+	}
+
+	return c.enrollWithRetry(ca, "Enroll", enrollmentID, opts...)
+}
+
+// EnrollForOrg enrolls a registered user of another organization with the default CA of
+// that organization, for example when an administrator enrolls users on behalf of another
+// organization of the consortium. The CA decides whether the enrollment is permitted.
+// The enrolled identity is stored with the MSP ID of the target organization and can be
+// retrieved from the identity manager of that organization.
+//  Parameters:
+//  enrollmentID enrollment ID of a registered user of the target organization
+//  targetOrg is the name of the organization, as configured in the network config
+//  opts are optional enrollment options
+//
+//  Returns:
+//  an error if enrollment fails
+func (c *Client) EnrollForOrg(enrollmentID string, targetOrg string, opts ...EnrollmentOption) error {
+	if targetOrg == "" {
+		return errors.New("target organization is required")
+	}
+	if strings.EqualFold(targetOrg, c.orgName) {
+		return c.Enroll(enrollmentID, opts...)
+	}
+
+	ca, err := c.caClientForOrg(targetOrg, "")
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("failed to get CA client for organization [%s]", targetOrg))
+	}
+
+	return c.enrollWithRetry(ca, "EnrollForOrg", enrollmentID, opts...)
+}
+
+func (c *Client) enrollWithRetry(ca mspapi.CAClient, op, enrollmentID string, opts ...EnrollmentOption) error {
+	start := time.Now()
+	err := c.enroll(ca, op, enrollmentID, opts...)
+	c.metrics.RecordEnrollment(time.Since(start), err)
+	c.logCompletion(op, enrollmentID, start, err)
+	return err
+}
+
+// BatchEnroll enrolls multiple registered users concurrently. The number of
+// enrollments in flight is bounded by the WithEnrollmentWorkers client option.
+//  Parameters:
+//  requests holds the enrollment ID and options of each user to enroll
+//
+//  Returns:
+//  the result of each enrollment, in the order of the requests, and
+//  a combined error if any of the enrollments failed
+func (c *Client) BatchEnroll(requests []EnrollmentRequest) ([]EnrollmentResult, error) {
+
+	ca, err := c.caClient()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]EnrollmentResult, len(requests))
+	sem := make(chan struct{}, c.enrollmentWorkers)
+
+	var wg sync.WaitGroup
+	wg.Add(len(requests))
+	for i, request := range requests {
+		sem <- struct{}{}
+		go func(i int, request EnrollmentRequest) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			start := time.Now()
+			err := c.enroll(ca, "Enroll", request.EnrollmentID, request.Options...)
+			c.metrics.RecordEnrollment(time.Since(start), err)
+
+			results[i] = EnrollmentResult{
+				EnrollmentID: request.EnrollmentID,
+				Err:          err,
+			}
+		}(i, request)
+	}
+	wg.Wait()
+
+	var errs error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = multi.Append(errs, errors.WithMessage(result.Err, fmt.Sprintf("failed to enroll [%s]", result.EnrollmentID)))
+		}
+	}
+
+	logger.Debugf("BatchEnroll of %d identities for org [%s], CA [%s] completed", len(requests), c.orgName, c.caName)
+	return results, errs
+}
+
+// enroll enrolls the user with the CA, retrying transient failures with the retry policy of the client
+func (c *Client) enroll(ca mspapi.CAClient, op, enrollmentID string, opts ...EnrollmentOption) error {
+
 	eo := enrollmentOptions{}
 	for _, param := range opts {
 		err := param(&eo)
@@ -366,10 +953,20 @@ func (c *Client) Enroll(enrollmentID string, opts ...EnrollmentOption) error {
 			return errors.WithMessage(err, "failed to enroll")
 		}
 	}
-
-	ca, err := newCAClient(c.ctx, c.orgName)
+	reqOpts, err := c.prepareOptsFromOptions(c.ctx, eo.reqOpts...)
 	if err != nil {
-		return err
+		return errors.WithMessage(err, "failed to enroll")
+	}
+
+	if eo.template != nil {
+		if eo.key != nil {
+			return errors.New("failed to enroll: existing key and HSM key template are mutually exclusive")
+		}
+		key, err := c.ctx.CryptoSuite().KeyGen(&pkcs11.ECDSATemplateKeyGenOpts{Template: *eo.template})
+		if err != nil {
+			return errors.WithMessage(err, "failed to generate key from HSM key template")
+		}
+		eo.key = key
 	}
 
 	req := &mspapi.EnrollmentRequest{
@@ -378,6 +975,8 @@ func (c *Client) Enroll(enrollmentID string, opts ...EnrollmentOption) error {
 		Profile: eo.profile,
 		Type:    eo.typ,
 		Label:   eo.label,
+		Key:     eo.key,
+		CSR:     eo.csr,
 	}
 
 	if len(eo.attrReqs) > 0 {
@@ -388,12 +987,19 @@ func (c *Client) Enroll(enrollmentID string, opts ...EnrollmentOption) error {
 		req.AttrReqs = attrs
 	}
 
-	return ca.Enroll(req)
+	_, err = c.invokeWithRetry(&reqOpts, ca, op, func(ca mspapi.CAClient) (interface{}, error) {
+		return nil, ca.Enroll(req)
+	})
+	return err
 }
 
-// Reenroll reenrolls an enrolled user in order to obtain a new signed X509 certificate
+// Reenroll reenrolls an enrolled user in order to obtain a new signed X509 certificate.
+// Additional attributes can be embedded in the new certificate with WithAttributeRequests.
+// A new key pair is generated for the CSR, so the CSR options (WithCSRHosts, WithCSRCommonName
+// and WithCSRKeyType) apply, while WithExistingKey and WithHSMKeyTemplate are rejected.
 //  Parameters:
 //  enrollmentID enrollment ID of a registered user
+//  opts are optional reenrollment options
 //
 //  Returns:
 //  an error if re-enrollment fails
@@ -405,8 +1011,15 @@ func (c *Client) Reenroll(enrollmentID string, opts ...EnrollmentOption) error {
 			return errors.WithMessage(err, "failed to enroll")
 		}
 	}
+	if eo.key != nil || eo.template != nil {
+		return errors.New("failed to reenroll: existing key and HSM key template are not supported for re-enrollment")
+	}
+	reqOpts, err := c.prepareOptsFromOptions(c.ctx, eo.reqOpts...)
+	if err != nil {
+		return errors.WithMessage(err, "failed to reenroll")
+	}
 
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := c.caClient()
 	if err != nil {
 		return err
 	}
@@ -415,6 +1028,7 @@ func (c *Client) Reenroll(enrollmentID string, opts ...EnrollmentOption) error {
 		Name:    enrollmentID,
 		Profile: eo.profile,
 		Label:   eo.label,
+		CSR:     eo.csr,
 	}
 	if len(eo.attrReqs) > 0 {
 		attrs := make([]*mspapi.AttributeRequest, 0)
@@ -423,54 +1037,177 @@ func (c *Client) Reenroll(enrollmentID string, opts ...EnrollmentOption) error {
 		}
 		req.AttrReqs = attrs
 	}
-	return ca.Reenroll(req)
+	start := time.Now()
+	_, err = c.invokeWithRetry(&reqOpts, ca, "Reenroll", func(ca mspapi.CAClient) (interface{}, error) {
+		return nil, ca.Reenroll(req)
+	})
+	c.metrics.RecordEnrollment(time.Since(start), err)
+	c.logCompletion("Reenroll", enrollmentID, start, err)
+	return err
 }
 
 // Register registers a User with the Fabric CA
 //  Parameters:
 //  request is registration request
+//  options holds optional request options
 //
 //  Returns:
 //  enrolment secret
-func (c *Client) Register(request *RegistrationRequest) (string, error) {
-	ca, err := newCAClient(c.ctx, c.orgName)
+func (c *Client) Register(request *RegistrationRequest, options ...RequestOption) (string, error) {
+	if err := c.ValidateRegistrationRequest(request); err != nil {
+		return "", err
+	}
+
+	// Read request options
+	opts, err := c.prepareOptsFromOptions(c.ctx, options...)
+	if err != nil {
+		return "", err
+	}
+
+	ca, err := c.caClient()
 	if err != nil {
 		return "", err
 	}
 
+	return c.registerWithRetry(&opts, ca, "Register", request)
+}
+
+func (c *Client) registerWithRetry(opts *requestOptions, ca mspapi.CAClient, op string, request *RegistrationRequest) (string, error) {
+	start := time.Now()
+	result, err := c.invokeWithRetry(opts, ca, op, func(ca mspapi.CAClient) (interface{}, error) {
+		return ca.Register(c.registrationRequest(request))
+	})
+	c.metrics.RecordRegistration(err)
+	c.logCompletion(op, request.Name, start, err)
+	if err != nil {
+		return "", err
+	}
+	return result.(string), nil
+}
+
+// RegisterAndEnroll registers a User with the Fabric CA and enrolls it using the
+// returned secret. If the enrollment fails, the registered identity is revoked so
+// that it cannot be enrolled later on.
+//  Parameters:
+//  request is registration request
+//  opts are optional enrollment options (the enrollment secret is set by this method). The request
+//  options set with WithRequestOptions also apply to the registration.
+//
+//  Returns:
+//  signing identity of the enrolled user
+func (c *Client) RegisterAndEnroll(request *RegistrationRequest, opts ...EnrollmentOption) (mspctx.SigningIdentity, error) {
+	if err := c.ValidateRegistrationRequest(request); err != nil {
+		return nil, err
+	}
+
+	eo := enrollmentOptions{}
+	for _, param := range opts {
+		if err := param(&eo); err != nil {
+			return nil, errors.WithMessage(err, "failed to enroll")
+		}
+	}
+	reqOpts, err := c.prepareOptsFromOptions(c.ctx, eo.reqOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ca, err := c.caClient()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := c.registerWithRetry(&reqOpts, ca, "RegisterAndEnroll", request)
+	if err != nil {
+		return nil, errors.WithMessage(err, "registration failed")
+	}
+
+	err = c.enrollWithRetry(ca, "RegisterAndEnroll", request.Name, append(opts[:len(opts):len(opts)], WithSecret(secret))...)
+	if err != nil {
+		_, revokeErr := ca.Revoke(&mspapi.RevocationRequest{Name: request.Name, CAName: c.caNameOrDefault(request.CAName)})
+		if revokeErr != nil {
+			logger.Warnf("failed to revoke [%s] after failed enrollment: %s", request.Name, revokeErr)
+		}
+		return nil, errors.WithMessage(err, "enrollment failed")
+	}
+
+	return c.GetSigningIdentity(request.Name)
+}
+
+// ValidateRegistrationRequest checks a registration request before it is sent to the CA.
+// The identity type is only checked if the allowed types were set with WithIdentityTypes.
+//  Parameters:
+//  request is registration request
+//
+//  Returns:
+//  an error describing the first problem found in the request
+func (c *Client) ValidateRegistrationRequest(request *RegistrationRequest) error {
+	if request == nil {
+		return errors.New("registration request is required")
+	}
+	if request.Name == "" {
+		return errors.New("registration request name is required")
+	}
+	if request.MaxEnrollments < -1 {
+		return errors.Errorf("invalid max enrollments [%d]: must be -1 (unlimited), 0 (server default) or positive", request.MaxEnrollments)
+	}
+	if request.Type == "" || len(c.identityTypes) == 0 {
+		return nil
+	}
+	for _, t := range c.identityTypes {
+		if strings.EqualFold(t, request.Type) {
+			return nil
+		}
+	}
+	return errors.Errorf("identity type [%s] is not allowed, must be one of %v", request.Type, c.identityTypes)
+}
+
+func (c *Client) registrationRequest(request *RegistrationRequest) *mspapi.RegistrationRequest {
 	var a []mspapi.Attribute
 	for i := range request.Attributes {
 		a = append(a, mspapi.Attribute{Name: request.Attributes[i].Name, Value: request.Attributes[i].Value, ECert: request.Attributes[i].ECert})
 	}
 
-	r := mspapi.RegistrationRequest{
+	return &mspapi.RegistrationRequest{
 		Name:           request.Name,
 		Type:           request.Type,
 		MaxEnrollments: request.MaxEnrollments,
 		Affiliation:    request.Affiliation,
 		Attributes:     a,
-		CAName:         request.CAName,
+		CAName:         c.caNameOrDefault(request.CAName),
 		Secret:         request.Secret,
 	}
-	return ca.Register(&r)
 }
 
 // Revoke revokes a User with the Fabric CA
 //  Parameters:
 //  request is revocation request
+//  options holds optional request options
 //
 //  Returns:
 //  revocation response
-func (c *Client) Revoke(request *RevocationRequest) (*RevocationResponse, error) {
-	ca, err := newCAClient(c.ctx, c.orgName)
+func (c *Client) Revoke(request *RevocationRequest, options ...RequestOption) (*RevocationResponse, error) {
+	// Read request options
+	opts, err := c.prepareOptsFromOptions(c.ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	ca, err := c.caClient()
 	if err != nil {
 		return nil, err
 	}
 	req := mspapi.RevocationRequest(*request)
-	resp, err := ca.Revoke(&req)
+	req.CAName = c.caNameOrDefault(req.CAName)
+	start := time.Now()
+	result, err := c.invokeWithRetry(&opts, ca, "Revoke", func(ca mspapi.CAClient) (interface{}, error) {
+		return ca.Revoke(&req)
+	})
+	c.metrics.RecordRevocation(err)
+	c.logCompletion("Revoke", req.Name, start, err)
 	if err != nil {
 		return nil, err
 	}
+	resp := result.(*mspapi.RevocationResponse)
 	var revokedCerts []RevokedCert
 	for i := range resp.RevokedCerts {
 		revokedCerts = append(
@@ -487,9 +1224,94 @@ func (c *Client) Revoke(request *RevocationRequest) (*RevocationResponse, error)
 	}, nil
 }
 
+// RevokeByAffiliation revokes the certificates of all identities of the given affiliation and its
+// sub-affiliations, e.g. when an organization is offboarded. Identities are revoked by name, so
+// identities whose certificates have all been revoked already are reported as AlreadyRevoked
+// rather than failing, and the call can be repeated until all revocations succeed.
+//  Parameters:
+//  affiliation is the affiliation of the identities to revoke
+//  reason is the reason for revocation. See https://godoc.org/golang.org/x/crypto/ocsp
+//  for valid values.
+//
+//  Returns:
+//  the result of each revocation and the CRL of the CA, and
+//  a combined error if any of the revocations failed
+func (c *Client) RevokeByAffiliation(affiliation, reason string) (*BulkRevocationResponse, error) {
+	if affiliation == "" {
+		return nil, errors.New("affiliation is required")
+	}
+
+	identities, err := c.ListIdentities(WithIdentityAffiliation(affiliation))
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed to list identities of affiliation [%s]", affiliation))
+	}
+
+	var errs error
+	results := make([]IdentityRevocationResult, len(identities))
+	for i, identity := range identities {
+		results[i].ID = identity.ID
+		resp, err := c.Revoke(&RevocationRequest{Name: identity.ID, Reason: reason, CAName: identity.CAName})
+		if err != nil {
+			results[i].Err = err
+			errs = multi.Append(errs, errors.WithMessage(err, fmt.Sprintf("failed to revoke [%s]", identity.ID)))
+			continue
+		}
+		results[i].RevokedCerts = resp.RevokedCerts
+		results[i].AlreadyRevoked = len(resp.RevokedCerts) == 0
+	}
+
+	crl, err := c.getCRL()
+	if err != nil {
+		errs = multi.Append(errs, errors.WithMessage(err, "failed to get CRL"))
+	}
+
+	logger.Debugf("RevokeByAffiliation of %d identities of affiliation [%s] for org [%s], CA [%s] completed", len(identities), affiliation, c.orgName, c.caName)
+	return &BulkRevocationResponse{Results: results, CRL: crl}, errs
+}
+
+// GetCRL returns the current certificate revocation list of the CA without revoking anything
+//  Parameters:
+//  options holds optional request options
+//
+//  Returns:
+//  DER-encoded CRL, as parsed by x509.ParseCRL
+func (c *Client) GetCRL(options ...RequestOption) ([]byte, error) {
+	crlPEM, err := c.getCRL(options...)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(crlPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode CRL PEM")
+	}
+	return block.Bytes, nil
+}
+
+// getCRL returns the PEM-encoded CRL of the CA
+func (c *Client) getCRL(options ...RequestOption) ([]byte, error) {
+	// Read request options
+	opts, err := c.prepareOptsFromOptions(c.ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	ca, err := c.caClient()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := opts.invoke(ca, func(ca mspapi.CAClient) (interface{}, error) {
+		return ca.GetCRL(c.caNameOrDefault(opts.CA))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]byte), nil
+}
+
 // GetCAInfo returns generic CA information
 func (c *Client) GetCAInfo() (*GetCAInfoResponse, error) {
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := c.caClient()
 	if err != nil {
 		return nil, err
 	}
@@ -507,7 +1329,8 @@ func (c *Client) GetCAInfo() (*GetCAInfoResponse, error) {
 //  id is user id
 //
 //  Returns:
-//  signing identity
+//  signing identity, or ErrCertRevoked if OCSP checking is enabled
+//  and the enrollment certificate has been revoked
 func (c *Client) GetSigningIdentity(id string) (mspctx.SigningIdentity, error) {
 	im, _ := c.ctx.IdentityManager(c.orgName)
 	si, err := im.GetSigningIdentity(id)
@@ -517,15 +1340,323 @@ func (c *Client) GetSigningIdentity(id string) (mspctx.SigningIdentity, error) {
 		}
 		return nil, err
 	}
+	if c.ocsp != nil {
+		if err := c.ocsp.verify(si.EnrollmentCertificate(), c.caChain); err != nil {
+			return nil, err
+		}
+	}
 	return si, nil
 }
 
+// HasEnrolled checks whether an identity with the given enrollment ID is enrolled,
+// i.e. its enrollment certificate and private key are available to the client
+//  Parameters:
+//  enrollmentID is the enrollment ID of the identity
+//
+//  Returns:
+//  true if the identity is enrolled, false if it is not
+func (c *Client) HasEnrolled(enrollmentID string) (bool, error) {
+	im, _ := c.ctx.IdentityManager(c.orgName)
+	_, err := im.GetSigningIdentity(enrollmentID)
+	if err != nil {
+		if err == mspctx.ErrUserNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// GetCertificate returns the parsed enrollment certificate of an enrolled identity
+//  Parameters:
+//  enrollmentID is the enrollment ID of the identity
+//
+//  Returns:
+//  the enrollment certificate, or ErrUserNotFound if the identity is not enrolled
+func (c *Client) GetCertificate(enrollmentID string) (*x509.Certificate, error) {
+	si, err := c.GetSigningIdentity(enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+	return parseCertificate(si.EnrollmentCertificate())
+}
+
+// GetCertificateChain returns the enrollment certificate of an enrolled identity followed by the
+// certificates of the CA chain that issued it, e.g. to present the full chain in TLS handshakes.
+// The CA chain is retrieved from the CA.
+//  Parameters:
+//  enrollmentID is the enrollment ID of the identity
+//
+//  Returns:
+//  the certificate chain ordered from the enrollment certificate to the root CA certificate,
+//  or ErrUserNotFound if the identity is not enrolled
+func (c *Client) GetCertificateChain(enrollmentID string) ([]*x509.Certificate, error) {
+	cert, err := c.GetCertificate(enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	chain, err := c.caChain()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to retrieve CA chain")
+	}
+	return buildCertificateChain(cert, chain)
+}
+
+// buildCertificateChain orders the certificates of the PEM-encoded CA chain from the issuer of cert to the root
+func buildCertificateChain(cert *x509.Certificate, caChain []byte) ([]*x509.Certificate, error) {
+	var cas []*x509.Certificate
+	for block, rest := pem.Decode(caChain); block != nil; block, rest = pem.Decode(rest) {
+		ca, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse CA certificate")
+		}
+		cas = append(cas, ca)
+	}
+
+	chain := []*x509.Certificate{cert}
+	for cert.CheckSignatureFrom(cert) != nil {
+		issuer := -1
+		for i, ca := range cas {
+			if cert.CheckSignatureFrom(ca) == nil {
+				issuer = i
+				break
+			}
+		}
+		if issuer < 0 {
+			return nil, errors.Errorf("issuer of certificate [%s] not found in CA chain", cert.Subject)
+		}
+		cert = cas[issuer]
+		chain = append(chain, cert)
+		cas = append(cas[:issuer], cas[issuer+1:]...)
+	}
+	return chain, nil
+}
+
+func (c *Client) caChain() ([]byte, error) {
+	ca, err := c.caClient()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ca.GetCAInfo()
+	if err != nil {
+		return nil, err
+	}
+	return resp.CAChain, nil
+}
+
 // CreateSigningIdentity creates a signing identity with the given options
 func (c *Client) CreateSigningIdentity(opts ...mspctx.SigningIdentityOption) (mspctx.SigningIdentity, error) {
 	im, _ := c.ctx.IdentityManager(c.orgName)
 	return im.CreateSigningIdentity(opts...)
 }
 
+// ImportSigningIdentity stores an externally issued enrollment certificate and its private key
+// in the SDK stores, without enrolling with the CA. The identity can then be retrieved with
+// GetSigningIdentity by a client of the organization with the given MSP ID.
+//  Parameters:
+//  enrollmentID is the ID of the identity
+//  mspID is the MSP ID of the identity; the MSP ID of the client's organization is used if empty
+//  cert is the PEM-encoded enrollment certificate
+//  privateKey is the PEM-encoded private key matching the certificate
+//
+//  Returns:
+//  an error if the certificate or key are invalid or cannot be stored
+func (c *Client) ImportSigningIdentity(enrollmentID, mspID string, cert []byte, privateKey []byte) error {
+	if enrollmentID == "" {
+		return errors.New("enrollment ID is required")
+	}
+	if mspID == "" {
+		orgConfig, ok := c.ctx.EndpointConfig().NetworkConfig().Organizations[strings.ToLower(c.orgName)]
+		if !ok {
+			return errors.Errorf("non-existent organization: '%s'", c.orgName)
+		}
+		mspID = orgConfig.MSPID
+	}
+
+	if _, err := parseCertificate(cert); err != nil {
+		return err
+	}
+	pubKey, err := cryptoutil.GetPublicKeyFromCert(cert, c.ctx.CryptoSuite())
+	if err != nil {
+		return errors.WithMessage(err, "fetching public key from cert failed")
+	}
+
+	key, err := fabricCaUtil.ImportBCCSPKeyFromPEMBytes(privateKey, c.ctx.CryptoSuite(), false)
+	if err != nil {
+		return errors.WithMessage(err, "failed to import private key")
+	}
+	if !bytes.Equal(key.SKI(), pubKey.SKI()) {
+		return errors.New("private key does not match certificate")
+	}
+
+	err = c.ctx.UserStore().Store(&mspctx.UserData{
+		ID:                    enrollmentID,
+		MSPID:                 mspID,
+		EnrollmentCertificate: cert,
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to store user")
+	}
+	return nil
+}
+
+// ExportSigningIdentity exports the enrollment certificate and private key of an identity.
+// Exporting is only permitted if the client was created with WithKeyExportAllowed(true),
+// and only keys held in the software key store can be exported.
+//  Parameters:
+//  enrollmentID is the ID of the identity
+//
+//  Returns:
+//  the PEM-encoded certificate and PKCS8 PEM-encoded private key
+func (c *Client) ExportSigningIdentity(enrollmentID string) (*ExportedIdentity, error) {
+	if !c.keyExportAllowed {
+		return nil, errors.New("private key export is not allowed")
+	}
+
+	si, err := c.GetSigningIdentity(enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	keyPath := filepath.Join(c.ctx.IdentityConfig().CAKeyStorePath(), "keystore", hex.EncodeToString(si.PrivateKey().SKI())+"_sk")
+	raw, err := ioutil.ReadFile(keyPath) // nolint: gas
+	if err != nil {
+		return nil, errors.Wrap(err, "private key not found in key store")
+	}
+	key, err := bccspUtils.PEMtoPrivateKey(raw, nil)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to parse private key")
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal private key")
+	}
+
+	return &ExportedIdentity{
+		ID:         si.Identifier().ID,
+		MSPID:      si.Identifier().MSPID,
+		Cert:       si.EnrollmentCertificate(),
+		PrivateKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+	}, nil
+}
+
+// logCompletion logs the outcome and duration of a CA operation at debug level
+func (c *Client) logCompletion(op, enrollmentID string, start time.Time, err error) {
+	elapsed := time.Since(start)
+	if err != nil {
+		logger.Debugf("%s of [%s] for org [%s], CA [%s] failed after %s: %s", op, enrollmentID, c.orgName, c.caName, elapsed, err)
+		return
+	}
+	logger.Debugf("%s of [%s] for org [%s], CA [%s] completed in %s", op, enrollmentID, c.orgName, c.caName, elapsed)
+}
+
+// caNameOrDefault returns the given CA name, or the client's CA name if empty
+func (c *Client) caNameOrDefault(caName string) string {
+	if caName != "" {
+		return caName
+	}
+	return c.caName
+}
+
+// invoke calls fn with the CA client and waits for it to complete, or for the request context to be done.
+// If the CA client supports request contexts, fn is called with a CA client bound to the request context,
+// so that a request that is still in flight when the context is done is cancelled. Otherwise the request
+// is abandoned rather than cancelled.
+func (o *requestOptions) invoke(ca mspapi.CAClient, fn func(ca mspapi.CAClient) (interface{}, error)) (interface{}, error) {
+	if o.ParentContext == nil && o.Timeout == 0 {
+		return fn(ca)
+	}
+
+	ctx, cancel := o.requestContext()
+	defer cancel()
+	return invokeWithContext(ctx, ca, fn)
+}
+
+// invokeWithRetry calls fn as invoke does, retrying transient failures with the retry policy of the client.
+// The timeout applies to all attempts, and no further attempts are made once the request context is done.
+func (c *Client) invokeWithRetry(o *requestOptions, ca mspapi.CAClient, op string, fn func(ca mspapi.CAClient) (interface{}, error)) (interface{}, error) {
+	var result interface{}
+	if o.ParentContext == nil && o.Timeout == 0 {
+		err := c.retryPolicy.do(op, func() error {
+			var err error
+			result, err = fn(ca)
+			return err
+		})
+		return result, err
+	}
+
+	ctx, cancel := o.requestContext()
+	defer cancel()
+
+	policy := c.retryPolicy
+	shouldRetry := policy.ShouldRetry
+	policy.ShouldRetry = func(err error) bool {
+		return ctx.Err() == nil && shouldRetry != nil && shouldRetry(err)
+	}
+	err := policy.do(op, func() error {
+		var err error
+		result, err = invokeWithContext(ctx, ca, fn)
+		return err
+	})
+	return result, err
+}
+
+// requestContext returns the context of the request, which is done when the parent context is done or
+// the timeout expires
+func (o *requestOptions) requestContext() (reqContext.Context, reqContext.CancelFunc) {
+	parent := o.ParentContext
+	if parent == nil {
+		parent = reqContext.Background()
+	}
+	if o.Timeout > 0 {
+		return reqContext.WithTimeout(parent, o.Timeout)
+	}
+	return reqContext.WithCancel(parent)
+}
+
+// invokeWithContext calls fn with the CA client and waits for it to complete, or for ctx to be done
+func invokeWithContext(ctx reqContext.Context, ca mspapi.CAClient, fn func(ca mspapi.CAClient) (interface{}, error)) (interface{}, error) {
+	if supportsContext(ca) {
+		bound, err := ca.(mspapi.ContextCAClient).WithContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result, err := fn(bound)
+		if ctx.Err() != nil {
+			return nil, errors.Wrap(ctx.Err(), "CA request aborted")
+		}
+		return result, err
+	}
+
+	type response struct {
+		result interface{}
+		err    error
+	}
+	done := make(chan response, 1)
+	go func() {
+		result, err := fn(ca)
+		done <- response{result: result, err: err}
+	}()
+
+	select {
+	case resp := <-done:
+		return resp.result, resp.err
+	case <-ctx.Done():
+		return nil, errors.Wrap(ctx.Err(), "CA request aborted")
+	}
+}
+
+// supportsContext returns true if the requests of the CA client can be bound to a request context
+func supportsContext(ca mspapi.CAClient) bool {
+	if ec, ok := ca.(*caErrorClient); ok {
+		return supportsContext(ec.ca)
+	}
+	_, ok := ca.(mspapi.ContextCAClient)
+	return ok
+}
+
 //prepareOptsFromOptions reads request options from Option array
 func (c *Client) prepareOptsFromOptions(ctx context.Client, options ...RequestOption) (requestOptions, error) {
 	opts := requestOptions{}
@@ -546,15 +1677,18 @@ func (c *Client) GetAffiliation(affiliation string, options ...RequestOption) (*
 		return nil, err
 	}
 
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := c.caClient()
 	if err != nil {
 		return nil, err
 	}
 
-	r, err := ca.GetAffiliation(affiliation, opts.CA)
+	result, err := opts.invoke(ca, func(ca mspapi.CAClient) (interface{}, error) {
+		return ca.GetAffiliation(affiliation, opts.CA)
+	})
 	if err != nil {
 		return nil, err
 	}
+	r := result.(*mspapi.AffiliationResponse)
 
 	resp := &AffiliationResponse{CAName: r.CAName, AffiliationInfo: AffiliationInfo{}}
 	err = fillAffiliationInfo(&resp.AffiliationInfo, r.Name, r.Affiliations, r.Identities)
@@ -570,15 +1704,18 @@ func (c *Client) GetAllAffiliations(options ...RequestOption) (*AffiliationRespo
 		return nil, err
 	}
 
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := c.caClient()
 	if err != nil {
 		return nil, err
 	}
 
-	r, err := ca.GetAllAffiliations(opts.CA)
+	result, err := opts.invoke(ca, func(ca mspapi.CAClient) (interface{}, error) {
+		return ca.GetAllAffiliations(opts.CA)
+	})
 	if err != nil {
 		return nil, err
 	}
+	r := result.(*mspapi.AffiliationResponse)
 
 	resp := &AffiliationResponse{CAName: r.CAName, AffiliationInfo: AffiliationInfo{}}
 	err = fillAffiliationInfo(&resp.AffiliationInfo, r.Name, r.Affiliations, r.Identities)
@@ -588,7 +1725,7 @@ func (c *Client) GetAllAffiliations(options ...RequestOption) (*AffiliationRespo
 
 // AddAffiliation adds a new affiliation to the server
 func (c *Client) AddAffiliation(request *AffiliationRequest) (*AffiliationResponse, error) {
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := c.caClient()
 	if err != nil {
 		return nil, err
 	}
@@ -612,7 +1749,7 @@ func (c *Client) AddAffiliation(request *AffiliationRequest) (*AffiliationRespon
 
 // ModifyAffiliation renames an existing affiliation on the server
 func (c *Client) ModifyAffiliation(request *ModifyAffiliationRequest) (*AffiliationResponse, error) {
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := c.caClient()
 	if err != nil {
 		return nil, err
 	}
@@ -639,7 +1776,7 @@ func (c *Client) ModifyAffiliation(request *ModifyAffiliationRequest) (*Affiliat
 
 // RemoveAffiliation removes an existing affiliation from the server
 func (c *Client) RemoveAffiliation(request *AffiliationRequest) (*AffiliationResponse, error) {
-	ca, err := newCAClient(c.ctx, c.orgName)
+	ca, err := c.caClient()
 	if err != nil {
 		return nil, err
 	}
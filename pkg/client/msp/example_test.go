@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"crypto/x509"
 	"fmt"
 
 	"github.com/cloudflare/cfssl/log"
@@ -205,6 +206,30 @@ func ExampleClient_Register() {
 	// Output: register user is completed
 }
 
+func ExampleClient_RegisterAndEnroll() {
+
+	ctx := mockClientProvider()
+
+	// Create msp client
+	c, err := New(ctx)
+	if err != nil {
+		fmt.Println("failed to create msp client")
+		return
+	}
+
+	username := randomUsername()
+	identity, err := c.RegisterAndEnroll(&RegistrationRequest{Name: username})
+	if err != nil {
+		fmt.Printf("RegisterAndEnroll return error %s\n", err)
+		return
+	}
+	if identity.Identifier().ID == username {
+		fmt.Println("register and enroll user is completed")
+	}
+
+	// Output: register and enroll user is completed
+}
+
 func ExampleClient_Enroll() {
 
 	ctx := mockClientProvider()
@@ -257,6 +282,37 @@ func ExampleClient_Reenroll() {
 
 }
 
+func ExampleClient_Reenroll_withAttributeRequests() {
+
+	ctx := mockClientProvider()
+
+	// Create msp client
+	c, err := New(ctx)
+	if err != nil {
+		fmt.Println("failed to create msp client")
+		return
+	}
+
+	username := randomUsername()
+
+	err = c.Enroll(username, WithSecret("enrollmentSecret"))
+	if err != nil {
+		fmt.Printf("failed to enroll user: %s\n", err)
+		return
+	}
+
+	// Request attributes to be embedded in the new certificate
+	attrs := []*AttributeRequest{{Name: "name1", Optional: true}}
+	err = c.Reenroll(username, WithAttributeRequests(attrs))
+	if err != nil {
+		fmt.Printf("failed to reenroll user: %s\n", err)
+		return
+	}
+	fmt.Println("reenroll user is completed")
+
+	// Output: reenroll user is completed
+}
+
 func ExampleClient_GetSigningIdentity() {
 
 	ctx := mockClientProvider()
@@ -403,6 +459,25 @@ func ExampleWithCA() {
 	// Output: 2 identities retrieved
 }
 
+func ExampleClient_ListIdentities() {
+
+	// Create msp client
+	c, err := New(mockClientProvider())
+	if err != nil {
+		fmt.Println("failed to create msp client")
+		return
+	}
+
+	results, err := c.ListIdentities(WithIdentityAffiliation("org2"))
+	if err != nil {
+		fmt.Printf("List identities return error %s\n", err)
+		return
+	}
+	fmt.Printf("%d identities retrieved\n", len(results))
+
+	// Output: 2 identities retrieved
+}
+
 func ExampleClient_CreateIdentity() {
 
 	// Create msp client
@@ -499,6 +574,145 @@ func ExampleClient_GetAllIdentities() {
 	// Output: 2 identities retrieved
 }
 
+func ExampleClient_AddAffiliation() {
+
+	// Create msp client
+	c, err := New(mockClientProvider())
+	if err != nil {
+		fmt.Println("failed to create msp client")
+		return
+	}
+
+	affiliationInfo, err := c.AddAffiliation(&AffiliationRequest{Name: "test1.com", Force: true})
+	if err != nil {
+		fmt.Printf("Add affiliation return error %s\n", err)
+		return
+	}
+	fmt.Printf("affiliation '%s' added\n", affiliationInfo.Name)
+
+	// Output: affiliation 'test1.com' added
+}
+
+func ExampleClient_GetAffiliation() {
+
+	// Create msp client
+	c, err := New(mockClientProvider())
+	if err != nil {
+		fmt.Println("failed to create msp client")
+		return
+	}
+
+	affiliationInfo, err := c.GetAffiliation("123")
+	if err != nil {
+		fmt.Printf("Get affiliation return error %s\n", err)
+		return
+	}
+	fmt.Printf("affiliation '%s' retrieved\n", affiliationInfo.Name)
+
+	// Output: affiliation 'test1.com' retrieved
+}
+
+func ExampleClient_GetAllAffiliations() {
+
+	// Create msp client
+	c, err := New(mockClientProvider())
+	if err != nil {
+		fmt.Println("failed to create msp client")
+		return
+	}
+
+	affiliationInfo, err := c.GetAllAffiliations()
+	if err != nil {
+		fmt.Printf("Get affiliations return error %s\n", err)
+		return
+	}
+	fmt.Printf("%d top level affiliation(s) retrieved\n", len(affiliationInfo.Affiliations))
+
+	// Output: 1 top level affiliation(s) retrieved
+}
+
+func ExampleClient_ModifyAffiliation() {
+
+	// Create msp client
+	c, err := New(mockClientProvider())
+	if err != nil {
+		fmt.Println("failed to create msp client")
+		return
+	}
+
+	affiliationInfo, err := c.ModifyAffiliation(&ModifyAffiliationRequest{NewName: "test1new.com", AffiliationRequest: AffiliationRequest{Name: "123"}})
+	if err != nil {
+		fmt.Printf("Modify affiliation return error %s\n", err)
+		return
+	}
+	fmt.Printf("affiliation renamed to '%s'\n", affiliationInfo.Name)
+
+	// Output: affiliation renamed to 'test1new.com'
+}
+
+func ExampleClient_RemoveAffiliation() {
+
+	// Create msp client
+	c, err := New(mockClientProvider())
+	if err != nil {
+		fmt.Println("failed to create msp client")
+		return
+	}
+
+	// Force removes child affiliations and identities as well
+	affiliationInfo, err := c.RemoveAffiliation(&AffiliationRequest{Name: "123", Force: true})
+	if err != nil {
+		fmt.Printf("Remove affiliation return error %s\n", err)
+		return
+	}
+	fmt.Printf("affiliation '%s' removed\n", affiliationInfo.Name)
+
+	// Output: affiliation 'test1.com' removed
+}
+
+func ExampleClient_GetCRL() {
+
+	// Create msp client
+	c, err := New(mockClientProvider())
+	if err != nil {
+		fmt.Println("failed to create msp client")
+		return
+	}
+
+	crlDER, err := c.GetCRL()
+	if err != nil {
+		fmt.Printf("Get CRL return error %s\n", err)
+		return
+	}
+	crl, err := x509.ParseCRL(crlDER)
+	if err != nil {
+		fmt.Printf("failed to parse CRL: %s\n", err)
+		return
+	}
+	fmt.Printf("CRL lists %d revoked certificate(s)\n", len(crl.TBSCertList.RevokedCertificates))
+
+	// Output: CRL lists 1 revoked certificate(s)
+}
+
+func ExampleClient_GetCAInfo() {
+
+	// Create msp client
+	c, err := New(mockClientProvider())
+	if err != nil {
+		fmt.Println("failed to create msp client")
+		return
+	}
+
+	caInfo, err := c.GetCAInfo()
+	if err != nil {
+		fmt.Printf("Get CA info return error %s\n", err)
+		return
+	}
+	fmt.Printf("connected to CA '%s' version %s\n", caInfo.CAName, caInfo.Version)
+
+	// Output: connected to CA '123' version 1.4
+}
+
 func mockClientProvider() context.ClientProvider {
 	log.SetLogger(nil)
 	f := testFixture{}
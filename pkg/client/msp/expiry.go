@@ -0,0 +1,115 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	mspctx "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+)
+
+// ExpiryCallback is invoked by the expiry watcher for every enrolled identity
+// whose enrollment certificate expires within the configured threshold
+type ExpiryCallback func(enrollmentID string, expiry time.Time)
+
+// StartExpiryWatcher periodically scans the user store for identities of the client's organization
+// and invokes the callback for those whose enrollment certificate expires within threshold.
+// The first scan is performed immediately. The user store must implement UserStoreLister.
+//  Parameters:
+//  interval is the time between scans
+//  threshold is how far ahead of expiry the callback should be invoked
+//  cb is the callback invoked for each identity about to expire
+//
+//  Returns:
+//  a Closer that stops the watcher
+func (c *Client) StartExpiryWatcher(interval time.Duration, threshold time.Duration, cb ExpiryCallback) (io.Closer, error) {
+	if interval <= 0 {
+		return nil, errors.New("interval must be greater than zero")
+	}
+	if cb == nil {
+		return nil, errors.New("callback is required")
+	}
+
+	lister, ok := c.ctx.UserStore().(mspctx.UserStoreLister)
+	if !ok {
+		return nil, errors.New("user store does not support listing users")
+	}
+
+	orgConfig, ok := c.ctx.EndpointConfig().NetworkConfig().Organizations[strings.ToLower(c.orgName)]
+	if !ok {
+		return nil, errors.Errorf("non-existent organization: '%s'", c.orgName)
+	}
+
+	w := &expiryWatcher{
+		lister:    lister,
+		mspID:     orgConfig.MSPID,
+		threshold: threshold,
+		callback:  cb,
+		done:      make(chan struct{}),
+	}
+	go w.run(interval)
+
+	return w, nil
+}
+
+type expiryWatcher struct {
+	lister    mspctx.UserStoreLister
+	mspID     string
+	threshold time.Duration
+	callback  ExpiryCallback
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Close stops the watcher
+func (w *expiryWatcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.done)
+	})
+	return nil
+}
+
+func (w *expiryWatcher) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		w.scan()
+		select {
+		case <-ticker.C:
+		case <-w.done:
+			return
+		}
+	}
+}
+
+func (w *expiryWatcher) scan() {
+	users, err := w.lister.LoadAll()
+	if err != nil {
+		logger.Warnf("expiry watcher failed to load users: %s", err)
+		return
+	}
+
+	deadline := time.Now().Add(w.threshold)
+	for _, user := range users {
+		if user.MSPID != w.mspID {
+			continue
+		}
+		cert, err := parseCertificate(user.EnrollmentCertificate)
+		if err != nil {
+			logger.Warnf("expiry watcher failed to parse certificate of [%s]: %s", user.ID, err)
+			continue
+		}
+		if cert.NotAfter.Before(deadline) {
+			w.callback(user.ID, cert.NotAfter)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"testing"
+	"time"
+
+	mspctx "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiryWatcher(t *testing.T) {
+	// Test certificates expire in one hour
+	cert, _ := newTestCert(t, 1, nil, nil)
+
+	store := msp.NewMemoryUserStore()
+	require.NoError(t, store.Store(&mspctx.UserData{ID: "user1", MSPID: "Org1MSP", EnrollmentCertificate: toPEM(cert)}))
+	require.NoError(t, store.Store(&mspctx.UserData{ID: "user2", MSPID: "Org2MSP", EnrollmentCertificate: toPEM(cert)}))
+	require.NoError(t, store.Store(&mspctx.UserData{ID: "user3", MSPID: "Org1MSP", EnrollmentCertificate: []byte("invalid")}))
+
+	expiring := make(chan string, 10)
+	w := &expiryWatcher{
+		lister:    store,
+		mspID:     "Org1MSP",
+		threshold: 2 * time.Hour,
+		callback: func(enrollmentID string, expiry time.Time) {
+			assert.Equal(t, cert.NotAfter, expiry)
+			expiring <- enrollmentID
+		},
+		done: make(chan struct{}),
+	}
+	go w.run(10 * time.Millisecond)
+
+	select {
+	case id := <-expiring:
+		assert.Equal(t, "user1", id)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for expiry callback")
+	}
+
+	// Repeated scans report the identity again
+	select {
+	case id := <-expiring:
+		assert.Equal(t, "user1", id)
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for expiry callback")
+	}
+
+	assert.NoError(t, w.Close())
+	assert.NoError(t, w.Close())
+
+	// Identities outside of the threshold are not reported
+	w = &expiryWatcher{
+		lister:    store,
+		mspID:     "Org1MSP",
+		threshold: time.Minute,
+		callback: func(enrollmentID string, expiry time.Time) {
+			t.Fatalf("Unexpected expiry callback for [%s]", enrollmentID)
+		},
+		done: make(chan struct{}),
+	}
+	w.scan()
+}
@@ -0,0 +1,107 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"github.com/pkg/errors"
+)
+
+// IdemixProvider implements the Idemix cryptography that is needed to obtain Idemix credentials and to verify
+// Idemix proofs, e.g. with the idemix package of Fabric. The SDK does not include an Idemix implementation,
+// so a provider must be set with WithIdemixProvider to use GetIdemixCredential and VerifyIdemixProof.
+type IdemixProvider interface {
+	// NewCredentialRequest creates a credential request for the nonce issued by the CA, with a new user
+	// secret key. It returns the JSON encoding of the credential request, as expected by the
+	// /idemix/credential endpoint of the CA, and the user secret key.
+	NewCredentialRequest(issuerPublicKey, nonce []byte) (credRequest []byte, secretKey []byte, err error)
+	// VerifyProof verifies that proof is a valid Idemix signature of nonce that discloses the given attribute values
+	VerifyProof(issuerPublicKey, proof, nonce []byte, attrs []string) (bool, error)
+}
+
+// IdemixCredential is an Idemix credential issued by the CA
+type IdemixCredential struct {
+	EnrollmentID string
+	// Credential is the proto bytes of the Idemix credential
+	Credential []byte
+	// SecretKey is the user secret key of the credential
+	SecretKey []byte
+	// Attributes are the attributes of the credential
+	Attributes map[string]interface{}
+	// CRI is the proto bytes of the credential revocation information
+	CRI []byte
+	// IssuerPublicKey is the Idemix issuer public key of the CA
+	IssuerPublicKey []byte
+}
+
+// GetIdemixCredential gets an Idemix credential for an enrolled user from the CA. The requests to the CA
+// are authenticated with the enrollment certificate of the user. The credential is stored in the user
+// store if the user store supports Idemix credentials (see UserIdemixCredentialStore). The user secret
+// key is not stored with it, so the caller must keep the returned SecretKey in secure storage.
+//  Parameters:
+//  enrollmentID is the enrollment ID of the user
+//
+//  Returns:
+//  the Idemix credential
+func (c *Client) GetIdemixCredential(enrollmentID string) (*IdemixCredential, error) {
+	if enrollmentID == "" {
+		return nil, errors.New("enrollment ID is required")
+	}
+	if c.idemixProvider == nil {
+		return nil, errors.New("Idemix provider is required")
+	}
+
+	ca, err := c.caClient()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := ca.GetIdemixCredential(enrollmentID, c.caName, c.idemixProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IdemixCredential{
+		EnrollmentID:    enrollmentID,
+		Credential:      resp.Credential,
+		SecretKey:       resp.SecretKey,
+		Attributes:      resp.Attrs,
+		CRI:             resp.CRI,
+		IssuerPublicKey: resp.IssuerPublicKey,
+	}, nil
+}
+
+// VerifyIdemixProof verifies an Idemix proof offline, against the Idemix issuer public key of the CA
+//  Parameters:
+//  proof is the Idemix signature to verify
+//  nonce is the nonce (message) signed by the proof
+//  attrs are the attribute values disclosed by the proof
+//
+//  Returns:
+//  true if the proof is valid
+func (c *Client) VerifyIdemixProof(proof []byte, nonce []byte, attrs []string) (bool, error) {
+	if len(proof) == 0 {
+		return false, errors.New("proof is required")
+	}
+	if c.idemixProvider == nil {
+		return false, errors.New("Idemix provider is required")
+	}
+
+	ca, err := c.caClient()
+	if err != nil {
+		return false, err
+	}
+
+	info, err := ca.GetCAInfo()
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to get Idemix issuer public key")
+	}
+	if len(info.IssuerPublicKey) == 0 {
+		return false, errors.New("CA has no Idemix issuer public key")
+	}
+
+	return c.idemixProvider.VerifyProof(info.IssuerPublicKey, proof, nonce, attrs)
+}
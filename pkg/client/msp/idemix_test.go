@@ -0,0 +1,104 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mspapi "github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
+	"github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmspapi"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetIdemixCredential(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ca := mockmspapi.NewMockCAClient(mockCtrl)
+	c := newClientWithCAClient(ca)
+
+	_, err := c.GetIdemixCredential("user1")
+	assert.EqualError(t, err, "Idemix provider is required")
+
+	provider := &mockIdemixProvider{}
+	require.NoError(t, WithIdemixProvider(provider)(c))
+
+	_, err = c.GetIdemixCredential("")
+	assert.EqualError(t, err, "enrollment ID is required")
+
+	ca.EXPECT().GetIdemixCredential("user1", "ca1", provider).Return(&mspapi.IdemixCredentialResponse{
+		Credential:      []byte("credential"),
+		SecretKey:       []byte("sk"),
+		Attrs:           map[string]interface{}{"OU": "org1"},
+		CRI:             []byte("cri"),
+		IssuerPublicKey: []byte("ipk"),
+	}, nil)
+	credential, err := c.GetIdemixCredential("user1")
+	require.NoError(t, err)
+	assert.Equal(t, &IdemixCredential{
+		EnrollmentID:    "user1",
+		Credential:      []byte("credential"),
+		SecretKey:       []byte("sk"),
+		Attributes:      map[string]interface{}{"OU": "org1"},
+		CRI:             []byte("cri"),
+		IssuerPublicKey: []byte("ipk"),
+	}, credential)
+
+	ca.EXPECT().GetIdemixCredential("user1", "ca1", provider).Return(nil, errors.New("Error Code: 20 - Authentication failure"))
+	_, err = c.GetIdemixCredential("user1")
+	assert.Equal(t, ErrCAAuthenticationFailure.Code, err.(*CAError).Code)
+}
+
+func TestVerifyIdemixProof(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	ca := mockmspapi.NewMockCAClient(mockCtrl)
+	c := newClientWithCAClient(ca)
+
+	_, err := c.VerifyIdemixProof([]byte("proof"), []byte("nonce"), nil)
+	assert.EqualError(t, err, "Idemix provider is required")
+
+	require.NoError(t, WithIdemixProvider(&mockIdemixProvider{proof: []byte("proof")})(c))
+
+	_, err = c.VerifyIdemixProof(nil, []byte("nonce"), nil)
+	assert.EqualError(t, err, "proof is required")
+
+	ca.EXPECT().GetCAInfo().Return(&mspapi.GetCAInfoResponse{}, nil)
+	_, err = c.VerifyIdemixProof([]byte("proof"), []byte("nonce"), nil)
+	assert.EqualError(t, err, "CA has no Idemix issuer public key")
+
+	ca.EXPECT().GetCAInfo().Return(&mspapi.GetCAInfoResponse{IssuerPublicKey: []byte("ipk")}, nil).Times(2)
+	valid, err := c.VerifyIdemixProof([]byte("proof"), []byte("nonce"), []string{"org1"})
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = c.VerifyIdemixProof([]byte("forged"), []byte("nonce"), []string{"org1"})
+	require.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestWithIdemixProvider(t *testing.T) {
+	assert.EqualError(t, WithIdemixProvider(nil)(&Client{}), "Idemix provider is required")
+}
+
+// mockIdemixProvider accepts proof for the issuer public key "ipk"
+type mockIdemixProvider struct {
+	proof []byte
+}
+
+func (p *mockIdemixProvider) NewCredentialRequest(issuerPublicKey, nonce []byte) ([]byte, []byte, error) {
+	return []byte(`{}`), []byte("sk"), nil
+}
+
+func (p *mockIdemixProvider) VerifyProof(issuerPublicKey, proof, nonce []byte, attrs []string) (bool, error) {
+	return bytes.Equal(issuerPublicKey, []byte("ipk")) && bytes.Equal(proof, p.proof), nil
+}
@@ -14,8 +14,24 @@ import (
 var (
 	// ErrUserNotFound indicates the user was not found
 	ErrUserNotFound = errors.New("user not found")
+
+	// ErrCertRevoked indicates the enrollment certificate has been revoked
+	ErrCertRevoked = errors.New("certificate revoked")
+
+	// ErrNoActiveKey indicates the identity has no current private key
+	ErrNoActiveKey = errors.New("no active key")
 )
 
+// ExportedIdentity is an identity exported by ExportSigningIdentity
+type ExportedIdentity struct {
+	ID    string
+	MSPID string
+	// Cert is the PEM-encoded enrollment certificate
+	Cert []byte
+	// PrivateKey is the PKCS8 PEM-encoded private key
+	PrivateKey []byte
+}
+
 // IdentityManager provides management of identities in a Fabric network
 type IdentityManager interface {
 	GetSigningIdentity(name string) (msp.SigningIdentity, error)
@@ -0,0 +1,105 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	mspctx "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
+	"github.com/pkg/errors"
+)
+
+// archivedKeysDir is the directory of the key store to which rotated keys are moved
+const archivedKeysDir = "archived"
+
+// RotateKey rotates the key of an enrolled identity, e.g. after the CA certificate has been renewed.
+// The identity is re-enrolled with a new key pair, authenticated with its current key, and the new
+// enrollment certificate and key are stored. The previous private key is moved to the archived
+// directory of the key store with the time of the rotation (e.g. keystore/archived/<SKI>_sk.20060102T150405Z),
+// so that it is no longer used but is not deleted.
+//  Parameters:
+//  enrollmentID enrollment ID of an enrolled user
+//  opts are optional reenrollment options, as for Reenroll
+//
+//  Returns:
+//  the signing identity with the new key, or ErrNoActiveKey if the identity has no current key
+func (c *Client) RotateKey(enrollmentID string, opts ...EnrollmentOption) (mspctx.SigningIdentity, error) {
+	oldSKI, err := c.activeKeySKI(enrollmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.Reenroll(enrollmentID, opts...); err != nil {
+		return nil, errors.WithMessage(err, "key rotation failed")
+	}
+
+	si, err := c.GetSigningIdentity(enrollmentID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get rotated identity")
+	}
+	if bytes.Equal(si.PrivateKey().SKI(), oldSKI) {
+		return nil, errors.New("key rotation failed: the CA re-enrolled the identity with the current key")
+	}
+
+	keyStorePath := filepath.Join(c.ctx.IdentityConfig().CAKeyStorePath(), "keystore")
+	if err := archiveKey(keyStorePath, oldSKI, time.Now()); err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("Rotated key of [%s] from [%x] to [%x]", enrollmentID, oldSKI, si.PrivateKey().SKI())
+	return si, nil
+}
+
+// activeKeySKI returns the SKI of the current private key of the enrolled identity
+func (c *Client) activeKeySKI(enrollmentID string) ([]byte, error) {
+	orgConfig, ok := c.ctx.EndpointConfig().NetworkConfig().Organizations[strings.ToLower(c.orgName)]
+	if !ok {
+		return nil, errors.Errorf("non-existent organization: '%s'", c.orgName)
+	}
+
+	userData, err := c.ctx.UserStore().Load(mspctx.IdentityIdentifier{MSPID: orgConfig.MSPID, ID: enrollmentID})
+	if err != nil {
+		if err == mspctx.ErrUserNotFound {
+			return nil, ErrNoActiveKey
+		}
+		return nil, errors.WithMessage(err, "failed to load enrolled identity")
+	}
+
+	pubKey, err := cryptoutil.GetPublicKeyFromCert(userData.EnrollmentCertificate, c.ctx.CryptoSuite())
+	if err != nil {
+		return nil, errors.WithMessage(err, "fetching public key from cert failed")
+	}
+	key, err := c.ctx.CryptoSuite().GetKey(pubKey.SKI())
+	if err != nil || !key.Private() {
+		return nil, ErrNoActiveKey
+	}
+	return key.SKI(), nil
+}
+
+// archiveKey moves the private key file of the given SKI to the archived directory of the key store.
+// Keys that are not stored in files (e.g. keys held by an HSM) are left as they are.
+func archiveKey(keyStorePath string, ski []byte, rotated time.Time) error {
+	name := hex.EncodeToString(ski) + "_sk"
+	keyPath := filepath.Join(keyStorePath, name)
+	if _, err := os.Stat(keyPath); os.IsNotExist(err) {
+		logger.Debugf("Private key [%x] is not in the key store, so it is not archived", ski)
+		return nil
+	}
+
+	archivePath := filepath.Join(keyStorePath, archivedKeysDir)
+	if err := os.MkdirAll(archivePath, 0700); err != nil {
+		return errors.Wrap(err, "failed to create archived key directory")
+	}
+	archived := filepath.Join(archivePath, name+"."+rotated.UTC().Format("20060102T150405Z"))
+	return errors.Wrap(os.Rename(keyPath, archived), "failed to archive private key")
+}
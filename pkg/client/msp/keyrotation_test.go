@@ -0,0 +1,61 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotateKey(t *testing.T) {
+	f := testFixture{}
+	sdk := f.setup()
+	defer f.close()
+
+	msp, err := New(sdk.Context())
+	require.NoError(t, err)
+
+	_, err = msp.RotateKey(randomUsername())
+	assert.Equal(t, ErrNoActiveKey, err)
+
+	enrolledUser := getEnrolledUser(t, msp)
+	ski := enrolledUser.PrivateKey().SKI()
+
+	// The mock CA re-enrolls with the key and cert it enrolled with, so the key must not be archived
+	_, err = msp.RotateKey(enrolledUser.Identifier().ID)
+	assert.EqualError(t, err, "key rotation failed: the CA re-enrolled the identity with the current key")
+	keyStorePath := filepath.Join(f.identityConfig.CAKeyStorePath(), "keystore")
+	_, err = os.Stat(filepath.Join(keyStorePath, hex.EncodeToString(ski)+"_sk"))
+	assert.NoError(t, err, "expecting current key to remain in the key store")
+}
+
+func TestArchiveKey(t *testing.T) {
+	keyStorePath, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(keyStorePath)
+
+	ski := []byte{0x01, 0x02}
+	require.NoError(t, ioutil.WriteFile(filepath.Join(keyStorePath, "0102_sk"), []byte("key"), 0600))
+
+	rotated := time.Date(2018, 9, 1, 10, 30, 0, 0, time.UTC)
+	require.NoError(t, archiveKey(keyStorePath, ski, rotated))
+	_, err = os.Stat(filepath.Join(keyStorePath, "0102_sk"))
+	assert.True(t, os.IsNotExist(err), "expecting key to be moved out of the key store")
+	key, err := ioutil.ReadFile(filepath.Join(keyStorePath, archivedKeysDir, "0102_sk.20180901T103000Z"))
+	require.NoError(t, err)
+	assert.Equal(t, "key", string(key))
+
+	// Keys that are not in the key store are skipped
+	assert.NoError(t, archiveKey(keyStorePath, []byte{0x03}, rotated))
+}
@@ -0,0 +1,30 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import "time"
+
+// Metrics receives the outcome of the CA operations performed by the MSP client.
+// Implementations must be safe for concurrent use.
+type Metrics interface {
+	// RecordEnrollment is called after each enrollment or re-enrollment with
+	// the time it took and the resulting error, if any
+	RecordEnrollment(duration time.Duration, err error)
+
+	// RecordRevocation is called after each revocation with the resulting error, if any
+	RecordRevocation(err error)
+
+	// RecordRegistration is called after each registration with the resulting error, if any
+	RecordRegistration(err error)
+}
+
+// noopMetrics is used when no metrics are provided to the client
+type noopMetrics struct{}
+
+func (noopMetrics) RecordEnrollment(time.Duration, error) {}
+func (noopMetrics) RecordRevocation(error)                {}
+func (noopMetrics) RecordRegistration(error)              {}
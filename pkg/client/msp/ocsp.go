@@ -0,0 +1,156 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ocsp"
+)
+
+const (
+	defaultOCSPCacheTTL = 5 * time.Minute
+	ocspRequestTimeout  = 10 * time.Second
+)
+
+// ocspChecker verifies the revocation status of enrollment certificates
+// against an OCSP responder. Responses are cached per certificate serial number.
+type ocspChecker struct {
+	responderURL string
+	ttl          time.Duration
+	httpClient   *http.Client
+	mutex        sync.RWMutex
+	cache        map[string]ocspCacheEntry
+}
+
+type ocspCacheEntry struct {
+	status int
+	expiry time.Time
+}
+
+func newOCSPChecker(responderURL string, ttl time.Duration) *ocspChecker {
+	return &ocspChecker{
+		responderURL: responderURL,
+		ttl:          ttl,
+		httpClient:   &http.Client{Timeout: ocspRequestTimeout},
+		cache:        make(map[string]ocspCacheEntry),
+	}
+}
+
+// verify returns ErrCertRevoked if the OCSP responder reports the given certificate as revoked.
+// caChain is only invoked if the status of the certificate is not cached.
+func (o *ocspChecker) verify(certPEM []byte, caChain func() ([]byte, error)) error {
+	cert, err := parseCertificate(certPEM)
+	if err != nil {
+		return err
+	}
+
+	serial := cert.SerialNumber.String()
+	status, ok := o.get(serial)
+	if !ok {
+		chain, err := caChain()
+		if err != nil {
+			return errors.WithMessage(err, "failed to retrieve CA chain")
+		}
+		issuer, err := findIssuer(cert, chain)
+		if err != nil {
+			return err
+		}
+		status, err = o.query(cert, issuer)
+		if err != nil {
+			return err
+		}
+		o.put(serial, status)
+	}
+
+	switch status {
+	case ocsp.Revoked:
+		return ErrCertRevoked
+	case ocsp.Unknown:
+		logger.Warnf("OCSP responder returned unknown status for certificate [%s]", serial)
+	}
+	return nil
+}
+
+func (o *ocspChecker) query(cert, issuer *x509.Certificate) (int, error) {
+	req, err := ocsp.CreateRequest(cert, issuer, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to create OCSP request")
+	}
+
+	httpResp, err := o.httpClient.Post(o.responderURL, "application/ocsp-request", bytes.NewReader(req))
+	if err != nil {
+		return 0, errors.Wrap(err, "OCSP request failed")
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return 0, errors.Errorf("OCSP responder returned status [%d]", httpResp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(httpResp.Body)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to read OCSP response")
+	}
+
+	resp, err := ocsp.ParseResponseForCert(body, cert, issuer)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to parse OCSP response")
+	}
+	return resp.Status, nil
+}
+
+func (o *ocspChecker) get(serial string) (int, bool) {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	entry, ok := o.cache[serial]
+	if !ok || time.Now().After(entry.expiry) {
+		return 0, false
+	}
+	return entry.status, true
+}
+
+func (o *ocspChecker) put(serial string, status int) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.cache[serial] = ocspCacheEntry{status: status, expiry: time.Now().Add(o.ttl)}
+}
+
+// findIssuer returns the certificate from the PEM-encoded chain that signed cert
+func findIssuer(cert *x509.Certificate, chain []byte) (*x509.Certificate, error) {
+	for block, rest := pem.Decode(chain); block != nil; block, rest = pem.Decode(rest) {
+		candidate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse CA certificate")
+		}
+		if cert.CheckSignatureFrom(candidate) == nil {
+			return candidate, nil
+		}
+	}
+	return nil, errors.New("issuer of enrollment certificate not found in CA chain")
+}
+
+func parseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode certificate PEM")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse certificate")
+	}
+	return cert, nil
+}
@@ -0,0 +1,165 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestOCSPChecker(t *testing.T) {
+	caCert, caKey := newTestCert(t, 1, nil, nil)
+	goodCert, _ := newTestCert(t, 2, caCert, caKey)
+	revokedCert, _ := newTestCert(t, 3, caCert, caKey)
+
+	var requests int32
+	responder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		req, err := ocsp.ParseRequest(body)
+		require.NoError(t, err)
+
+		status := ocsp.Good
+		if req.SerialNumber.Cmp(revokedCert.SerialNumber) == 0 {
+			status = ocsp.Revoked
+		}
+		template := ocsp.Response{
+			Status:       status,
+			SerialNumber: req.SerialNumber,
+			ThisUpdate:   time.Now(),
+			NextUpdate:   time.Now().Add(time.Hour),
+			RevokedAt:    time.Now(),
+		}
+		resp, err := ocsp.CreateResponse(caCert, caCert, template, caKey)
+		require.NoError(t, err)
+		_, err = w.Write(resp)
+		require.NoError(t, err)
+	}))
+	defer responder.Close()
+
+	caChain := func() ([]byte, error) {
+		return toPEM(caCert), nil
+	}
+
+	checker := newOCSPChecker(responder.URL, time.Minute)
+
+	err := checker.verify(toPEM(goodCert), caChain)
+	assert.NoError(t, err)
+
+	err = checker.verify(toPEM(revokedCert), caChain)
+	assert.Equal(t, ErrCertRevoked, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// Cached responses must not hit the responder
+	err = checker.verify(toPEM(revokedCert), caChain)
+	assert.Equal(t, ErrCertRevoked, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+
+	// Expired cache entries are refreshed
+	checker = newOCSPChecker(responder.URL, time.Nanosecond)
+	err = checker.verify(toPEM(goodCert), caChain)
+	assert.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	err = checker.verify(toPEM(goodCert), caChain)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests))
+
+	// Issuer not in CA chain
+	otherCA, _ := newTestCert(t, 4, nil, nil)
+	err = checker.verify(toPEM(goodCert), func() ([]byte, error) { return toPEM(otherCA), nil })
+	assert.Error(t, err)
+
+	err = checker.verify([]byte("invalid"), caChain)
+	assert.Error(t, err)
+}
+
+func TestOCSPCheckerTimeout(t *testing.T) {
+	caCert, caKey := newTestCert(t, 1, nil, nil)
+	cert, _ := newTestCert(t, 2, caCert, caKey)
+
+	done := make(chan struct{})
+	responder := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer responder.Close()
+	defer close(done)
+
+	checker := newOCSPChecker(responder.URL, time.Minute)
+	assert.Equal(t, ocspRequestTimeout, checker.httpClient.Timeout)
+
+	// A stalled responder fails the check once the request times out
+	checker.httpClient.Timeout = 10 * time.Millisecond
+	err := checker.verify(toPEM(cert), func() ([]byte, error) { return toPEM(caCert), nil })
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "OCSP request failed")
+}
+
+func TestWithOCSPCheckError(t *testing.T) {
+	_, err := New(mockClientProvider(), WithOCSPCheck(""))
+	if err == nil {
+		t.Fatal("Should have failed due to missing OCSP responder URL")
+	}
+}
+
+// newTestCert returns a certificate issued by issuer, or a self-signed CA certificate if issuer is nil
+func newTestCert(t *testing.T, serial int64, issuer *x509.Certificate, issuerKey crypto.Signer) (*x509.Certificate, crypto.Signer) {
+	return newTestCertificate(t, serial, issuer == nil, issuer, issuerKey)
+}
+
+// newTestCACert returns an intermediate CA certificate issued by issuer
+func newTestCACert(t *testing.T, serial int64, issuer *x509.Certificate, issuerKey crypto.Signer) (*x509.Certificate, crypto.Signer) {
+	return newTestCertificate(t, serial, true, issuer, issuerKey)
+}
+
+func newTestCertificate(t *testing.T, serial int64, isCA bool, issuer *x509.Certificate, issuerKey crypto.Signer) (*x509.Certificate, crypto.Signer) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	if isCA {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	}
+	if issuer == nil {
+		issuer = template
+		issuerKey = key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, key.Public(), issuerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func toPEM(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+}
@@ -0,0 +1,98 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package prometheus provides a Prometheus implementation of the MSP client metrics.
+//
+//  Basic Flow:
+//  1) Create the metrics and register them with a Prometheus registerer
+//  2) Provide them to the MSP client using the msp.WithMetrics option
+package prometheus
+
+import (
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	namespace = "msp"
+
+	resultLabel   = "result"
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+// Metrics records MSP client operations as Prometheus metrics
+type Metrics struct {
+	enrollmentDuration *prom.HistogramVec
+	revocations        *prom.CounterVec
+	registrations      *prom.CounterVec
+}
+
+// New creates the MSP client metrics and registers them with the given registerer
+//  Parameters:
+//  registerer is the Prometheus registerer, e.g. prometheus.DefaultRegisterer
+//
+//  Returns:
+//  the MSP client metrics
+func New(registerer prom.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		enrollmentDuration: prom.NewHistogramVec(
+			prom.HistogramOpts{
+				Namespace: namespace,
+				Name:      "enrollment_duration_seconds",
+				Help:      "The time to complete enrollments and re-enrollments with the CA.",
+				Buckets:   prom.DefBuckets,
+			},
+			[]string{resultLabel},
+		),
+		revocations: prom.NewCounterVec(
+			prom.CounterOpts{
+				Namespace: namespace,
+				Name:      "revocations_total",
+				Help:      "The number of revocations performed with the CA.",
+			},
+			[]string{resultLabel},
+		),
+		registrations: prom.NewCounterVec(
+			prom.CounterOpts{
+				Namespace: namespace,
+				Name:      "registrations_total",
+				Help:      "The number of registrations performed with the CA.",
+			},
+			[]string{resultLabel},
+		),
+	}
+
+	for _, c := range []prom.Collector{m.enrollmentDuration, m.revocations, m.registrations} {
+		if err := registerer.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// RecordEnrollment observes the duration of an enrollment
+func (m *Metrics) RecordEnrollment(duration time.Duration, err error) {
+	m.enrollmentDuration.WithLabelValues(result(err)).Observe(duration.Seconds())
+}
+
+// RecordRevocation counts a revocation
+func (m *Metrics) RecordRevocation(err error) {
+	m.revocations.WithLabelValues(result(err)).Inc()
+}
+
+// RecordRegistration counts a registration
+func (m *Metrics) RecordRegistration(err error) {
+	m.registrations.WithLabelValues(result(err)).Inc()
+}
+
+func result(err error) string {
+	if err != nil {
+		return resultFailure
+	}
+	return resultSuccess
+}
@@ -0,0 +1,60 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package prometheus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/client/msp"
+	"github.com/pkg/errors"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var _ msp.Metrics = (*Metrics)(nil)
+
+func TestMetrics(t *testing.T) {
+	registry := prom.NewRegistry()
+	m, err := New(registry)
+	require.NoError(t, err)
+
+	m.RecordEnrollment(time.Second, nil)
+	m.RecordEnrollment(2*time.Second, errors.New("enrollment failed"))
+	m.RecordRevocation(nil)
+	m.RecordRegistration(nil)
+	m.RecordRegistration(nil)
+	m.RecordRegistration(errors.New("registration failed"))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			key := family.GetName() + "/" + metric.GetLabel()[0].GetValue()
+			if h := metric.GetHistogram(); h != nil {
+				values[key] = h.GetSampleSum()
+			} else {
+				values[key] = metric.GetCounter().GetValue()
+			}
+		}
+	}
+
+	assert.Equal(t, map[string]float64{
+		"msp_enrollment_duration_seconds/success": 1,
+		"msp_enrollment_duration_seconds/failure": 2,
+		"msp_revocations_total/success":           1,
+		"msp_registrations_total/success":         2,
+		"msp_registrations_total/failure":         1,
+	}, values)
+
+	// Metrics can only be registered once
+	_, err = New(registry)
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// RetryPolicy defines how CA requests that failed with a transient error are retried
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts, including the first one
+	MaxAttempts int
+	// InitialDelay is the delay before the first retry
+	InitialDelay time.Duration
+	// BackoffFactor is the factor by which the delay is multiplied after each retry
+	BackoffFactor float64
+	// ShouldRetry decides whether a failed request is retried. It defaults to IsTransientError.
+	ShouldRetry func(err error) bool
+}
+
+// DefaultRetryPolicy retries transient errors three times, starting with a delay of one second
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:   4,
+	InitialDelay:  time.Second,
+	BackoffFactor: 2,
+	ShouldRetry:   IsTransientError,
+}
+
+// noRetryPolicy is used when no retry policy is provided to the client
+var noRetryPolicy = RetryPolicy{MaxAttempts: 1}
+
+// IsTransientError returns true if the CA request failed due to a network error or
+// because the CA is overloaded or unavailable (HTTP 429 or 503).
+// Errors reported by the CA for the request itself, such as an unknown identity, are not transient.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if _, ok := errors.Cause(err).(net.Error); ok {
+		return true
+	}
+	// The Fabric CA client only reports the HTTP status as text
+	msg := err.Error()
+	for _, code := range []int{http.StatusTooManyRequests, http.StatusServiceUnavailable} {
+		if strings.Contains(msg, fmt.Sprintf("status code %d ", code)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p RetryPolicy) validate() error {
+	if p.MaxAttempts < 1 {
+		return errors.New("max attempts must be at least 1")
+	}
+	if p.InitialDelay < 0 {
+		return errors.New("initial delay must not be negative")
+	}
+	if p.BackoffFactor < 1 {
+		return errors.New("backoff factor must be at least 1")
+	}
+	return nil
+}
+
+// do invokes fn until it succeeds, the error is not retryable or the attempts are exhausted
+func (p RetryPolicy) do(op string, fn func() error) error {
+	delay := p.InitialDelay
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.MaxAttempts || !p.ShouldRetry(err) {
+			return err
+		}
+		logger.Debugf("%s attempt %d of %d failed, retrying in %s: %s", op, attempt, p.MaxAttempts, delay, err)
+		time.Sleep(delay)
+		delay = time.Duration(float64(delay) * p.BackoffFactor)
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTransientError(t *testing.T) {
+	netErr := &url.Error{Op: "Post", URL: "http://localhost:7054", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}
+
+	assert.False(t, IsTransientError(nil))
+	assert.True(t, IsTransientError(errors.Wrap(errors.Wrap(netErr, "POST failure of request"), "enroll failed")))
+	assert.True(t, IsTransientError(errors.New("Failed with server status code 503 for request:\nPOST /enroll")))
+	assert.True(t, IsTransientError(errors.New("Failed with server status code 429 for request:\nPOST /enroll")))
+	assert.False(t, IsTransientError(errors.New("Failed with server status code 401 for request:\nPOST /enroll")))
+	assert.False(t, IsTransientError(errors.New("Response from server: Error Code: 20 - Authentication failure")))
+}
+
+func TestRetryPolicy(t *testing.T) {
+	transient := errors.New("Failed with server status code 503 for request:\nPOST /enroll")
+	policy := RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond, BackoffFactor: 2, ShouldRetry: IsTransientError}
+
+	attempts := 0
+	err := policy.do("Enroll", func() error {
+		attempts++
+		if attempts < 3 {
+			return transient
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, attempts)
+
+	// Attempts are exhausted
+	attempts = 0
+	err = policy.do("Enroll", func() error {
+		attempts++
+		return transient
+	})
+	assert.Equal(t, transient, err)
+	assert.Equal(t, 3, attempts)
+
+	// Non transient errors are not retried
+	attempts = 0
+	err = policy.do("Enroll", func() error {
+		attempts++
+		return errors.New("Response from server: Error Code: 20 - Authentication failure")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+
+	// No retries by default
+	attempts = 0
+	err = noRetryPolicy.do("Enroll", func() error {
+		attempts++
+		return transient
+	})
+	assert.Equal(t, transient, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRetryPolicyValidation(t *testing.T) {
+	assert.NoError(t, DefaultRetryPolicy.validate())
+	assert.Error(t, RetryPolicy{MaxAttempts: 0, BackoffFactor: 1}.validate())
+	assert.Error(t, RetryPolicy{MaxAttempts: 1, BackoffFactor: 0.5}.validate())
+	assert.Error(t, RetryPolicy{MaxAttempts: 1, BackoffFactor: 1, InitialDelay: -time.Second}.validate())
+}
@@ -0,0 +1,72 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	minSecretLength = 8
+
+	lowerChars  = "abcdefghijklmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars  = "0123456789"
+	secretChars = lowerChars + upperChars + digitChars
+)
+
+// GenerateEnrollmentSecret generates a cryptographically random enrollment secret
+// that can be set on a RegistrationRequest. The secret contains at least one lower case
+// letter, one upper case letter and one digit, and no characters that need escaping.
+//  Parameters:
+//  length is the length of the secret, at least 8
+//
+//  Returns:
+//  the enrollment secret
+func (c *Client) GenerateEnrollmentSecret(length int) (string, error) {
+	if length < minSecretLength {
+		return "", errors.Errorf("secret length must be at least %d", minSecretLength)
+	}
+
+	secret := make([]byte, length)
+	// The first characters guarantee each character class, the remaining ones are taken from all classes
+	for i, chars := range []string{lowerChars, upperChars, digitChars} {
+		ch, err := randomChar(chars)
+		if err != nil {
+			return "", err
+		}
+		secret[i] = ch
+	}
+	for i := 3; i < length; i++ {
+		ch, err := randomChar(secretChars)
+		if err != nil {
+			return "", err
+		}
+		secret[i] = ch
+	}
+
+	// Shuffle so that the guaranteed characters are not at predictable positions
+	for i := length - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", errors.Wrap(err, "failed to generate secret")
+		}
+		secret[i], secret[j.Int64()] = secret[j.Int64()], secret[i]
+	}
+	return string(secret), nil
+}
+
+func randomChar(chars string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(chars))))
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to generate secret")
+	}
+	return chars[n.Int64()], nil
+}
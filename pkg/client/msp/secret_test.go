@@ -0,0 +1,37 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateEnrollmentSecret(t *testing.T) {
+	c := &Client{}
+
+	_, err := c.GenerateEnrollmentSecret(minSecretLength - 1)
+	assert.Error(t, err)
+
+	secrets := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		secret, err := c.GenerateEnrollmentSecret(12)
+		require.NoError(t, err)
+		assert.Len(t, secret, 12)
+		assert.True(t, strings.ContainsAny(secret, lowerChars), "missing lower case letter in %s", secret)
+		assert.True(t, strings.ContainsAny(secret, upperChars), "missing upper case letter in %s", secret)
+		assert.True(t, strings.ContainsAny(secret, digitChars), "missing digit in %s", secret)
+		for _, ch := range secret {
+			assert.True(t, strings.ContainsRune(secretChars, ch), "unexpected character in %s", secret)
+		}
+		secrets[secret] = true
+	}
+	assert.Len(t, secrets, 100, "expecting unique secrets")
+}
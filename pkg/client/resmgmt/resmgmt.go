@@ -21,10 +21,12 @@ package resmgmt
 
 import (
 	reqContext "context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"math/rand"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -32,6 +34,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/configtx"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/channel"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
@@ -115,6 +118,13 @@ type SaveChannelRequest struct {
 	SigningIdentities []msp.SigningIdentity // Users that sign channel configuration
 }
 
+// CreateChannelRequest holds parameters for creating a channel from a channel profile
+type CreateChannelRequest struct {
+	ChannelID         string
+	Profile           configtx.ChannelProfile
+	SigningIdentities []msp.SigningIdentity // Users that sign the channel creation config update (e.g. admins of the channel's organizations)
+}
+
 // SaveChannelResponse contains response parameters for save channel
 type SaveChannelResponse struct {
 	TransactionID fab.TransactionID
@@ -125,6 +135,12 @@ type RequestOption func(ctx context.Client, opts *requestOptions) error
 
 var logger = logging.NewLogger("fabsdk/client")
 
+// ErrAlreadyJoined is returned by JoinChannel if the target peers have already joined the channel
+var ErrAlreadyJoined = errors.New("peer has already joined the channel")
+
+// ledgerExistsMsg is included in the error returned by the peer when the ledger of the channel already exists
+const ledgerExistsMsg = "LedgerID already exists"
+
 // Client enables managing resources in Fabric network.
 type Client struct {
 	ctx              context.Client
@@ -190,12 +206,13 @@ func New(ctxProvider context.ClientProvider, opts ...ClientOption) (*Client, err
 }
 
 // JoinChannel allows for peers to join existing channel with optional custom options (specific peers, filtered peers). If peer(s) are not specified in options it will default to all peers that belong to client's MSP.
+// The genesis block of the channel is fetched from the orderer, which may be specified with WithOrdererEndpoint.
 //  Parameters:
 //  channel is manadatory channel name
 //  options holds optional request options
 //
 //  Returns:
-//  an error if join fails
+//  an error if join fails (ErrAlreadyJoined if all target peers have already joined the channel)
 func (rc *Client) JoinChannel(channelID string, options ...RequestOption) error {
 
 	if channelID == "" {
@@ -245,12 +262,30 @@ func (rc *Client) JoinChannel(channelID string, options ...RequestOption) error
 	defer peerReqCtxCancel()
 	err = resource.JoinChannel(peerReqCtx, joinChannelRequest, peersToTxnProcessors(targets), resource.WithRetry(opts.Retry))
 	if err != nil {
+		if isAlreadyJoined(err) {
+			return ErrAlreadyJoined
+		}
 		return errors.WithMessage(err, "join channel failed")
 	}
 
 	return nil
 }
 
+// isAlreadyJoined returns true if the join channel error of every target is due to the target having already joined the channel
+func isAlreadyJoined(err error) bool {
+	errs, ok := errors.Cause(err).(multi.Errors)
+	if !ok {
+		return strings.Contains(err.Error(), ledgerExistsMsg)
+	}
+
+	for _, e := range errs {
+		if !strings.Contains(e.Error(), ledgerExistsMsg) {
+			return false
+		}
+	}
+	return len(errs) > 0
+}
+
 // filterTargets is helper method to filter peers
 func filterTargets(peers []fab.Peer, filter fab.TargetFilter) []fab.Peer {
 
@@ -560,50 +595,83 @@ func (rc *Client) QueryInstantiatedChaincodes(channelID string, options ...Reque
 		return nil, err
 	}
 
-	chCtx, err := contextImpl.NewChannel(
-		func() (context.Client, error) {
-			return rc.ctx, nil
-		},
-		channelID,
-	)
+	target, l, v, err := rc.prepareChannelQuery(channelID, opts)
 	if err != nil {
-		return nil, errors.WithMessage(err, "failed to create channel context")
+		return nil, err
 	}
 
-	var target fab.ProposalProcessor
-	if len(opts.Targets) >= 1 {
-		target = opts.Targets[0]
-	} else {
-		// select random channel peer
-		var err error
-		target, err = rc.selectRandomChannelPeer(chCtx)
-		if err != nil {
-			return nil, err
-		}
-	}
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
 
-	l, err := channel.NewLedger(channelID)
+	responses, err := l.QueryInstantiatedChaincodes(reqCtx, []fab.ProposalProcessor{target}, v)
 	if err != nil {
 		return nil, err
 	}
 
-	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
-	defer cancel()
+	return responses[0], nil
+}
 
-	// Channel service membership is required to verify signature
-	channelService := chCtx.ChannelService()
+// ChaincodeInfo describes a chaincode instantiated on a channel
+type ChaincodeInfo struct {
+	Name     string
+	Version  string
+	Path     string
+	ESCCName string
+	VSCCName string
+	// Policy is the endorsement policy of the chaincode
+	Policy *common.SignaturePolicyEnvelope
+}
 
-	membership, err := channelService.Membership()
+// ListInstantiatedChaincodes lists the instantiated chaincodes on a peer for specific channel, including their
+// endorsement policies. If peer is not specified in options it will query random peer on this channel.
+//  Parameters:
+//  channel is manadatory channel name
+//  options hold optional request options
+//
+//  Returns:
+//  list of instantiated chaincodes
+func (rc *Client) ListInstantiatedChaincodes(channelID string, options ...RequestOption) ([]*ChaincodeInfo, error) {
+	opts, err := rc.prepareRequestOpts(options...)
 	if err != nil {
-		return nil, errors.WithMessage(err, "membership creation failed")
+		return nil, err
 	}
 
-	responses, err := l.QueryInstantiatedChaincodes(reqCtx, []fab.ProposalProcessor{target}, &verifier.Signature{Membership: membership})
+	target, l, v, err := rc.prepareChannelQuery(channelID, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	return responses[0], nil
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	targets := []fab.ProposalProcessor{target}
+	responses, err := l.QueryInstantiatedChaincodes(reqCtx, targets, v)
+	if err != nil {
+		return nil, err
+	}
+
+	var chaincodes []*ChaincodeInfo
+	for _, cc := range responses[0].Chaincodes {
+		ccData, err := l.QueryChaincodeData(reqCtx, cc.Name, targets, v)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("failed to query data of chaincode [%s]", cc.Name))
+		}
+
+		policy := &common.SignaturePolicyEnvelope{}
+		if err := proto.Unmarshal(ccData[0].Policy, policy); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal endorsement policy of chaincode [%s]", cc.Name)
+		}
+
+		chaincodes = append(chaincodes, &ChaincodeInfo{
+			Name:     cc.Name,
+			Version:  cc.Version,
+			Path:     cc.Path,
+			ESCCName: cc.Escc,
+			VSCCName: cc.Vscc,
+			Policy:   policy,
+		})
+	}
+	return chaincodes, nil
 }
 
 // QueryCollectionsConfig queries the collections config on a peer for specific channel. If peer is not specified in options it will query random peer on this channel.
@@ -620,6 +688,25 @@ func (rc *Client) QueryCollectionsConfig(channelID string, chaincodeName string,
 		return nil, err
 	}
 
+	target, l, v, err := rc.prepareChannelQuery(channelID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
+	defer cancel()
+
+	responses, err := l.QueryCollectionsConfig(reqCtx, chaincodeName, []fab.ProposalProcessor{target}, v)
+	if err != nil {
+		return nil, err
+	}
+
+	return responses[0], nil
+}
+
+// prepareChannelQuery returns the target peer, ledger and response verifier for a query on the given channel.
+// If no target is specified in the options a random channel peer is selected.
+func (rc *Client) prepareChannelQuery(channelID string, opts requestOptions) (fab.ProposalProcessor, *channel.Ledger, channel.ResponseVerifier, error) {
 	chCtx, err := contextImpl.NewChannel(
 		func() (context.Client, error) {
 			return rc.ctx, nil
@@ -627,7 +714,7 @@ func (rc *Client) QueryCollectionsConfig(channelID string, chaincodeName string,
 		channelID,
 	)
 	if err != nil {
-		return nil, errors.WithMessage(err, "failed to create channel context")
+		return nil, nil, nil, errors.WithMessage(err, "failed to create channel context")
 	}
 
 	var target fab.ProposalProcessor
@@ -635,35 +722,24 @@ func (rc *Client) QueryCollectionsConfig(channelID string, chaincodeName string,
 		target = opts.Targets[0]
 	} else {
 		// select random channel peer
-		var err error
 		target, err = rc.selectRandomChannelPeer(chCtx)
 		if err != nil {
-			return nil, err
+			return nil, nil, nil, err
 		}
 	}
 
 	l, err := channel.NewLedger(channelID)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	reqCtx, cancel := rc.createRequestContext(opts, fab.PeerResponse)
-	defer cancel()
-
 	// Channel service membership is required to verify signature
-	channelService := chCtx.ChannelService()
-
-	membership, err := channelService.Membership()
+	membership, err := chCtx.ChannelService().Membership()
 	if err != nil {
-		return nil, errors.WithMessage(err, "membership creation failed")
+		return nil, nil, nil, errors.WithMessage(err, "membership creation failed")
 	}
 
-	responses, err := l.QueryCollectionsConfig(reqCtx, chaincodeName, []fab.ProposalProcessor{target}, &verifier.Signature{Membership: membership})
-	if err != nil {
-		return nil, err
-	}
-
-	return responses[0], nil
+	return target, l, &verifier.Signature{Membership: membership}, nil
 }
 
 func (rc *Client) selectRandomChannelPeer(ctx context.Channel) (fab.ProposalProcessor, error) {
@@ -715,6 +791,31 @@ func (rc *Client) QueryChannels(options ...RequestOption) (*pb.ChannelQueryRespo
 
 }
 
+// ListChannels lists the IDs of the channels that a peer has joined, e.g. for monitoring tools that discover
+// channel membership without out-of-band configuration. The channels are queried with the cscc GetChannels query.
+//  Parameters:
+//  peer is the peer to query
+//  options hold optional request options
+//
+//  Returns:
+//  the IDs of the channels that the peer has joined
+func (rc *Client) ListChannels(peer fab.Peer, options ...RequestOption) ([]string, error) {
+	if peer == nil {
+		return nil, errors.New("peer is required")
+	}
+
+	response, err := rc.QueryChannels(append(options, WithTargets(peer))...)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list channels")
+	}
+
+	channelIDs := make([]string, len(response.Channels))
+	for i, info := range response.Channels {
+		channelIDs[i] = info.ChannelId
+	}
+	return channelIDs, nil
+}
+
 // validateSendCCProposal
 func (rc *Client) getCCProposalTargets(channelID string, req InstantiateCCRequest, opts requestOptions) ([]fab.Peer, error) {
 
@@ -938,6 +1039,46 @@ func (rc *Client) SaveChannel(req SaveChannelRequest, options ...RequestOption)
 		return SaveChannelResponse{}, errors.WithMessage(err, "extracting channel config from ConfigTx failed")
 	}
 
+	return rc.submitChannelConfig(req, chConfig, opts)
+}
+
+// CreateChannel creates a channel from the given channel profile, without the channel creation transaction
+// generated by configtxgen. The channel creation config update is generated from the profile and signed by
+// the signing identities of the request. The orderer then generates the genesis block of the channel.
+//  Parameters:
+//  req holds info about mandatory channel name and profile
+//  options holds optional request options
+//
+//  Returns:
+//  save channel response with transaction ID
+func (rc *Client) CreateChannel(req CreateChannelRequest, options ...RequestOption) (SaveChannelResponse, error) {
+
+	opts, err := rc.prepareRequestOpts(options...)
+	if err != nil {
+		return SaveChannelResponse{}, err
+	}
+
+	if req.ChannelID == "" {
+		return SaveChannelResponse{}, errors.New("must provide channel ID")
+	}
+
+	configUpdate, err := configtx.NewChannelCreateConfigUpdate(req.ChannelID, &req.Profile)
+	if err != nil {
+		return SaveChannelResponse{}, errors.WithMessage(err, "creating channel config from profile failed")
+	}
+
+	chConfig, err := proto.Marshal(configUpdate)
+	if err != nil {
+		return SaveChannelResponse{}, errors.Wrap(err, "marshal channel config failed")
+	}
+
+	logger.Debugf("creating channel: %s", req.ChannelID)
+
+	return rc.submitChannelConfig(SaveChannelRequest{ChannelID: req.ChannelID, SigningIdentities: req.SigningIdentities}, chConfig, opts)
+}
+
+// submitChannelConfig signs the given channel config update and sends it to the orderer
+func (rc *Client) submitChannelConfig(req SaveChannelRequest, chConfig []byte, opts requestOptions) (SaveChannelResponse, error) {
 	orderer, err := rc.requestOrderer(&opts, req.ChannelID)
 	if err != nil {
 		return SaveChannelResponse{}, errors.WithMessage(err, "failed to find orderer for request")
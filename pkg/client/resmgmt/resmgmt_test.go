@@ -8,6 +8,7 @@ package resmgmt
 
 import (
 	"bufio"
+	reqContext "context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -26,6 +27,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/configtx"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	configImpl "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
@@ -37,10 +39,13 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/provider/fabpvdr"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var (
@@ -126,6 +131,32 @@ func TestJoinChannelSuccess(t *testing.T) {
 
 }
 
+func TestJoinChannelAlreadyJoined(t *testing.T) {
+	srv := &fcmocks.MockEndorserServer{}
+	addr := srv.Start(testAddress)
+	defer srv.Stop()
+
+	ctx := setupTestContext("test", "Org1MSP")
+
+	// Create mock orderer with simple mock block
+	orderer := fcmocks.NewMockOrderer("", nil)
+	orderer.EnqueueForSendDeliver(fcmocks.NewSimpleMockBlock())
+	orderer.EnqueueForSendDeliver(common.Status_SUCCESS)
+	orderer.CloseQueue()
+
+	setupCustomOrderer(ctx, orderer)
+
+	rc := setupResMgmtClient(t, ctx)
+
+	// Setup target peers
+	peer1, _ := peer.New(fcmocks.NewMockEndpointConfig(), peer.WithURL("grpc://"+addr))
+
+	// The peer rejects the genesis block since its ledger already exists
+	srv.ProposalError = errors.New("Cannot create ledger from genesis block, due to LedgerID already exists")
+	err := rc.JoinChannel("mychannel", WithTargets(peer1))
+	assert.Equal(t, ErrAlreadyJoined, err)
+}
+
 func TestWithFilterOption(t *testing.T) {
 	ctx := setupTestContext("test", "Org1MSP")
 	rc := setupResMgmtClient(t, ctx, getDefaultTargetFilterOption())
@@ -461,6 +492,52 @@ func TestQueryInstantiatedChaincodes(t *testing.T) {
 	}
 }
 
+// sequenceMockPeer responds to each proposal with the next payload
+type sequenceMockPeer struct {
+	*fcmocks.MockPeer
+	payloads [][]byte
+}
+
+func (p *sequenceMockPeer) ProcessTransactionProposal(ctx reqContext.Context, tp fab.ProcessProposalRequest) (*fab.TransactionProposalResponse, error) {
+	p.Payload = p.payloads[p.ProcessProposalCalls]
+	return p.MockPeer.ProcessTransactionProposal(ctx, tp)
+}
+
+func TestListInstantiatedChaincodes(t *testing.T) {
+	rc := setupDefaultResMgmtClient(t)
+
+	_, err := rc.ListInstantiatedChaincodes("mychannel")
+	if err == nil {
+		t.Fatal("ListInstantiatedChaincodes: peer cannot be nil")
+	}
+
+	ccResponse := &pb.ChaincodeQueryResponse{Chaincodes: []*pb.ChaincodeInfo{{Name: "mycc", Version: "v1", Path: "github.com/mycc", Escc: "escc", Vscc: "vscc"}}}
+	ccResponseBytes, err := proto.Marshal(ccResponse)
+	assert.Nil(t, err)
+
+	policy := cauthdsl.SignedByMspMember("Org1MSP")
+	ccData := &ccprovider.ChaincodeData{Name: "mycc", Version: "v1", Escc: "escc", Vscc: "vscc", Policy: protos_utils.MarshalOrPanic(policy)}
+	ccDataBytes, err := proto.Marshal(ccData)
+	assert.Nil(t, err)
+
+	peer := &sequenceMockPeer{
+		MockPeer: &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP", Status: http.StatusOK},
+		payloads: [][]byte{ccResponseBytes, ccDataBytes},
+	}
+
+	chaincodes, err := rc.ListInstantiatedChaincodes("mychannel", WithTargets(peer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, chaincodes, 1)
+	assert.Equal(t, "mycc", chaincodes[0].Name)
+	assert.Equal(t, "v1", chaincodes[0].Version)
+	assert.Equal(t, "github.com/mycc", chaincodes[0].Path)
+	assert.Equal(t, "escc", chaincodes[0].ESCCName)
+	assert.Equal(t, "vscc", chaincodes[0].VSCCName)
+	assert.True(t, proto.Equal(policy, chaincodes[0].Policy))
+}
+
 func TestQueryCollectionsConfig(t *testing.T) {
 	rc := setupDefaultResMgmtClient(t)
 
@@ -520,6 +597,26 @@ func TestQueryChannels(t *testing.T) {
 
 }
 
+func TestListChannels(t *testing.T) {
+
+	rc := setupDefaultResMgmtClient(t)
+
+	responseBytes, err := proto.Marshal(&pb.ChannelQueryResponse{Channels: []*pb.ChannelInfo{{ChannelId: "mychannel"}, {ChannelId: "orgchannel"}}})
+	require.NoError(t, err)
+
+	_, err = rc.ListChannels(nil)
+	assert.EqualError(t, err, "peer is required")
+
+	peer := &fcmocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, MockMSP: "Org1MSP", Status: http.StatusOK, Payload: responseBytes}
+	channelIDs, err := rc.ListChannels(peer)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mychannel", "orgchannel"}, channelIDs)
+
+	peer.Status = http.StatusInternalServerError
+	_, err = rc.ListChannels(peer)
+	assert.Error(t, err)
+}
+
 func TestInstallCCWithOpts(t *testing.T) {
 
 	rc := setupDefaultResMgmtClient(t)
@@ -1133,6 +1230,44 @@ func TestSaveChannelSuccess(t *testing.T) {
 	assert.NotEmpty(t, resp.TransactionID, "transaction ID should be populated")
 }
 
+func TestCreateChannel(t *testing.T) {
+
+	mb := fcmocks.MockBroadcastServer{}
+	addr := mb.Start("127.0.0.1:0")
+	defer mb.Stop()
+
+	ctx := setupTestContext("test", "Org1MSP")
+
+	mockConfig := &fcmocks.MockConfig{}
+	grpcOpts := make(map[string]interface{})
+	grpcOpts["allow-insecure"] = true
+
+	oConfig := &fab.OrdererConfig{
+		URL:         addr,
+		GRPCOptions: grpcOpts,
+	}
+	mockConfig.SetCustomOrdererCfg(oConfig)
+	ctx.SetEndpointConfig(mockConfig)
+
+	cc := setupResMgmtClient(t, ctx)
+
+	profile := configtx.ChannelProfile{
+		Consortium:    "SampleConsortium",
+		Organizations: []string{"Org1MSP", "Org2MSP"},
+		Capabilities:  []string{fab.V1_2Capability},
+	}
+
+	_, err := cc.CreateChannel(CreateChannelRequest{Profile: profile})
+	assert.NotNil(t, err, "Should have failed for empty channel ID")
+
+	_, err = cc.CreateChannel(CreateChannelRequest{ChannelID: "mychannel", Profile: configtx.ChannelProfile{Organizations: profile.Organizations}})
+	assert.NotNil(t, err, "Should have failed for profile without consortium")
+
+	resp, err := cc.CreateChannel(CreateChannelRequest{ChannelID: "mychannel", Profile: profile}, WithOrdererEndpoint("example.com"))
+	assert.Nil(t, err, "error should be nil")
+	assert.NotEmpty(t, resp.TransactionID, "transaction ID should be populated")
+}
+
 func TestSaveChannelFailure(t *testing.T) {
 
 	// Set up context with error in create channel
@@ -32,3 +32,13 @@ type ConfigProvider func() ([]ConfigBackend, error)
 type ConfigBackend interface {
 	Lookup(key string) (interface{}, bool)
 }
+
+// ConfigChangeNotifier is implemented by config backends that notify of changes of their config
+// (e.g. a config backend that watches a key-value store)
+type ConfigChangeNotifier interface {
+	// ConfigLocation returns the location of the config (e.g. a URL)
+	ConfigLocation() string
+	// ConfigChanges returns a channel on which the YAML config is published each time it changes.
+	// The channel is closed once done is closed.
+	ConfigChanges(done <-chan struct{}) <-chan []byte
+}
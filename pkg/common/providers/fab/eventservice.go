@@ -113,6 +113,16 @@ type ConnectionEvent struct {
 	Err       error
 }
 
+// ReconnectEvent is sent when the client has reconnected to the event server after the
+// connection was lost. The registrations of the client remain active across the reconnect.
+type ReconnectEvent struct {
+	// LastBlockNum is the number of the last block received before the reconnect,
+	// or math.MaxUint64 if no block was received
+	LastBlockNum uint64
+	// Attempts is the number of connection attempts that were needed to reconnect
+	Attempts uint
+}
+
 // EventSnapshot contains a snapshot of the event client before it was stopped.
 // The snapshot includes all of the event registrations and the last block received.
 type EventSnapshot interface {
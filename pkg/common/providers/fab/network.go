@@ -135,6 +135,8 @@ type PeerConfig struct {
 	URL         string
 	GRPCOptions map[string]interface{}
 	TLSCACert   *x509.Certificate
+	// Properties contains application-defined properties of the peer (e.g. "locality")
+	Properties map[string]interface{}
 }
 
 // CertKeyPair contains the private key and certificate
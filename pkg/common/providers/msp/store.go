@@ -12,6 +12,9 @@ type UserData struct {
 	ID                    string
 	MSPID                 string
 	EnrollmentCertificate []byte
+	// Label is an optional display name of the user. It is loaded by user stores that
+	// implement UserLabelStore and set with StoreLabel; Store leaves it unchanged.
+	Label string
 }
 
 // UserStore is responsible for UserData persistence
@@ -20,6 +23,29 @@ type UserStore interface {
 	Load(IdentityIdentifier) (*UserData, error)
 }
 
+// UserStoreLister is implemented by user stores that are able to enumerate their contents
+type UserStoreLister interface {
+	LoadAll() ([]*UserData, error)
+}
+
+// UserLabelStore is implemented by user stores that are able to store labels of users.
+// Storing an empty label removes the label.
+type UserLabelStore interface {
+	StoreLabel(id IdentityIdentifier, label string) error
+}
+
+// UserIdemixCredentialStore is implemented by user stores that are able to store the Idemix credentials
+// of users. LoadIdemixCredential returns nil if the user has no Idemix credential.
+type UserIdemixCredentialStore interface {
+	StoreIdemixCredential(id IdentityIdentifier, credential []byte) error
+	LoadIdemixCredential(id IdentityIdentifier) ([]byte, error)
+}
+
+// UserStoreDeleter is implemented by user stores that are able to delete users
+type UserStoreDeleter interface {
+	Delete(IdentityIdentifier) error
+}
+
 // PrivKeyKey is a composite key for accessing a private key in the key store
 type PrivKeyKey struct {
 	ID    string
@@ -0,0 +1,116 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"github.com/golang/protobuf/proto"
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// ChannelProfile defines the configuration of a new application channel
+type ChannelProfile struct {
+	// Consortium is the name of the consortium (defined in the orderer system channel) that the channel is created in
+	Consortium string
+	// Organizations are the names of the consortium's organizations that are members of the channel
+	Organizations []string
+	// Capabilities are the application capabilities of the channel (e.g. V1_2)
+	Capabilities []string
+}
+
+// NewChannelCreateConfigUpdate returns the config update that creates the given channel, equivalent to the
+// channel creation transaction generated by configtxgen. The orderer generates the genesis block of the
+// channel from the update, using the definitions of the organizations in the consortium and the orderer
+// configuration of the orderer system channel.
+func NewChannelCreateConfigUpdate(channelID string, profile *ChannelProfile) (*common.ConfigUpdate, error) {
+	if channelID == "" {
+		return nil, errors.New("channel ID is required")
+	}
+	if profile == nil || profile.Consortium == "" {
+		return nil, &ValidationError{Field: "channel profile", Reason: "consortium is required"}
+	}
+	if len(profile.Organizations) == 0 {
+		return nil, &ValidationError{Field: "channel profile", Reason: "at least one organization is required"}
+	}
+
+	// The organizations are defined by the consortium, so the update only refers to them
+	readSetOrgs := make(map[string]*common.ConfigGroup)
+	writeSetOrgs := make(map[string]*common.ConfigGroup)
+	for _, org := range profile.Organizations {
+		if org == "" {
+			return nil, &ValidationError{Field: "channel profile", Reason: "organization name is required"}
+		}
+		readSetOrgs[org] = &common.ConfigGroup{}
+		writeSetOrgs[org] = &common.ConfigGroup{}
+	}
+
+	consortium, err := proto.Marshal(&common.Consortium{Name: profile.Consortium})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal consortium failed")
+	}
+
+	application := &common.ConfigGroup{
+		Version:   1,
+		ModPolicy: adminsPolicyName,
+		Groups:    writeSetOrgs,
+		Values:    make(map[string]*common.ConfigValue),
+		Policies:  make(map[string]*common.ConfigPolicy),
+	}
+
+	policies := map[string]common.ImplicitMetaPolicy_Rule{
+		channelConfig.ReadersPolicyKey: common.ImplicitMetaPolicy_ANY,
+		channelConfig.WritersPolicyKey: common.ImplicitMetaPolicy_ANY,
+		channelConfig.AdminsPolicyKey:  common.ImplicitMetaPolicy_MAJORITY,
+	}
+	for name, rule := range policies {
+		policy, err := implicitMetaPolicy(name, rule)
+		if err != nil {
+			return nil, err
+		}
+		application.Policies[name] = &common.ConfigPolicy{Policy: policy, ModPolicy: adminsPolicyName}
+	}
+
+	if len(profile.Capabilities) > 0 {
+		capabilities := &common.Capabilities{Capabilities: make(map[string]*common.Capability)}
+		for _, capability := range profile.Capabilities {
+			capabilities.Capabilities[capability] = &common.Capability{}
+		}
+		if err := setValue(application, channelConfig.CapabilitiesKey, capabilities); err != nil {
+			return nil, err
+		}
+	}
+
+	return &common.ConfigUpdate{
+		ChannelId: channelID,
+		ReadSet: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{
+				applicationGroupKey: {Groups: readSetOrgs},
+			},
+			Values: map[string]*common.ConfigValue{
+				channelConfig.ConsortiumKey: {},
+			},
+		},
+		WriteSet: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{
+				applicationGroupKey: application,
+			},
+			Values: map[string]*common.ConfigValue{
+				channelConfig.ConsortiumKey: {Value: consortium},
+			},
+		},
+	}, nil
+}
+
+// implicitMetaPolicy returns a policy that is satisfied according to the given rule by the sub-policies with the given name
+func implicitMetaPolicy(subPolicy string, rule common.ImplicitMetaPolicy_Rule) (*common.Policy, error) {
+	value, err := proto.Marshal(&common.ImplicitMetaPolicy{SubPolicy: subPolicy, Rule: rule})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal implicit meta policy failed")
+	}
+	return &common.Policy{Type: int32(common.Policy_IMPLICIT_META), Value: value}, nil
+}
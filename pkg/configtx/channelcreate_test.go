@@ -0,0 +1,56 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func TestNewChannelCreateConfigUpdate(t *testing.T) {
+	_, err := NewChannelCreateConfigUpdate("", &ChannelProfile{Consortium: "SampleConsortium", Organizations: []string{"Org1MSP"}})
+	assert.Error(t, err, "expected error for empty channel ID")
+
+	var validationErr *ValidationError
+	_, err = NewChannelCreateConfigUpdate("mychannel", &ChannelProfile{Organizations: []string{"Org1MSP"}})
+	assert.IsType(t, validationErr, err, "expected validation error for profile without consortium")
+	_, err = NewChannelCreateConfigUpdate("mychannel", &ChannelProfile{Consortium: "SampleConsortium"})
+	assert.IsType(t, validationErr, err, "expected validation error for profile without organizations")
+
+	update, err := NewChannelCreateConfigUpdate("mychannel", &ChannelProfile{
+		Consortium:    "SampleConsortium",
+		Organizations: []string{"Org1MSP", "Org2MSP"},
+		Capabilities:  []string{fab.V1_2Capability},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "mychannel", update.ChannelId)
+
+	consortium := &common.Consortium{}
+	require.NoError(t, proto.Unmarshal(update.WriteSet.Values[channelConfig.ConsortiumKey].Value, consortium))
+	assert.Equal(t, "SampleConsortium", consortium.Name)
+
+	readSetApp := update.ReadSet.Groups[applicationGroupKey]
+	require.NotNil(t, readSetApp)
+	assert.Len(t, readSetApp.Groups, 2)
+
+	writeSetApp := update.WriteSet.Groups[applicationGroupKey]
+	require.NotNil(t, writeSetApp)
+	assert.EqualValues(t, 1, writeSetApp.Version)
+	assert.Len(t, writeSetApp.Groups, 2)
+	assert.Len(t, writeSetApp.Policies, 3)
+
+	capabilities, err := loadCapabilities(writeSetApp.Values[channelConfig.CapabilitiesKey])
+	require.NoError(t, err)
+	assert.Equal(t, []string{fab.V1_2Capability}, capabilities)
+}
@@ -0,0 +1,373 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package configtx decodes channel configuration blocks (e.g. the blocks returned by QueryConfigBlock)
+// into a typed configuration.
+//
+//  Basic Flow:
+//  1) Query the config block of the channel
+//  2) Parse the block using ParseConfigBlock
+//  3) Inspect the application, orderer or consortiums configuration
+package configtx
+
+import (
+	"sort"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	applicationGroupKey = "Application"
+	consortiumsGroupKey = "Consortiums"
+)
+
+// Config is the decoded configuration of a channel
+type Config struct {
+	channelID        string
+	blockNumber      uint64
+	sequence         uint64
+	consortium       string
+	hashingAlgorithm string
+	ordererAddresses []string
+	capabilities     []string
+	application      *ApplicationConfig
+	orderer          *OrdererConfig
+	consortiums      []*ConsortiumConfig
+	raw              *common.Config
+}
+
+// Organization is the configuration of an organization of the channel
+type Organization struct {
+	Name string
+	// MSPID is the ID of the organization's MSP
+	MSPID string
+	// MSP is the config of the organization's MSP (nil if the MSP is not a Fabric MSP)
+	MSP         *mb.FabricMSPConfig
+	AnchorPeers []*pb.AnchorPeer
+}
+
+// ApplicationConfig is the configuration of the application (peer) organizations of an application channel
+type ApplicationConfig struct {
+	Organizations []*Organization
+	Capabilities  []string
+}
+
+// OrdererConfig is the configuration of the ordering service of the channel
+type OrdererConfig struct {
+	ConsensusType string
+	BatchSize     *ab.BatchSize
+	BatchTimeout  time.Duration
+	KafkaBrokers  []string
+	Organizations []*Organization
+	Capabilities  []string
+}
+
+// ConsortiumConfig is the configuration of a consortium of the orderer system channel
+type ConsortiumConfig struct {
+	Name          string
+	Organizations []*Organization
+}
+
+// ParseConfigBlock decodes the channel configuration contained in the given config block.
+func ParseConfigBlock(block *common.Block) (*Config, error) {
+	if block == nil {
+		return nil, errors.New("block is required")
+	}
+	if block.Header == nil {
+		return nil, errors.New("expected header in block")
+	}
+	if block.Data == nil || len(block.Data.Data) == 0 {
+		return nil, errors.New("block has no data")
+	}
+
+	channelID, configEnvelope, err := unmarshalConfigEnvelope(block.Data.Data[0])
+	if err != nil {
+		return nil, err
+	}
+	if configEnvelope.Config == nil || configEnvelope.Config.ChannelGroup == nil {
+		return nil, errors.New("config envelope has no channel group")
+	}
+
+	config := &Config{
+		channelID:   channelID,
+		blockNumber: block.Header.Number,
+		sequence:    configEnvelope.Config.Sequence,
+		raw:         configEnvelope.Config,
+	}
+	if err := config.load(configEnvelope.Config.ChannelGroup); err != nil {
+		return nil, errors.WithMessage(err, "failed to load channel config")
+	}
+	return config, nil
+}
+
+// ChannelID returns the ID of the channel
+func (c *Config) ChannelID() string {
+	return c.channelID
+}
+
+// BlockNumber returns the number of the config block
+func (c *Config) BlockNumber() uint64 {
+	return c.blockNumber
+}
+
+// Sequence returns the sequence number of the configuration, which is incremented on each config update
+func (c *Config) Sequence() uint64 {
+	return c.sequence
+}
+
+// Consortium returns the name of the consortium that the application channel was created in
+func (c *Config) Consortium() string {
+	return c.consortium
+}
+
+// HashingAlgorithm returns the name of the hashing algorithm used for the blocks of the channel
+func (c *Config) HashingAlgorithm() string {
+	return c.hashingAlgorithm
+}
+
+// OrdererAddresses returns the addresses of the orderers of the channel
+func (c *Config) OrdererAddresses() []string {
+	return c.ordererAddresses
+}
+
+// Capabilities returns the channel capabilities
+func (c *Config) Capabilities() []string {
+	return c.capabilities
+}
+
+// Application returns the application configuration, or nil if the channel is not an application channel
+func (c *Config) Application() *ApplicationConfig {
+	return c.application
+}
+
+// Orderer returns the orderer configuration, or nil if the block does not contain an orderer configuration
+func (c *Config) Orderer() *OrdererConfig {
+	return c.orderer
+}
+
+// Consortiums returns the consortiums, which are only defined in the orderer system channel
+func (c *Config) Consortiums() []*ConsortiumConfig {
+	return c.consortiums
+}
+
+// Raw returns the config proto
+func (c *Config) Raw() *common.Config {
+	return c.raw
+}
+
+func (c *Config) load(group *common.ConfigGroup) error {
+	for key, value := range group.Values {
+		var err error
+		switch key {
+		case channelConfig.ConsortiumKey:
+			consortium := &common.Consortium{}
+			err = unmarshalValue(key, value, consortium)
+			c.consortium = consortium.Name
+		case channelConfig.HashingAlgorithmKey:
+			hashingAlgorithm := &common.HashingAlgorithm{}
+			err = unmarshalValue(key, value, hashingAlgorithm)
+			c.hashingAlgorithm = hashingAlgorithm.Name
+		case channelConfig.OrdererAddressesKey:
+			addresses := &common.OrdererAddresses{}
+			err = unmarshalValue(key, value, addresses)
+			c.ordererAddresses = addresses.Addresses
+		case channelConfig.CapabilitiesKey:
+			c.capabilities, err = loadCapabilities(value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if g, ok := group.Groups[applicationGroupKey]; ok {
+		if c.application, err = loadApplication(g); err != nil {
+			return errors.WithMessage(err, "failed to load application config")
+		}
+	}
+	if g, ok := group.Groups[channelConfig.OrdererGroupKey]; ok {
+		if c.orderer, err = loadOrderer(g); err != nil {
+			return errors.WithMessage(err, "failed to load orderer config")
+		}
+	}
+	if g, ok := group.Groups[consortiumsGroupKey]; ok {
+		if c.consortiums, err = loadConsortiums(g); err != nil {
+			return errors.WithMessage(err, "failed to load consortiums config")
+		}
+	}
+	return nil
+}
+
+func loadApplication(group *common.ConfigGroup) (*ApplicationConfig, error) {
+	app := &ApplicationConfig{}
+
+	var err error
+	if value, ok := group.Values[channelConfig.CapabilitiesKey]; ok {
+		if app.Capabilities, err = loadCapabilities(value); err != nil {
+			return nil, err
+		}
+	}
+	if app.Organizations, err = loadOrganizations(group); err != nil {
+		return nil, err
+	}
+	return app, nil
+}
+
+func loadOrderer(group *common.ConfigGroup) (*OrdererConfig, error) {
+	orderer := &OrdererConfig{}
+
+	for key, value := range group.Values {
+		var err error
+		switch key {
+		case channelConfig.ConsensusTypeKey:
+			consensusType := &ab.ConsensusType{}
+			err = unmarshalValue(key, value, consensusType)
+			orderer.ConsensusType = consensusType.Type
+		case channelConfig.BatchSizeKey:
+			orderer.BatchSize = &ab.BatchSize{}
+			err = unmarshalValue(key, value, orderer.BatchSize)
+		case channelConfig.BatchTimeoutKey:
+			batchTimeout := &ab.BatchTimeout{}
+			if err = unmarshalValue(key, value, batchTimeout); err == nil {
+				orderer.BatchTimeout, err = time.ParseDuration(batchTimeout.Timeout)
+				err = errors.Wrap(err, "invalid batch timeout")
+			}
+		case channelConfig.KafkaBrokersKey:
+			kafkaBrokers := &ab.KafkaBrokers{}
+			err = unmarshalValue(key, value, kafkaBrokers)
+			orderer.KafkaBrokers = kafkaBrokers.Brokers
+		case channelConfig.CapabilitiesKey:
+			orderer.Capabilities, err = loadCapabilities(value)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if orderer.Organizations, err = loadOrganizations(group); err != nil {
+		return nil, err
+	}
+	return orderer, nil
+}
+
+func loadConsortiums(group *common.ConfigGroup) ([]*ConsortiumConfig, error) {
+	var consortiums []*ConsortiumConfig
+	for _, name := range sortedKeys(group.Groups) {
+		orgs, err := loadOrganizations(group.Groups[name])
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to load consortium "+name)
+		}
+		consortiums = append(consortiums, &ConsortiumConfig{Name: name, Organizations: orgs})
+	}
+	return consortiums, nil
+}
+
+// loadOrganizations loads the organizations of the given group, sorted by name
+func loadOrganizations(group *common.ConfigGroup) ([]*Organization, error) {
+	var orgs []*Organization
+	for _, name := range sortedKeys(group.Groups) {
+		org, err := loadOrganization(name, group.Groups[name])
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to load organization "+name)
+		}
+		orgs = append(orgs, org)
+	}
+	return orgs, nil
+}
+
+func loadOrganization(name string, group *common.ConfigGroup) (*Organization, error) {
+	org := &Organization{Name: name}
+
+	if value, ok := group.Values[channelConfig.MSPKey]; ok {
+		mspConfig := &mb.MSPConfig{}
+		if err := unmarshalValue(channelConfig.MSPKey, value, mspConfig); err != nil {
+			return nil, err
+		}
+		// MSP type 0 is the Fabric MSP
+		if mspConfig.Type == 0 {
+			org.MSP = &mb.FabricMSPConfig{}
+			if err := proto.Unmarshal(mspConfig.Config, org.MSP); err != nil {
+				return nil, errors.Wrap(err, "unmarshal FabricMSPConfig failed")
+			}
+			org.MSPID = org.MSP.Name
+		}
+	}
+
+	if value, ok := group.Values[channelConfig.AnchorPeersKey]; ok {
+		anchorPeers := &pb.AnchorPeers{}
+		if err := unmarshalValue(channelConfig.AnchorPeersKey, value, anchorPeers); err != nil {
+			return nil, err
+		}
+		org.AnchorPeers = anchorPeers.AnchorPeers
+	}
+
+	return org, nil
+}
+
+// loadCapabilities returns the names of the capabilities in the given value, sorted by name
+func loadCapabilities(value *common.ConfigValue) ([]string, error) {
+	capabilities := &common.Capabilities{}
+	if err := unmarshalValue(channelConfig.CapabilitiesKey, value, capabilities); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range capabilities.Capabilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func unmarshalValue(key string, value *common.ConfigValue, msg proto.Message) error {
+	if err := proto.Unmarshal(value.Value, msg); err != nil {
+		return errors.Wrapf(err, "unmarshal %s from config failed", key)
+	}
+	return nil
+}
+
+func unmarshalConfigEnvelope(data []byte) (string, *common.ConfigEnvelope, error) {
+	envelope := &common.Envelope{}
+	if err := proto.Unmarshal(data, envelope); err != nil {
+		return "", nil, errors.Wrap(err, "unmarshal envelope from config block failed")
+	}
+	payload := &common.Payload{}
+	if err := proto.Unmarshal(envelope.Payload, payload); err != nil {
+		return "", nil, errors.Wrap(err, "unmarshal payload from envelope failed")
+	}
+	if payload.Header == nil {
+		return "", nil, errors.New("expected header in payload")
+	}
+	channelHeader := &common.ChannelHeader{}
+	if err := proto.Unmarshal(payload.Header.ChannelHeader, channelHeader); err != nil {
+		return "", nil, errors.Wrap(err, "unmarshal channel header from payload failed")
+	}
+	if common.HeaderType(channelHeader.Type) != common.HeaderType_CONFIG {
+		return "", nil, errors.New("block must be of type 'CONFIG'")
+	}
+	configEnvelope := &common.ConfigEnvelope{}
+	if err := proto.Unmarshal(payload.Data, configEnvelope); err != nil {
+		return "", nil, errors.Wrap(err, "unmarshal config envelope failed")
+	}
+	return channelHeader.ChannelId, configEnvelope, nil
+}
+
+func sortedKeys(groups map[string]*common.ConfigGroup) []string {
+	var keys []string
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+)
+
+func TestParseConfigBlock(t *testing.T) {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:               "Admins",
+			MSPNames:                []string{"Org2MSP", "Org1MSP"},
+			OrdererAddress:          "localhost:7050",
+			RootCA:                  "root-ca",
+			ChannelCapabilities:     []string{fab.V1_1Capability},
+			OrdererCapabilities:     []string{fab.V1_1Capability},
+			ApplicationCapabilities: []string{fab.V1_2Capability, fab.V1_1Capability},
+		},
+		Index: 3,
+	}
+
+	config, err := ParseConfigBlock(builder.Build())
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 3, config.BlockNumber())
+	assert.Equal(t, []string{"localhost:7050"}, config.OrdererAddresses())
+	assert.Equal(t, []string{fab.V1_1Capability}, config.Capabilities())
+	assert.NotNil(t, config.Raw())
+	assert.Empty(t, config.Consortiums())
+
+	app := config.Application()
+	require.NotNil(t, app)
+	assert.Equal(t, []string{fab.V1_1Capability, fab.V1_2Capability}, app.Capabilities)
+	require.Len(t, app.Organizations, 2)
+	assert.Equal(t, "Org1MSP", app.Organizations[0].Name)
+	assert.Equal(t, "Org1MSP", app.Organizations[0].MSPID)
+	require.NotNil(t, app.Organizations[0].MSP)
+	assert.Equal(t, [][]byte{[]byte("root-ca")}, app.Organizations[0].MSP.RootCerts)
+	assert.Equal(t, "Org2MSP", app.Organizations[1].Name)
+
+	orderer := config.Orderer()
+	require.NotNil(t, orderer)
+	assert.Equal(t, "sample-Consensus-Type", orderer.ConsensusType)
+	require.NotNil(t, orderer.BatchSize)
+	assert.EqualValues(t, 10, orderer.BatchSize.MaxMessageCount)
+	assert.Equal(t, 2*time.Second, orderer.BatchTimeout)
+	assert.Equal(t, []string{fab.V1_1Capability}, orderer.Capabilities)
+	require.Len(t, orderer.Organizations, 1)
+	assert.Equal(t, "OrdererMSP", orderer.Organizations[0].MSPID)
+}
+
+func TestParseConfigBlockInvalid(t *testing.T) {
+	_, err := ParseConfigBlock(nil)
+	assert.Error(t, err, "expected error for nil block")
+
+	_, err = ParseConfigBlock(&common.Block{Header: &common.BlockHeader{}, Data: &common.BlockData{}})
+	assert.Error(t, err, "expected error for block without data")
+
+	_, err = ParseConfigBlock(mocks.NewSimpleMockBlock())
+	assert.Error(t, err, "expected error for block that is not a config block")
+}
@@ -0,0 +1,338 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/resource"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+const adminsPolicyName = channelConfig.AdminsPolicyKey
+
+// ValidationError is returned by the setters of Modifier if a value violates Fabric's constraints
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Reason)
+}
+
+// Modifier modifies a copy of a channel config and creates the config update that applies the modifications.
+type Modifier struct {
+	channelID string
+	original  *common.Config
+	updated   *common.Config
+}
+
+// NewModifier returns a Modifier for the given config. The given config is not modified.
+func NewModifier(config *Config) (*Modifier, error) {
+	if config == nil || config.Raw() == nil || config.Raw().ChannelGroup == nil {
+		return nil, errors.New("config with channel group is required")
+	}
+
+	return &Modifier{
+		channelID: config.ChannelID(),
+		original:  config.Raw(),
+		updated:   proto.Clone(config.Raw()).(*common.Config),
+	}, nil
+}
+
+// SetBatchSize sets the batch size of the orderer.
+//  Parameters:
+//  maxMessageCount is the maximum number of transactions in a block
+//  absoluteMaxBytes is the maximum size of a block
+//  preferredMaxBytes is the preferred maximum size of a block (a block may exceed it if a single transaction does)
+func (m *Modifier) SetBatchSize(maxMessageCount, absoluteMaxBytes, preferredMaxBytes uint32) error {
+	if maxMessageCount == 0 {
+		return &ValidationError{Field: "batch size", Reason: "max message count must be greater than zero"}
+	}
+	if absoluteMaxBytes == 0 {
+		return &ValidationError{Field: "batch size", Reason: "absolute max bytes must be greater than zero"}
+	}
+	if preferredMaxBytes == 0 || preferredMaxBytes > absoluteMaxBytes {
+		return &ValidationError{Field: "batch size", Reason: "preferred max bytes must be greater than zero and at most absolute max bytes"}
+	}
+
+	group, err := m.group(channelConfig.OrdererGroupKey)
+	if err != nil {
+		return err
+	}
+
+	return setValue(group, channelConfig.BatchSizeKey, &ab.BatchSize{
+		MaxMessageCount:   maxMessageCount,
+		AbsoluteMaxBytes:  absoluteMaxBytes,
+		PreferredMaxBytes: preferredMaxBytes,
+	})
+}
+
+// SetBatchTimeout sets the time that the orderer waits for transactions before it cuts a block.
+func (m *Modifier) SetBatchTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return &ValidationError{Field: "batch timeout", Reason: "timeout must be positive"}
+	}
+
+	group, err := m.group(channelConfig.OrdererGroupKey)
+	if err != nil {
+		return err
+	}
+
+	return setValue(group, channelConfig.BatchTimeoutKey, &ab.BatchTimeout{Timeout: timeout.String()})
+}
+
+// AddOrg adds the given organization to the application organizations of the channel. The Readers and Writers
+// policies of the organization are satisfied by any member of the organization's MSP, and the Admins policy by
+// an admin of the MSP.
+func (m *Modifier) AddOrg(org *Organization) error {
+	if org == nil || org.Name == "" {
+		return &ValidationError{Field: "organization", Reason: "name is required"}
+	}
+	if org.MSP == nil || org.MSP.Name == "" {
+		return &ValidationError{Field: "organization", Reason: "Fabric MSP config with name is required"}
+	}
+	if org.MSPID != "" && org.MSPID != org.MSP.Name {
+		return &ValidationError{Field: "organization", Reason: "MSP ID does not match the name of the MSP config"}
+	}
+	if len(org.MSP.RootCerts) == 0 {
+		return &ValidationError{Field: "organization", Reason: "MSP config must have at least one root certificate"}
+	}
+
+	group, err := m.group(applicationGroupKey)
+	if err != nil {
+		return err
+	}
+	if _, ok := group.Groups[org.Name]; ok {
+		return &ValidationError{Field: "organization", Reason: "organization " + org.Name + " already exists"}
+	}
+
+	orgGroup, err := newOrgGroup(org)
+	if err != nil {
+		return err
+	}
+	if group.Groups == nil {
+		group.Groups = make(map[string]*common.ConfigGroup)
+	}
+	group.Groups[org.Name] = orgGroup
+	return nil
+}
+
+// UpdatePolicy replaces the existing policy with the given name in the given group.
+//  Parameters:
+//  groupPath is the path of the group relative to the channel group, separated by "/" (e.g. "Application/Org1MSP"); empty for the channel group
+//  name is the name of the policy (e.g. "Admins")
+//  policy is the new policy, which must be a signature or implicit meta policy
+func (m *Modifier) UpdatePolicy(groupPath string, name string, policy *common.Policy) error {
+	if policy == nil {
+		return &ValidationError{Field: "policy", Reason: "policy is required"}
+	}
+	if err := validatePolicy(policy); err != nil {
+		return err
+	}
+
+	group, err := m.group(groupPath)
+	if err != nil {
+		return err
+	}
+	configPolicy, ok := group.Policies[name]
+	if !ok {
+		return &ValidationError{Field: "policy", Reason: "policy " + name + " does not exist in group [" + groupPath + "]"}
+	}
+
+	configPolicy.Policy = policy
+	return nil
+}
+
+// ConfigUpdate computes the config update that applies the modifications. ErrNoChanges is
+// returned if the config was not modified.
+func (m *Modifier) ConfigUpdate() (*common.ConfigUpdate, error) {
+	return computeUpdate(m.channelID, m.original, m.updated)
+}
+
+// CreateSignedEnvelope creates the config update envelope for the modifications, signed by the user of the
+// given context. The marshalled envelope can be submitted with the SaveChannel function of the resource
+// management client, which adds the signatures of the signing identities of the request.
+func (m *Modifier) CreateSignedEnvelope(ctx context.Client) (*common.Envelope, error) {
+	if m.channelID == "" {
+		return nil, errors.New("channel ID of the config is required")
+	}
+
+	configUpdate, err := m.ConfigUpdate()
+	if err != nil {
+		return nil, err
+	}
+	configUpdateBytes, err := proto.Marshal(configUpdate)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal config update failed")
+	}
+
+	signature, err := resource.CreateConfigSignature(ctx, configUpdateBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to sign config update")
+	}
+
+	configUpdateEnvelopeBytes, err := proto.Marshal(&common.ConfigUpdateEnvelope{
+		ConfigUpdate: configUpdateBytes,
+		Signatures:   []*common.ConfigSignature{signature},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal config update envelope failed")
+	}
+
+	txh, err := txn.NewHeader(ctx, m.channelID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create transaction header")
+	}
+	channelHeader, err := txn.CreateChannelHeader(common.HeaderType_CONFIG_UPDATE, txn.ChannelHeaderOpts{TxnHeader: txh})
+	if err != nil {
+		return nil, errors.WithMessage(err, "CreateChannelHeader failed")
+	}
+	payload, err := txn.CreatePayload(txh, channelHeader, configUpdateEnvelopeBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "CreatePayload failed")
+	}
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal payload failed")
+	}
+
+	payloadSignature, err := ctx.SigningManager().Sign(payloadBytes, ctx.PrivateKey())
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing of payload failed")
+	}
+
+	return &common.Envelope{Payload: payloadBytes, Signature: payloadSignature}, nil
+}
+
+// group returns the group of the updated config with the given path
+func (m *Modifier) group(path string) (*common.ConfigGroup, error) {
+	group := m.updated.ChannelGroup
+	if path == "" {
+		return group, nil
+	}
+	for _, name := range strings.Split(path, "/") {
+		child, ok := group.Groups[name]
+		if !ok {
+			return nil, errors.Errorf("config group [%s] not found", path)
+		}
+		group = child
+	}
+	return group, nil
+}
+
+func setValue(group *common.ConfigGroup, key string, msg proto.Message) error {
+	value, err := proto.Marshal(msg)
+	if err != nil {
+		return errors.Wrapf(err, "marshal %s failed", key)
+	}
+
+	configValue, ok := group.Values[key]
+	if !ok {
+		if group.Values == nil {
+			group.Values = make(map[string]*common.ConfigValue)
+		}
+		configValue = &common.ConfigValue{ModPolicy: adminsPolicyName}
+		group.Values[key] = configValue
+	}
+	configValue.Value = value
+	return nil
+}
+
+func validatePolicy(policy *common.Policy) error {
+	switch common.Policy_PolicyType(policy.Type) {
+	case common.Policy_SIGNATURE:
+		envelope := &common.SignaturePolicyEnvelope{}
+		if err := proto.Unmarshal(policy.Value, envelope); err != nil || envelope.Rule == nil {
+			return &ValidationError{Field: "policy", Reason: "invalid signature policy"}
+		}
+	case common.Policy_IMPLICIT_META:
+		implicitMeta := &common.ImplicitMetaPolicy{}
+		if err := proto.Unmarshal(policy.Value, implicitMeta); err != nil || implicitMeta.SubPolicy == "" {
+			return &ValidationError{Field: "policy", Reason: "invalid implicit meta policy"}
+		}
+	default:
+		return &ValidationError{Field: "policy", Reason: "policy type must be SIGNATURE or IMPLICIT_META"}
+	}
+	return nil
+}
+
+func newOrgGroup(org *Organization) (*common.ConfigGroup, error) {
+	mspConfigBytes, err := proto.Marshal(org.MSP)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal MSP config failed")
+	}
+
+	group := &common.ConfigGroup{
+		Groups:    make(map[string]*common.ConfigGroup),
+		Values:    make(map[string]*common.ConfigValue),
+		Policies:  make(map[string]*common.ConfigPolicy),
+		ModPolicy: adminsPolicyName,
+	}
+
+	// MSP type 0 is the Fabric MSP
+	if err := setValue(group, channelConfig.MSPKey, &mb.MSPConfig{Type: 0, Config: mspConfigBytes}); err != nil {
+		return nil, err
+	}
+	if len(org.AnchorPeers) > 0 {
+		if err := setValue(group, channelConfig.AnchorPeersKey, &pb.AnchorPeers{AnchorPeers: org.AnchorPeers}); err != nil {
+			return nil, err
+		}
+	}
+
+	roles := map[string]mb.MSPRole_MSPRoleType{
+		channelConfig.ReadersPolicyKey: mb.MSPRole_MEMBER,
+		channelConfig.WritersPolicyKey: mb.MSPRole_MEMBER,
+		channelConfig.AdminsPolicyKey:  mb.MSPRole_ADMIN,
+	}
+	for name, role := range roles {
+		policy, err := signedByRole(org.MSP.Name, role)
+		if err != nil {
+			return nil, err
+		}
+		group.Policies[name] = &common.ConfigPolicy{Policy: policy, ModPolicy: adminsPolicyName}
+	}
+
+	return group, nil
+}
+
+// signedByRole returns a signature policy that requires a signature of an identity with the given role of the given MSP
+func signedByRole(mspID string, role mb.MSPRole_MSPRoleType) (*common.Policy, error) {
+	principal, err := proto.Marshal(&mb.MSPRole{MspIdentifier: mspID, Role: role})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal MSP role failed")
+	}
+
+	envelope, err := proto.Marshal(&common.SignaturePolicyEnvelope{
+		Version: 0,
+		Rule: &common.SignaturePolicy{
+			Type: &common.SignaturePolicy_NOutOf_{
+				NOutOf: &common.SignaturePolicy_NOutOf{
+					N:     1,
+					Rules: []*common.SignaturePolicy{{Type: &common.SignaturePolicy_SignedBy{SignedBy: 0}}},
+				},
+			},
+		},
+		Identities: []*mb.MSPPrincipal{{PrincipalClassification: mb.MSPPrincipal_ROLE, Principal: principal}},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal signature policy failed")
+	}
+
+	return &common.Policy{Type: int32(common.Policy_SIGNATURE), Value: envelope}, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	channelConfig "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/orderer"
+)
+
+func newTestConfig(t *testing.T) *Config {
+	builder := &mocks.MockConfigBlockBuilder{
+		MockConfigGroupBuilder: mocks.MockConfigGroupBuilder{
+			ModPolicy:      "Admins",
+			MSPNames:       []string{"Org1MSP"},
+			OrdererAddress: "localhost:7050",
+			RootCA:         "root-ca",
+		},
+	}
+
+	config, err := ParseConfigBlock(builder.Build())
+	require.NoError(t, err)
+	config.channelID = "mychannel"
+	return config
+}
+
+func TestModifierValidation(t *testing.T) {
+	modifier, err := NewModifier(newTestConfig(t))
+	require.NoError(t, err)
+
+	var validationErr *ValidationError
+	err = modifier.SetBatchSize(0, 100, 10)
+	assert.IsType(t, validationErr, err, "expected validation error for zero max message count")
+	err = modifier.SetBatchSize(10, 100, 200)
+	assert.IsType(t, validationErr, err, "expected validation error for preferred max bytes greater than absolute max bytes")
+	err = modifier.SetBatchTimeout(0)
+	assert.IsType(t, validationErr, err, "expected validation error for zero batch timeout")
+	err = modifier.AddOrg(&Organization{Name: "Org2MSP"})
+	assert.IsType(t, validationErr, err, "expected validation error for organization without MSP")
+	err = modifier.AddOrg(&Organization{Name: "Org1MSP", MSP: &mb.FabricMSPConfig{Name: "Org1MSP", RootCerts: [][]byte{[]byte("ca")}}})
+	assert.IsType(t, validationErr, err, "expected validation error for existing organization")
+	err = modifier.UpdatePolicy("Application", "Admins", &common.Policy{Type: int32(common.Policy_MSP)})
+	assert.IsType(t, validationErr, err, "expected validation error for unsupported policy type")
+
+	_, err = modifier.ConfigUpdate()
+	assert.Equal(t, ErrNoChanges, err, "expected no changes after invalid modifications")
+}
+
+func TestModifierConfigUpdate(t *testing.T) {
+	config := newTestConfig(t)
+	modifier, err := NewModifier(config)
+	require.NoError(t, err)
+
+	require.NoError(t, modifier.SetBatchSize(20, 1000, 500))
+	require.NoError(t, modifier.SetBatchTimeout(5*time.Second))
+	require.NoError(t, modifier.AddOrg(&Organization{Name: "Org2MSP", MSP: &mb.FabricMSPConfig{Name: "Org2MSP", RootCerts: [][]byte{[]byte("ca")}}}))
+
+	readersPolicy := config.Raw().ChannelGroup.Groups["Application"].Policies["Readers"].Policy
+	newPolicy, err := signedByRole("Org1MSP", mb.MSPRole_ADMIN)
+	require.NoError(t, err)
+	require.NoError(t, modifier.UpdatePolicy("Application", "Readers", newPolicy))
+	assert.Equal(t, readersPolicy, config.Raw().ChannelGroup.Groups["Application"].Policies["Readers"].Policy, "expected original config to be unchanged")
+
+	update, err := modifier.ConfigUpdate()
+	require.NoError(t, err)
+	assert.Equal(t, "mychannel", update.ChannelId)
+
+	ordererWriteSet := update.WriteSet.Groups[channelConfig.OrdererGroupKey]
+	require.NotNil(t, ordererWriteSet)
+	batchSizeValue := ordererWriteSet.Values[channelConfig.BatchSizeKey]
+	require.NotNil(t, batchSizeValue)
+	assert.EqualValues(t, 1, batchSizeValue.Version)
+	batchSize := &ab.BatchSize{}
+	require.NoError(t, proto.Unmarshal(batchSizeValue.Value, batchSize))
+	assert.EqualValues(t, 20, batchSize.MaxMessageCount)
+	assert.NotNil(t, ordererWriteSet.Values[channelConfig.BatchTimeoutKey])
+	assert.Nil(t, ordererWriteSet.Values[channelConfig.ConsensusTypeKey], "expected unchanged value not to be in the write set")
+
+	appWriteSet := update.WriteSet.Groups["Application"]
+	require.NotNil(t, appWriteSet)
+	assert.EqualValues(t, 1, appWriteSet.Version, "expected version of application group to be incremented for new organization")
+	require.NotNil(t, appWriteSet.Groups["Org2MSP"])
+	assert.EqualValues(t, 0, appWriteSet.Groups["Org2MSP"].Version)
+	assert.EqualValues(t, 1, appWriteSet.Policies["Readers"].Version)
+	assert.NotNil(t, update.ReadSet.Groups["Application"].Groups["Org1MSP"], "expected existing organization in the read set")
+
+	ctx := mocks.NewMockContext(mspmocks.NewMockSigningIdentity("user1", "Org1MSP"))
+	envelope, err := modifier.CreateSignedEnvelope(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, envelope.Signature)
+
+	payload := &common.Payload{}
+	require.NoError(t, proto.Unmarshal(envelope.Payload, payload))
+	configUpdateEnvelope := &common.ConfigUpdateEnvelope{}
+	require.NoError(t, proto.Unmarshal(payload.Data, configUpdateEnvelope))
+	assert.Len(t, configUpdateEnvelope.Signatures, 1)
+}
@@ -0,0 +1,33 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/common/tools/configtxlator/update"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// ErrNoChanges is returned when computing a config update if the updated config does not differ from the original config
+var ErrNoChanges = errors.New("no differences detected between original and updated config")
+
+// computeUpdate computes the config update that transforms the original config into the updated config,
+// using configtxlator's algorithm. The read set contains the versions of the elements that the update
+// depends on, and the write set contains the modified elements with incremented versions.
+func computeUpdate(channelID string, original, updated *common.Config) (*common.ConfigUpdate, error) {
+	if original.ChannelGroup == nil || updated.ChannelGroup == nil {
+		return nil, errors.New("channel group is required in the original and updated config")
+	}
+
+	configUpdate, err := update.Compute(original, updated)
+	if err != nil {
+		// With both channel groups present, Compute fails only if there are no differences
+		return nil, ErrNoChanges
+	}
+	configUpdate.ChannelId = channelID
+	return configUpdate, nil
+}
@@ -304,6 +304,13 @@ func WithTimeout(timeout time.Duration) ReqContextOptions {
 	}
 }
 
+//WithNoTimeout creates a request context without a timeout, which is then only bounded by its parent ReqContext
+func WithNoTimeout() ReqContextOptions {
+	return func(ctx *requestContextOpts) {
+		ctx.noTimeout = true
+	}
+}
+
 //WithParent sets existing reqContext as a parent ReqContext
 func WithParent(context reqContext.Context) ReqContextOptions {
 	return func(ctx *requestContextOpts) {
@@ -317,6 +324,7 @@ type ReqContextOptions func(opts *requestContextOpts)
 type requestContextOpts struct {
 	timeoutType   fab.TimeoutType
 	timeout       time.Duration
+	noTimeout     bool
 	parentContext reqContext.Context
 }
 
@@ -335,6 +343,12 @@ func NewRequest(client context.Client, options ...ReqContextOptions) (reqContext
 		parentContext = reqContext.Background()
 	}
 
+	ctx := reqContext.WithValue(parentContext, reqContextCommManager, client.InfraProvider().CommManager())
+	ctx = reqContext.WithValue(ctx, reqContextClient, client)
+	if reqCtxOpts.noTimeout {
+		return reqContext.WithCancel(ctx)
+	}
+
 	var timeout time.Duration
 	if reqCtxOpts.timeout > 0 {
 		timeout = reqCtxOpts.timeout
@@ -344,8 +358,6 @@ func NewRequest(client context.Client, options ...ReqContextOptions) (reqContext
 		timeout = client.EndpointConfig().Timeout(reqCtxOpts.timeoutType)
 	}
 
-	ctx := reqContext.WithValue(parentContext, reqContextCommManager, client.InfraProvider().CommManager())
-	ctx = reqContext.WithValue(ctx, reqContextClient, client)
 	ctx, cancel := reqContext.WithTimeout(ctx, timeout)
 
 	return ctx, cancel
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package comm
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"crypto/tls"
 
 	"crypto/x509"
@@ -31,6 +33,43 @@ func TLSConfig(cert *x509.Certificate, serverName string, config fab.EndpointCon
 	return &tls.Config{RootCAs: certPool, Certificates: config.TLSClientCerts(), ServerName: serverName}, nil
 }
 
+// TLSCertPinner is implemented by endpoint configs that pin the TLS certificates of peers and orderers
+type TLSCertPinner interface {
+	// TLSCertPin returns the SHA-256 fingerprint of the TLS certificate of the given peer or orderer URL (nil if it is not pinned)
+	TLSCertPin(url string) []byte
+}
+
+// PinTLSCert pins the TLS certificate of the peer or orderer with the given URL if the endpoint config
+// implements TLSCertPinner and holds a pin for the URL. The leaf certificate presented by the server must then
+// match the pinned fingerprint, and it is not verified against the TLS CA certs (e.g. because the CA is not
+// available at runtime). The VerifyPeerCertificate callback of tlsConfig, if any, is still invoked.
+func PinTLSCert(tlsConfig *tls.Config, url string, config fab.EndpointConfig) {
+	pinner, ok := config.(TLSCertPinner)
+	if !ok {
+		return
+	}
+	fingerprint := pinner.TLSCertPin(url)
+	if fingerprint == nil {
+		return
+	}
+
+	verify := tlsConfig.VerifyPeerCertificate
+	tlsConfig.InsecureSkipVerify = true
+	tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return errors.Errorf("no TLS certificate presented by [%s]", url)
+		}
+		hash := sha256.Sum256(rawCerts[0])
+		if !bytes.Equal(hash[:], fingerprint) {
+			return errors.Errorf("TLS certificate presented by [%s] does not match the pinned certificate", url)
+		}
+		if verify != nil {
+			return verify(rawCerts, verifiedChains)
+		}
+		return nil
+	}
+}
+
 // TLSCertHash is a utility method to calculate the SHA256 hash of the configured certificate (for usage in channel headers)
 func TLSCertHash(config fab.EndpointConfig) ([]byte, error) {
 	certs := config.TLSClientCerts()
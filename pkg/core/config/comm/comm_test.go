@@ -20,8 +20,12 @@ import (
 
 	"crypto/x509"
 
+	"crypto/sha256"
+
 	"github.com/golang/mock/gomock"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/test/mockfab"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -92,6 +96,48 @@ func TestTLSConfigHappyPath(t *testing.T) {
 	}
 }
 
+func TestPinTLSCert(t *testing.T) {
+	leaf := []byte("leaf certificate")
+	fingerprint := sha256.Sum256(leaf)
+	config := &mockTLSCertPinConfig{pins: map[string][]byte{"grpcs://peer0:7051": fingerprint[:]}}
+
+	// No pin for the URL
+	tlsConfig := &tls.Config{}
+	PinTLSCert(tlsConfig, "grpcs://peer1:7051", config)
+	assert.False(t, tlsConfig.InsecureSkipVerify)
+	assert.Nil(t, tlsConfig.VerifyPeerCertificate)
+
+	verifyErr := errors.New("verify error")
+	var verifyCalled bool
+	tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		verifyCalled = true
+		return verifyErr
+	}
+	PinTLSCert(tlsConfig, "grpcs://peer0:7051", config)
+	assert.True(t, tlsConfig.InsecureSkipVerify)
+
+	err := tlsConfig.VerifyPeerCertificate([][]byte{[]byte("other certificate")}, nil)
+	assert.EqualError(t, err, "TLS certificate presented by [grpcs://peer0:7051] does not match the pinned certificate")
+	assert.False(t, verifyCalled)
+
+	err = tlsConfig.VerifyPeerCertificate(nil, nil)
+	assert.EqualError(t, err, "no TLS certificate presented by [grpcs://peer0:7051]")
+
+	// The original callback is invoked once the pin matches
+	err = tlsConfig.VerifyPeerCertificate([][]byte{leaf}, nil)
+	assert.Equal(t, verifyErr, err)
+	assert.True(t, verifyCalled)
+}
+
+type mockTLSCertPinConfig struct {
+	fab.EndpointConfig
+	pins map[string][]byte
+}
+
+func (c *mockTLSCertPinConfig) TLSCertPin(url string) []byte {
+	return c.pins[url]
+}
+
 func createNCerts(n int) []*x509.Certificate {
 	var certs []*x509.Certificate
 	for i := 0; i < n; i++ {
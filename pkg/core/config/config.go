@@ -17,6 +17,8 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/migration"
+	yaml "gopkg.in/yaml.v2"
 )
 
 var logModules = [...]string{"fabsdk", "fabsdk/client", "fabsdk/core", "fabsdk/fab", "fabsdk/common",
@@ -63,6 +65,77 @@ func FromFile(name string, opts ...Option) core.ConfigProvider {
 			return nil, errors.Wrapf(err, "loading config file failed: %s", name)
 		}
 
+		err = migrateSchema(backend)
+		if err != nil {
+			return nil, err
+		}
+		setLogLevel(backend)
+
+		return []core.ConfigBackend{backend}, nil
+	}
+}
+
+// FromFiles reads the named config files in order and deep-merges them: the values of a file override
+// the values of the same keys in the files before it (e.g. a base config followed by environment-specific
+// overrides). Sections are merged key by key, and values that are not of the same type as the values
+// they would override are ignored.
+func FromFiles(names ...string) core.ConfigProvider {
+	return func() ([]core.ConfigBackend, error) {
+		if len(names) == 0 {
+			return nil, errors.New("at least one filename is required")
+		}
+
+		backend, err := newBackend()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range names {
+			if name == "" {
+				return nil, errors.New("filename is required")
+			}
+
+			backend.configViper.SetConfigFile(name)
+			err = backend.configViper.MergeInConfig()
+			if err != nil {
+				return nil, errors.Wrapf(err, "loading config file failed: %s", name)
+			}
+		}
+
+		err = migrateSchema(backend)
+		if err != nil {
+			return nil, err
+		}
+		setLogLevel(backend)
+
+		return []core.ConfigBackend{backend}, nil
+	}
+}
+
+// FromReaders loads YAML configuration from the readers in order and deep-merges it like FromFiles
+func FromReaders(readers ...io.Reader) core.ConfigProvider {
+	return func() ([]core.ConfigBackend, error) {
+		if len(readers) == 0 {
+			return nil, errors.New("at least one reader is required")
+		}
+
+		backend, err := newBackend()
+		if err != nil {
+			return nil, err
+		}
+
+		backend.configViper.SetConfigType("yaml")
+		for i, in := range readers {
+			err = backend.configViper.MergeConfig(in)
+			if err != nil {
+				return nil, errors.Wrapf(err, "loading config from reader %d failed", i)
+			}
+		}
+
+		err = migrateSchema(backend)
+		if err != nil {
+			return nil, err
+		}
 		setLogLevel(backend)
 
 		return []core.ConfigBackend{backend}, nil
@@ -94,6 +167,10 @@ func initFromReader(in io.Reader, configType string, opts ...Option) ([]core.Con
 	if err != nil {
 		return nil, err
 	}
+	err = migrateSchema(backend)
+	if err != nil {
+		return nil, err
+	}
 	setLogLevel(backend)
 
 	return []core.ConfigBackend{backend}, nil
@@ -145,6 +222,33 @@ func newViper(cmdRootPrefix string) *viper.Viper {
 	return myViper
 }
 
+// migrateSchema migrates a config of another schema version to the schema version read by the SDK
+func migrateSchema(backend *defConfigBackend) error {
+	if migration.ValidateSchemaVersion(backend, migration.SchemaVersion1) == nil {
+		return nil
+	}
+
+	// The sections are read one by one since the names of entities (e.g. peers) may contain dots
+	config := make(map[string]interface{})
+	for _, key := range backend.configViper.AllKeys() {
+		section := strings.SplitN(key, ".", 2)[0]
+		config[section] = backend.configViper.Get(section)
+	}
+	content, err := yaml.Marshal(config)
+	if err != nil {
+		return errors.Wrap(err, "marshal of config failed")
+	}
+
+	var migrated bytes.Buffer
+	err = migration.MigrateConfig(bytes.NewReader(content), "", migration.SchemaVersion1, &migrated)
+	if err != nil {
+		return errors.WithMessage(err, "config schema migration failed")
+	}
+
+	backend.configViper.SetConfigType("yaml")
+	return backend.configViper.ReadConfig(&migrated)
+}
+
 // setLogLevel will set the log level of the client
 func setLogLevel(backend core.ConfigBackend) {
 	loggingLevelString, _ := backend.Lookup("client.logging.level")
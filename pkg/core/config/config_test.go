@@ -8,7 +8,9 @@ package config
 
 import (
 	"bytes"
+	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
@@ -17,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/util/test"
 
 	"github.com/pkg/errors"
+	"github.com/spf13/cast"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -82,6 +85,108 @@ func TestFromFileEmptyFilename(t *testing.T) {
 	}
 }
 
+const (
+	baseConfig = `
+client:
+  organization: org1
+peers:
+  peer0.org1.example.com:
+    url: grpcs://peer0.org1.example.com:7051
+    grpcOptions:
+      ssl-target-name-override: peer0.org1.example.com
+      fail-fast: false
+`
+	overrideConfig = `
+peers:
+  peer0.org1.example.com:
+    url: grpcs://peer0.example.com:8051
+    grpcOptions:
+      fail-fast: true
+  peer1.org1.example.com:
+    url: grpcs://peer1.org1.example.com:7051
+`
+)
+
+func TestFromReaders(t *testing.T) {
+	backends, err := FromReaders(strings.NewReader(baseConfig), strings.NewReader(overrideConfig))()
+	if err != nil {
+		t.Fatalf("Failed to initialize config from readers. Error: %s", err)
+	}
+
+	// Values of the later config override the values of the earlier config, sections are merged
+	expected := map[string]interface{}{
+		"client.organization":                                               "org1",
+		"peers.peer0.org1.example.com.url":                                  "grpcs://peer0.example.com:8051",
+		"peers.peer0.org1.example.com.grpcOptions.fail-fast":                true,
+		"peers.peer0.org1.example.com.grpcOptions.ssl-target-name-override": "peer0.org1.example.com",
+		"peers.peer1.org1.example.com.url":                                  "grpcs://peer1.org1.example.com:7051",
+	}
+	for key, value := range expected {
+		v, ok := backends[0].Lookup(key)
+		assert.True(t, ok, "expecting %s to be found", key)
+		assert.Equal(t, value, v, "unexpected value of %s", key)
+	}
+
+	_, err = FromReaders()()
+	assert.EqualError(t, err, "at least one reader is required")
+	_, err = FromReaders(strings.NewReader(baseConfig), strings.NewReader("peers: ["))()
+	assert.Error(t, err)
+}
+
+func TestFromFiles(t *testing.T) {
+	f, err := ioutil.TempFile("", "config-override-*.yaml")
+	if err != nil {
+		t.Fatalf("Failed to create config file. Error: %s", err)
+	}
+	defer os.Remove(f.Name())
+	_, err = f.WriteString("client:\n  organization: org2\n")
+	f.Close()
+	if err != nil {
+		t.Fatalf("Failed to write config file. Error: %s", err)
+	}
+
+	backends, err := FromFiles(configTestFilePath, f.Name())()
+	if err != nil {
+		t.Fatalf("Failed to initialize config from files. Error: %s", err)
+	}
+	org, _ := backends[0].Lookup("client.organization")
+	assert.Equal(t, "org2", org)
+	path, _ := backends[0].Lookup("client.cryptoconfig.path")
+	assert.NotEmpty(t, path, "expecting values of the base config file to be kept")
+
+	_, err = FromFiles()()
+	assert.EqualError(t, err, "at least one filename is required")
+	_, err = FromFiles(configTestFilePath, "")()
+	assert.EqualError(t, err, "filename is required")
+	_, err = FromFiles(configTestFilePath, "testdata/missing.yaml")()
+	assert.Error(t, err)
+}
+
+func TestFromRawSchemaVersion2(t *testing.T) {
+	configV2 := `
+version: 2.0.0
+client:
+  cryptoSuite:
+    security:
+      level: 384
+peers:
+  peer0.org1.example.com:
+    grpcOptions:
+      sslTargetNameOverride: peer0.org1.example.com
+`
+	backends, err := FromRaw([]byte(configV2), configType)()
+	if err != nil {
+		t.Fatalf("Failed to initialize config from bytes array. Error: %s", err)
+	}
+
+	// The config is migrated to the schema version read by the SDK
+	level, _ := backends[0].Lookup("client.BCCSP.security.level")
+	assert.Equal(t, 384, level)
+	peers, _ := backends[0].Lookup("peers")
+	peer := cast.ToStringMap(cast.ToStringMap(peers)["peer0.org1.example.com"])
+	assert.Equal(t, map[string]interface{}{"ssl-target-name-override": "peer0.org1.example.com"}, cast.ToStringMap(peer["grpcoptions"]))
+}
+
 func TestInitConfigSuccess(t *testing.T) {
 	//Test init config
 	//...Positive case
@@ -0,0 +1,125 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+)
+
+var logger = logging.NewLogger("fabsdk/core")
+
+var envKeyReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// EnvConfigProvider returns a config provider that overrides the config of the given provider (e.g. FromFile)
+// with environment variables. Environment variables take precedence over the values of the given provider.
+//
+// The name of the environment variable of a config key is the env prefix (FABRIC_SDK by default, see
+// WithEnvPrefix) followed by an underscore and the key in upper case, with dots and dashes replaced by underscores:
+//  client.organization                                    -> FABRIC_SDK_CLIENT_ORGANIZATION
+//  client.BCCSP.security.level                            -> FABRIC_SDK_CLIENT_BCCSP_SECURITY_LEVEL
+//  peers.peer0.org1.example.com.url                       -> FABRIC_SDK_PEERS_PEER0_ORG1_EXAMPLE_COM_URL
+//  orderers.orderer.example.com.grpcOptions.fail-fast     -> FABRIC_SDK_ORDERERS_ORDERER_EXAMPLE_COM_GRPCOPTIONS_FAIL_FAST
+//
+// Values nested in a section of the config (e.g. the URL of a peer) are only overridden if they are defined by
+// the given provider. An overriding value is converted to the type of the value it replaces (e.g. bool or int).
+func EnvConfigProvider(provider core.ConfigProvider, opts ...Option) core.ConfigProvider {
+	return func() ([]core.ConfigBackend, error) {
+		o := options{
+			envPrefix: cmdRoot,
+		}
+		for _, option := range opts {
+			if err := option(&o); err != nil {
+				return nil, errors.WithMessage(err, "Error in options passed to create env config backend")
+			}
+		}
+
+		backends, err := provider()
+		if err != nil {
+			return nil, err
+		}
+
+		return []core.ConfigBackend{&envConfigBackend{prefix: o.envPrefix, backends: backends}}, nil
+	}
+}
+
+// EnvKey returns the name of the environment variable that overrides the given config key (see EnvConfigProvider)
+func EnvKey(prefix, key string) string {
+	return strings.ToUpper(prefix + "_" + envKeyReplacer.Replace(key))
+}
+
+// envConfigBackend overrides the values of other backends with environment variables
+type envConfigBackend struct {
+	prefix   string
+	backends []core.ConfigBackend
+}
+
+// Lookup gets the config item value by Key
+func (c *envConfigBackend) Lookup(key string) (interface{}, bool) {
+	for _, backend := range c.backends {
+		if value, ok := backend.Lookup(key); ok {
+			return c.override(key, value), true
+		}
+	}
+
+	if value, ok := os.LookupEnv(EnvKey(c.prefix, key)); ok {
+		return value, true
+	}
+	return nil, false
+}
+
+// override returns the given value of the key with the values of the environment variables applied
+func (c *envConfigBackend) override(key string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		overridden := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			overridden[k] = c.override(key+"."+k, e)
+		}
+		return overridden
+	case map[interface{}]interface{}:
+		overridden := make(map[interface{}]interface{}, len(v))
+		for k, e := range v {
+			overridden[k] = c.override(fmt.Sprintf("%s.%v", key, k), e)
+		}
+		return overridden
+	}
+
+	envValue, ok := os.LookupEnv(EnvKey(c.prefix, key))
+	if !ok {
+		return value
+	}
+
+	converted, err := convertEnvValue(envValue, value)
+	if err != nil {
+		logger.Warnf("Environment variable [%s] cannot be converted to the type of config [%s]: %s", EnvKey(c.prefix, key), key, err)
+		return envValue
+	}
+	return converted
+}
+
+// convertEnvValue converts the value of an environment variable to the type of the given config value
+func convertEnvValue(envValue string, value interface{}) (interface{}, error) {
+	switch value.(type) {
+	case bool:
+		return cast.ToBoolE(envValue)
+	case int:
+		return cast.ToIntE(envValue)
+	case int64:
+		return cast.ToInt64E(envValue)
+	case float64:
+		return cast.ToFloat64E(envValue)
+	default:
+		return envValue, nil
+	}
+}
@@ -0,0 +1,69 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const envTestConfig = `
+client:
+  organization: org1
+peers:
+  peer0.org1.example.com:
+    url: grpcs://peer0.org1.example.com:7051
+    grpcOptions:
+      fail-fast: false
+      keep-alive-time: 0s
+`
+
+func TestEnvConfigProvider(t *testing.T) {
+	env := map[string]string{
+		"FABRIC_SDK_CLIENT_ORGANIZATION":                                "org2",
+		"FABRIC_SDK_PEERS_PEER0_ORG1_EXAMPLE_COM_URL":                   "grpcs://peer0.example.com:8051",
+		"FABRIC_SDK_PEERS_PEER0_ORG1_EXAMPLE_COM_GRPCOPTIONS_FAIL_FAST": "true",
+		"FABRIC_SDK_CLIENT_CREDENTIALSTORE_PATH":                        "/tmp/state-store",
+	}
+	for k, v := range env {
+		require.NoError(t, os.Setenv(k, v))
+		defer os.Unsetenv(k)
+	}
+
+	backends, err := EnvConfigProvider(FromRaw([]byte(envTestConfig), "yaml"))()
+	require.NoError(t, err)
+	require.Len(t, backends, 1)
+	backend := backends[0]
+
+	value, ok := backend.Lookup("client.organization")
+	assert.True(t, ok)
+	assert.Equal(t, "org2", value)
+
+	// Keys that are not defined in the config file
+	value, ok = backend.Lookup("client.credentialStore.path")
+	assert.True(t, ok)
+	assert.Equal(t, "/tmp/state-store", value)
+	_, ok = backend.Lookup("client.cryptoconfig.path")
+	assert.False(t, ok)
+
+	// Values nested in a section are overridden and converted to the type of the config value
+	value, ok = backend.Lookup("peers")
+	require.True(t, ok)
+	peer := value.(map[string]interface{})["peer0.org1.example.com"].(map[string]interface{})
+	assert.Equal(t, "grpcs://peer0.example.com:8051", peer["url"])
+	grpcOptions := peer["grpcoptions"].(map[string]interface{})
+	assert.Equal(t, true, grpcOptions["fail-fast"])
+	assert.Equal(t, "0s", grpcOptions["keep-alive-time"])
+}
+
+func TestEnvKey(t *testing.T) {
+	assert.Equal(t, "FABRIC_SDK_CLIENT_ORGANIZATION", EnvKey(cmdRoot, "client.organization"))
+	assert.Equal(t, "FABRIC_SDK_ORDERERS_ORDERER_EXAMPLE_COM_GRPCOPTIONS_FAIL_FAST", EnvKey(cmdRoot, "orderers.orderer.example.com.grpcOptions.fail-fast"))
+}
@@ -0,0 +1,187 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/pkg/errors"
+)
+
+const (
+	rangePath = "/v3/kv/range"
+	watchPath = "/v3/watch"
+)
+
+// client is a client of the JSON gateway of the etcd v3 API
+type client struct {
+	endpoints  []string
+	httpClient *http.Client
+}
+
+type keyValue struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+type responseHeader struct {
+	Revision int64 `json:"revision,string"`
+}
+
+type rangeRequest struct {
+	Key      []byte `json:"key"`
+	RangeEnd []byte `json:"range_end"`
+}
+
+type rangeResponse struct {
+	Header responseHeader `json:"header"`
+	Kvs    []keyValue     `json:"kvs"`
+}
+
+type watchCreateRequest struct {
+	Key           []byte `json:"key"`
+	RangeEnd      []byte `json:"range_end"`
+	StartRevision int64  `json:"start_revision,string"`
+}
+
+type watchRequest struct {
+	CreateRequest *watchCreateRequest `json:"create_request"`
+}
+
+type watchResponse struct {
+	Result struct {
+		Header   responseHeader `json:"header"`
+		Created  bool           `json:"created"`
+		Canceled bool           `json:"canceled"`
+		Events   []struct {
+			Type string   `json:"type"`
+			Kv   keyValue `json:"kv"`
+		} `json:"events"`
+	} `json:"result"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func newClient(endpoints []string, tlsConfig *tls.Config) *client {
+	scheme := "http://"
+	if tlsConfig != nil {
+		scheme = "https://"
+	}
+
+	var urls []string
+	for _, endpoint := range endpoints {
+		endpoint = strings.TrimSuffix(endpoint, "/")
+		if !strings.Contains(endpoint, "://") {
+			endpoint = scheme + endpoint
+		}
+		urls = append(urls, endpoint)
+	}
+
+	return &client{
+		endpoints:  urls,
+		httpClient: &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}},
+	}
+}
+
+// getPrefix returns the key-values under the given key prefix and the revision of the store
+func (c *client) getPrefix(ctx context.Context, prefix string) (map[string][]byte, int64, error) {
+	var resp rangeResponse
+	err := c.post(ctx, rangePath, &rangeRequest{Key: []byte(prefix), RangeEnd: prefixEnd(prefix)}, func(body io.Reader) error {
+		return json.NewDecoder(body).Decode(&resp)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	kvs := make(map[string][]byte, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		kvs[string(kv.Key)] = kv.Value
+	}
+	return kvs, resp.Header.Revision, nil
+}
+
+// watchPrefix watches the keys under the given prefix from the given revision, and invokes onChange each time they change.
+// It returns when the watch fails or ctx is done.
+func (c *client) watchPrefix(ctx context.Context, prefix string, revision int64, onChange func()) error {
+	req := &watchRequest{CreateRequest: &watchCreateRequest{Key: []byte(prefix), RangeEnd: prefixEnd(prefix), StartRevision: revision}}
+	return c.post(ctx, watchPath, req, func(body io.Reader) error {
+		decoder := json.NewDecoder(body)
+		for {
+			var resp watchResponse
+			if err := decoder.Decode(&resp); err != nil {
+				return errors.Wrap(err, "reading watch response failed")
+			}
+			if resp.Error != nil {
+				return errors.Errorf("watch failed: %s", resp.Error.Message)
+			}
+			if resp.Result.Canceled {
+				return errors.New("watch was canceled")
+			}
+			if len(resp.Result.Events) > 0 {
+				onChange()
+			}
+		}
+	})
+}
+
+// post sends the request to the endpoints in order until one of them responds
+func (c *client) post(ctx context.Context, path string, request interface{}, handleResponse func(body io.Reader) error) error {
+	reqBytes, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrap(err, "marshal of etcd request failed")
+	}
+
+	var errs multi.Errors
+	for _, endpoint := range c.endpoints {
+		req, err := http.NewRequest(http.MethodPost, endpoint+path, bytes.NewReader(reqBytes))
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "invalid etcd endpoint [%s]", endpoint))
+			continue
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.httpClient.Do(req.WithContext(ctx))
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "request to etcd endpoint [%s] failed", endpoint))
+			continue
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			body, _ := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			errs = append(errs, errors.Errorf("etcd endpoint [%s] returned status [%s]: %s", endpoint, resp.Status, body))
+			continue
+		}
+
+		err = handleResponse(resp.Body)
+		resp.Body.Close()
+		return err
+	}
+	return errs.ToError()
+}
+
+// prefixEnd returns the end of the range of the keys with the given prefix
+func prefixEnd(prefix string) []byte {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	// The prefix is all 0xff: the range ends at the last key
+	return []byte{0}
+}
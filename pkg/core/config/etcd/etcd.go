@@ -0,0 +1,171 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package etcd provides an SDK config provider that reads the config from etcd.
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/core")
+
+const (
+	requestTimeout = 10 * time.Second
+	retryInterval  = 5 * time.Second
+)
+
+// NewEtcdConfigProvider returns a config provider that reads the config stored in etcd under the given key prefix
+// (see MarshalConfig and UnmarshalConfig). The config backend of the provider implements core.ConfigChangeNotifier:
+// it watches the keys under the prefix, and the SDK reloads its endpoint config when they change.
+//
+// etcd is accessed through the JSON gateway of its v3 API, so etcd 3.4 or later is required.
+//  Parameters:
+//  endpoints are the URLs of the etcd members (e.g. https://etcd1:2379), which are tried in order
+//  prefix is the key prefix under which the config is stored
+//  tlsConfig is the TLS config of the connections to etcd (nil for plain HTTP)
+//
+//  Returns:
+//  the config provider
+func NewEtcdConfigProvider(endpoints []string, prefix string, tlsConfig *tls.Config) (core.ConfigProvider, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("at least one etcd endpoint is required")
+	}
+	if strings.Trim(prefix, "/") == "" {
+		return nil, errors.New("etcd key prefix is required")
+	}
+
+	b := &configBackend{
+		client: newClient(endpoints, tlsConfig),
+		prefix: keyPrefix(prefix),
+	}
+
+	return func() ([]core.ConfigBackend, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+		defer cancel()
+
+		content, revision, err := b.load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		backends, err := config.FromRaw(content, "yaml")()
+		if err != nil {
+			return nil, errors.WithMessage(err, "loading config from etcd failed")
+		}
+
+		return []core.ConfigBackend{&configBackend{
+			ConfigBackend: backends[0],
+			client:        b.client,
+			prefix:        b.prefix,
+			content:       content,
+			revision:      revision,
+		}}, nil
+	}, nil
+}
+
+// configBackend is the backend of the config read from etcd
+type configBackend struct {
+	core.ConfigBackend
+	client   *client
+	prefix   string
+	content  []byte
+	revision int64
+}
+
+// ConfigLocation returns the location of the config in etcd
+func (b *configBackend) ConfigLocation() string {
+	return strings.Join(b.client.endpoints, ",") + "/" + strings.Trim(b.prefix, "/")
+}
+
+// ConfigChanges watches the config in etcd and publishes it each time it changes
+func (b *configBackend) ConfigChanges(done <-chan struct{}) <-chan []byte {
+	changes := make(chan []byte)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-done
+		cancel()
+	}()
+
+	go func() {
+		defer close(changes)
+
+		content, revision := b.content, b.revision
+		publish := func(newContent []byte) bool {
+			if bytes.Equal(newContent, content) {
+				return true
+			}
+			content = newContent
+			select {
+			case changes <- content:
+				return true
+			case <-done:
+				return false
+			}
+		}
+
+		for {
+			err := b.client.watchPrefix(ctx, b.prefix, revision+1, func() {
+				newContent, newRevision, err := b.load(ctx)
+				if err != nil {
+					logger.Warnf("Config change in etcd is not published: %s", err)
+					return
+				}
+				revision = newRevision
+				publish(newContent)
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Warnf("Watching config in etcd failed: %s", err)
+
+			select {
+			case <-time.After(retryInterval):
+			case <-done:
+				return
+			}
+
+			// Changes may have been missed while the config was not watched
+			newContent, newRevision, err := b.load(ctx)
+			if err != nil {
+				logger.Warnf("Config in etcd is not reloaded: %s", err)
+				continue
+			}
+			revision = newRevision
+			if !publish(newContent) {
+				return
+			}
+		}
+	}()
+
+	return changes
+}
+
+// load reads the YAML config from etcd
+func (b *configBackend) load(ctx context.Context) ([]byte, int64, error) {
+	kvs, revision, err := b.client.getPrefix(ctx, b.prefix)
+	if err != nil {
+		return nil, 0, errors.WithMessage(err, "reading config from etcd failed")
+	}
+	if len(kvs) == 0 {
+		return nil, 0, errors.Errorf("no config found in etcd under prefix [%s]", b.prefix)
+	}
+
+	content, err := UnmarshalConfig(b.prefix, kvs)
+	if err != nil {
+		return nil, 0, err
+	}
+	return content, revision, nil
+}
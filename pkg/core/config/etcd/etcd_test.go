@@ -0,0 +1,197 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcd
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPrefix = "/fabric/sdk"
+
+const testConfig = `
+client:
+  organization: org1
+peers:
+  peer0.org1.example.com:
+    url: grpc://peer0.org1.example.com:7051
+`
+
+func TestEtcdConfigProvider(t *testing.T) {
+	etcd := newFakeEtcd()
+	server := httptest.NewServer(etcd)
+	defer server.Close()
+
+	kvs, err := MarshalConfig(testPrefix, []byte(testConfig))
+	require.NoError(t, err)
+	for key, value := range kvs {
+		etcd.put(key, value)
+	}
+	etcd.put("/fabric/other", []byte("other: value"))
+
+	provider, err := NewEtcdConfigProvider([]string{server.URL}, testPrefix, nil)
+	require.NoError(t, err)
+	backends, err := provider()
+	require.NoError(t, err)
+	require.Len(t, backends, 1)
+
+	value, ok := backends[0].Lookup("client.organization")
+	assert.True(t, ok)
+	assert.Equal(t, "org1", value)
+	_, ok = backends[0].Lookup("other")
+	assert.False(t, ok, "expecting keys outside of the prefix to be ignored")
+
+	notifier, ok := backends[0].(core.ConfigChangeNotifier)
+	require.True(t, ok, "expecting config backend to notify of config changes")
+	assert.Equal(t, server.URL+"/fabric/sdk", notifier.ConfigLocation())
+
+	done := make(chan struct{})
+	closeDone := func() {
+		if done != nil {
+			close(done)
+			done = nil
+		}
+	}
+	defer closeDone()
+	changes := notifier.ConfigChanges(done)
+
+	etcd.put(testPrefix+"/peers/peer1.org1.example.com/url", []byte("grpc://peer1.org1.example.com:7051"))
+	select {
+	case content := <-changes:
+		assert.Contains(t, string(content), "peer1.org1.example.com")
+		assert.Contains(t, string(content), "peer0.org1.example.com")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for config change")
+	}
+
+	closeDone()
+	select {
+	case _, ok := <-changes:
+		assert.False(t, ok, "expecting changes to be closed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for changes to be closed")
+	}
+}
+
+func TestEtcdConfigProviderErrors(t *testing.T) {
+	_, err := NewEtcdConfigProvider(nil, testPrefix, nil)
+	assert.EqualError(t, err, "at least one etcd endpoint is required")
+	_, err = NewEtcdConfigProvider([]string{"localhost:2379"}, "/", nil)
+	assert.EqualError(t, err, "etcd key prefix is required")
+
+	server := httptest.NewServer(newFakeEtcd())
+	defer server.Close()
+
+	provider, err := NewEtcdConfigProvider([]string{server.URL}, testPrefix, nil)
+	require.NoError(t, err)
+	_, err = provider()
+	assert.EqualError(t, err, "no config found in etcd under prefix [/fabric/sdk/]")
+
+	// The endpoints are tried in order
+	provider, err = NewEtcdConfigProvider([]string{"http://127.0.0.1:1", server.URL}, testPrefix, nil)
+	require.NoError(t, err)
+	_, err = provider()
+	assert.EqualError(t, err, "no config found in etcd under prefix [/fabric/sdk/]")
+}
+
+func TestPrefixEnd(t *testing.T) {
+	assert.Equal(t, []byte("/fabric/sdk0"), prefixEnd("/fabric/sdk/"))
+	assert.Equal(t, []byte{'b'}, prefixEnd("a\xff"))
+	assert.Equal(t, []byte{0}, prefixEnd("\xff"))
+}
+
+// fakeEtcd implements the range and watch requests of the JSON gateway of etcd
+type fakeEtcd struct {
+	lock     sync.Mutex
+	kvs      map[string][]byte
+	revision int64
+	changed  chan struct{}
+}
+
+func newFakeEtcd() *fakeEtcd {
+	return &fakeEtcd{kvs: make(map[string][]byte), revision: 1, changed: make(chan struct{})}
+}
+
+func (e *fakeEtcd) put(key string, value []byte) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.kvs[key] = value
+	e.revision++
+	close(e.changed)
+	e.changed = make(chan struct{})
+}
+
+func (e *fakeEtcd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case rangePath:
+		var req rangeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		e.lock.Lock()
+		resp := rangeResponse{Header: responseHeader{Revision: e.revision}}
+		for key, value := range e.kvs {
+			if key >= string(req.Key) && key < string(req.RangeEnd) {
+				resp.Kvs = append(resp.Kvs, keyValue{Key: []byte(key), Value: value})
+			}
+		}
+		e.lock.Unlock()
+
+		json.NewEncoder(w).Encode(&resp) // nolint: errcheck
+	case watchPath:
+		var req watchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		key := base64.StdEncoding.EncodeToString(req.CreateRequest.Key)
+
+		e.lock.Lock()
+		changed, revision := e.changed, e.revision
+		e.lock.Unlock()
+		fmt.Fprintf(w, `{"result":{"header":{"revision":"%d"},"created":true}}`+"\n", revision)
+		w.(http.Flusher).Flush()
+
+		// Changes from the start revision are sent right away
+		if revision < req.CreateRequest.StartRevision {
+			select {
+			case <-changed:
+			case <-r.Context().Done():
+				return
+			}
+		}
+		for {
+			e.lock.Lock()
+			changed, revision = e.changed, e.revision
+			e.lock.Unlock()
+			fmt.Fprintf(w, `{"result":{"header":{"revision":"%d"},"events":[{"kv":{"key":"%s"}}]}}`+"\n", revision, key)
+			w.(http.Flusher).Flush()
+
+			select {
+			case <-changed:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	default:
+		http.Error(w, strings.TrimPrefix(r.URL.Path, "/")+" not found", http.StatusNotFound)
+	}
+}
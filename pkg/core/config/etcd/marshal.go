@@ -0,0 +1,97 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// MarshalConfig returns the key-values under which the given YAML config is stored in etcd.
+// Each top-level section of the config (client, organizations, peers, ...) is stored as YAML
+// under the key <prefix>/<section>.
+//  Parameters:
+//  prefix is the etcd key prefix of the config
+//  config is the YAML config
+//
+//  Returns:
+//  the key-values to store in etcd
+func MarshalConfig(prefix string, config []byte) (map[string][]byte, error) {
+	var sections map[interface{}]interface{}
+	if err := yaml.Unmarshal(config, &sections); err != nil {
+		return nil, errors.Wrap(err, "unmarshal of YAML config failed")
+	}
+
+	kvs := make(map[string][]byte, len(sections))
+	for name, section := range sections {
+		value, err := yaml.Marshal(section)
+		if err != nil {
+			return nil, errors.Wrapf(err, "marshal of config section [%v] failed", name)
+		}
+		kvs[keyPrefix(prefix)+fmt.Sprint(name)] = value
+	}
+	return kvs, nil
+}
+
+// UnmarshalConfig returns the YAML config stored in the given key-values. The path of a key relative to
+// the prefix is the path of its value in the config: <prefix>/peers holds the YAML of the peers section,
+// and <prefix>/peers/peer0.org1.example.com/url holds the URL of peer0.org1.example.com. More specific
+// keys override the values of the sections that contain them.
+//  Parameters:
+//  prefix is the etcd key prefix of the config
+//  kvs are the key-values under the prefix
+//
+//  Returns:
+//  the YAML config
+func UnmarshalConfig(prefix string, kvs map[string][]byte) ([]byte, error) {
+	keys := make([]string, 0, len(kvs))
+	for key := range kvs {
+		keys = append(keys, key)
+	}
+	// Sections come before the keys that they contain
+	sort.Strings(keys)
+
+	config := make(map[interface{}]interface{})
+	for _, key := range keys {
+		path := strings.Split(strings.TrimPrefix(key, keyPrefix(prefix)), "/")
+		if !strings.HasPrefix(key, keyPrefix(prefix)) || path[0] == "" {
+			return nil, errors.Errorf("key [%s] is not a config key under prefix [%s]", key, prefix)
+		}
+
+		var value interface{}
+		if err := yaml.Unmarshal(kvs[key], &value); err != nil {
+			return nil, errors.Wrapf(err, "unmarshal of the value of key [%s] failed", key)
+		}
+
+		section := config
+		for _, name := range path[:len(path)-1] {
+			next, ok := section[name]
+			if !ok {
+				next = make(map[interface{}]interface{})
+				section[name] = next
+			}
+			if section, ok = next.(map[interface{}]interface{}); !ok {
+				return nil, errors.Errorf("key [%s] is under config value [%s], which is not a section", key, name)
+			}
+		}
+		section[path[len(path)-1]] = value
+	}
+
+	content, err := yaml.Marshal(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal of YAML config failed")
+	}
+	return content, nil
+}
+
+func keyPrefix(prefix string) string {
+	return strings.TrimSuffix(prefix, "/") + "/"
+}
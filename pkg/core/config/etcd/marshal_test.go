@@ -0,0 +1,61 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	yaml "gopkg.in/yaml.v2"
+)
+
+func TestMarshalConfig(t *testing.T) {
+	kvs, err := MarshalConfig(testPrefix+"/", []byte(testConfig))
+	require.NoError(t, err)
+	assert.Len(t, kvs, 2)
+	assert.Equal(t, "organization: org1\n", string(kvs["/fabric/sdk/client"]))
+
+	content, err := UnmarshalConfig(testPrefix, kvs)
+	require.NoError(t, err)
+	assert.Equal(t, unmarshalYAML(t, []byte(testConfig)), unmarshalYAML(t, content))
+
+	_, err = MarshalConfig(testPrefix, []byte("client: ["))
+	assert.Error(t, err)
+}
+
+func TestUnmarshalConfig(t *testing.T) {
+	// More specific keys override the values of the sections that contain them
+	content, err := UnmarshalConfig(testPrefix, map[string][]byte{
+		"/fabric/sdk/peers":                            []byte("peer0.org1.example.com:\n  url: grpc://peer0.org1.example.com:7051\n"),
+		"/fabric/sdk/peers/peer0.org1.example.com/url": []byte("grpc://peer0.org1.example.com:8051"),
+		"/fabric/sdk/client/organization":              []byte("org1"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, unmarshalYAML(t, []byte(`
+client:
+  organization: org1
+peers:
+  peer0.org1.example.com:
+    url: grpc://peer0.org1.example.com:8051
+`)), unmarshalYAML(t, content))
+
+	_, err = UnmarshalConfig(testPrefix, map[string][]byte{
+		"/fabric/sdk/client":              []byte("org1"),
+		"/fabric/sdk/client/organization": []byte("org1"),
+	})
+	assert.EqualError(t, err, "key [/fabric/sdk/client/organization] is under config value [client], which is not a section")
+
+	_, err = UnmarshalConfig(testPrefix, map[string][]byte{"/fabric/other": []byte("value")})
+	assert.EqualError(t, err, "key [/fabric/other] is not a config key under prefix [/fabric/sdk]")
+}
+
+func unmarshalYAML(t *testing.T, content []byte) map[interface{}]interface{} {
+	var config map[interface{}]interface{}
+	require.NoError(t, yaml.Unmarshal(content, &config))
+	return config
+}
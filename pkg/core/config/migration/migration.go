@@ -0,0 +1,231 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package migration rewrites SDK config files between versions of the config schema.
+package migration
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// SchemaVersion1 is the version of the config schema read by the SDK. Config files without a version are of this version.
+	SchemaVersion1 = "1.0.0"
+	// SchemaVersion2 is the version of the config schema with the renamed fields of the v1.0 to v2.0 migration (see MigrateConfig)
+	SchemaVersion2 = "2.0.0"
+
+	versionKey = "version"
+)
+
+// rename renames the key from to the key to in the sections at path
+type rename struct {
+	// path is the path of the sections that contain the key, where "*" matches any section name
+	path string
+	from string
+	to   string
+}
+
+// migration describes the changes of the config schema from a version to the next
+type migration struct {
+	from    string
+	to      string
+	renames []rename
+}
+
+var migrations = []migration{
+	{
+		from: SchemaVersion1,
+		to:   SchemaVersion2,
+		renames: []rename{
+			{path: "client", from: "BCCSP", to: "cryptoSuite"},
+			{path: "client.credentialStore", from: "cryptoStore", to: "keyStore"},
+			{path: "peers.*.grpcOptions", from: "ssl-target-name-override", to: "sslTargetNameOverride"},
+			{path: "orderers.*.grpcOptions", from: "ssl-target-name-override", to: "sslTargetNameOverride"},
+		},
+	},
+}
+
+// SchemaVersion returns the schema version of the given config (SchemaVersion1 if the config has no version)
+func SchemaVersion(backend core.ConfigBackend) string {
+	version, ok := backend.Lookup(versionKey)
+	if !ok || version == nil {
+		return SchemaVersion1
+	}
+	return cast.ToString(version)
+}
+
+// ValidateSchemaVersion checks that the config is of the expected schema version. Versions
+// are compared by their major and minor numbers (e.g. 1.0 and 1.0.0 are the same version).
+//  Parameters:
+//  backend is the config
+//  expectedVersion is the expected schema version (e.g. SchemaVersion1)
+//
+//  Returns:
+//  an error if the config is of another schema version
+func ValidateSchemaVersion(backend core.ConfigBackend, expectedVersion string) error {
+	expected, err := schemaVersion(expectedVersion)
+	if err != nil {
+		return err
+	}
+	version, err := schemaVersion(SchemaVersion(backend))
+	if err != nil {
+		return errors.WithMessage(err, "invalid config")
+	}
+	if version != expected {
+		return errors.Errorf("config schema version [%s] does not match the expected schema version [%s]", version, expected)
+	}
+	return nil
+}
+
+// MigrateConfig rewrites a YAML config from a schema version to another schema version. The schema may be
+// migrated to a later or to an earlier version. The v1.0 to v2.0 migration renames the following fields:
+//  client.BCCSP                                            -> client.cryptoSuite
+//  client.credentialStore.cryptoStore                      -> client.credentialStore.keyStore
+//  peers.<peer>.grpcOptions.ssl-target-name-override       -> peers.<peer>.grpcOptions.sslTargetNameOverride
+//  orderers.<orderer>.grpcOptions.ssl-target-name-override -> orderers.<orderer>.grpcOptions.sslTargetNameOverride
+//
+//  Parameters:
+//  input is the YAML config
+//  fromVersion is the schema version of the config (if empty, the version field of the config is used)
+//  toVersion is the schema version to migrate the config to
+//  output is the writer of the migrated YAML config
+//
+//  Returns:
+//  an error if the config cannot be read or migrated
+func MigrateConfig(input io.Reader, fromVersion, toVersion string, output io.Writer) error {
+	content, err := ioutil.ReadAll(input)
+	if err != nil {
+		return errors.Wrap(err, "reading config failed")
+	}
+	var config yaml.MapSlice
+	if err := yaml.Unmarshal(content, &config); err != nil {
+		return errors.Wrap(err, "unmarshal of YAML config failed")
+	}
+
+	if fromVersion == "" {
+		fromVersion = SchemaVersion1
+		if version, ok := get(config, versionKey); ok && version != nil {
+			fromVersion = cast.ToString(version)
+		}
+	}
+	from, err := schemaVersionIndex(fromVersion)
+	if err != nil {
+		return err
+	}
+	to, err := schemaVersionIndex(toVersion)
+	if err != nil {
+		return err
+	}
+
+	for i := from; i < to; i++ {
+		for _, r := range migrations[i].renames {
+			renameKey(config, strings.Split(r.path, "."), r.from, r.to)
+		}
+	}
+	for i := from - 1; i >= to; i-- {
+		for _, r := range migrations[i].renames {
+			renameKey(config, strings.Split(r.path, "."), r.to, r.from)
+		}
+	}
+	config = set(config, versionKey, schemaVersions()[to])
+
+	migrated, err := yaml.Marshal(config)
+	if err != nil {
+		return errors.Wrap(err, "marshal of YAML config failed")
+	}
+	_, err = output.Write(migrated)
+	return errors.Wrap(err, "writing migrated config failed")
+}
+
+// renameKey renames the key from to the key to in the sections at the given path
+func renameKey(section yaml.MapSlice, path []string, from, to string) {
+	if len(path) == 0 {
+		for i, item := range section {
+			if strings.EqualFold(fmt.Sprint(item.Key), from) {
+				section[i].Key = to
+			}
+		}
+		return
+	}
+
+	for _, item := range section {
+		if path[0] != "*" && !strings.EqualFold(fmt.Sprint(item.Key), path[0]) {
+			continue
+		}
+		if subsection, ok := item.Value.(yaml.MapSlice); ok {
+			renameKey(subsection, path[1:], from, to)
+		}
+	}
+}
+
+func get(section yaml.MapSlice, key string) (interface{}, bool) {
+	for _, item := range section {
+		if fmt.Sprint(item.Key) == key {
+			return item.Value, true
+		}
+	}
+	return nil, false
+}
+
+func set(section yaml.MapSlice, key string, value interface{}) yaml.MapSlice {
+	for i, item := range section {
+		if fmt.Sprint(item.Key) == key {
+			section[i].Value = value
+			return section
+		}
+	}
+	return append(yaml.MapSlice{{Key: key, Value: value}}, section...)
+}
+
+// schemaVersions returns the schema versions in order
+func schemaVersions() []string {
+	versions := []string{migrations[0].from}
+	for _, m := range migrations {
+		versions = append(versions, m.to)
+	}
+	return versions
+}
+
+func schemaVersionIndex(version string) (int, error) {
+	v, err := schemaVersion(version)
+	if err != nil {
+		return 0, err
+	}
+	for i, sv := range schemaVersions() {
+		if s, _ := schemaVersion(sv); s == v {
+			return i, nil
+		}
+	}
+	return 0, errors.Errorf("unsupported config schema version [%s]", version)
+}
+
+// schemaVersion returns the major and minor numbers of a version of the form [v]major[.minor[.patch]]
+func schemaVersion(version string) (string, error) {
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".")
+	if len(parts) > 3 {
+		return "", errors.Errorf("invalid config schema version [%s]", version)
+	}
+	numbers := []int{0, 0}
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return "", errors.Errorf("invalid config schema version [%s]", version)
+		}
+		if i < 2 {
+			numbers[i] = n
+		}
+	}
+	return fmt.Sprintf("%d.%d", numbers[0], numbers[1]), nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const configV1 = `version: 1.0.0
+client:
+  organization: org1
+  credentialStore:
+    path: /tmp/state-store
+    cryptoStore:
+      path: /tmp/msp
+  BCCSP:
+    security:
+      level: 256
+peers:
+  peer0.org1.example.com:
+    url: grpcs://peer0.org1.example.com:7051
+    grpcOptions:
+      ssl-target-name-override: peer0.org1.example.com
+      fail-fast: false
+orderers:
+  orderer.example.com:
+    url: grpcs://orderer.example.com:7050
+    grpcOptions:
+      ssl-target-name-override: orderer.example.com
+`
+
+const configV2 = `version: 2.0.0
+client:
+  organization: org1
+  credentialStore:
+    path: /tmp/state-store
+    keyStore:
+      path: /tmp/msp
+  cryptoSuite:
+    security:
+      level: 256
+peers:
+  peer0.org1.example.com:
+    url: grpcs://peer0.org1.example.com:7051
+    grpcOptions:
+      sslTargetNameOverride: peer0.org1.example.com
+      fail-fast: false
+orderers:
+  orderer.example.com:
+    url: grpcs://orderer.example.com:7050
+    grpcOptions:
+      sslTargetNameOverride: orderer.example.com
+`
+
+func TestMigrateConfig(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, MigrateConfig(strings.NewReader(configV1), "1.0", "v2.0", &out))
+	assert.Equal(t, configV2, out.String())
+
+	out.Reset()
+	require.NoError(t, MigrateConfig(strings.NewReader(configV2), "", SchemaVersion1, &out))
+	assert.Equal(t, configV1, out.String())
+
+	// A config without a version is of schema version 1
+	out.Reset()
+	require.NoError(t, MigrateConfig(strings.NewReader(strings.TrimPrefix(configV1, "version: 1.0.0\n")), "", SchemaVersion2, &out))
+	assert.Equal(t, configV2, out.String())
+
+	err := MigrateConfig(strings.NewReader(configV1), "1.0", "3.0", &out)
+	assert.EqualError(t, err, "unsupported config schema version [3.0]")
+	err = MigrateConfig(strings.NewReader(configV1), "latest", "2.0", &out)
+	assert.EqualError(t, err, "invalid config schema version [latest]")
+	err = MigrateConfig(strings.NewReader("client: ["), "1.0", "2.0", &out)
+	assert.Error(t, err)
+}
+
+func TestValidateSchemaVersion(t *testing.T) {
+	assert.NoError(t, ValidateSchemaVersion(mockBackend{}, SchemaVersion1))
+	assert.NoError(t, ValidateSchemaVersion(mockBackend{"version": "1.0"}, SchemaVersion1))
+	assert.NoError(t, ValidateSchemaVersion(mockBackend{"version": 2}, "v2.0.1"))
+
+	err := ValidateSchemaVersion(mockBackend{"version": "2.0.0"}, SchemaVersion1)
+	assert.EqualError(t, err, "config schema version [2.0] does not match the expected schema version [1.0]")
+	err = ValidateSchemaVersion(mockBackend{"version": "two"}, SchemaVersion2)
+	assert.EqualError(t, err, "invalid config: invalid config schema version [two]")
+}
+
+type mockBackend map[string]interface{}
+
+func (b mockBackend) Lookup(key string) (interface{}, bool) {
+	value, ok := b[key]
+	return value, ok
+}
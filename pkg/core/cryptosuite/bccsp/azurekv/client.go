@@ -0,0 +1,156 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package azurekv
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	apiVersion     = "7.4"
+	requestTimeout = 30 * time.Second
+)
+
+// TokenCredential provides the OAuth 2.0 access tokens of the requests to Key Vault. It is typically
+// implemented by an adapter of azcore.TokenCredential with the scope https://vault.azure.net/.default.
+type TokenCredential interface {
+	Token() (string, error)
+}
+
+// client is a client of the keys REST API of Azure Key Vault
+type client struct {
+	vaultURL   string
+	cred       TokenCredential
+	httpClient *http.Client
+}
+
+func newClient(vaultURL string, cred TokenCredential) (*client, error) {
+	u, err := url.Parse(vaultURL)
+	if err != nil || u.Host == "" {
+		return nil, errors.Errorf("invalid Key Vault URL [%s]", vaultURL)
+	}
+	if cred == nil {
+		return nil, errors.New("Key Vault credential is required")
+	}
+	return &client{
+		vaultURL:   strings.TrimSuffix(vaultURL, "/"),
+		cred:       cred,
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}, nil
+}
+
+// jsonWebKey is the public part of an EC key in JWK format
+type jsonWebKey struct {
+	KID   string `json:"kid"`
+	KTY   string `json:"kty"`
+	Curve string `json:"crv"`
+	X     string `json:"x"`
+	Y     string `json:"y"`
+}
+
+type keyBundle struct {
+	Key jsonWebKey `json:"key"`
+}
+
+// createKey creates a new version of the named EC key on the given curve (e.g. P-256)
+func (c *client) createKey(name, curve string) (*jsonWebKey, error) {
+	req := map[string]interface{}{
+		"kty":     "EC",
+		"crv":     curve,
+		"key_ops": []string{"sign", "verify"},
+	}
+	var bundle keyBundle
+	if err := c.do(http.MethodPost, c.vaultURL+"/keys/"+name+"/create", req, &bundle); err != nil {
+		return nil, err
+	}
+	return &bundle.Key, nil
+}
+
+// getKey returns the latest version of the named key
+func (c *client) getKey(name string) (*jsonWebKey, error) {
+	var bundle keyBundle
+	if err := c.do(http.MethodGet, c.vaultURL+"/keys/"+name, nil, &bundle); err != nil {
+		return nil, err
+	}
+	return &bundle.Key, nil
+}
+
+// sign signs the digest with the key version identified by kid, and returns the signature as the concatenation of R and S
+func (c *client) sign(kid, algorithm string, digest []byte) ([]byte, error) {
+	req := map[string]interface{}{
+		"alg":   algorithm,
+		"value": base64.RawURLEncoding.EncodeToString(digest),
+	}
+	var resp struct {
+		Value string `json:"value"`
+	}
+	if err := c.do(http.MethodPost, strings.TrimSuffix(kid, "/")+"/sign", req, &resp); err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(resp.Value, "="))
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding signature returned by Key Vault failed")
+	}
+	return signature, nil
+}
+
+// do sends a request to the Key Vault API, and unmarshals the response into data
+func (c *client) do(method, address string, body interface{}, data interface{}) error {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		if reqBody, err = json.Marshal(body); err != nil {
+			return errors.Wrap(err, "marshal of Key Vault request failed")
+		}
+	}
+
+	token, err := c.cred.Token()
+	if err != nil {
+		return errors.Wrap(err, "getting Key Vault access token failed")
+	}
+
+	req, err := http.NewRequest(method, address+"?api-version="+apiVersion, bytes.NewReader(reqBody))
+	if err != nil {
+		return errors.Wrap(err, "creating Key Vault request failed")
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Key Vault request failed")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "reading Key Vault response failed")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var kvErr struct {
+			Error struct {
+				Code    string `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.Unmarshal(respBody, &kvErr) // nolint: errcheck
+		return errors.Errorf("Key Vault returned status %d: %s: %s", resp.StatusCode, kvErr.Error.Code, kvErr.Error.Message)
+	}
+	return errors.Wrap(json.Unmarshal(respBody, data), "unmarshal of Key Vault response failed")
+}
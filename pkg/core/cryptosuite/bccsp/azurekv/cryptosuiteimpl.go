@@ -0,0 +1,288 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package azurekv provides a BCCSP whose private keys are kept in Azure Key Vault.
+package azurekv
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/core")
+
+// NewAzureKeyVaultBCCSP returns a BCCSP whose private keys are versions of a key of Azure Key Vault.
+// KeyGen creates a new version of the key, and Sign delegates to the Sign API of Key Vault (with
+// the ES256 or ES384 algorithm), so that the private key material never leaves Key Vault. Hashing,
+// verification and public key import are done in software.
+//  Parameters:
+//  vaultURL is the URL of the key vault (e.g. https://myvault.vault.azure.net)
+//  keyName is the name of the EC key in the key vault
+//  cred provides the access tokens of the requests to Key Vault
+//
+//  Returns:
+//  the BCCSP
+func NewAzureKeyVaultBCCSP(vaultURL, keyName string, cred TokenCredential) (bccsp.BCCSP, error) {
+	if keyName == "" {
+		return nil, errors.New("Key Vault key name is required")
+	}
+	client, err := newClient(vaultURL, cred)
+	if err != nil {
+		return nil, err
+	}
+
+	swCSP, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	if err != nil {
+		return nil, errors.WithMessage(err, "initializing software BCCSP failed")
+	}
+
+	return &impl{
+		client:  client,
+		keyName: keyName,
+		swCSP:   swCSP,
+		keys:    make(map[string]*key),
+	}, nil
+}
+
+type impl struct {
+	client  *client
+	keyName string
+	swCSP   bccsp.BCCSP
+	lock    sync.RWMutex
+	// keys are the Key Vault key versions by hex SKI
+	keys map[string]*key
+}
+
+// KeyGen creates a new version of the EC key in Key Vault
+func (csp *impl) KeyGen(opts bccsp.KeyGenOpts) (bccsp.Key, error) {
+	if opts == nil {
+		return nil, errors.New("key generation options are required")
+	}
+
+	var curve string
+	switch opts.Algorithm() {
+	case bccsp.ECDSA, bccsp.ECDSAP256:
+		curve = "P-256"
+	case bccsp.ECDSAP384:
+		curve = "P-384"
+	default:
+		return nil, errors.Errorf("unsupported key generation algorithm [%s]", opts.Algorithm())
+	}
+
+	jwk, err := csp.client.createKey(csp.keyName, curve)
+	if err != nil {
+		return nil, errors.WithMessage(err, "creating key in Key Vault failed")
+	}
+	k, err := csp.addKey(jwk)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("Created key [%s] in Key Vault", k.kid)
+	return k, nil
+}
+
+// KeyDeriv is not supported
+func (csp *impl) KeyDeriv(k bccsp.Key, opts bccsp.KeyDerivOpts) (bccsp.Key, error) {
+	return nil, errors.New("key derivation is not supported by the Key Vault BCCSP")
+}
+
+// KeyImport imports a public key. Private keys cannot be imported.
+func (csp *impl) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (bccsp.Key, error) {
+	k, err := csp.swCSP.KeyImport(raw, opts)
+	if err != nil {
+		return nil, err
+	}
+	if k.Private() || k.Symmetric() {
+		return nil, errors.New("only public keys can be imported into the Key Vault BCCSP")
+	}
+	return k, nil
+}
+
+// GetKey returns a reference to the Key Vault key version of the given SKI
+func (csp *impl) GetKey(ski []byte) (bccsp.Key, error) {
+	csp.lock.RLock()
+	k, ok := csp.keys[hex.EncodeToString(ski)]
+	csp.lock.RUnlock()
+	if ok {
+		return k, nil
+	}
+
+	// The key may have been created by another instance of the BCCSP
+	jwk, err := csp.client.getKey(csp.keyName)
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading key from Key Vault failed")
+	}
+	k, err = csp.addKey(jwk)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(k.SKI(), ski) {
+		return nil, errors.Errorf("key with SKI [%x] not found in Key Vault", ski)
+	}
+	return k, nil
+}
+
+// Hash hashes msg in software
+func (csp *impl) Hash(msg []byte, opts bccsp.HashOpts) ([]byte, error) {
+	return csp.swCSP.Hash(msg, opts)
+}
+
+// GetHash returns a hash function in software
+func (csp *impl) GetHash(opts bccsp.HashOpts) (hash.Hash, error) {
+	return csp.swCSP.GetHash(opts)
+}
+
+// Sign signs the digest with a Key Vault key
+func (csp *impl) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	kvKey, ok := k.(*key)
+	if !ok {
+		return nil, errors.New("only Key Vault keys can be used for signing")
+	}
+
+	var algorithm string
+	switch len(digest) {
+	case 32:
+		algorithm = "ES256"
+	case 48:
+		algorithm = "ES384"
+	default:
+		return nil, errors.Errorf("invalid digest length [%d]", len(digest))
+	}
+
+	signature, err := csp.client.sign(kvKey.kid, algorithm, digest)
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing with Key Vault failed")
+	}
+	if len(signature) == 0 || len(signature)%2 != 0 {
+		return nil, errors.Errorf("invalid signature length [%d] returned by Key Vault", len(signature))
+	}
+
+	// Key Vault returns the concatenation of R and S, and Fabric only accepts ASN.1 signatures with a low S value
+	r := new(big.Int).SetBytes(signature[:len(signature)/2])
+	s := new(big.Int).SetBytes(signature[len(signature)/2:])
+	s, _, err = utils.ToLowS(kvKey.ecdsaPublicKey, s)
+	if err != nil {
+		return nil, err
+	}
+	return utils.MarshalECDSASignature(r, s)
+}
+
+// Verify verifies the signature in software
+func (csp *impl) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	if kvKey, ok := k.(*key); ok {
+		k = kvKey.publicKey
+	}
+	return csp.swCSP.Verify(k, signature, digest, opts)
+}
+
+// Encrypt is not supported
+func (csp *impl) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) ([]byte, error) {
+	return nil, errors.New("encryption is not supported by the Key Vault BCCSP")
+}
+
+// Decrypt is not supported
+func (csp *impl) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) ([]byte, error) {
+	return nil, errors.New("decryption is not supported by the Key Vault BCCSP")
+}
+
+// addKey imports the public key of a Key Vault key version, and returns a reference to the key version
+func (csp *impl) addKey(jwk *jsonWebKey) (*key, error) {
+	if jwk.KID == "" {
+		return nil, errors.New("Key Vault key has no key ID")
+	}
+	ecdsaPublicKey, err := jwk.ecdsaPublicKey()
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid public key of Key Vault key [%s]", jwk.KID)
+	}
+
+	publicKey, err := csp.swCSP.KeyImport(ecdsaPublicKey, &bccsp.ECDSAGoPublicKeyImportOpts{Temporary: true})
+	if err != nil {
+		return nil, errors.WithMessage(err, "importing public key failed")
+	}
+
+	k := &key{kid: jwk.KID, publicKey: publicKey, ecdsaPublicKey: ecdsaPublicKey}
+	csp.lock.Lock()
+	csp.keys[hex.EncodeToString(k.SKI())] = k
+	csp.lock.Unlock()
+	return k, nil
+}
+
+// ecdsaPublicKey returns the ECDSA public key of the JWK
+func (jwk *jsonWebKey) ecdsaPublicKey() (*ecdsa.PublicKey, error) {
+	if !strings.HasPrefix(jwk.KTY, "EC") {
+		return nil, errors.Errorf("key type [%s] is not EC", jwk.KTY)
+	}
+
+	var curve elliptic.Curve
+	switch jwk.Curve {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	default:
+		return nil, errors.Errorf("unsupported curve [%s]", jwk.Curve)
+	}
+
+	x, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(jwk.X, "="))
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding X coordinate failed")
+	}
+	y, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(jwk.Y, "="))
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding Y coordinate failed")
+	}
+
+	publicKey := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	if !curve.IsOnCurve(publicKey.X, publicKey.Y) {
+		return nil, errors.New("public key is not on the curve")
+	}
+	return publicKey, nil
+}
+
+// key is a reference to a version of a Key Vault key. It carries the key ID and the public key only.
+type key struct {
+	kid            string
+	publicKey      bccsp.Key
+	ecdsaPublicKey *ecdsa.PublicKey
+}
+
+// Bytes returns an error since private keys cannot be exported from Key Vault
+func (k *key) Bytes() ([]byte, error) {
+	return nil, errors.New("private keys cannot be exported from Key Vault")
+}
+
+// SKI returns the subject key identifier of the key
+func (k *key) SKI() []byte {
+	return k.publicKey.SKI()
+}
+
+// Symmetric returns false
+func (k *key) Symmetric() bool {
+	return false
+}
+
+// Private returns true
+func (k *key) Private() bool {
+	return true
+}
+
+// PublicKey returns the public key of the key
+func (k *key) PublicKey() (bccsp.Key, error) {
+	return k.publicKey, nil
+}
@@ -0,0 +1,294 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package azurekv
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testKeyName = "fabric-signer"
+	testToken   = "test-token"
+)
+
+type staticToken string
+
+func (t staticToken) Token() (string, error) {
+	if t == "" {
+		return "", errors.New("no token")
+	}
+	return string(t), nil
+}
+
+func TestAzureKeyVaultBCCSP(t *testing.T) {
+	vault := newMockKeyVault()
+	server := httptest.NewServer(vault)
+	defer server.Close()
+	vault.url = server.URL
+
+	csp, err := NewAzureKeyVaultBCCSP(server.URL+"/", testKeyName, staticToken(testToken))
+	require.NoError(t, err)
+
+	k, err := csp.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	require.NoError(t, err)
+	assert.True(t, k.Private())
+	assert.False(t, k.Symmetric())
+	assert.NotEmpty(t, k.SKI())
+	assert.Equal(t, server.URL+"/keys/"+testKeyName+"/1", k.(*key).kid)
+	_, err = k.Bytes()
+	assert.Error(t, err, "expecting private key not to be exportable")
+
+	digest, err := csp.Hash([]byte("message"), cryptosuite.GetSHA256Opts())
+	require.NoError(t, err)
+	signature, err := csp.Sign(k, digest, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1:ES256"}, vault.signed)
+
+	// Key Vault may return signatures with a high S value
+	publicKey := &vault.latest(testKeyName).PublicKey
+	_, s, err := utils.UnmarshalECDSASignature(signature)
+	require.NoError(t, err)
+	lowS, err := utils.IsLowS(publicKey, s)
+	require.NoError(t, err)
+	assert.True(t, lowS, "expecting signature with a low S value")
+
+	valid, err := csp.Verify(k, signature, digest, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// The public key is imported in software
+	pub, err := k.PublicKey()
+	require.NoError(t, err)
+	assert.Equal(t, k.SKI(), pub.SKI())
+	pubDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	imported, err := csp.KeyImport(pubDER, &bccsp.ECDSAPKIXPublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	valid, err = csp.Verify(imported, signature, digest, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// Keys are found by SKI, including keys created by other instances of the BCCSP
+	found, err := csp.GetKey(k.SKI())
+	require.NoError(t, err)
+	assert.Equal(t, k, found)
+	other, err := NewAzureKeyVaultBCCSP(server.URL, testKeyName, staticToken(testToken))
+	require.NoError(t, err)
+	found, err = other.GetKey(k.SKI())
+	require.NoError(t, err)
+	assert.Equal(t, k.SKI(), found.SKI())
+	_, err = other.GetKey([]byte("unknown"))
+	assert.EqualError(t, err, "key with SKI [756e6b6e6f776e] not found in Key Vault")
+
+	// Signing with P-384 keys uses ES384
+	k, err = csp.KeyGen(&bccsp.ECDSAP384KeyGenOpts{})
+	require.NoError(t, err)
+	digest, err = csp.Hash([]byte("message"), &bccsp.SHA384Opts{})
+	require.NoError(t, err)
+	signature, err = csp.Sign(k, digest, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1:ES256", "2:ES384"}, vault.signed)
+	valid, err = csp.Verify(k, signature, digest, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestAzureKeyVaultBCCSPErrors(t *testing.T) {
+	_, err := NewAzureKeyVaultBCCSP("myvault", testKeyName, staticToken(testToken))
+	assert.EqualError(t, err, "invalid Key Vault URL [myvault]")
+	_, err = NewAzureKeyVaultBCCSP("https://myvault.vault.azure.net", "", staticToken(testToken))
+	assert.EqualError(t, err, "Key Vault key name is required")
+	_, err = NewAzureKeyVaultBCCSP("https://myvault.vault.azure.net", testKeyName, nil)
+	assert.EqualError(t, err, "Key Vault credential is required")
+
+	vault := newMockKeyVault()
+	server := httptest.NewServer(vault)
+	defer server.Close()
+	vault.url = server.URL
+
+	csp, err := NewAzureKeyVaultBCCSP(server.URL, testKeyName, staticToken("invalid"))
+	require.NoError(t, err)
+	_, err = csp.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	assert.EqualError(t, err, "creating key in Key Vault failed: Key Vault returned status 401: Unauthorized: invalid token")
+	csp, err = NewAzureKeyVaultBCCSP(server.URL, testKeyName, staticToken(""))
+	require.NoError(t, err)
+	_, err = csp.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	assert.EqualError(t, err, "creating key in Key Vault failed: getting Key Vault access token failed: no token")
+
+	csp, err = NewAzureKeyVaultBCCSP(server.URL, testKeyName, staticToken(testToken))
+	require.NoError(t, err)
+	_, err = csp.GetKey([]byte("unknown"))
+	assert.EqualError(t, err, "reading key from Key Vault failed: Key Vault returned status 404: KeyNotFound: key not found")
+	_, err = csp.KeyGen(&bccsp.AESKeyGenOpts{})
+	assert.EqualError(t, err, "unsupported key generation algorithm [AES]")
+	_, err = csp.KeyDeriv(nil, nil)
+	assert.EqualError(t, err, "key derivation is not supported by the Key Vault BCCSP")
+	_, err = csp.Encrypt(nil, nil, nil)
+	assert.EqualError(t, err, "encryption is not supported by the Key Vault BCCSP")
+	_, err = csp.Decrypt(nil, nil, nil)
+	assert.EqualError(t, err, "decryption is not supported by the Key Vault BCCSP")
+
+	swKey, err := csp.(*impl).swCSP.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	_, err = csp.Sign(swKey, make([]byte, 32), nil)
+	assert.EqualError(t, err, "only Key Vault keys can be used for signing")
+
+	k, err := csp.KeyGen(&bccsp.ECDSAKeyGenOpts{})
+	require.NoError(t, err)
+	_, err = csp.Sign(k, make([]byte, 20), nil)
+	assert.EqualError(t, err, "invalid digest length [20]")
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	privateKeyDER, err := x509.MarshalECPrivateKey(privateKey)
+	require.NoError(t, err)
+	_, err = csp.KeyImport(privateKeyDER, &bccsp.ECDSAPrivateKeyImportOpts{Temporary: true})
+	assert.EqualError(t, err, "only public keys can be imported into the Key Vault BCCSP")
+
+	_, err = (&jsonWebKey{KTY: "RSA"}).ecdsaPublicKey()
+	assert.EqualError(t, err, "key type [RSA] is not EC")
+	_, err = (&jsonWebKey{KTY: "EC", Curve: "P-256K"}).ecdsaPublicKey()
+	assert.EqualError(t, err, "unsupported curve [P-256K]")
+	_, err = (&jsonWebKey{KTY: "EC", Curve: "P-256", X: "AQ", Y: "AQ"}).ecdsaPublicKey()
+	assert.EqualError(t, err, "public key is not on the curve")
+}
+
+// mockKeyVault implements the create, get and sign requests of the keys API of Key Vault.
+// Signatures are returned with a high S value.
+type mockKeyVault struct {
+	url    string
+	lock   sync.Mutex
+	keys   map[string][]*ecdsa.PrivateKey
+	signed []string
+}
+
+func newMockKeyVault() *mockKeyVault {
+	return &mockKeyVault{keys: make(map[string][]*ecdsa.PrivateKey)}
+}
+
+func (m *mockKeyVault) latest(name string) *ecdsa.PrivateKey {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	versions := m.keys[name]
+	return versions[len(versions)-1]
+}
+
+func (m *mockKeyVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("api-version") != apiVersion {
+		writeError(w, http.StatusBadRequest, "BadParameter", "invalid api-version")
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+testToken {
+		writeError(w, http.StatusUnauthorized, "Unauthorized", "invalid token")
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "create":
+		var req struct {
+			Curve string `json:"crv"`
+		}
+		json.NewDecoder(r.Body).Decode(&req) // nolint: errcheck
+		curve := elliptic.P256()
+		if req.Curve == "P-384" {
+			curve = elliptic.P384()
+		}
+		privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "InternalError", err.Error())
+			return
+		}
+		m.keys[parts[1]] = append(m.keys[parts[1]], privateKey)
+		m.writeKey(w, parts[1], len(m.keys[parts[1]]))
+	case r.Method == http.MethodGet && len(parts) == 2:
+		m.writeKey(w, parts[1], len(m.keys[parts[1]]))
+	case r.Method == http.MethodPost && len(parts) == 4 && parts[3] == "sign":
+		version, _ := strconv.Atoi(parts[2])
+		versions := m.keys[parts[1]]
+		if version < 1 || version > len(versions) {
+			writeError(w, http.StatusNotFound, "KeyNotFound", "key not found")
+			return
+		}
+		var req struct {
+			Algorithm string `json:"alg"`
+			Value     string `json:"value"`
+		}
+		json.NewDecoder(r.Body).Decode(&req) // nolint: errcheck
+		digest, _ := base64.RawURLEncoding.DecodeString(req.Value)
+		m.signed = append(m.signed, parts[2]+":"+req.Algorithm)
+
+		privateKey := versions[version-1]
+		rs, s, err := ecdsa.Sign(rand.Reader, privateKey, digest)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "InternalError", err.Error())
+			return
+		}
+		if s.Cmp(new(big.Int).Rsh(privateKey.Params().N, 1)) <= 0 {
+			s.Sub(privateKey.Params().N, s)
+		}
+		size := (privateKey.Params().BitSize + 7) / 8
+		signature := append(padded(rs, size), padded(s, size)...)
+		json.NewEncoder(w).Encode(map[string]string{"value": base64.RawURLEncoding.EncodeToString(signature)}) // nolint: errcheck
+	default:
+		writeError(w, http.StatusNotFound, "NotFound", r.URL.Path+" not found")
+	}
+}
+
+func (m *mockKeyVault) writeKey(w http.ResponseWriter, name string, version int) {
+	versions := m.keys[name]
+	if version < 1 || version > len(versions) {
+		writeError(w, http.StatusNotFound, "KeyNotFound", "key not found")
+		return
+	}
+	publicKey := versions[version-1].PublicKey
+	size := (publicKey.Params().BitSize + 7) / 8
+	json.NewEncoder(w).Encode(map[string]interface{}{ // nolint: errcheck
+		"key": map[string]string{
+			"kid": fmt.Sprintf("%s/keys/%s/%d", m.url, name, version),
+			"kty": "EC",
+			"crv": publicKey.Params().Name,
+			"x":   base64.RawURLEncoding.EncodeToString(padded(publicKey.X, size)),
+			"y":   base64.RawURLEncoding.EncodeToString(padded(publicKey.Y, size)),
+		},
+	})
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{ // nolint: errcheck
+		"error": map[string]string{"code": code, "message": message},
+	})
+}
+
+func padded(n *big.Int, size int) []byte {
+	b := n.Bytes()
+	return append(make([]byte, size-len(b)), b...)
+}
@@ -0,0 +1,195 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gcpkms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultEndpoint  = "https://cloudkms.googleapis.com"
+	metadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+	requestTimeout   = 30 * time.Second
+)
+
+// RetryableCodes are the HTTP status codes of the Cloud KMS API that are treated as transient
+var RetryableCodes = map[status.Group][]status.Code{
+	status.HTTPTransportStatus: {
+		status.Code(http.StatusTooManyRequests),
+		status.Code(http.StatusInternalServerError),
+		status.Code(http.StatusBadGateway),
+		status.Code(http.StatusServiceUnavailable),
+		status.Code(http.StatusGatewayTimeout),
+	},
+}
+
+// DefaultRetryOpts are the default retry options of the requests to Cloud KMS
+var DefaultRetryOpts = retry.Opts{
+	Attempts:       3,
+	InitialBackoff: 250 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	BackoffFactor:  2.0,
+	RetryableCodes: RetryableCodes,
+}
+
+// TokenSource provides the OAuth 2.0 access tokens of the requests to Cloud KMS
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// client is a client of the REST API of Cloud KMS
+type client struct {
+	endpoint    string
+	tokenSource TokenSource
+	retryOpts   retry.Opts
+	httpClient  *http.Client
+}
+
+// publicKey returns the PEM public key and the algorithm of the given key version
+func (c *client) publicKey(ctx context.Context, name string) (pem string, algorithm string, err error) {
+	var resp struct {
+		PEM       string `json:"pem"`
+		Algorithm string `json:"algorithm"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/v1/"+name+"/publicKey", nil, &resp); err != nil {
+		return "", "", err
+	}
+	return resp.PEM, resp.Algorithm, nil
+}
+
+// asymmetricSign signs the digest with the given key version, and returns the DER encoded signature
+func (c *client) asymmetricSign(ctx context.Context, name, digestAlgorithm string, digest []byte) ([]byte, error) {
+	req := map[string]interface{}{
+		"digest": map[string][]byte{digestAlgorithm: digest},
+	}
+	var resp struct {
+		Signature []byte `json:"signature"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/v1/"+name+":asymmetricSign", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Signature, nil
+}
+
+// do sends a request to the Cloud KMS API, retrying on transient errors, and unmarshals the response into data
+func (c *client) do(ctx context.Context, method, path string, body interface{}, data interface{}) error {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		if reqBody, err = json.Marshal(body); err != nil {
+			return errors.Wrap(err, "marshal of Cloud KMS request failed")
+		}
+	}
+
+	respBody, err := retry.NewInvoker(retry.New(c.retryOpts)).Invoke(
+		func() (interface{}, error) {
+			return c.send(ctx, method, path, reqBody)
+		},
+	)
+	if err != nil {
+		return err
+	}
+	return errors.Wrap(json.Unmarshal(respBody.([]byte), data), "unmarshal of Cloud KMS response failed")
+}
+
+func (c *client) send(ctx context.Context, method, path string, reqBody []byte) ([]byte, error) {
+	token, err := c.tokenSource.Token(ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "getting Cloud KMS access token failed")
+	}
+
+	req, err := http.NewRequest(method, c.endpoint+path, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, errors.Wrap(err, "creating Cloud KMS request failed")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cloud KMS request failed")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading Cloud KMS response failed")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var kmsErr struct {
+			Error struct {
+				Message string `json:"message"`
+				Status  string `json:"status"`
+			} `json:"error"`
+		}
+		json.Unmarshal(respBody, &kmsErr) // nolint: errcheck
+		return nil, errors.WithStack(status.New(status.HTTPTransportStatus, int32(resp.StatusCode),
+			strings.TrimSpace(kmsErr.Error.Status+" "+kmsErr.Error.Message), []interface{}{c.endpoint}))
+	}
+	return respBody, nil
+}
+
+// metadataTokenSource gets the access tokens of the default service account from the metadata server of Compute Engine
+type metadataTokenSource struct {
+	httpClient *http.Client
+	lock       sync.Mutex
+	token      string
+	expiry     time.Time
+}
+
+func (s *metadataTokenSource) Token(ctx context.Context) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expiry) {
+		return s.token, nil
+	}
+
+	req, err := http.NewRequest(http.MethodGet, metadataTokenURL, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "creating metadata server request failed")
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", errors.Wrap(err, "metadata server request failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("metadata server returned status %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", errors.Wrap(err, "unmarshal of metadata server token failed")
+	}
+
+	// Tokens are renewed a minute before they expire
+	s.token = token.AccessToken
+	s.expiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return s.token, nil
+}
@@ -0,0 +1,267 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package gcpkms provides a BCCSP whose private key is kept in Google Cloud Key Management Service (Cloud KMS).
+package gcpkms
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+	"hash"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/core")
+
+// Algorithms of the Cloud KMS key versions supported by the BCCSP
+const (
+	AlgorithmECSignP256SHA256 = "EC_SIGN_P256_SHA256"
+	AlgorithmECSignP384SHA384 = "EC_SIGN_P384_SHA384"
+)
+
+type options struct {
+	endpoint    string
+	tokenSource TokenSource
+	retryOpts   retry.Opts
+}
+
+// Option configures the Cloud KMS BCCSP
+type Option func(opts *options)
+
+// WithEndpoint sets the endpoint of the Cloud KMS API (https://cloudkms.googleapis.com by default)
+func WithEndpoint(endpoint string) Option {
+	return func(opts *options) {
+		opts.endpoint = endpoint
+	}
+}
+
+// WithTokenSource sets the source of the access tokens of the requests to Cloud KMS. By default,
+// the tokens of the default service account are read from the metadata server of Compute Engine.
+func WithTokenSource(tokenSource TokenSource) Option {
+	return func(opts *options) {
+		opts.tokenSource = tokenSource
+	}
+}
+
+// WithRetry sets the retry options of the requests to Cloud KMS (DefaultRetryOpts by default)
+func WithRetry(retryOpts retry.Opts) Option {
+	return func(opts *options) {
+		opts.retryOpts = retryOpts
+	}
+}
+
+// NewGCPKMSBCCSP returns a BCCSP whose private key is a key version of Cloud KMS. Sign delegates
+// to the asymmetricSign API of Cloud KMS, so that the private key material never leaves Cloud KMS.
+// Hashing, verification and public key import are done in software. The key version is read at
+// startup, and must be an EC P-256 or P-384 signing key. Transient errors of the Cloud KMS API
+// are retried.
+//  Parameters:
+//  ctx is the context of the startup requests to Cloud KMS
+//  keyResourceName is the resource name of the key version
+//  (projects/<project>/locations/<location>/keyRings/<key ring>/cryptoKeys/<key>/cryptoKeyVersions/<version>)
+//  opts are the options of the BCCSP
+//
+//  Returns:
+//  the BCCSP
+func NewGCPKMSBCCSP(ctx context.Context, keyResourceName string, opts ...Option) (bccsp.BCCSP, error) {
+	keyResourceName = strings.Trim(keyResourceName, "/")
+	if !strings.HasPrefix(keyResourceName, "projects/") || !strings.Contains(keyResourceName, "/cryptoKeyVersions/") {
+		return nil, errors.Errorf("invalid Cloud KMS key version resource name [%s]", keyResourceName)
+	}
+
+	o := options{endpoint: defaultEndpoint, retryOpts: DefaultRetryOpts}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	httpClient := &http.Client{Timeout: requestTimeout}
+	if o.tokenSource == nil {
+		o.tokenSource = &metadataTokenSource{httpClient: httpClient}
+	}
+
+	swCSP, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	if err != nil {
+		return nil, errors.WithMessage(err, "initializing software BCCSP failed")
+	}
+
+	csp := &impl{
+		client: &client{
+			endpoint:    strings.TrimSuffix(o.endpoint, "/"),
+			tokenSource: o.tokenSource,
+			retryOpts:   o.retryOpts,
+			httpClient:  httpClient,
+		},
+		swCSP: swCSP,
+	}
+	if csp.key, err = csp.loadKey(ctx, keyResourceName); err != nil {
+		return nil, err
+	}
+	logger.Debugf("Initialized Cloud KMS BCCSP with key [%s]", keyResourceName)
+	return csp, nil
+}
+
+type impl struct {
+	client *client
+	swCSP  bccsp.BCCSP
+	key    *key
+}
+
+// KeyGen is not supported since the key version is created in Cloud KMS
+func (csp *impl) KeyGen(opts bccsp.KeyGenOpts) (bccsp.Key, error) {
+	return nil, errors.New("key generation is not supported by the Cloud KMS BCCSP")
+}
+
+// KeyDeriv is not supported
+func (csp *impl) KeyDeriv(k bccsp.Key, opts bccsp.KeyDerivOpts) (bccsp.Key, error) {
+	return nil, errors.New("key derivation is not supported by the Cloud KMS BCCSP")
+}
+
+// KeyImport imports a public key. Private keys cannot be imported.
+func (csp *impl) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (bccsp.Key, error) {
+	k, err := csp.swCSP.KeyImport(raw, opts)
+	if err != nil {
+		return nil, err
+	}
+	if k.Private() || k.Symmetric() {
+		return nil, errors.New("only public keys can be imported into the Cloud KMS BCCSP")
+	}
+	return k, nil
+}
+
+// GetKey returns a reference to the Cloud KMS key version if it has the given SKI
+func (csp *impl) GetKey(ski []byte) (bccsp.Key, error) {
+	if !bytes.Equal(csp.key.SKI(), ski) {
+		return nil, errors.Errorf("Cloud KMS key with SKI [%x] not found", ski)
+	}
+	return csp.key, nil
+}
+
+// Hash hashes msg in software
+func (csp *impl) Hash(msg []byte, opts bccsp.HashOpts) ([]byte, error) {
+	return csp.swCSP.Hash(msg, opts)
+}
+
+// GetHash returns a hash function in software
+func (csp *impl) GetHash(opts bccsp.HashOpts) (hash.Hash, error) {
+	return csp.swCSP.GetHash(opts)
+}
+
+// Sign signs the digest with the Cloud KMS key version
+func (csp *impl) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	kmsKey, ok := k.(*key)
+	if !ok {
+		return nil, errors.New("only Cloud KMS keys can be used for signing")
+	}
+	if len(digest) != kmsKey.digestSize {
+		return nil, errors.Errorf("invalid digest length [%d] for key algorithm [%s]", len(digest), kmsKey.algorithm)
+	}
+
+	signature, err := csp.client.asymmetricSign(context.Background(), kmsKey.name, kmsKey.digestAlgorithm, digest)
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing with Cloud KMS failed")
+	}
+	// Fabric only accepts ECDSA signatures with a low S value
+	return utils.SignatureToLowS(kmsKey.ecdsaPublicKey, signature)
+}
+
+// Verify verifies the signature in software
+func (csp *impl) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	if kmsKey, ok := k.(*key); ok {
+		k = kmsKey.publicKey
+	}
+	return csp.swCSP.Verify(k, signature, digest, opts)
+}
+
+// Encrypt is not supported
+func (csp *impl) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) ([]byte, error) {
+	return nil, errors.New("encryption is not supported by the Cloud KMS BCCSP")
+}
+
+// Decrypt is not supported
+func (csp *impl) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) ([]byte, error) {
+	return nil, errors.New("decryption is not supported by the Cloud KMS BCCSP")
+}
+
+// loadKey reads the public key of the key version, and checks that the algorithm of the key version is supported
+func (csp *impl) loadKey(ctx context.Context, name string) (*key, error) {
+	publicKeyPEM, algorithm, err := csp.client.publicKey(ctx, name)
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading public key from Cloud KMS failed")
+	}
+
+	k := &key{name: name, algorithm: algorithm}
+	switch algorithm {
+	case AlgorithmECSignP256SHA256:
+		k.digestAlgorithm, k.digestSize = "sha256", 32
+	case AlgorithmECSignP384SHA384:
+		k.digestAlgorithm, k.digestSize = "sha384", 48
+	default:
+		return nil, errors.Errorf("unsupported algorithm [%s] of Cloud KMS key [%s], expecting EC P-256 or P-384 signing key", algorithm, name)
+	}
+
+	block, _ := pem.Decode([]byte(publicKeyPEM))
+	if block == nil {
+		return nil, errors.Errorf("invalid public key of Cloud KMS key [%s]", name)
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid public key of Cloud KMS key [%s]", name)
+	}
+	var ok bool
+	if k.ecdsaPublicKey, ok = pub.(*ecdsa.PublicKey); !ok {
+		return nil, errors.Errorf("Cloud KMS key [%s] is not an ECDSA key", name)
+	}
+
+	if k.publicKey, err = csp.swCSP.KeyImport(k.ecdsaPublicKey, &bccsp.ECDSAGoPublicKeyImportOpts{Temporary: true}); err != nil {
+		return nil, errors.WithMessage(err, "importing public key failed")
+	}
+	return k, nil
+}
+
+// key is a reference to a Cloud KMS key version. It carries the resource name and the public key only.
+type key struct {
+	name            string
+	algorithm       string
+	digestAlgorithm string
+	digestSize      int
+	publicKey       bccsp.Key
+	ecdsaPublicKey  *ecdsa.PublicKey
+}
+
+// Bytes returns an error since private keys cannot be exported from Cloud KMS
+func (k *key) Bytes() ([]byte, error) {
+	return nil, errors.New("private keys cannot be exported from Cloud KMS")
+}
+
+// SKI returns the subject key identifier of the key
+func (k *key) SKI() []byte {
+	return k.publicKey.SKI()
+}
+
+// Symmetric returns false
+func (k *key) Symmetric() bool {
+	return false
+}
+
+// Private returns true
+func (k *key) Private() bool {
+	return true
+}
+
+// PublicKey returns the public key of the key
+func (k *key) PublicKey() (bccsp.Key, error) {
+	return k.publicKey, nil
+}
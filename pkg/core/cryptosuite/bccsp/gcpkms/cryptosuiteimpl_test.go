@@ -0,0 +1,283 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gcpkms
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/retry"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testKeyName = "projects/p/locations/global/keyRings/fabric/cryptoKeys/signer/cryptoKeyVersions/1"
+	testToken   = "test-token"
+)
+
+var testRetryOpts = retry.Opts{
+	Attempts:       2,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     time.Millisecond,
+	BackoffFactor:  2.0,
+	RetryableCodes: RetryableCodes,
+}
+
+type staticTokenSource string
+
+func (s staticTokenSource) Token(ctx context.Context) (string, error) {
+	if s == "" {
+		return "", errors.New("no token")
+	}
+	return string(s), nil
+}
+
+func TestGCPKMSBCCSP(t *testing.T) {
+	kms := newMockKMS(t, elliptic.P256())
+	server := httptest.NewServer(kms)
+	defer server.Close()
+
+	// Transient errors are retried
+	kms.failures = 1
+	csp, err := NewGCPKMSBCCSP(context.Background(), testKeyName,
+		WithEndpoint(server.URL+"/"), WithTokenSource(staticTokenSource(testToken)), WithRetry(testRetryOpts))
+	require.NoError(t, err)
+	assert.Equal(t, 2, kms.requests)
+
+	_, err = csp.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	assert.EqualError(t, err, "key generation is not supported by the Cloud KMS BCCSP")
+
+	pub, err := kms.publicKeyImport(csp)
+	require.NoError(t, err)
+	k, err := csp.GetKey(pub.SKI())
+	require.NoError(t, err)
+	assert.True(t, k.Private())
+	assert.False(t, k.Symmetric())
+	_, err = k.Bytes()
+	assert.Error(t, err, "expecting private key not to be exportable")
+	_, err = csp.GetKey([]byte("unknown"))
+	assert.EqualError(t, err, "Cloud KMS key with SKI [756e6b6e6f776e] not found")
+
+	digest, err := csp.Hash([]byte("message"), cryptosuite.GetSHA256Opts())
+	require.NoError(t, err)
+	kms.failures = 1
+	signature, err := csp.Sign(k, digest, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sha256"}, kms.signed)
+
+	// Cloud KMS may return signatures with a high S value
+	_, s, err := utils.UnmarshalECDSASignature(signature)
+	require.NoError(t, err)
+	lowS, err := utils.IsLowS(&kms.privateKey.PublicKey, s)
+	require.NoError(t, err)
+	assert.True(t, lowS, "expecting signature with a low S value")
+
+	valid, err := csp.Verify(k, signature, digest, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	valid, err = csp.Verify(pub, signature, digest, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = csp.Sign(k, make([]byte, 48), nil)
+	assert.EqualError(t, err, "invalid digest length [48] for key algorithm [EC_SIGN_P256_SHA256]")
+}
+
+func TestGCPKMSBCCSPP384(t *testing.T) {
+	kms := newMockKMS(t, elliptic.P384())
+	server := httptest.NewServer(kms)
+	defer server.Close()
+
+	csp, err := NewGCPKMSBCCSP(context.Background(), testKeyName, WithEndpoint(server.URL), WithTokenSource(staticTokenSource(testToken)))
+	require.NoError(t, err)
+	pub, err := kms.publicKeyImport(csp)
+	require.NoError(t, err)
+	k, err := csp.GetKey(pub.SKI())
+	require.NoError(t, err)
+
+	digest, err := csp.Hash([]byte("message"), &bccsp.SHA384Opts{})
+	require.NoError(t, err)
+	signature, err := csp.Sign(k, digest, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sha384"}, kms.signed)
+	valid, err := csp.Verify(k, signature, digest, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestGCPKMSBCCSPErrors(t *testing.T) {
+	_, err := NewGCPKMSBCCSP(context.Background(), "projects/p/locations/global/keyRings/fabric/cryptoKeys/signer")
+	assert.EqualError(t, err, "invalid Cloud KMS key version resource name [projects/p/locations/global/keyRings/fabric/cryptoKeys/signer]")
+
+	kms := newMockKMS(t, elliptic.P256())
+	server := httptest.NewServer(kms)
+	defer server.Close()
+
+	// Errors other than transient errors are not retried
+	_, err = NewGCPKMSBCCSP(context.Background(), testKeyName, WithEndpoint(server.URL), WithTokenSource(staticTokenSource("invalid")), WithRetry(testRetryOpts))
+	require.Error(t, err)
+	s, ok := status.FromError(err)
+	require.True(t, ok, "expecting status error")
+	assert.Equal(t, status.HTTPTransportStatus, s.Group)
+	assert.EqualValues(t, http.StatusUnauthorized, s.Code)
+	assert.Equal(t, "UNAUTHENTICATED invalid token", s.Message)
+	assert.Equal(t, 1, kms.requests)
+
+	// Transient errors are retried up to the number of attempts
+	kms.failures = 3
+	_, err = NewGCPKMSBCCSP(context.Background(), testKeyName, WithEndpoint(server.URL), WithTokenSource(staticTokenSource(testToken)), WithRetry(testRetryOpts))
+	require.Error(t, err)
+	s, ok = status.FromError(err)
+	require.True(t, ok, "expecting status error")
+	assert.EqualValues(t, http.StatusServiceUnavailable, s.Code)
+	assert.Equal(t, 4, kms.requests)
+
+	_, err = NewGCPKMSBCCSP(context.Background(), testKeyName, WithEndpoint(server.URL), WithTokenSource(staticTokenSource("")))
+	assert.EqualError(t, err, "reading public key from Cloud KMS failed: getting Cloud KMS access token failed: no token")
+
+	// The algorithm of the key version is checked at startup
+	kms.algorithm = "RSA_SIGN_PKCS1_2048_SHA256"
+	_, err = NewGCPKMSBCCSP(context.Background(), testKeyName, WithEndpoint(server.URL), WithTokenSource(staticTokenSource(testToken)))
+	assert.EqualError(t, err, "unsupported algorithm [RSA_SIGN_PKCS1_2048_SHA256] of Cloud KMS key ["+testKeyName+"], expecting EC P-256 or P-384 signing key")
+	kms.algorithm = AlgorithmECSignP256SHA256
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	require.NoError(t, err)
+	kms.publicKey = &rsaKey.PublicKey
+	_, err = NewGCPKMSBCCSP(context.Background(), testKeyName, WithEndpoint(server.URL), WithTokenSource(staticTokenSource(testToken)))
+	assert.EqualError(t, err, "Cloud KMS key ["+testKeyName+"] is not an ECDSA key")
+	kms.publicKey = nil
+
+	csp, err := NewGCPKMSBCCSP(context.Background(), testKeyName, WithEndpoint(server.URL), WithTokenSource(staticTokenSource(testToken)))
+	require.NoError(t, err)
+	_, err = csp.KeyDeriv(nil, nil)
+	assert.EqualError(t, err, "key derivation is not supported by the Cloud KMS BCCSP")
+	_, err = csp.Encrypt(nil, nil, nil)
+	assert.EqualError(t, err, "encryption is not supported by the Cloud KMS BCCSP")
+	_, err = csp.Decrypt(nil, nil, nil)
+	assert.EqualError(t, err, "decryption is not supported by the Cloud KMS BCCSP")
+
+	swKey, err := csp.(*impl).swCSP.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	_, err = csp.Sign(swKey, make([]byte, 32), nil)
+	assert.EqualError(t, err, "only Cloud KMS keys can be used for signing")
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	privateKeyDER, err := x509.MarshalECPrivateKey(privateKey)
+	require.NoError(t, err)
+	_, err = csp.KeyImport(privateKeyDER, &bccsp.ECDSAPrivateKeyImportOpts{Temporary: true})
+	assert.EqualError(t, err, "only public keys can be imported into the Cloud KMS BCCSP")
+}
+
+// mockKMS implements the publicKey and asymmetricSign requests of the Cloud KMS API for the key version testKeyName.
+// Signatures are returned with a high S value.
+type mockKMS struct {
+	t          *testing.T
+	lock       sync.Mutex
+	privateKey *ecdsa.PrivateKey
+	// publicKey overrides the public key of the private key
+	publicKey interface{}
+	algorithm string
+	// failures is the number of requests that fail with a transient error
+	failures int
+	requests int
+	signed   []string
+}
+
+func newMockKMS(t *testing.T, curve elliptic.Curve) *mockKMS {
+	privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+	require.NoError(t, err)
+	algorithm := AlgorithmECSignP256SHA256
+	if curve == elliptic.P384() {
+		algorithm = AlgorithmECSignP384SHA384
+	}
+	return &mockKMS{t: t, privateKey: privateKey, algorithm: algorithm}
+}
+
+// publicKeyImport imports the public key of the key version into the BCCSP
+func (m *mockKMS) publicKeyImport(csp bccsp.BCCSP) (bccsp.Key, error) {
+	der, err := x509.MarshalPKIXPublicKey(&m.privateKey.PublicKey)
+	require.NoError(m.t, err)
+	return csp.KeyImport(der, &bccsp.ECDSAPKIXPublicKeyImportOpts{Temporary: true})
+}
+
+func (m *mockKMS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.requests++
+	if m.failures > 0 {
+		m.failures--
+		writeError(w, http.StatusServiceUnavailable, "UNAVAILABLE", "try again")
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+testToken {
+		writeError(w, http.StatusUnauthorized, "UNAUTHENTICATED", "invalid token")
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/"+testKeyName+"/publicKey":
+		var publicKey interface{} = &m.privateKey.PublicKey
+		if m.publicKey != nil {
+			publicKey = m.publicKey
+		}
+		der, err := x509.MarshalPKIXPublicKey(publicKey)
+		require.NoError(m.t, err)
+		pemBytes := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+		json.NewEncoder(w).Encode(map[string]string{"pem": string(pemBytes), "algorithm": m.algorithm}) // nolint: errcheck
+	case r.Method == http.MethodPost && r.URL.Path == "/v1/"+testKeyName+":asymmetricSign":
+		var req struct {
+			Digest map[string][]byte `json:"digest"`
+		}
+		require.NoError(m.t, json.NewDecoder(r.Body).Decode(&req))
+		require.Len(m.t, req.Digest, 1)
+		var digest []byte
+		for algorithm, d := range req.Digest {
+			m.signed = append(m.signed, algorithm)
+			digest = d
+		}
+
+		rs, s, err := ecdsa.Sign(rand.Reader, m.privateKey, digest)
+		require.NoError(m.t, err)
+		if s.Cmp(new(big.Int).Rsh(m.privateKey.Params().N, 1)) <= 0 {
+			s.Sub(m.privateKey.Params().N, s)
+		}
+		signature, err := asn1.Marshal(struct{ R, S *big.Int }{rs, s})
+		require.NoError(m.t, err)
+		json.NewEncoder(w).Encode(map[string][]byte{"signature": signature}) // nolint: errcheck
+	default:
+		writeError(w, http.StatusNotFound, "NOT_FOUND", strings.TrimPrefix(r.URL.Path, "/")+" not found")
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, status, message string) {
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{ // nolint: errcheck
+		"error": map[string]interface{}{"code": code, "status": status, "message": message},
+	})
+}
@@ -0,0 +1,270 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package kms provides a BCCSP whose private keys are kept in AWS Key Management Service (KMS).
+package kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/hex"
+	"hash"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/wrapper"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/core")
+
+// Key specs and signing algorithms of KMS
+const (
+	KeySpecECCNISTP256 = "ECC_NIST_P256"
+	KeySpecECCNISTP384 = "ECC_NIST_P384"
+
+	SigningAlgorithmECDSASHA256 = "ECDSA_SHA_256"
+	SigningAlgorithmECDSASHA384 = "ECDSA_SHA_384"
+)
+
+// Client is the subset of the KMS API used by the BCCSP. It is typically implemented by an
+// adapter of the KMS client of the AWS SDK, so that the SDK does not depend on the AWS SDK.
+type Client interface {
+	// CreateKey creates an asymmetric signing key of the given key spec (e.g. ECC_NIST_P256), and returns its key ID
+	CreateKey(keySpec string) (keyID string, err error)
+	// GetPublicKey returns the DER encoded public key (SubjectPublicKeyInfo) of the given key
+	GetPublicKey(keyID string) ([]byte, error)
+	// Sign signs the digest (message type DIGEST) with the given key and signing algorithm, and returns the DER encoded signature
+	Sign(keyID string, digest []byte, signingAlgorithm string) ([]byte, error)
+}
+
+// GetSuite returns a crypto suite whose private keys are kept in KMS.
+//  Parameters:
+//  client is the KMS client
+//  keyIDs are the IDs (or ARNs) of existing KMS keys that may be looked up by their SKI
+//
+//  Returns:
+//  the crypto suite
+func GetSuite(client Client, keyIDs ...string) (core.CryptoSuite, error) {
+	csp, err := New(client, keyIDs...)
+	if err != nil {
+		return nil, err
+	}
+	return wrapper.NewCryptoSuite(csp), nil
+}
+
+// New returns a BCCSP whose private keys are kept in KMS. KeyGen creates a key in KMS, and Sign
+// delegates to the Sign API of KMS with the ID of the key, so that the private key material never
+// leaves KMS. Hashing, verification and public key import are done in software.
+//  Parameters:
+//  client is the KMS client
+//  keyIDs are the IDs (or ARNs) of existing KMS keys that may be looked up by their SKI
+//
+//  Returns:
+//  the BCCSP
+func New(client Client, keyIDs ...string) (bccsp.BCCSP, error) {
+	if client == nil {
+		return nil, errors.New("KMS client is required")
+	}
+
+	swCSP, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	if err != nil {
+		return nil, errors.WithMessage(err, "initializing software BCCSP failed")
+	}
+
+	csp := &impl{
+		client: client,
+		swCSP:  swCSP,
+		keys:   make(map[string]*key),
+	}
+	for _, keyID := range keyIDs {
+		if _, err := csp.loadKey(keyID); err != nil {
+			return nil, err
+		}
+	}
+	return csp, nil
+}
+
+type impl struct {
+	client Client
+	swCSP  bccsp.BCCSP
+	lock   sync.RWMutex
+	// keys are the KMS keys by hex SKI
+	keys map[string]*key
+}
+
+// KeyGen creates an ECDSA key in KMS
+func (csp *impl) KeyGen(opts bccsp.KeyGenOpts) (bccsp.Key, error) {
+	if opts == nil {
+		return nil, errors.New("key generation options are required")
+	}
+
+	var keySpec string
+	switch opts.Algorithm() {
+	case bccsp.ECDSA, bccsp.ECDSAP256:
+		keySpec = KeySpecECCNISTP256
+	case bccsp.ECDSAP384:
+		keySpec = KeySpecECCNISTP384
+	default:
+		return nil, errors.Errorf("unsupported key generation algorithm [%s]", opts.Algorithm())
+	}
+
+	keyID, err := csp.client.CreateKey(keySpec)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating key in KMS failed")
+	}
+	k, err := csp.loadKey(keyID)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("Created key [%s] in KMS", keyID)
+	return k, nil
+}
+
+// KeyDeriv is not supported
+func (csp *impl) KeyDeriv(k bccsp.Key, opts bccsp.KeyDerivOpts) (bccsp.Key, error) {
+	return nil, errors.New("key derivation is not supported by the KMS BCCSP")
+}
+
+// KeyImport imports a public key. Private keys cannot be imported.
+func (csp *impl) KeyImport(raw interface{}, opts bccsp.KeyImportOpts) (bccsp.Key, error) {
+	k, err := csp.swCSP.KeyImport(raw, opts)
+	if err != nil {
+		return nil, err
+	}
+	if k.Private() || k.Symmetric() {
+		return nil, errors.New("only public keys can be imported into the KMS BCCSP")
+	}
+	return k, nil
+}
+
+// GetKey returns a reference to the KMS key of the given SKI
+func (csp *impl) GetKey(ski []byte) (bccsp.Key, error) {
+	csp.lock.RLock()
+	defer csp.lock.RUnlock()
+
+	k, ok := csp.keys[hex.EncodeToString(ski)]
+	if !ok {
+		return nil, errors.Errorf("KMS key with SKI [%x] not found", ski)
+	}
+	return k, nil
+}
+
+// Hash hashes msg in software
+func (csp *impl) Hash(msg []byte, opts bccsp.HashOpts) ([]byte, error) {
+	return csp.swCSP.Hash(msg, opts)
+}
+
+// GetHash returns a hash function in software
+func (csp *impl) GetHash(opts bccsp.HashOpts) (hash.Hash, error) {
+	return csp.swCSP.GetHash(opts)
+}
+
+// Sign signs the digest with a KMS key
+func (csp *impl) Sign(k bccsp.Key, digest []byte, opts bccsp.SignerOpts) ([]byte, error) {
+	kmsKey, ok := k.(*key)
+	if !ok {
+		return nil, errors.New("only KMS keys can be used for signing")
+	}
+
+	var signingAlgorithm string
+	switch len(digest) {
+	case 32:
+		signingAlgorithm = SigningAlgorithmECDSASHA256
+	case 48:
+		signingAlgorithm = SigningAlgorithmECDSASHA384
+	default:
+		return nil, errors.Errorf("invalid digest length [%d]", len(digest))
+	}
+
+	signature, err := csp.client.Sign(kmsKey.keyID, digest, signingAlgorithm)
+	if err != nil {
+		return nil, errors.Wrapf(err, "signing with KMS key [%s] failed", kmsKey.keyID)
+	}
+	// Fabric only accepts ECDSA signatures with a low S value
+	return utils.SignatureToLowS(kmsKey.ecdsaPublicKey, signature)
+}
+
+// Verify verifies the signature in software
+func (csp *impl) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.SignerOpts) (bool, error) {
+	if kmsKey, ok := k.(*key); ok {
+		k = kmsKey.publicKey
+	}
+	return csp.swCSP.Verify(k, signature, digest, opts)
+}
+
+// Encrypt is not supported
+func (csp *impl) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) ([]byte, error) {
+	return nil, errors.New("encryption is not supported by the KMS BCCSP")
+}
+
+// Decrypt is not supported
+func (csp *impl) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) ([]byte, error) {
+	return nil, errors.New("decryption is not supported by the KMS BCCSP")
+}
+
+// loadKey reads the public key of a KMS key, and returns a reference to the key
+func (csp *impl) loadKey(keyID string) (*key, error) {
+	der, err := csp.client.GetPublicKey(keyID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading public key of KMS key [%s] failed", keyID)
+	}
+	pub, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid public key of KMS key [%s]", keyID)
+	}
+	ecdsaPublicKey, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("KMS key [%s] is not an ECDSA key", keyID)
+	}
+
+	publicKey, err := csp.swCSP.KeyImport(ecdsaPublicKey, &bccsp.ECDSAGoPublicKeyImportOpts{Temporary: true})
+	if err != nil {
+		return nil, errors.WithMessage(err, "importing public key failed")
+	}
+
+	k := &key{keyID: keyID, publicKey: publicKey, ecdsaPublicKey: ecdsaPublicKey}
+	csp.lock.Lock()
+	csp.keys[hex.EncodeToString(k.SKI())] = k
+	csp.lock.Unlock()
+	return k, nil
+}
+
+// key is a reference to a private key in KMS. It carries the KMS key ID and the public key only.
+type key struct {
+	keyID          string
+	publicKey      bccsp.Key
+	ecdsaPublicKey *ecdsa.PublicKey
+}
+
+// Bytes returns an error since private keys cannot be exported from KMS
+func (k *key) Bytes() ([]byte, error) {
+	return nil, errors.New("private keys cannot be exported from KMS")
+}
+
+// SKI returns the subject key identifier of the key
+func (k *key) SKI() []byte {
+	return k.publicKey.SKI()
+}
+
+// Symmetric returns false
+func (k *key) Symmetric() bool {
+	return false
+}
+
+// Private returns true
+func (k *key) Private() bool {
+	return true
+}
+
+// PublicKey returns the public key of the key
+func (k *key) PublicKey() (bccsp.Key, error) {
+	return k.publicKey, nil
+}
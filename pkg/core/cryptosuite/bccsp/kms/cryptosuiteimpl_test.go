@@ -0,0 +1,208 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kms
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKMSCryptoSuite(t *testing.T) {
+	client := newMockKMS()
+	cs, err := GetSuite(client)
+	require.NoError(t, err)
+
+	k, err := cs.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	require.NoError(t, err)
+	assert.True(t, k.Private())
+	assert.False(t, k.Symmetric())
+	assert.NotEmpty(t, k.SKI())
+	_, err = k.Bytes()
+	assert.Error(t, err, "expecting private key not to be exportable")
+	require.Len(t, client.keys, 1)
+
+	digest, err := cs.Hash([]byte("message"), cryptosuite.GetSHA256Opts())
+	require.NoError(t, err)
+	signature, err := cs.Sign(k, digest, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key-1:" + SigningAlgorithmECDSASHA256}, client.signed)
+
+	// KMS may return signatures with a high S value
+	publicKey := &client.keys["key-1"].PublicKey
+	_, s, err := utils.UnmarshalECDSASignature(signature)
+	require.NoError(t, err)
+	lowS, err := utils.IsLowS(publicKey, s)
+	require.NoError(t, err)
+	assert.True(t, lowS, "expecting signature with a low S value")
+
+	valid, err := cs.Verify(k, signature, digest, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// The public key is imported in software
+	pub, err := k.PublicKey()
+	require.NoError(t, err)
+	assert.Equal(t, k.SKI(), pub.SKI())
+	pubDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	imported, err := cs.KeyImport(pubDER, &bccsp.ECDSAPKIXPublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	valid, err = cs.Verify(imported, signature, digest, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	found, err := cs.GetKey(k.SKI())
+	require.NoError(t, err)
+	assert.Equal(t, k.SKI(), found.SKI())
+
+	// Existing KMS keys are found by the SKI of their public key
+	other, err := New(client, "key-1")
+	require.NoError(t, err)
+	existing, err := other.GetKey(k.SKI())
+	require.NoError(t, err)
+	assert.Equal(t, "key-1", existing.(*key).keyID)
+	_, err = other.GetKey([]byte("unknown"))
+	assert.EqualError(t, err, "KMS key with SKI [756e6b6e6f776e] not found")
+}
+
+func TestKMSCryptoSuiteP384(t *testing.T) {
+	client := newMockKMS()
+	csp, err := New(client)
+	require.NoError(t, err)
+
+	k, err := csp.KeyGen(&bccsp.ECDSAP384KeyGenOpts{})
+	require.NoError(t, err)
+	digest, err := csp.Hash([]byte("message"), &bccsp.SHA384Opts{})
+	require.NoError(t, err)
+	signature, err := csp.Sign(k, digest, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"key-1:" + SigningAlgorithmECDSASHA384}, client.signed)
+
+	valid, err := csp.Verify(k, signature, digest, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestKMSCryptoSuiteErrors(t *testing.T) {
+	_, err := New(nil)
+	assert.EqualError(t, err, "KMS client is required")
+	_, err = New(newMockKMS(), "unknown")
+	assert.EqualError(t, err, "reading public key of KMS key [unknown] failed: key [unknown] not found")
+
+	csp, err := New(newMockKMS())
+	require.NoError(t, err)
+
+	_, err = csp.KeyGen(&bccsp.AESKeyGenOpts{})
+	assert.EqualError(t, err, "unsupported key generation algorithm [AES]")
+	_, err = csp.KeyDeriv(nil, nil)
+	assert.EqualError(t, err, "key derivation is not supported by the KMS BCCSP")
+	_, err = csp.Encrypt(nil, nil, nil)
+	assert.EqualError(t, err, "encryption is not supported by the KMS BCCSP")
+	_, err = csp.Decrypt(nil, nil, nil)
+	assert.EqualError(t, err, "decryption is not supported by the KMS BCCSP")
+
+	swKey, err := csp.(*impl).swCSP.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	_, err = csp.Sign(swKey, make([]byte, 32), nil)
+	assert.EqualError(t, err, "only KMS keys can be used for signing")
+
+	k, err := csp.KeyGen(&bccsp.ECDSAKeyGenOpts{})
+	require.NoError(t, err)
+	_, err = csp.Sign(k, make([]byte, 20), nil)
+	assert.EqualError(t, err, "invalid digest length [20]")
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	privateKeyDER, err := x509.MarshalECPrivateKey(privateKey)
+	require.NoError(t, err)
+	_, err = csp.KeyImport(privateKeyDER, &bccsp.ECDSAPrivateKeyImportOpts{Temporary: true})
+	assert.EqualError(t, err, "only public keys can be imported into the KMS BCCSP")
+}
+
+// mockKMS keeps ECDSA keys in memory, and returns signatures with a high S value
+type mockKMS struct {
+	lock   sync.Mutex
+	keys   map[string]*ecdsa.PrivateKey
+	signed []string
+}
+
+func newMockKMS() *mockKMS {
+	return &mockKMS{keys: make(map[string]*ecdsa.PrivateKey)}
+}
+
+func (m *mockKMS) CreateKey(keySpec string) (string, error) {
+	var curve elliptic.Curve
+	switch keySpec {
+	case KeySpecECCNISTP256:
+		curve = elliptic.P256()
+	case KeySpecECCNISTP384:
+		curve = elliptic.P384()
+	default:
+		return "", errors.Errorf("unsupported key spec [%s]", keySpec)
+	}
+	privateKey, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		return "", err
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	keyID := fmt.Sprintf("key-%d", len(m.keys)+1)
+	m.keys[keyID] = privateKey
+	return keyID, nil
+}
+
+func (m *mockKMS) GetPublicKey(keyID string) ([]byte, error) {
+	privateKey, err := m.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	return x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+}
+
+func (m *mockKMS) Sign(keyID string, digest []byte, signingAlgorithm string) ([]byte, error) {
+	privateKey, err := m.key(keyID)
+	if err != nil {
+		return nil, err
+	}
+	m.lock.Lock()
+	m.signed = append(m.signed, keyID+":"+signingAlgorithm)
+	m.lock.Unlock()
+
+	r, s, err := ecdsa.Sign(rand.Reader, privateKey, digest)
+	if err != nil {
+		return nil, err
+	}
+	if s.Cmp(new(big.Int).Rsh(privateKey.Params().N, 1)) <= 0 {
+		s.Sub(privateKey.Params().N, s)
+	}
+	return asn1.Marshal(struct{ R, S *big.Int }{r, s})
+}
+
+func (m *mockKMS) key(keyID string) (*ecdsa.PrivateKey, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	privateKey, ok := m.keys[keyID]
+	if !ok {
+		return nil, errors.Errorf("key [%s] not found", keyID)
+	}
+	return privateKey, nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	mPkcs11 "github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
+)
+
+// protectedAttributes are set by the PKCS#11 cryptosuite and cannot be overridden by a key template.
+// They identify the key and keep the private key sensitive and non-extractable.
+var protectedAttributes = map[uint]string{
+	mPkcs11.CKA_CLASS:       "CKA_CLASS",
+	mPkcs11.CKA_KEY_TYPE:    "CKA_KEY_TYPE",
+	mPkcs11.CKA_EC_PARAMS:   "CKA_EC_PARAMS",
+	mPkcs11.CKA_ID:          "CKA_ID",
+	mPkcs11.CKA_LABEL:       "CKA_LABEL",
+	mPkcs11.CKA_EXTRACTABLE: "CKA_EXTRACTABLE",
+	mPkcs11.CKA_SENSITIVE:   "CKA_SENSITIVE",
+}
+
+// ObjectAttributes holds the PKCS#11 attributes applied to the objects of a generated key pair,
+// in addition to the ones set by the PKCS#11 cryptosuite
+type ObjectAttributes struct {
+	Public  []*mPkcs11.Attribute
+	Private []*mPkcs11.Attribute
+}
+
+// ECDSATemplateKeyGenOpts contains options for generating an ECDSA key pair in the HSM
+// whose objects are created with the given attribute template
+type ECDSATemplateKeyGenOpts struct {
+	Template  ObjectAttributes
+	Temporary bool
+}
+
+// Algorithm returns the key generation algorithm identifier (to be used).
+func (opts *ECDSATemplateKeyGenOpts) Algorithm() string {
+	return "ECDSA"
+}
+
+// Ephemeral returns true if the key to generate has to be ephemeral,
+// false otherwise.
+func (opts *ECDSATemplateKeyGenOpts) Ephemeral() bool {
+	return opts.Temporary
+}
+
+// MergeAttributes returns the default attributes with those of the template applied.
+// Template attributes replace default attributes of the same type; protected attributes are rejected.
+func MergeAttributes(defaults []*mPkcs11.Attribute, template []*mPkcs11.Attribute) ([]*mPkcs11.Attribute, error) {
+	merged := make([]*mPkcs11.Attribute, len(defaults))
+	copy(merged, defaults)
+
+	for _, attr := range template {
+		if name, ok := protectedAttributes[attr.Type]; ok {
+			return nil, errors.Errorf("attribute %s cannot be set by the key template", name)
+		}
+		replaced := false
+		for i, d := range merged {
+			if d.Type == attr.Type {
+				merged[i] = attr
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, attr)
+		}
+	}
+	return merged, nil
+}
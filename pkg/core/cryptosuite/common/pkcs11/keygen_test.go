@@ -0,0 +1,43 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"testing"
+
+	mPkcs11 "github.com/miekg/pkcs11"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeAttributes(t *testing.T) {
+	defaults := []*mPkcs11.Attribute{
+		mPkcs11.NewAttribute(mPkcs11.CKA_TOKEN, true),
+		mPkcs11.NewAttribute(mPkcs11.CKA_SIGN, true),
+	}
+
+	merged, err := MergeAttributes(defaults, []*mPkcs11.Attribute{
+		mPkcs11.NewAttribute(mPkcs11.CKA_SIGN, false),
+		mPkcs11.NewAttribute(mPkcs11.CKA_MODIFIABLE, false),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []*mPkcs11.Attribute{
+		mPkcs11.NewAttribute(mPkcs11.CKA_TOKEN, true),
+		mPkcs11.NewAttribute(mPkcs11.CKA_SIGN, false),
+		mPkcs11.NewAttribute(mPkcs11.CKA_MODIFIABLE, false),
+	}, merged)
+
+	// Defaults are not modified
+	assert.Equal(t, mPkcs11.NewAttribute(mPkcs11.CKA_SIGN, true), defaults[1])
+
+	_, err = MergeAttributes(defaults, []*mPkcs11.Attribute{mPkcs11.NewAttribute(mPkcs11.CKA_EXTRACTABLE, true)})
+	assert.Error(t, err)
+
+	opts := &ECDSATemplateKeyGenOpts{Temporary: true}
+	assert.Equal(t, "ECDSA", opts.Algorithm())
+	assert.True(t, opts.Ephemeral())
+}
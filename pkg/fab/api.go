@@ -37,6 +37,7 @@ type PeerConfig struct {
 	URL         string
 	GRPCOptions map[string]interface{}
 	TLSCACerts  endpoint.TLSConfig
+	Properties  map[string]interface{}
 }
 
 // OrganizationConfig provides the definition of an organization in the network
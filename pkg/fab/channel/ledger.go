@@ -18,6 +18,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/txn"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
 )
@@ -28,6 +29,7 @@ const (
 	lscc                  = "lscc"
 	lsccChaincodes        = "getchaincodes"
 	lsccCollectionsConfig = "getcollectionsconfig"
+	lsccChaincodeData     = "getccdata"
 )
 
 // Ledger is a client that provides access to the underlying ledger of a channel.
@@ -234,6 +236,32 @@ func createCollectionsConfigQueryResponse(tpr *fab.TransactionProposalResponse)
 	return &response, nil
 }
 
+// QueryChaincodeData queries the instantiation data, including the endorsement policy, of a chaincode on this channel.
+func (c *Ledger) QueryChaincodeData(reqCtx reqContext.Context, chaincodeName string, targets []fab.ProposalProcessor, verifier ResponseVerifier) ([]*ccprovider.ChaincodeData, error) {
+	cir := createChaincodeDataInvokeRequest(c.chName, chaincodeName)
+	tprs, errs := queryChaincode(reqCtx, c.chName, cir, targets, verifier)
+
+	responses := []*ccprovider.ChaincodeData{}
+	for _, tpr := range tprs {
+		r, err := createChaincodeDataQueryResponse(tpr)
+		if err != nil {
+			errs = multi.Append(errs, errors.WithMessage(err, "From target: "+tpr.Endorser))
+		} else {
+			responses = append(responses, r)
+		}
+	}
+	return responses, errs
+}
+
+func createChaincodeDataQueryResponse(tpr *fab.TransactionProposalResponse) (*ccprovider.ChaincodeData, error) {
+	response := ccprovider.ChaincodeData{}
+	err := proto.Unmarshal(tpr.ProposalResponse.GetResponse().Payload, &response)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal of transaction proposal response failed")
+	}
+	return &response, nil
+}
+
 // QueryConfigBlock returns the current configuration block for the specified channel. If the
 // peer doesn't belong to the channel, return error
 func (c *Ledger) QueryConfigBlock(reqCtx reqContext.Context, targets []fab.ProposalProcessor, verifier ResponseVerifier) (*common.Block, error) {
@@ -301,6 +329,15 @@ func createChaincodeInvokeRequest() fab.ChaincodeInvokeRequest {
 	return cir
 }
 
+func createChaincodeDataInvokeRequest(channelID string, chaincodeName string) fab.ChaincodeInvokeRequest {
+	cir := fab.ChaincodeInvokeRequest{
+		ChaincodeID: lscc,
+		Fcn:         lsccChaincodeData,
+		Args:        [][]byte{[]byte(channelID), []byte(chaincodeName)},
+	}
+	return cir
+}
+
 func createCollectionsConfigInvokeRequest(chaincodeName string) fab.ChaincodeInvokeRequest {
 	cir := fab.ChaincodeInvokeRequest{
 		ChaincodeID: lscc,
@@ -20,6 +20,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
 	"github.com/stretchr/testify/assert"
 )
@@ -99,6 +100,25 @@ func TestQueryInstantiatedChaincodes(t *testing.T) {
 
 }
 
+func TestQueryChaincodeData(t *testing.T) {
+	channel, _ := setupTestLedger()
+	ccData := &ccprovider.ChaincodeData{Name: "mycc", Version: "v1", Escc: "escc", Vscc: "vscc", Policy: []byte("policy")}
+	payload, err := proto.Marshal(ccData)
+	assert.Nil(t, err)
+	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Status: 200, Payload: payload}
+
+	reqCtx, cancel := context.NewRequest(setupContext(), context.WithTimeout(10*time.Second))
+	defer cancel()
+
+	res, err := channel.QueryChaincodeData(reqCtx, "mycc", []fab.ProposalProcessor{&peer}, nil)
+	if err != nil || len(res) != 1 {
+		t.Fatalf("Test QueryChaincodeData failed: %s", err)
+	}
+	assert.Equal(t, "mycc", res[0].Name)
+	assert.Equal(t, "v1", res[0].Version)
+	assert.Equal(t, []byte("policy"), res[0].Policy)
+}
+
 func TestQueryTransaction(t *testing.T) {
 	channel, _ := setupTestLedger()
 	peer := mocks.MockPeer{MockName: "Peer1", MockURL: "http://peer1.com", MockRoles: []string{}, MockCert: nil, Status: 200}
@@ -177,6 +177,11 @@ func (t *Transactor) CreateTransaction(request fab.TransactionRequest) (*fab.Tra
 	return txn.New(request)
 }
 
+// Orderers returns the orderers of the channel
+func (t *Transactor) Orderers() []fab.Orderer {
+	return t.orderers
+}
+
 // SendTransaction send a transaction to the chain’s orderer service (one or more orderer endpoints) for consensus and committing to the ledger.
 func (t *Transactor) SendTransaction(tx *fab.Transaction) (*fab.TransactionResponse, error) {
 	ctx, ok := contextImpl.RequestClientContext(t.reqCtx)
@@ -13,6 +13,7 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/spf13/cast"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/keepalive"
 )
 
@@ -77,6 +78,24 @@ func WithConnectTimeout(value time.Duration) options.Opt {
 	}
 }
 
+// WithMaxConnections sets the maximum number of GRPC connections cached by the connector
+func WithMaxConnections(value int) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(maxConnectionsSetter); ok {
+			setter.SetMaxConnections(value)
+		}
+	}
+}
+
+// WithPeerDialOptions sets GRPC dial options of the connections to the given peer (or orderer) URL
+func WithPeerDialOptions(url string, value ...grpc.DialOption) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(peerDialOptionsSetter); ok {
+			setter.SetPeerDialOptions(url, value)
+		}
+	}
+}
+
 // WithInsecure indicates to fall back to an insecure connection if the
 // connection URL does not specify a protocol
 func WithInsecure() options.Opt {
@@ -141,10 +160,18 @@ type insecureSetter interface {
 	SetInsecure(value bool)
 }
 
+type maxConnectionsSetter interface {
+	SetMaxConnections(value int)
+}
+
 type connectTimeoutSetter interface {
 	SetConnectTimeout(value time.Duration)
 }
 
+type peerDialOptionsSetter interface {
+	SetPeerDialOptions(url string, value []grpc.DialOption)
+}
+
 // OptsFromPeerConfig returns a set of connection options from the given peer config
 func OptsFromPeerConfig(peerCfg *fab.PeerConfig) []options.Opt {
 
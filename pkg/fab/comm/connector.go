@@ -11,9 +11,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/keepalive"
 )
 
 const (
@@ -24,7 +27,9 @@ const (
 // It provides a GRPC compatible Context Dialer interface via the "DialContext" method.
 // Connections provided by this component are monitored for becoming idle or entering shutdown state.
 // When connections has its usages closed for longer than "idleTime", the connection is closed and removed
-// from the connection cache. Callers must release connections by calling the "ReleaseConn" method.
+// from the connection cache. Unused connections that are in transient failure state are removed as well.
+// The number of cached connections may be limited (see WithMaxConnections): when all connections are in use,
+// "DialContext" waits until a connection is released or the context is done. Callers must release connections by calling the "ReleaseConn" method.
 // The Close method will flush all remaining open connections. This component should be considered
 // unusable after calling Close.
 //
@@ -42,6 +47,12 @@ type CachingConnector struct {
 	waitgroup     sync.WaitGroup
 	janitorDone   chan bool
 	janitorClosed chan bool
+	// released is closed (and replaced) when a connection is released or removed
+	released chan struct{}
+
+	maxConns        int
+	keepAliveParams keepalive.ClientParameters
+	peerDialOpts    map[string][]grpc.DialOption
 }
 
 type cachedConn struct {
@@ -53,14 +64,16 @@ type cachedConn struct {
 
 // NewCachingConnector creates a GRPC connection cache. The cache is governed by
 // sweepTime and idleTime.
-func NewCachingConnector(sweepTime time.Duration, idleTime time.Duration) *CachingConnector {
+func NewCachingConnector(sweepTime time.Duration, idleTime time.Duration, opts ...options.Opt) *CachingConnector {
 	cc := CachingConnector{
 		conns:         map[string]*cachedConn{},
 		index:         map[*grpc.ClientConn]*cachedConn{},
 		janitorDone:   make(chan bool, 1),
 		janitorClosed: make(chan bool, 1),
+		released:      make(chan struct{}),
 		sweepTime:     sweepTime,
 		idleTime:      idleTime,
+		peerDialOpts:  map[string][]grpc.DialOption{},
 	}
 
 	// cc.janitorClosed determines if a goroutine needs to be spun up.
@@ -69,9 +82,37 @@ func NewCachingConnector(sweepTime time.Duration, idleTime time.Duration) *Cachi
 	// the go chan with a bootstrap value so that cachingConnector spins up the
 	// goroutine on first usage.
 	cc.janitorClosed <- true
+
+	options.Apply(&cc, opts)
 	return &cc
 }
 
+// SetMaxConnections sets the maximum number of cached connections (0 means no limit)
+func (cc *CachingConnector) SetMaxConnections(value int) {
+	logger.Debugf("MaxConnections: %d", value)
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+	cc.maxConns = value
+}
+
+// SetKeepAliveParams sets the GRPC keep-alive parameters of connections for which
+// keep-alive parameters are not specified
+func (cc *CachingConnector) SetKeepAliveParams(value keepalive.ClientParameters) {
+	logger.Debugf("KeepAliveParams: %#v", value)
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+	cc.keepAliveParams = value
+}
+
+// SetPeerDialOptions sets GRPC dial options of the connections to the given peer (or orderer) URL.
+// These options take precedence over the default options and over the options of the dialer.
+func (cc *CachingConnector) SetPeerDialOptions(url string, opts []grpc.DialOption) {
+	logger.Debugf("PeerDialOptions: %s", url)
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+	cc.peerDialOpts[endpoint.ToAddress(url)] = opts
+}
+
 // Close cleans up cached connections.
 func (cc *CachingConnector) Close() {
 	cc.lock.RLock()
@@ -107,6 +148,7 @@ func (cc *CachingConnector) Close() {
 	close(cc.janitorClosed)
 	close(cc.janitorDone)
 	cc.janitorDone = nil
+	cc.notifyReleased()
 }
 
 // DialContext is a wrapper for grpc.DialContext where connections are cached.
@@ -129,6 +171,7 @@ func (cc *CachingConnector) DialContext(ctx context.Context, target string, opts
 	if err := cc.openConn(ctx, c); err != nil {
 		cc.lock.Lock()
 		setClosed(c)
+		cc.notifyReleased()
 		cc.lock.Unlock()
 		return nil, errors.Errorf("dialing connection timed out [%s]", target)
 	}
@@ -162,6 +205,7 @@ func (cc *CachingConnector) ReleaseConn(conn *grpc.ClientConn) {
 	logger.Debugf("ReleaseConn [%s]", cconn.target)
 
 	setClosed(cconn)
+	cc.notifyReleased()
 
 	cc.ensureJanitorStarted()
 }
@@ -169,6 +213,11 @@ func (cc *CachingConnector) ReleaseConn(conn *grpc.ClientConn) {
 func (cc *CachingConnector) loadConn(target string) (*cachedConn, bool) {
 	c, ok := cc.conns[target]
 	if ok {
+		if c.open == 0 && c.conn.GetState() == connectivity.TransientFailure {
+			logger.Debugf("discarding cached connection in transient failure state [%s: %p]", target, c)
+			cc.removeConn(c)
+			return nil, false
+		}
 		if c.conn.GetState() != connectivity.Shutdown {
 			logger.Debugf("using cached connection [%s: %p]", target, c)
 			// Set connection open as soon as it is loaded to prevent the janitor
@@ -191,6 +240,28 @@ func (cc *CachingConnector) createConn(ctx context.Context, target string, opts
 		return cconn, nil
 	}
 
+	for cc.maxConns > 0 && len(cc.conns) >= cc.maxConns && !cc.evictIdleConn() {
+		if err := cc.waitForRelease(ctx); err != nil {
+			return nil, errors.Wrapf(err, "maximum number of connections [%d] reached", cc.maxConns)
+		}
+		if cc.janitorDone == nil {
+			return nil, errors.New("caching connector is closed")
+		}
+		// The connection may have been created by another caller while waiting
+		if cconn, ok := cc.loadConn(target); ok {
+			return cconn, nil
+		}
+	}
+
+	if cc.keepAliveParams.Time > 0 || cc.keepAliveParams.Timeout > 0 {
+		// Keep-alive parameters in opts take precedence
+		opts = append([]grpc.DialOption{grpc.WithKeepaliveParams(cc.keepAliveParams)}, opts...)
+	}
+	if peerOpts, ok := cc.peerDialOpts[endpoint.ToAddress(target)]; ok {
+		// Per-peer options are applied last so that they override all other options
+		opts = append(opts, peerOpts...)
+	}
+
 	logger.Debugf("creating connection [%s]", target)
 	conn, err := grpc.DialContext(ctx, target, opts...)
 	if err != nil {
@@ -210,6 +281,44 @@ func (cc *CachingConnector) createConn(ctx context.Context, target string, opts
 	return cconn, nil
 }
 
+// evictIdleConn removes the unused connection that was released first. It returns false if all connections are in use.
+func (cc *CachingConnector) evictIdleConn() bool {
+	var idle *cachedConn
+	for _, c := range cc.conns {
+		if c.open == 0 && (idle == nil || c.lastClose.Before(idle.lastClose)) {
+			idle = c
+		}
+	}
+	if idle == nil {
+		return false
+	}
+	logger.Debugf("evicting idle connection [%s]", idle.target)
+	cc.removeConn(idle)
+	return true
+}
+
+// waitForRelease waits until a connection is released or removed, or the context is done.
+// The lock must be held by the caller; it is released while waiting.
+func (cc *CachingConnector) waitForRelease(ctx context.Context) error {
+	released := cc.released
+	cc.lock.Unlock()
+	defer cc.lock.Lock()
+
+	logger.Debugf("waiting for a connection to be released")
+	select {
+	case <-released:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// notifyReleased wakes up the callers that wait for a connection to be released
+func (cc *CachingConnector) notifyReleased() {
+	close(cc.released)
+	cc.released = make(chan struct{})
+}
+
 func (cc *CachingConnector) openConn(ctx context.Context, c *cachedConn) error {
 
 	err := waitConn(ctx, c.conn, connectivity.Ready)
@@ -245,6 +354,7 @@ func (cc *CachingConnector) shutdownConn(cconn *cachedConn) {
 	logger.Debugf("connection was shutdown [%s]", cconn.target)
 	delete(cc.conns, cconn.target)
 	delete(cc.index, cconn.conn)
+	cc.notifyReleased()
 
 	cc.ensureJanitorStarted()
 }
@@ -258,6 +368,9 @@ func (cc *CachingConnector) sweepAndRemove() {
 		} else if conn.GetState() == connectivity.Shutdown {
 			logger.Debugf("connection already closed [%s]", cachedConn.target)
 			cc.removeConn(cachedConn)
+		} else if cachedConn.open == 0 && conn.GetState() == connectivity.TransientFailure {
+			logger.Debugf("connection janitor closing connection in transient failure state [%s]", cachedConn.target)
+			cc.removeConn(cachedConn)
 		}
 	}
 }
@@ -266,6 +379,7 @@ func (cc *CachingConnector) removeConn(c *cachedConn) {
 	logger.Debugf("removing connection [%s]", c.target)
 	delete(cc.index, c.conn)
 	delete(cc.conns, c.target)
+	cc.notifyReleased()
 	if err := c.conn.Close(); err != nil {
 		logger.Debugf("unable to close connection [%s]", err)
 	}
@@ -131,6 +131,51 @@ func TestConnectorHappyFlushNumber2(t *testing.T) {
 	assert.Equal(t, connectivity.Shutdown, conn3.GetState(), "connection should be shutdown")
 }
 
+func TestConnectorMaxConnections(t *testing.T) {
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime, WithMaxConnections(1))
+	defer connector.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn1, err := connector.DialContext(ctx, endorserAddr[0], grpc.WithInsecure())
+	cancel()
+	require.NoError(t, err, "DialContext should have succeeded")
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	_, err = connector.DialContext(ctx, endorserAddr[1], grpc.WithInsecure())
+	cancel()
+	assert.Error(t, err, "expecting error when all connections are in use until the context is done")
+
+	// DialContext waits until a connection is released
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		connector.ReleaseConn(conn1)
+	}()
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	conn2, err := connector.DialContext(ctx, endorserAddr[1], grpc.WithInsecure())
+	cancel()
+	require.NoError(t, err, "DialContext should have succeeded after idle connection was evicted")
+	assert.Equal(t, connectivity.Shutdown, conn1.GetState(), "evicted connection should be shutdown")
+	assert.NotEqual(t, connectivity.Shutdown, conn2.GetState(), "connection should not be shutdown")
+}
+
+func TestConnectorPeerDialOptions(t *testing.T) {
+	// Transport security is only provided for the first endorser
+	connector := NewCachingConnector(normalSweepTime, normalIdleTime, WithPeerDialOptions("grpc://"+endorserAddr[0], grpc.WithInsecure()))
+	defer connector.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), normalTimeout)
+	conn1, err := connector.DialContext(ctx, endorserAddr[0])
+	cancel()
+	require.NoError(t, err, "DialContext should have succeeded with per-peer dial options")
+	connector.ReleaseConn(conn1)
+
+	ctx, cancel = context.WithTimeout(context.Background(), normalTimeout)
+	_, err = connector.DialContext(ctx, endorserAddr[1])
+	cancel()
+	assert.Error(t, err, "expecting error when transport security is not set")
+}
+
 func TestConnectorShouldJanitorRestart(t *testing.T) {
 	connector := NewCachingConnector(shortSweepTime, shortIdleTime)
 	defer connector.Close()
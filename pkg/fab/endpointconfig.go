@@ -829,6 +829,7 @@ func (c *EndpointConfig) loadAllPeerConfigs(networkConfig *fab.NetworkConfig, en
 			URL:         peerConfig.URL,
 			GRPCOptions: peerConfig.GRPCOptions,
 			TLSCACert:   tlsCert,
+			Properties:  peerConfig.Properties,
 		})
 	}
 	return nil
@@ -1507,6 +1508,7 @@ func (c *EndpointConfig) getMappedPeer(host string) *fab.PeerConfig {
 		URL:         peerConfig.URL,
 		TLSCACert:   peerConfig.TLSCACert,
 		GRPCOptions: make(map[string]interface{}),
+		Properties:  peerConfig.Properties,
 	}
 
 	for key, val := range peerConfig.GRPCOptions {
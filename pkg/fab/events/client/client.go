@@ -99,7 +99,8 @@ func (c *Client) Connect() error {
 	if c.maxConnAttempts == 1 {
 		return c.connect()
 	}
-	return c.connectWithRetry(c.maxConnAttempts, c.timeBetweenConnAttempts)
+	_, err := c.connectWithRetry(c.maxConnAttempts, fixedDelay(c.timeBetweenConnAttempts))
+	return err
 }
 
 // CloseIfIdle closes the connection to the event server only if there are no outstanding
@@ -278,12 +279,11 @@ func (c *Client) t(handlerImp handler, errch chan error) error {
 	return nil
 }
 
-func (c *Client) connectWithRetry(maxAttempts uint, timeBetweenAttempts time.Duration) error {
+// connectWithRetry attempts to connect until it succeeds or maxAttempts is reached, waiting
+// for the duration returned by timeBetweenAttempts after each failed attempt. The number of attempts is returned.
+func (c *Client) connectWithRetry(maxAttempts uint, timeBetweenAttempts func(attempt uint) time.Duration) (uint, error) {
 	if c.Stopped() {
-		return errors.New("event client is closed")
-	}
-	if timeBetweenAttempts < time.Second {
-		timeBetweenAttempts = time.Second
+		return 0, errors.New("event client is closed")
 	}
 
 	var attempts uint
@@ -294,14 +294,37 @@ func (c *Client) connectWithRetry(maxAttempts uint, timeBetweenAttempts time.Dur
 			logger.Warnf("... connection attempt failed: %s", err)
 			if maxAttempts > 0 && attempts >= maxAttempts {
 				logger.Warn("maximum connect attempts exceeded")
-				return errors.New("maximum connect attempts exceeded")
+				return attempts, errors.New("maximum connect attempts exceeded")
 			}
-			time.Sleep(timeBetweenAttempts)
+			time.Sleep(timeBetweenAttempts(attempts))
 		} else {
 			logger.Debug("... connect succeeded.")
-			return nil
+			return attempts, nil
+		}
+	}
+}
+
+// fixedDelay returns the given time between connect attempts, but at least one second
+func fixedDelay(timeBetweenAttempts time.Duration) func(uint) time.Duration {
+	if timeBetweenAttempts < time.Second {
+		timeBetweenAttempts = time.Second
+	}
+	return func(uint) time.Duration {
+		return timeBetweenAttempts
+	}
+}
+
+// reconnectDelay returns the time to wait after the given failed reconnect attempt. If a backoff factor
+// is set then the initial reconnect delay is multiplied by the factor for each failed attempt, up to the maximum delay.
+func (c *Client) reconnectDelay(attempt uint) time.Duration {
+	delay := float64(c.reconnInitialDelay)
+	for i := uint(0); i < attempt; i++ {
+		delay *= c.reconnBackoffFactor
+		if c.maxReconnDelay > 0 && delay >= float64(c.maxReconnDelay) {
+			return c.maxReconnDelay
 		}
 	}
+	return time.Duration(delay)
 }
 
 // RegisterBlockEvent registers for block events. If the client is not authorized to receive
@@ -410,11 +433,35 @@ func (c *Client) reconnect() {
 		}
 	}
 
-	if err := c.connectWithRetry(c.maxReconnAttempts, c.timeBetweenConnAttempts); err != nil {
+	lastBlockNum := c.Dispatcher().LastBlockNum()
+
+	timeBetweenAttempts := fixedDelay(c.timeBetweenConnAttempts)
+	if c.reconnBackoffFactor > 0 && c.reconnInitialDelay > 0 {
+		timeBetweenAttempts = c.reconnectDelay
+	}
+
+	attempts, err := c.connectWithRetry(c.maxReconnAttempts, timeBetweenAttempts)
+	if err != nil {
 		logger.Warnf("Could not reconnect event client: %s. Closing.", err)
 		c.Close()
-	} else {
-		logger.Infof("Event client has reconnected")
+		return
+	}
+
+	logger.Infof("Event client has reconnected")
+	c.notifyReconnectEventChan(&fab.ReconnectEvent{LastBlockNum: lastBlockNum, Attempts: attempts})
+}
+
+func (c *Client) notifyReconnectEventChan(event *fab.ReconnectEvent) {
+	c.RLock()
+	defer c.RUnlock()
+	if c.reconnEventCh == nil {
+		return
+	}
+	select {
+	case c.reconnEventCh <- event:
+		logger.Debugln("Sent reconnect event to subscriber.")
+	default:
+		logger.Warnln("Reconnect event channel is full. Dropping reconnect event.")
 	}
 }
 
@@ -424,6 +471,10 @@ func (c *Client) closeConnectEventChan() {
 	if c.connEventCh != nil {
 		close(c.connEventCh)
 	}
+	if c.reconnEventCh != nil {
+		close(c.reconnEventCh)
+		c.reconnEventCh = nil
+	}
 }
 
 func (c *Client) notifyConnectEventChan(event *dispatcher.ConnectionEvent) {
@@ -11,6 +11,7 @@ package client
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"sync"
 	"testing"
 	"time"
@@ -1117,6 +1118,63 @@ func testReconnect(t *testing.T, reconnect bool, maxReconnectAttempts uint, expe
 	}
 }
 
+func TestReconnectWithBackoff(t *testing.T) {
+	cp := mockconn.NewProviderFactory()
+
+	connectch := make(chan *dispatcher.ConnectionEvent)
+	reconnectch := make(chan *fab.ReconnectEvent, 1)
+
+	ledger := servicemocks.NewMockLedger(servicemocks.BlockEventFactory, sourceURL)
+
+	eventClient, _, err := newClientWithMockConnAndOpts(
+		fabmocks.NewMockContext(
+			mspmocks.NewMockSigningIdentity("user1", "Org1MSP"),
+		),
+		fabmocks.NewMockChannelCfg("mychannel"),
+		clientmocks.NewDiscoveryService(peer1, peer2),
+		cp.FlakeyProvider(
+			mockconn.NewConnectResults(
+				mockconn.NewConnectResult(mockconn.FirstAttempt, clientmocks.ConnFactory),
+				mockconn.NewConnectResult(mockconn.FourthAttempt, clientmocks.ConnFactory),
+			),
+			mockconn.WithLedger(ledger),
+		),
+		clientProvider,
+		[]options.Opt{
+			esdispatcher.WithEventConsumerTimeout(3 * time.Second),
+			WithMaxConnectAttempts(1),
+			WithReconnectDelay(10*time.Millisecond, 30*time.Millisecond, 2),
+			WithMaxReconnectAttempts(3),
+			WithConnectionEvent(connectch),
+			WithReconnectEvent(reconnectch),
+			WithResponseTimeout(2 * time.Second),
+		},
+	)
+	if err != nil {
+		t.Fatalf("error creating channel event client: %s", err)
+	}
+	if err := eventClient.Connect(); err != nil {
+		t.Fatalf("error connecting channel event client: %s", err)
+	}
+	defer eventClient.Close()
+
+	assert.Equal(t, 20*time.Millisecond, eventClient.reconnectDelay(1))
+	assert.Equal(t, 30*time.Millisecond, eventClient.reconnectDelay(2), "expected delay to be capped")
+
+	outcomech := make(chan mockconn.Outcome)
+	go listenConnection(connectch, outcomech)
+
+	cp.Connection().ProduceEvent(newDisconnectedEvent())
+
+	select {
+	case event := <-reconnectch:
+		assert.Equal(t, uint(3), event.Attempts)
+		assert.Equal(t, uint64(math.MaxUint64), event.LastBlockNum, "expected no block to be received")
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for reconnect event")
+	}
+}
+
 // testReconnectRegistration tests the scenario when an events client is registered to receive events and the connection to the
 // event service is lost. After the connection is re-established, events should once again be received without the caller having to
 // re-register for those events.
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client/dispatcher"
 )
 
@@ -23,6 +24,12 @@ type params struct {
 	maxReconnAttempts       uint
 	permitBlockEvents       bool
 	reconn                  bool
+
+	// reconnBackoffFactor multiplies the time between reconnect attempts after each failed
+	// attempt, up to maxReconnDelay. If zero then timeBetweenConnAttempts is used.
+	reconnBackoffFactor float64
+	maxReconnDelay      time.Duration
+	reconnEventCh       chan *fab.ReconnectEvent
 }
 
 func defaultParams() *params {
@@ -97,6 +104,27 @@ func WithConnectionEvent(value chan *dispatcher.ConnectionEvent) options.Opt {
 	}
 }
 
+// WithReconnectDelay sets the delay before the first attempt to reconnect after a connection has been lost.
+// After each failed attempt the delay is multiplied by factor, up to the given maximum. If initial is not
+// greater than zero then the time between connection attempts is used instead.
+func WithReconnectDelay(initial, max time.Duration, factor float64) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(reconnectDelaySetter); ok {
+			setter.SetReconnectDelay(initial, max, factor)
+		}
+	}
+}
+
+// WithReconnectEvent sets the channel that is to receive a reconnect event each time the client has reconnected to
+// the event server. The event contains the number of the last block received, allowing the receiver to detect gaps.
+func WithReconnectEvent(value chan *fab.ReconnectEvent) options.Opt {
+	return func(p options.Params) {
+		if setter, ok := p.(reconnectEventChSetter); ok {
+			setter.SetReconnectEventCh(value)
+		}
+	}
+}
+
 // WithTimeBetweenConnectAttempts sets the time between connection attempts.
 func WithTimeBetweenConnectAttempts(value time.Duration) options.Opt {
 	return func(p options.Params) {
@@ -144,6 +172,18 @@ func (p *params) SetTimeBetweenConnectAttempts(value time.Duration) {
 	p.timeBetweenConnAttempts = value
 }
 
+func (p *params) SetReconnectDelay(initial, max time.Duration, factor float64) {
+	logger.Debugf("ReconnectDelay: initial %s, max %s, factor %f", initial, max, factor)
+	p.reconnInitialDelay = initial
+	p.maxReconnDelay = max
+	p.reconnBackoffFactor = factor
+}
+
+func (p *params) SetReconnectEventCh(value chan *fab.ReconnectEvent) {
+	logger.Debugf("ReconnectEventCh: %#v", value)
+	p.reconnEventCh = value
+}
+
 func (p *params) SetConnectEventCh(value chan *dispatcher.ConnectionEvent) {
 	logger.Debugf("ConnectEventCh: %#v", value)
 	p.connEventCh = value
@@ -175,6 +215,14 @@ type reconnectInitialDelaySetter interface {
 	SetReconnectInitialDelay(value time.Duration)
 }
 
+type reconnectDelaySetter interface {
+	SetReconnectDelay(initial, max time.Duration, factor float64)
+}
+
+type reconnectEventChSetter interface {
+	SetReconnectEventCh(value chan *fab.ReconnectEvent)
+}
+
 type connectEventChSetter interface {
 	SetConnectEventCh(value chan *dispatcher.ConnectionEvent)
 }
@@ -96,3 +96,13 @@ func (mgr *MockCAClient) RemoveAffiliation(request *api.AffiliationRequest) (*ap
 func (mgr *MockCAClient) GetCAInfo() (*api.GetCAInfoResponse, error) {
 	return nil, errors.New("not implemented")
 }
+
+// GetCRL returns the CRL of the CA
+func (mgr *MockCAClient) GetCRL(caname string) ([]byte, error) {
+	return nil, errors.New("not implemented")
+}
+
+// GetIdemixCredential gets an Idemix credential for a user
+func (mgr *MockCAClient) GetIdemixCredential(enrollmentID, caname string, provider api.IdemixProvider) (*api.IdemixCredentialResponse, error) {
+	return nil, errors.New("not implemented")
+}
@@ -334,7 +334,7 @@ func (b *MockConfigGroupBuilder) buildConsensusType() *ab.ConsensusType {
 
 func (b *MockConfigGroupBuilder) buildBatchTimeout() *ab.BatchTimeout {
 	return &ab.BatchTimeout{
-		Timeout: "123",
+		Timeout: "2s",
 	}
 }
 
@@ -84,6 +84,7 @@ func New(config fab.EndpointConfig, opts ...Option) (*Orderer, error) {
 		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
 			return verifier.VerifyPeerCertificate(rawCerts, verifiedChains)
 		}
+		comm.PinTLSCert(tlsConfig, orderer.url, config)
 
 		grpcOpts = append(grpcOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	} else {
@@ -23,6 +23,33 @@ import (
 
 // block retrieves the block at the given position
 func retrieveBlock(reqCtx reqContext.Context, orderers []fab.Orderer, channel string, pos *ab.SeekPosition, opts options) (*common.Block, error) {
+	payload, err := createSeekPayload(reqCtx, channel, pos, pos)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := retry.NewInvoker(retry.New(opts.retry)).Invoke(
+		func() (interface{}, error) {
+			return txn.SendPayload(reqCtx, payload, orderers)
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*common.Block), err
+}
+
+// retrieveBlocks retrieves the blocks from start to stop over a single deliver stream
+func retrieveBlocks(reqCtx reqContext.Context, orderer fab.Orderer, channel string, start, stop *ab.SeekPosition) (chan *common.Block, chan error, error) {
+	payload, err := createSeekPayload(reqCtx, channel, start, stop)
+	if err != nil {
+		return nil, nil, err
+	}
+	return txn.SendDeliverPayload(reqCtx, payload, orderer)
+}
+
+// createSeekPayload creates a DELIVER_SEEK_INFO payload for the blocks from start to stop
+func createSeekPayload(reqCtx reqContext.Context, channel string, start, stop *ab.SeekPosition) (*common.Payload, error) {
 	ctx, ok := contextImpl.RequestClientContext(reqCtx)
 	if !ok {
 		return nil, errors.New("failed get client context from reqContext for signPayload")
@@ -68,8 +95,8 @@ func retrieveBlock(reqCtx reqContext.Context, orderers []fab.Orderer, channel st
 	}
 
 	seekInfo := &ab.SeekInfo{
-		Start:    pos,
-		Stop:     pos,
+		Start:    start,
+		Stop:     stop,
 		Behavior: ab.SeekInfo_BLOCK_UNTIL_READY,
 	}
 
@@ -78,20 +105,10 @@ func retrieveBlock(reqCtx reqContext.Context, orderers []fab.Orderer, channel st
 		return nil, errors.Wrap(err, "marshal seek info failed")
 	}
 
-	payload := common.Payload{
+	return &common.Payload{
 		Header: seekHeader,
 		Data:   seekInfoBytes,
-	}
-
-	resp, err := retry.NewInvoker(retry.New(opts.retry)).Invoke(
-		func() (interface{}, error) {
-			return txn.SendPayload(reqCtx, &payload, orderers)
-		},
-	)
-	if err != nil {
-		return nil, err
-	}
-	return resp.(*common.Block), err
+	}, nil
 }
 
 // newNewestSeekPosition returns a SeekPosition that requests the newest block
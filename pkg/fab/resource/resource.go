@@ -137,6 +137,16 @@ func GenesisBlockFromOrderer(reqCtx reqContext.Context, channelName string, orde
 	return retrieveBlock(reqCtx, []fab.Orderer{orderer}, channelName, newSpecificSeekPosition(0), optionsValue)
 }
 
+// BlocksFromOrderer requests the blocks of the given channel in the range [startNum, endNum] from the
+// orderer over a single deliver stream. The blocks are received on the returned block channel,
+// which is closed when the deliver stream ends.
+func BlocksFromOrderer(reqCtx reqContext.Context, channelName string, orderer fab.Orderer, startNum, endNum uint64) (chan *common.Block, chan error, error) {
+	if startNum > endNum {
+		return nil, nil, errors.Errorf("start block number %d is greater than end block number %d", startNum, endNum)
+	}
+	return retrieveBlocks(reqCtx, orderer, channelName, newSpecificSeekPosition(startNum), newSpecificSeekPosition(endNum))
+}
+
 // LastConfigFromOrderer fetches the current configuration block for the specified channel
 // from the given orderer
 func LastConfigFromOrderer(reqCtx reqContext.Context, channelName string, orderer fab.Orderer, opts ...Opt) (*common.Block, error) {
@@ -0,0 +1,168 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+// TransactionBuilder composes a chaincode invocation transaction step by step, for callers that sign
+// proposals and transactions outside of the SDK, e.g. offline signers or multi-party signing:
+// the proposal created by Proposal is signed and sent to the endorsers by the caller, the endorsements
+// are added with CollectEndorsement, and Build assembles the transaction envelope for the orderer.
+type TransactionBuilder struct {
+	channelID     string
+	chaincodeID   string
+	args          [][]byte
+	transientData map[string][]byte
+
+	proposal      *pb.Proposal
+	proposalBytes []byte
+	responses     []*fab.TransactionProposalResponse
+}
+
+// NewTransactionBuilder returns a builder of a transaction on the given channel
+func NewTransactionBuilder(channelID string) *TransactionBuilder {
+	return &TransactionBuilder{channelID: channelID}
+}
+
+// SetChaincodeID sets the ID of the chaincode that is invoked
+func (b *TransactionBuilder) SetChaincodeID(chaincodeID string) *TransactionBuilder {
+	b.chaincodeID = chaincodeID
+	return b
+}
+
+// SetArgs sets the arguments of the chaincode invocation; the first argument is the function name
+func (b *TransactionBuilder) SetArgs(args [][]byte) *TransactionBuilder {
+	b.args = args
+	return b
+}
+
+// SetTransientData sets the transient data of the proposal, which is not included in the transaction
+func (b *TransactionBuilder) SetTransientData(data map[string][]byte) *TransactionBuilder {
+	b.transientData = data
+	return b
+}
+
+// Proposal creates the unsigned proposal of the transaction. The header holds the creator of the transaction,
+// which must be the identity that signs the proposal and the transaction envelope.
+func (b *TransactionBuilder) Proposal(txh fab.TransactionHeader) (*fab.TransactionProposal, error) {
+	if txh.ChannelID() != b.channelID {
+		return nil, errors.Errorf("transaction header is for channel [%s], not [%s]", txh.ChannelID(), b.channelID)
+	}
+	if len(b.args) == 0 {
+		return nil, errors.New("args are required")
+	}
+
+	return CreateChaincodeInvokeProposal(txh, fab.ChaincodeInvokeRequest{
+		ChaincodeID:  b.chaincodeID,
+		Fcn:          string(b.args[0]),
+		Args:         b.args[1:],
+		TransientMap: b.transientData,
+	})
+}
+
+// CollectEndorsement adds the endorsement of the given signed proposal to the transaction.
+// All endorsements must be for the same proposal and return the same proposal response payload.
+func (b *TransactionBuilder) CollectEndorsement(proposal *pb.SignedProposal, response *pb.ProposalResponse) error {
+	if proposal == nil || response == nil || response.Response == nil {
+		return errors.New("proposal and proposal response are required")
+	}
+
+	tpr := &fab.TransactionProposalResponse{ProposalResponse: response}
+	if err := validateProposalResponses([]*fab.TransactionProposalResponse{tpr}); err != nil {
+		return err
+	}
+
+	if b.proposal == nil {
+		p := &pb.Proposal{}
+		if err := proto.Unmarshal(proposal.ProposalBytes, p); err != nil {
+			return errors.Wrap(err, "unmarshal proposal failed")
+		}
+		if err := b.validateProposal(p); err != nil {
+			return err
+		}
+		b.proposal = p
+		b.proposalBytes = proposal.ProposalBytes
+	} else {
+		if !bytes.Equal(proposal.ProposalBytes, b.proposalBytes) {
+			return errors.New("endorsement is for a different proposal")
+		}
+		if !bytes.Equal(response.Payload, b.responses[0].ProposalResponse.Payload) {
+			return errors.New("proposal response payload does not match the payload of the collected endorsements")
+		}
+	}
+
+	b.responses = append(b.responses, tpr)
+	return nil
+}
+
+// validateProposal checks that the proposal is for the channel and chaincode of the builder
+func (b *TransactionBuilder) validateProposal(proposal *pb.Proposal) error {
+	hdr, err := protos_utils.GetHeader(proposal.Header)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal proposal header failed")
+	}
+	channelHeader, err := protos_utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	if err != nil {
+		return errors.Wrap(err, "unmarshal channel header failed")
+	}
+	if channelHeader.ChannelId != b.channelID {
+		return errors.Errorf("proposal is for channel [%s], not [%s]", channelHeader.ChannelId, b.channelID)
+	}
+
+	if b.chaincodeID == "" {
+		return nil
+	}
+	hdrExt, err := protos_utils.GetChaincodeHeaderExtension(hdr)
+	if err != nil {
+		return err
+	}
+	if hdrExt.ChaincodeId.GetName() != b.chaincodeID {
+		return errors.Errorf("proposal is for chaincode [%s], not [%s]", hdrExt.ChaincodeId.GetName(), b.chaincodeID)
+	}
+	return nil
+}
+
+// Build assembles the transaction envelope from the collected endorsements. The envelope is not signed:
+// its Signature must be set to the signature of the Payload bytes by the creator of the proposal before
+// the envelope is sent to the orderer.
+func (b *TransactionBuilder) Build() (*common.Envelope, error) {
+	if b.proposal == nil {
+		return nil, errors.New("at least one endorsement is necessary")
+	}
+
+	tx, err := New(fab.TransactionRequest{
+		Proposal:          &fab.TransactionProposal{Proposal: b.proposal},
+		ProposalResponses: b.responses,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hdr, err := protos_utils.GetHeader(b.proposal.Header)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshal proposal header failed")
+	}
+	txBytes, err := protos_utils.GetBytesTransaction(tx.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	payloadBytes, err := proto.Marshal(&common.Payload{Header: hdr, Data: txBytes})
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling of payload failed")
+	}
+	return &common.Envelope{Payload: payloadBytes}, nil
+}
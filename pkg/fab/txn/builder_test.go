@@ -0,0 +1,79 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txn
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/peer"
+	protos_utils "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/utils"
+)
+
+func TestTransactionBuilder(t *testing.T) {
+	txh := &TransactionHeader{id: "1234", creator: []byte("creator"), nonce: []byte("nonce"), channelID: "testchannel"}
+
+	builder := NewTransactionBuilder("testchannel").
+		SetChaincodeID("testCC").
+		SetArgs([][]byte{[]byte("invoke"), []byte("a")}).
+		SetTransientData(map[string][]byte{"secret": []byte("value")})
+
+	_, err := builder.Build()
+	assert.Error(t, err, "expected error without endorsements")
+
+	_, err = builder.Proposal(&TransactionHeader{channelID: "otherchannel"})
+	assert.Error(t, err)
+
+	proposal, err := builder.Proposal(txh)
+	require.NoError(t, err)
+	proposalBytes, err := proto.Marshal(proposal.Proposal)
+	require.NoError(t, err)
+	signedProposal := &pb.SignedProposal{ProposalBytes: proposalBytes, Signature: []byte("signature")}
+
+	newResponse := func(endorser string, status int32, payload string) *pb.ProposalResponse {
+		return &pb.ProposalResponse{
+			Response:    &pb.Response{Status: status},
+			Payload:     []byte(payload),
+			Endorsement: &pb.Endorsement{Endorser: []byte(endorser), Signature: []byte(endorser + " signature")},
+		}
+	}
+
+	require.NoError(t, builder.CollectEndorsement(signedProposal, newResponse("peer1", 200, "payload")))
+	require.NoError(t, builder.CollectEndorsement(signedProposal, newResponse("peer2", 200, "payload")))
+	assert.Error(t, builder.CollectEndorsement(signedProposal, newResponse("peer3", 500, "payload")), "expected error for failed endorsement")
+	assert.Error(t, builder.CollectEndorsement(signedProposal, newResponse("peer3", 200, "other payload")), "expected error for mismatched payload")
+	assert.Error(t, builder.CollectEndorsement(&pb.SignedProposal{ProposalBytes: []byte("other")}, newResponse("peer3", 200, "payload")), "expected error for other proposal")
+
+	envelope, err := builder.Build()
+	require.NoError(t, err)
+	assert.Nil(t, envelope.Signature)
+
+	payload := &common.Payload{}
+	require.NoError(t, proto.Unmarshal(envelope.Payload, payload))
+	channelHeader, err := protos_utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	require.NoError(t, err)
+	assert.Equal(t, "testchannel", channelHeader.ChannelId)
+	assert.Equal(t, "1234", channelHeader.TxId)
+
+	tx := &pb.Transaction{}
+	require.NoError(t, proto.Unmarshal(payload.Data, tx))
+	require.Len(t, tx.Actions, 1)
+	actionPayload := &pb.ChaincodeActionPayload{}
+	require.NoError(t, proto.Unmarshal(tx.Actions[0].Payload, actionPayload))
+	assert.Equal(t, []byte("payload"), actionPayload.Action.ProposalResponsePayload)
+	require.Len(t, actionPayload.Action.Endorsements, 2)
+	assert.Equal(t, []byte("peer1"), actionPayload.Action.Endorsements[0].Endorser)
+	assert.Equal(t, []byte("peer2"), actionPayload.Action.Endorsements[1].Endorser)
+
+	// The proposal must be for the channel and chaincode of the builder
+	assert.Error(t, NewTransactionBuilder("otherchannel").CollectEndorsement(signedProposal, newResponse("peer1", 200, "payload")))
+	assert.Error(t, NewTransactionBuilder("testchannel").SetChaincodeID("otherCC").CollectEndorsement(signedProposal, newResponse("peer1", 200, "payload")))
+}
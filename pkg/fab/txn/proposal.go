@@ -84,18 +84,10 @@ func SendProposal(reqCtx reqContext.Context, proposal *fab.TransactionProposal,
 		return nil, errors.New("proposal is required")
 	}
 
-	if len(targets) < 1 {
-		return nil, errors.New("targets is required")
+	if err := validateTargets(targets); err != nil {
+		return nil, err
 	}
 
-	for _, p := range targets {
-		if p == nil {
-			return nil, errors.New("target is nil")
-		}
-	}
-
-	targets = getTargetsWithoutDuplicates(targets)
-
 	ctx, ok := context.RequestClientContext(reqCtx)
 	if !ok {
 		return nil, errors.New("failed get client context from reqContext for signProposal")
@@ -105,6 +97,39 @@ func SendProposal(reqCtx reqContext.Context, proposal *fab.TransactionProposal,
 		return nil, errors.WithMessage(err, "sign proposal failed")
 	}
 
+	return sendSignedProposal(reqCtx, signedProposal, targets)
+}
+
+// SendSignedProposal sends a proposal that has been signed outside of the SDK to ProposalProcessor.
+func SendSignedProposal(reqCtx reqContext.Context, signedProposal *pb.SignedProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+
+	if signedProposal == nil {
+		return nil, errors.New("signed proposal is required")
+	}
+
+	if err := validateTargets(targets); err != nil {
+		return nil, err
+	}
+
+	return sendSignedProposal(reqCtx, signedProposal, targets)
+}
+
+func validateTargets(targets []fab.ProposalProcessor) error {
+	if len(targets) < 1 {
+		return errors.New("targets is required")
+	}
+
+	for _, p := range targets {
+		if p == nil {
+			return errors.New("target is nil")
+		}
+	}
+	return nil
+}
+
+func sendSignedProposal(reqCtx reqContext.Context, signedProposal *pb.SignedProposal, targets []fab.ProposalProcessor) ([]*fab.TransactionProposalResponse, error) {
+	targets = getTargetsWithoutDuplicates(targets)
+
 	request := fab.ProcessProposalRequest{SignedProposal: signedProposal}
 
 	var responseMtx sync.Mutex
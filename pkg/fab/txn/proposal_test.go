@@ -291,6 +291,32 @@ func TestProposalResponseError(t *testing.T) {
 	assert.Equal(t, testError, errs[0])
 }
 
+func TestSendSignedProposal(t *testing.T) {
+	user := mspmocks.NewMockSigningIdentity("test", "1234")
+	ctx := mocks.NewMockContext(user)
+
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	proc := mock_context.NewMockProposalProcessor(mockCtrl)
+
+	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(10*time.Second))
+	defer cancel()
+
+	_, err := SendSignedProposal(reqCtx, nil, []fab.ProposalProcessor{proc})
+	assert.EqualError(t, err, "signed proposal is required")
+
+	signedProposal := &pb.SignedProposal{ProposalBytes: []byte("proposal"), Signature: []byte("offline signature")}
+	_, err = SendSignedProposal(reqCtx, signedProposal, nil)
+	assert.EqualError(t, err, "targets is required")
+
+	// The signed proposal is sent as it is, without being signed by the identity of the context
+	tpr := fab.TransactionProposalResponse{Endorser: "example.com", Status: 200}
+	proc.EXPECT().ProcessTransactionProposal(gomock.Any(), fab.ProcessProposalRequest{SignedProposal: signedProposal}).Return(&tpr, nil)
+	responses, err := SendSignedProposal(reqCtx, signedProposal, []fab.ProposalProcessor{proc})
+	require.NoError(t, err)
+	assert.Equal(t, []*fab.TransactionProposalResponse{&tpr}, responses)
+}
+
 func setupMassiveTestPeers(numberOfPeers int) []fab.ProposalProcessor {
 	peers := []fab.ProposalProcessor{}
 
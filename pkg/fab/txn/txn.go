@@ -157,12 +157,12 @@ func BroadcastPayload(reqCtx reqContext.Context, payload *common.Payload, ordere
 		return nil, err
 	}
 
-	return broadcastEnvelope(reqCtx, envelope, orderers)
+	return BroadcastEnvelope(reqCtx, envelope, orderers)
 }
 
-// broadcastEnvelope will send the given envelope to some orderer, picking random endpoints
+// BroadcastEnvelope will send the given signed envelope to some orderer, picking random endpoints
 // until all are exhausted
-func broadcastEnvelope(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
+func BroadcastEnvelope(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderers []fab.Orderer) (*fab.TransactionResponse, error) {
 	// Check if orderers are defined
 	if len(orderers) == 0 {
 		return nil, errors.New("orderers not set")
@@ -229,6 +229,23 @@ func SendPayload(reqCtx reqContext.Context, payload *common.Payload, orderers []
 	return nil, errResp
 }
 
+// SendDeliverPayload signs the given seek payload and sends it to the orderer.
+// The delivered blocks are received on the returned block channel, which is closed when the deliver stream ends.
+func SendDeliverPayload(reqCtx reqContext.Context, payload *common.Payload, orderer fab.Orderer) (chan *common.Block, chan error, error) {
+	ctx, ok := context.RequestClientContext(reqCtx)
+	if !ok {
+		return nil, nil, errors.New("failed get client context from reqContext for signPayload")
+	}
+	envelope, err := signPayload(ctx, payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	logger.Debugf("Requesting blocks from orderer :%s\n", orderer.URL())
+	blocks, errs := orderer.SendDeliver(reqCtx, envelope)
+	return blocks, errs, nil
+}
+
 // sendEnvelope sends the given envelope to each orderer and returns a block response
 func sendEnvelope(reqCtx reqContext.Context, envelope *fab.SignedEnvelope, orderer fab.Orderer) (*common.Block, error) {
 
@@ -151,7 +151,7 @@ func TestBroadcastEnvelope(t *testing.T) {
 	reqCtx, cancel := context.NewRequest(ctx, context.WithTimeout(10*time.Second))
 	defer cancel()
 
-	res, err := broadcastEnvelope(reqCtx, sigEnvelope, orderers)
+	res, err := BroadcastEnvelope(reqCtx, sigEnvelope, orderers)
 
 	if err != nil {
 		t.Fatalf("Test Broadcast Envelope Failed, cause %s %+v", err, res)
@@ -181,7 +181,7 @@ func TestBroadcastEnvelope(t *testing.T) {
 	}
 	// It should always succeed even though one of them has failed
 	for i := 0; i < broadcastCount; i++ {
-		if res, err1 := broadcastEnvelope(reqCtx, sigEnvelope, orderers); err1 != nil {
+		if res, err1 := BroadcastEnvelope(reqCtx, sigEnvelope, orderers); err1 != nil {
 			t.Fatalf("Test Broadcast Envelope Failed, cause %s %+v", err1, res)
 		}
 	}
@@ -196,13 +196,13 @@ func checkBroadcastCount(broadcastCount int, orderer1 *mocks.MockOrderer, ordere
 		orderer2.EnqueueSendBroadcastError(errors.New("Service Unavailable"))
 	}
 	for i := 0; i < broadcastCount; i++ {
-		_, err1 := broadcastEnvelope(reqCtx, sigEnvelope, orderers)
+		_, err1 := BroadcastEnvelope(reqCtx, sigEnvelope, orderers)
 		if !strings.Contains(err1.Error(), "Service Unavailable") {
 			t.Fatal("Test Broadcast failed but didn't return the correct reason(should contain 'Service Unavailable')")
 		}
 	}
 	emptyOrderers := []fab.Orderer{}
-	_, err := broadcastEnvelope(reqCtx, sigEnvelope, emptyOrderers)
+	_, err := BroadcastEnvelope(reqCtx, sigEnvelope, emptyOrderers)
 	if err == nil || err.Error() != "orderers not set" {
 		t.Fatal("orderers not set validation on broadcast envelope is not working as expected")
 	}
@@ -0,0 +1,161 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
+)
+
+// ConfigError describes an invalid value of the SDK config
+type ConfigError struct {
+	// Key is the config key of the invalid value (e.g. peers.peer0.org1.example.com.url)
+	Key    string
+	Value  interface{}
+	Reason string
+}
+
+// Error returns the error message
+func (e ConfigError) Error() string {
+	return fmt.Sprintf("invalid config [%s] value [%v]: %s", e.Key, e.Value, e.Reason)
+}
+
+// ValidateConfig checks that the required settings of the SDK config are present and well formed,
+// so that malformed config is reported when the SDK is created rather than when it is first used.
+//  Parameters:
+//  cryptoSuiteConfig, endpointConfig and identityConfig are the configs to validate (nil configs are skipped)
+//
+//  Returns:
+//  the invalid config values (empty if the config is valid)
+func ValidateConfig(cryptoSuiteConfig core.CryptoSuiteConfig, endpointConfig fab.EndpointConfig, identityConfig msp.IdentityConfig) []ConfigError {
+	var errs []ConfigError
+	if cryptoSuiteConfig != nil {
+		errs = append(errs, validateCryptoSuiteConfig(cryptoSuiteConfig)...)
+	}
+	if endpointConfig != nil {
+		errs = append(errs, validateEndpointConfig(endpointConfig)...)
+		if identityConfig != nil {
+			errs = append(errs, validateIdentityConfig(identityConfig, endpointConfig)...)
+		}
+	}
+
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Key < errs[j].Key })
+	return errs
+}
+
+func validateCryptoSuiteConfig(config core.CryptoSuiteConfig) []ConfigError {
+	var errs []ConfigError
+
+	provider := config.SecurityProvider()
+	if provider != "sw" && provider != "pkcs11" {
+		errs = append(errs, ConfigError{Key: "client.BCCSP.security.default.provider", Value: provider, Reason: "security provider must be SW or PKCS11"})
+	}
+	if algorithm := config.SecurityAlgorithm(); algorithm != "SHA2" && algorithm != "SHA3" {
+		errs = append(errs, ConfigError{Key: "client.BCCSP.security.hashAlgorithm", Value: algorithm, Reason: "hash algorithm must be SHA2 or SHA3"})
+	}
+	if level := config.SecurityLevel(); level != 256 && level != 384 {
+		errs = append(errs, ConfigError{Key: "client.BCCSP.security.level", Value: level, Reason: "security level must be 256 or 384"})
+	}
+
+	if provider == "pkcs11" {
+		if config.SecurityProviderLibPath() == "" {
+			errs = append(errs, ConfigError{Key: "client.BCCSP.security.library", Value: "", Reason: "no PKCS11 library was found"})
+		}
+		if config.SecurityProviderLabel() == "" {
+			errs = append(errs, ConfigError{Key: "client.BCCSP.security.label", Value: "", Reason: "a PKCS11 token label is required"})
+		}
+	}
+	return errs
+}
+
+func validateEndpointConfig(config fab.EndpointConfig) []ConfigError {
+	var errs []ConfigError
+	networkConfig := config.NetworkConfig()
+	if networkConfig == nil {
+		return nil
+	}
+
+	for name, peerConfig := range networkConfig.Peers {
+		if err := validateAddress("peers."+name+".url", peerConfig.URL); err != nil {
+			errs = append(errs, *err)
+		}
+	}
+	for name, ordererConfig := range networkConfig.Orderers {
+		if err := validateAddress("orderers."+name+".url", ordererConfig.URL); err != nil {
+			errs = append(errs, *err)
+		}
+	}
+	for name, orgConfig := range networkConfig.Organizations {
+		if orgConfig.MSPID == "" {
+			errs = append(errs, ConfigError{Key: "organizations." + name + ".mspid", Value: "", Reason: "MSP ID is required"})
+		}
+	}
+	return errs
+}
+
+func validateIdentityConfig(config msp.IdentityConfig, endpointConfig fab.EndpointConfig) []ConfigError {
+	var errs []ConfigError
+	networkConfig := endpointConfig.NetworkConfig()
+	if networkConfig == nil {
+		return nil
+	}
+
+	if client := config.Client(); client != nil && client.Organization != "" {
+		orgName := strings.ToLower(client.Organization)
+		orgConfig, ok := networkConfig.Organizations[orgName]
+		switch {
+		case !ok:
+			errs = append(errs, ConfigError{Key: "client.organization", Value: client.Organization, Reason: "organization is not defined in organizations"})
+		case orgConfig.CryptoPath == "" && len(orgConfig.Users) == 0:
+			errs = append(errs, ConfigError{Key: "organizations." + orgName + ".cryptoPath", Value: "", Reason: "either a crypto path (MSP directory) or embedded users are required"})
+		case orgConfig.CryptoPath != "" && !filepath.IsAbs(orgConfig.CryptoPath) && endpointConfig.CryptoConfigPath() == "":
+			errs = append(errs, ConfigError{Key: "client.cryptoconfig.path", Value: "", Reason: "crypto config path is required for the relative crypto path of the client organization"})
+		}
+	}
+
+	for orgName, orgConfig := range networkConfig.Organizations {
+		cas := orgConfig.CertificateAuthorities
+		if len(cas) == 0 {
+			continue
+		}
+		caConfig, ok := config.CAConfig(orgName)
+		if !ok {
+			continue
+		}
+		if u, err := url.Parse(caConfig.URL); err != nil || u.Host == "" {
+			errs = append(errs, ConfigError{Key: "certificateAuthorities." + cas[0] + ".url", Value: caConfig.URL, Reason: "CA URL must be an absolute URL (e.g. https://ca.org1.example.com:7054)"})
+		}
+	}
+	return errs
+}
+
+// validateAddress checks that the URL of a peer or orderer is of the form [grpc[s]://]host:port
+func validateAddress(key, address string) *ConfigError {
+	if _, _, err := net.SplitHostPort(endpoint.ToAddress(address)); err != nil {
+		return &ConfigError{Key: key, Value: address, Reason: "URL must be of the form [grpc[s]://]host:port"}
+	}
+	return nil
+}
+
+// configErrors returns the aggregate of the given config errors (nil if there are none)
+func configErrors(errs []ConfigError) error {
+	var merr multi.Errors
+	for _, err := range errs {
+		merr = append(merr, err)
+	}
+	return merr.ToError()
+}
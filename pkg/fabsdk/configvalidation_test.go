@@ -0,0 +1,129 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	configImpl "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validConfig = `
+client:
+  organization: org1
+  cryptoconfig:
+    path: /tmp/crypto-config
+  BCCSP:
+    security:
+      default:
+        provider: "SW"
+      hashAlgorithm: "SHA2"
+      level: 256
+organizations:
+  org1:
+    mspid: Org1MSP
+    cryptoPath: peerOrganizations/org1.example.com/users/{username}@org1.example.com/msp
+    peers:
+      - peer0.org1.example.com
+    certificateAuthorities:
+      - ca.org1.example.com
+orderers:
+  orderer.example.com:
+    url: grpc://orderer.example.com:7050
+peers:
+  peer0.org1.example.com:
+    url: grpc://peer0.org1.example.com:7051
+certificateAuthorities:
+  ca.org1.example.com:
+    url: http://ca.org1.example.com:7054
+    tlsCACerts:
+      pem:
+        - ca cert
+`
+
+const invalidConfig = `
+client:
+  organization: org1
+  BCCSP:
+    security:
+      default:
+        provider: "XX"
+      hashAlgorithm: "MD5"
+      level: 128
+organizations:
+  org1:
+    peers:
+      - peer0.org1.example.com
+    certificateAuthorities:
+      - ca.org1.example.com
+orderers:
+  orderer.example.com:
+    url: grpc://orderer.example.com
+peers:
+  peer0.org1.example.com:
+    url: grpc://peer0.org1.example.com
+certificateAuthorities:
+  ca.org1.example.com:
+    url: ca.org1.example.com:7054
+    tlsCACerts:
+      pem:
+        - ca cert
+`
+
+func TestValidateConfig(t *testing.T) {
+	errs := validateRawConfig(t, validConfig)
+	assert.Empty(t, errs)
+
+	errs = validateRawConfig(t, invalidConfig)
+	var keys []string
+	for _, err := range errs {
+		keys = append(keys, err.Key)
+	}
+	assert.Equal(t, []string{
+		"certificateAuthorities.ca.org1.example.com.url",
+		"client.BCCSP.security.default.provider",
+		"client.BCCSP.security.hashAlgorithm",
+		"client.BCCSP.security.level",
+		"orderers.orderer.example.com.url",
+		"organizations.org1.cryptoPath",
+		"organizations.org1.mspid",
+		"peers.peer0.org1.example.com.url",
+	}, keys)
+
+	assert.Equal(t, "grpc://peer0.org1.example.com", errs[7].Value)
+	assert.EqualError(t, errs[7], "invalid config [peers.peer0.org1.example.com.url] value [grpc://peer0.org1.example.com]: URL must be of the form [grpc[s]://]host:port")
+
+	err := configErrors(errs)
+	merr, ok := err.(multi.Errors)
+	require.True(t, ok, "expecting multi error")
+	assert.Len(t, merr, len(errs))
+	assert.Nil(t, configErrors(nil))
+}
+
+func TestNewWithInvalidConfig(t *testing.T) {
+	_, err := New(configImpl.FromRaw([]byte(invalidConfig), "yaml"))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid configuration")
+}
+
+func validateRawConfig(t *testing.T, raw string) []ConfigError {
+	backends, err := configImpl.FromRaw([]byte(raw), "yaml")()
+	require.NoError(t, err)
+
+	endpointConfig, err := fabImpl.ConfigFromBackend(backends...)
+	require.NoError(t, err)
+	identityConfig, err := mspImpl.ConfigFromBackend(backends...)
+	require.NoError(t, err)
+
+	return ValidateConfig(cryptosuite.ConfigFromBackend(backends...), endpointConfig, identityConfig)
+}
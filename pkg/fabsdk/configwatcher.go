@@ -0,0 +1,429 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/pkg/errors"
+)
+
+const configChangeEventBufferSize = 10
+
+// ConfigChangeEvent is published when the config watched by the SDK changes: either the config file
+// watched with WithConfigWatcher, or the config of a config backend that implements core.ConfigChangeNotifier
+type ConfigChangeEvent struct {
+	// Path is the path of the config file, or the location of the config of the config backend
+	Path string
+	// AddedPeers and AddedOrderers are the names of the peers and orderers that were added to the config
+	AddedPeers    []string
+	AddedOrderers []string
+	// UpdatedTLSCerts are the names of the peers and orderers whose TLS CA certificate was updated
+	UpdatedTLSCerts []string
+	// Applied indicates whether the SDK now uses the new config. The new config is not applied if it is
+	// invalid or if it changes the network config in a way that requires a restart (e.g. a peer is removed
+	// or an organization is modified), in which case Err describes why.
+	Applied bool
+	Err     error
+}
+
+type configWatcherOpts struct {
+	path     string
+	interval time.Duration
+}
+
+// WithConfigWatcher enables reloading of the endpoint config (peers, orderers, channels and TLS certificates)
+// from the given YAML config file without restarting the SDK. The file is checked for changes at the given interval.
+// Changes that only add peers, orderers or channels or that update TLS certificates are applied live, and apply to
+// connections that are established afterwards. Each change is published to the listeners registered with
+// RegisterConfigChangeListener. Identity and crypto suite config are not reloaded.
+// The endpoint config is reloaded by running the config provider of the SDK again, so that environment variable
+// overrides (EnvConfigProvider) and merged config files (FromFiles) are applied to the new config as well. The config
+// provider must therefore read the watched file, e.g. FromFile or FromFiles (providers that read from a byte array or a
+// reader do not see the changes of the file).
+// The config of a config backend that implements core.ConfigChangeNotifier (e.g. the etcd config backend)
+// is reloaded in the same way without this option.
+func WithConfigWatcher(path string, interval time.Duration) Option {
+	return func(opts *options) error {
+		if path == "" {
+			return errors.New("config file path is required")
+		}
+		if interval <= 0 {
+			return errors.New("config watcher interval must be greater than zero")
+		}
+		opts.configWatcher = &configWatcherOpts{path: path, interval: interval}
+		return nil
+	}
+}
+
+// configChangeNotifier returns the first of the config backends that notifies of changes of its config, if any
+func configChangeNotifier(backends []core.ConfigBackend) core.ConfigChangeNotifier {
+	for _, backend := range backends {
+		if notifier, ok := backend.(core.ConfigChangeNotifier); ok {
+			return notifier
+		}
+	}
+	return nil
+}
+
+// RegisterConfigChangeListener returns a channel on which the changes of the config watched by the SDK
+// are published (see ConfigChangeEvent). The channel is closed when the SDK is closed. Events are dropped
+// if the listener does not keep up with them.
+func (sdk *FabricSDK) RegisterConfigChangeListener() (<-chan *ConfigChangeEvent, error) {
+	if sdk.configWatcher == nil {
+		return nil, errors.New("config watcher is not enabled")
+	}
+	return sdk.configWatcher.register(), nil
+}
+
+// endpointConfigLoader loads the current endpoint config, e.g. by running the config provider of the SDK
+type endpointConfigLoader func() (fab.EndpointConfig, error)
+
+// configWatcher swaps the endpoint config when the watched config changes
+type configWatcher struct {
+	path           string
+	changes        func(done <-chan struct{}) <-chan []byte
+	load           endpointConfigLoader
+	endpointConfig *reloadableEndpointConfig
+	lock           sync.Mutex
+	listeners      []chan *ConfigChangeEvent
+	done           chan struct{}
+	stopped        chan struct{}
+	stopOnce       sync.Once
+}
+
+func newConfigWatcher(opts *configWatcherOpts, endpointConfig fab.EndpointConfig, load endpointConfigLoader) (*configWatcher, error) {
+	content, err := ioutil.ReadFile(opts.path)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading watched config file failed")
+	}
+	hash := sha256.Sum256(content)
+
+	return newWatcher(opts.path, configFileChanges(opts.path, opts.interval, hash[:]), endpointConfig, load), nil
+}
+
+// newNotifierConfigWatcher returns a config watcher of the config of a config backend that notifies of its changes
+func newNotifierConfigWatcher(notifier core.ConfigChangeNotifier, endpointConfig fab.EndpointConfig, load endpointConfigLoader) *configWatcher {
+	return newWatcher(notifier.ConfigLocation(), notifier.ConfigChanges, endpointConfig, load)
+}
+
+func newWatcher(path string, changes func(done <-chan struct{}) <-chan []byte, endpointConfig fab.EndpointConfig, load endpointConfigLoader) *configWatcher {
+	return &configWatcher{
+		path:           path,
+		changes:        changes,
+		load:           load,
+		endpointConfig: &reloadableEndpointConfig{config: endpointConfig},
+		done:           make(chan struct{}),
+		stopped:        make(chan struct{}),
+	}
+}
+
+func (w *configWatcher) start() {
+	changes := w.changes(w.done)
+	go func() {
+		defer close(w.stopped)
+		for range changes {
+			logger.Infof("Config [%s] changed", w.path)
+			w.publish(w.reload())
+		}
+	}()
+}
+
+func (w *configWatcher) stop() {
+	w.stopOnce.Do(func() {
+		close(w.done)
+		<-w.stopped
+
+		w.lock.Lock()
+		defer w.lock.Unlock()
+		for _, listener := range w.listeners {
+			close(listener)
+		}
+		w.listeners = nil
+	})
+}
+
+func (w *configWatcher) register() <-chan *ConfigChangeEvent {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	listener := make(chan *ConfigChangeEvent, configChangeEventBufferSize)
+	w.listeners = append(w.listeners, listener)
+	return listener
+}
+
+// configFileChanges returns the changes of a config file, which is checked at the given interval
+func configFileChanges(path string, interval time.Duration, hash []byte) func(done <-chan struct{}) <-chan []byte {
+	return func(done <-chan struct{}) <-chan []byte {
+		changes := make(chan []byte)
+		go func() {
+			defer close(changes)
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ticker.C:
+				case <-done:
+					return
+				}
+
+				content, err := ioutil.ReadFile(path)
+				if err != nil {
+					logger.Warnf("Reading watched config file [%s] failed: %s", path, err)
+					continue
+				}
+				newHash := sha256.Sum256(content)
+				if bytes.Equal(newHash[:], hash) {
+					continue
+				}
+				hash = newHash[:]
+
+				select {
+				case changes <- content:
+				case <-done:
+					return
+				}
+			}
+		}()
+		return changes
+	}
+}
+
+// reload loads the changed config in the same way as the config that is in use, and applies it if possible
+func (w *configWatcher) reload() *ConfigChangeEvent {
+	newConfig, err := w.load()
+	if err != nil {
+		return &ConfigChangeEvent{Path: w.path, Err: errors.WithMessage(err, "loading endpoint config failed")}
+	}
+	if err := configErrors(ValidateConfig(nil, newConfig, nil)); err != nil {
+		return &ConfigChangeEvent{Path: w.path, Err: errors.WithMessage(err, "invalid configuration")}
+	}
+
+	event, err := compareNetworkConfigs(w.endpointConfig.NetworkConfig(), newConfig.NetworkConfig())
+	event.Path = w.path
+	if err != nil {
+		logger.Warnf("Changes of config [%s] are not applied: %s", w.path, err)
+		event.Err = errors.WithMessage(err, "config changes require a restart of the SDK")
+		return event
+	}
+
+	w.endpointConfig.set(newConfig)
+	event.Applied = true
+	return event
+}
+
+func (w *configWatcher) publish(event *ConfigChangeEvent) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	for _, listener := range w.listeners {
+		select {
+		case listener <- event:
+		default:
+			logger.Warn("Config change listener is not keeping up; dropping config change event")
+		}
+	}
+}
+
+// compareNetworkConfigs returns the changes from oldConfig to newConfig, and an error if newConfig
+// changes oldConfig in other ways than adding peers, orderers and channels or updating TLS certificates
+func compareNetworkConfigs(oldConfig, newConfig *fab.NetworkConfig) (*ConfigChangeEvent, error) {
+	event := &ConfigChangeEvent{}
+	var errs multi.Errors
+
+	for name, peer := range newConfig.Peers {
+		oldPeer, ok := oldConfig.Peers[name]
+		switch {
+		case !ok:
+			event.AddedPeers = append(event.AddedPeers, name)
+		case oldPeer.URL != peer.URL || !reflect.DeepEqual(oldPeer.GRPCOptions, peer.GRPCOptions) || !reflect.DeepEqual(oldPeer.Properties, peer.Properties):
+			errs = append(errs, errors.Errorf("peer [%s] was modified", name))
+		case !equalCerts(oldPeer.TLSCACert, peer.TLSCACert):
+			event.UpdatedTLSCerts = append(event.UpdatedTLSCerts, name)
+		}
+	}
+	for name := range oldConfig.Peers {
+		if _, ok := newConfig.Peers[name]; !ok {
+			errs = append(errs, errors.Errorf("peer [%s] was removed", name))
+		}
+	}
+
+	for name, orderer := range newConfig.Orderers {
+		oldOrderer, ok := oldConfig.Orderers[name]
+		switch {
+		case !ok:
+			event.AddedOrderers = append(event.AddedOrderers, name)
+		case oldOrderer.URL != orderer.URL || !reflect.DeepEqual(oldOrderer.GRPCOptions, orderer.GRPCOptions):
+			errs = append(errs, errors.Errorf("orderer [%s] was modified", name))
+		case !equalCerts(oldOrderer.TLSCACert, orderer.TLSCACert):
+			event.UpdatedTLSCerts = append(event.UpdatedTLSCerts, name)
+		}
+	}
+	for name := range oldConfig.Orderers {
+		if _, ok := newConfig.Orderers[name]; !ok {
+			errs = append(errs, errors.Errorf("orderer [%s] was removed", name))
+		}
+	}
+
+	// Identity managers are created for the organizations when the SDK is initialized,
+	// so organizations may only be extended with new peers
+	for name, org := range newConfig.Organizations {
+		oldOrg, ok := oldConfig.Organizations[name]
+		if !ok {
+			errs = append(errs, errors.Errorf("organization [%s] was added", name))
+			continue
+		}
+		peersRemoved := !containsAll(org.Peers, oldOrg.Peers)
+		org.Peers, oldOrg.Peers = nil, nil
+		if peersRemoved || !reflect.DeepEqual(oldOrg, org) {
+			errs = append(errs, errors.Errorf("organization [%s] was modified", name))
+		}
+	}
+	for name := range oldConfig.Organizations {
+		if _, ok := newConfig.Organizations[name]; !ok {
+			errs = append(errs, errors.Errorf("organization [%s] was removed", name))
+		}
+	}
+
+	for name, channel := range oldConfig.Channels {
+		newChannel, ok := newConfig.Channels[name]
+		if !ok {
+			errs = append(errs, errors.Errorf("channel [%s] was removed", name))
+			continue
+		}
+		if !reflect.DeepEqual(channel.Orderers, newChannel.Orderers) || !reflect.DeepEqual(channel.Policies, newChannel.Policies) {
+			errs = append(errs, errors.Errorf("channel [%s] was modified", name))
+			continue
+		}
+		for peer, peerChannelConfig := range channel.Peers {
+			if newPeerChannelConfig, ok := newChannel.Peers[peer]; !ok || !reflect.DeepEqual(peerChannelConfig, newPeerChannelConfig) {
+				errs = append(errs, errors.Errorf("peer [%s] of channel [%s] was modified", peer, name))
+			}
+		}
+	}
+
+	sort.Strings(event.AddedPeers)
+	sort.Strings(event.AddedOrderers)
+	sort.Strings(event.UpdatedTLSCerts)
+	return event, errs.ToError()
+}
+
+// containsAll returns true if all elements of b are in a
+func containsAll(a, b []string) bool {
+	elements := make(map[string]bool)
+	for _, e := range a {
+		elements[e] = true
+	}
+	for _, e := range b {
+		if !elements[e] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalCerts(a, b *x509.Certificate) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(b)
+}
+
+// reloadableEndpointConfig is an endpoint config that may be replaced while it is in use
+type reloadableEndpointConfig struct {
+	lock   sync.RWMutex
+	config fab.EndpointConfig
+}
+
+func (c *reloadableEndpointConfig) get() fab.EndpointConfig {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.config
+}
+
+func (c *reloadableEndpointConfig) set(config fab.EndpointConfig) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.config = config
+}
+
+// Timeout reads timeouts for the given timeout type
+func (c *reloadableEndpointConfig) Timeout(timeoutType fab.TimeoutType) time.Duration {
+	return c.get().Timeout(timeoutType)
+}
+
+// OrderersConfig returns a list of defined orderers
+func (c *reloadableEndpointConfig) OrderersConfig() []fab.OrdererConfig {
+	return c.get().OrderersConfig()
+}
+
+// OrdererConfig returns the requested orderer
+func (c *reloadableEndpointConfig) OrdererConfig(nameOrURL string) (*fab.OrdererConfig, bool) {
+	return c.get().OrdererConfig(nameOrURL)
+}
+
+// PeersConfig returns a list of peers for the given org
+func (c *reloadableEndpointConfig) PeersConfig(org string) ([]fab.PeerConfig, bool) {
+	return c.get().PeersConfig(org)
+}
+
+// PeerConfig returns the requested peer
+func (c *reloadableEndpointConfig) PeerConfig(nameOrURL string) (*fab.PeerConfig, bool) {
+	return c.get().PeerConfig(nameOrURL)
+}
+
+// NetworkConfig returns the network configuration defined in the config file
+func (c *reloadableEndpointConfig) NetworkConfig() *fab.NetworkConfig {
+	return c.get().NetworkConfig()
+}
+
+// NetworkPeers returns the network peers configuration, all the peers from all the orgs in config
+func (c *reloadableEndpointConfig) NetworkPeers() []fab.NetworkPeer {
+	return c.get().NetworkPeers()
+}
+
+// ChannelConfig returns the channel configuration
+func (c *reloadableEndpointConfig) ChannelConfig(name string) *fab.ChannelEndpointConfig {
+	return c.get().ChannelConfig(name)
+}
+
+// ChannelPeers returns the channel peers configuration
+func (c *reloadableEndpointConfig) ChannelPeers(name string) []fab.ChannelPeer {
+	return c.get().ChannelPeers(name)
+}
+
+// ChannelOrderers returns a list of channel orderers
+func (c *reloadableEndpointConfig) ChannelOrderers(name string) []fab.OrdererConfig {
+	return c.get().ChannelOrderers(name)
+}
+
+// TLSCACertPool returns the configured cert pool
+func (c *reloadableEndpointConfig) TLSCACertPool() fab.CertPool {
+	return c.get().TLSCACertPool()
+}
+
+// TLSClientCerts loads the client's certs for mutual TLS
+func (c *reloadableEndpointConfig) TLSClientCerts() []tls.Certificate {
+	return c.get().TLSClientCerts()
+}
+
+// CryptoConfigPath is the path to the crypto config
+func (c *reloadableEndpointConfig) CryptoConfigPath() string {
+	return c.get().CryptoConfigPath()
+}
@@ -0,0 +1,179 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fabsdk
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	configImpl "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const addedPeerConfig = `
+  peer1.org1.example.com:
+    url: grpc://peer1.org1.example.com:7051
+`
+
+func TestConfigWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configwatcher")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "config.yaml")
+	require.NoError(t, ioutil.WriteFile(path, []byte(validConfig), 0600))
+
+	// The config is reloaded with the env overrides of the config provider of the SDK
+	urlKey := configImpl.EnvKey("FABRIC_SDK", "peers.peer1.org1.example.com.url")
+	require.NoError(t, os.Setenv(urlKey, "grpc://peer1.override.example.com:7051"))
+	defer os.Unsetenv(urlKey)
+
+	configProvider := configImpl.EnvConfigProvider(configImpl.FromFile(path))
+	load := (&FabricSDK{}).endpointConfigLoader(configProvider)
+	endpointConfig, err := load()
+	require.NoError(t, err)
+
+	w, err := newConfigWatcher(&configWatcherOpts{path: path, interval: 10 * time.Millisecond}, endpointConfig, load)
+	require.NoError(t, err)
+	events := w.register()
+	w.start()
+
+	// A new peer is applied live
+	newConfig := strings.Replace(validConfig, "\npeers:\n", "\npeers:"+addedPeerConfig, 1)
+	require.NoError(t, ioutil.WriteFile(path, []byte(newConfig), 0600))
+
+	event := receiveConfigChangeEvent(t, events)
+	assert.NoError(t, event.Err)
+	assert.True(t, event.Applied)
+	assert.Equal(t, path, event.Path)
+	assert.Equal(t, []string{"peer1.org1.example.com"}, event.AddedPeers)
+	peerConfig, ok := w.endpointConfig.PeerConfig("peer1.org1.example.com")
+	require.True(t, ok, "expecting added peer to be found")
+	assert.Equal(t, "grpc://peer1.override.example.com:7051", peerConfig.URL, "expecting env override to be applied")
+
+	// Removing the peer requires a restart
+	require.NoError(t, ioutil.WriteFile(path, []byte(validConfig), 0600))
+
+	event = receiveConfigChangeEvent(t, events)
+	assert.False(t, event.Applied)
+	assert.Error(t, event.Err)
+	assert.Contains(t, event.Err.Error(), "peer [peer1.org1.example.com] was removed")
+	_, ok = w.endpointConfig.PeerConfig("peer1.org1.example.com")
+	assert.True(t, ok, "expecting config with added peer to still be in use")
+
+	w.stop()
+	_, ok = <-events
+	assert.False(t, ok, "expecting listener to be closed")
+}
+
+func TestNotifierConfigWatcher(t *testing.T) {
+	backends, err := configImpl.FromRaw([]byte(validConfig), "yaml")()
+	require.NoError(t, err)
+	endpointConfig, err := fabImpl.ConfigFromBackend(backends...)
+	require.NoError(t, err)
+
+	assert.Nil(t, configChangeNotifier(backends))
+	notifier := &mockConfigChangeNotifier{ConfigBackend: backends[0], changes: make(chan []byte)}
+	require.Equal(t, notifier, configChangeNotifier([]core.ConfigBackend{notifier}))
+
+	// The config provider reads the current config of the notifier
+	newConfig := []byte(strings.Replace(validConfig, "\npeers:\n", "\npeers:"+addedPeerConfig, 1))
+	configProvider := func() ([]core.ConfigBackend, error) {
+		return configImpl.FromRaw(newConfig, "yaml")()
+	}
+
+	w := newNotifierConfigWatcher(notifier, endpointConfig, (&FabricSDK{}).endpointConfigLoader(configProvider))
+	events := w.register()
+	w.start()
+
+	notifier.changes <- newConfig
+
+	event := receiveConfigChangeEvent(t, events)
+	assert.NoError(t, event.Err)
+	assert.True(t, event.Applied)
+	assert.Equal(t, "mock://config", event.Path)
+	assert.Equal(t, []string{"peer1.org1.example.com"}, event.AddedPeers)
+
+	w.stop()
+	_, ok := <-events
+	assert.False(t, ok, "expecting listener to be closed")
+}
+
+func TestCompareNetworkConfigs(t *testing.T) {
+	oldConfig := loadNetworkConfig(t, validConfig)
+
+	event, err := compareNetworkConfigs(oldConfig, loadNetworkConfig(t, validConfig))
+	require.NoError(t, err)
+	assert.Empty(t, event.AddedPeers)
+
+	modified := strings.Replace(validConfig, "mspid: Org1MSP", "mspid: OtherMSP", 1)
+	_, err = compareNetworkConfigs(oldConfig, loadNetworkConfig(t, modified))
+	assert.EqualError(t, err, "organization [org1] was modified")
+
+	modified = strings.Replace(validConfig, "peer0.org1.example.com:7051", "peer0.org1.example.com:8051", 1)
+	_, err = compareNetworkConfigs(oldConfig, loadNetworkConfig(t, modified))
+	assert.EqualError(t, err, "peer [peer0.org1.example.com] was modified")
+}
+
+func TestWithConfigWatcher(t *testing.T) {
+	opts := options{}
+	assert.Error(t, WithConfigWatcher("", time.Second)(&opts))
+	assert.Error(t, WithConfigWatcher("config.yaml", 0)(&opts))
+	require.NoError(t, WithConfigWatcher("config.yaml", time.Second)(&opts))
+	assert.Equal(t, &configWatcherOpts{path: "config.yaml", interval: time.Second}, opts.configWatcher)
+}
+
+type mockConfigChangeNotifier struct {
+	core.ConfigBackend
+	changes chan []byte
+}
+
+func (n *mockConfigChangeNotifier) ConfigLocation() string {
+	return "mock://config"
+}
+
+func (n *mockConfigChangeNotifier) ConfigChanges(done <-chan struct{}) <-chan []byte {
+	changes := make(chan []byte)
+	go func() {
+		defer close(changes)
+		for {
+			select {
+			case content := <-n.changes:
+				changes <- content
+			case <-done:
+				return
+			}
+		}
+	}()
+	return changes
+}
+
+func receiveConfigChangeEvent(t *testing.T, events <-chan *ConfigChangeEvent) *ConfigChangeEvent {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for config change event")
+		return nil
+	}
+}
+
+func loadNetworkConfig(t *testing.T, raw string) *fab.NetworkConfig {
+	backends, err := configImpl.FromRaw([]byte(raw), "yaml")()
+	require.NoError(t, err)
+	endpointConfig, err := fabImpl.ConfigFromBackend(backends...)
+	require.NoError(t, err)
+	return endpointConfig.NetworkConfig()
+}
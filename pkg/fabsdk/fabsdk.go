@@ -8,6 +8,9 @@ SPDX-License-Identifier: Apache-2.0
 package fabsdk
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
 	"math/rand"
 	"time"
 
@@ -19,15 +22,19 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/context"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/lookup"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/logging/api"
 	fabImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/comm"
 	sdkApi "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/api"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics"
 	metricsCfg "github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/metrics/cfg"
 	mspImpl "github.com/hyperledger/fabric-sdk-go/pkg/msp"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 )
 
 var logger = logging.NewLogger("fabsdk")
@@ -39,6 +46,7 @@ type FabricSDK struct {
 	cryptoSuite   core.CryptoSuite
 	system        *operations.System
 	clientMetrics *metrics.ClientMetrics
+	configWatcher *configWatcher
 }
 
 type configs struct {
@@ -59,6 +67,10 @@ type options struct {
 	ConfigBackend     []core.ConfigBackend
 	ProviderOpts      []coptions.Opt // Provider options are passed along to the various providers
 	metricsConfig     metricsCfg.MetricsConfig
+	userStoreProvider UserStoreProvider
+	tlsCertPins       map[string][]byte
+	trustAnchors      []*x509.Certificate
+	configWatcher     *configWatcherOpts
 }
 
 // Option configures the SDK.
@@ -177,6 +189,21 @@ func WithMSPPkg(msp sdkApi.MSPProviderFactory) Option {
 	}
 }
 
+// UserStoreProvider creates the user store used by the SDK from the identity config
+type UserStoreProvider func(config msp.IdentityConfig) (msp.UserStore, error)
+
+// WithUserStoreProvider injects the provider of the user store, e.g. a database-backed
+// or encrypted store, instead of the user store created by the MSP implementation.
+func WithUserStoreProvider(provider UserStoreProvider) Option {
+	return func(opts *options) error {
+		if provider == nil {
+			return errors.New("user store provider is nil")
+		}
+		opts.userStoreProvider = provider
+		return nil
+	}
+}
+
 // WithServicePkg injects the service implementation into the SDK.
 func WithServicePkg(service sdkApi.ServiceProviderFactory) Option {
 	return func(opts *options) error {
@@ -222,6 +249,109 @@ func WithErrorHandler(value fab.ErrorHandler) Option {
 	return WithProviderOpts(withErrorHandlerProviderOpt(value))
 }
 
+// WithGRPCPoolSize sets the maximum number of GRPC connections that are kept open by the SDK.
+// When the limit is reached, the connection that has been unused for the longest time is closed.
+// If all connections are in use, opening a connection waits until a connection is released or the
+// request context is done, in which case the request fails with a "maximum number of connections" error.
+// Connections that have been unused for longer than the connection idle timeout are always closed.
+func WithGRPCPoolSize(n int) Option {
+	return func(opts *options) error {
+		if n <= 0 {
+			return errors.New("GRPC pool size must be greater than zero")
+		}
+		return WithProviderOpts(comm.WithMaxConnections(n))(opts)
+	}
+}
+
+// WithGRPCKeepalive sets the keep-alive parameters of GRPC connections.
+// Keep-alive parameters in the GRPC options of a peer or orderer take precedence.
+func WithGRPCKeepalive(params keepalive.ClientParameters) Option {
+	return WithProviderOpts(comm.WithKeepAliveParams(params))
+}
+
+// WithPeerGRPCOptions sets GRPC dial options of the connections to the peer (or orderer) with the given URL,
+// e.g. message size limits or compression for a peer that is reached over a WAN link.
+// These options take precedence over the default GRPC options of the SDK.
+func WithPeerGRPCOptions(peerURL string, opts ...grpc.DialOption) Option {
+	return func(o *options) error {
+		if peerURL == "" {
+			return errors.New("peer URL is required")
+		}
+		return WithProviderOpts(comm.WithPeerDialOptions(peerURL, opts...))(o)
+	}
+}
+
+// WithTLSCertPin pins the TLS certificate of the peer or orderer with the given URL to the given
+// SHA-256 fingerprint of its leaf certificate. The pinned certificate is accepted without being verified
+// against the TLS CA certs, which allows connections in environments where the CA is not available.
+func WithTLSCertPin(url string, fingerprint []byte) Option {
+	return func(opts *options) error {
+		if url == "" {
+			return errors.New("URL is required")
+		}
+		if len(fingerprint) != sha256.Size {
+			return errors.Errorf("TLS certificate fingerprint must be a SHA-256 hash of %d bytes", sha256.Size)
+		}
+		if opts.tlsCertPins == nil {
+			opts.tlsCertPins = make(map[string][]byte)
+		}
+		opts.tlsCertPins[endpoint.ToAddress(url)] = fingerprint
+		return nil
+	}
+}
+
+// tlsCertPinConfig adds TLS certificate pins to an endpoint config (see comm.TLSCertPinner)
+type tlsCertPinConfig struct {
+	fab.EndpointConfig
+	pins map[string][]byte
+}
+
+// TLSCertPin returns the SHA-256 fingerprint of the TLS certificate of the given peer or orderer URL
+func (c *tlsCertPinConfig) TLSCertPin(url string) []byte {
+	return c.pins[endpoint.ToAddress(url)]
+}
+
+// WithAdditionalTrustAnchor adds the certificates of the given PEM bundle to the TLS CA certs that the
+// TLS certificates of peers and orderers are verified with, in addition to the TLS CA certs of the config.
+// This allows trust anchors to be added at runtime without a config change, e.g. during a CA certificate
+// rollover. The option may be passed more than once.
+func WithAdditionalTrustAnchor(pemBundle []byte) Option {
+	return func(opts *options) error {
+		var certs []*x509.Certificate
+		for block, rest := pem.Decode(pemBundle); block != nil; block, rest = pem.Decode(rest) {
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return errors.Wrap(err, "failed to parse trust anchor certificate")
+			}
+			certs = append(certs, cert)
+		}
+		if len(certs) == 0 {
+			return errors.New("trust anchor PEM bundle contains no certificates")
+		}
+		opts.trustAnchors = append(opts.trustAnchors, certs...)
+		return nil
+	}
+}
+
+// trustAnchorConfig adds trust anchors to the TLS CA cert pool of an endpoint config. The certs are
+// added whenever the pool is retrieved, so that they are kept when the endpoint config is reloaded.
+type trustAnchorConfig struct {
+	fab.EndpointConfig
+	certs []*x509.Certificate
+}
+
+// TLSCACertPool returns the TLS CA cert pool of the endpoint config with the trust anchors added
+func (c *trustAnchorConfig) TLSCACertPool() fab.CertPool {
+	certPool := c.EndpointConfig.TLSCACertPool()
+	if certPool != nil {
+		certPool.Add(c.certs...)
+	}
+	return certPool
+}
+
 // providerInit interface allows for initializing providers
 // TODO: minimize interface
 type providerInit interface {
@@ -252,7 +382,7 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 	rand.Seed(time.Now().UnixNano())
 
 	// Initialize state store
-	userStore, err := sdk.opts.MSP.CreateUserStore(cfg.identityConfig)
+	userStore, err := sdk.createUserStore(cfg.identityConfig)
 	if err != nil {
 		return errors.WithMessage(err, "failed to create state store")
 	}
@@ -274,6 +404,7 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 	if err != nil {
 		return errors.WithMessage(err, "failed to create infra provider")
 	}
+	coptions.Apply(infraProvider, sdk.opts.ProviderOpts)
 
 	// Initialize local discovery provider
 	localDiscoveryProvider, err := sdk.opts.Service.CreateLocalDiscoveryProvider(cfg.endpointConfig)
@@ -324,6 +455,10 @@ func initSDK(sdk *FabricSDK, configProvider core.ConfigProvider, opts []Option)
 		}
 	}
 
+	if sdk.configWatcher != nil {
+		sdk.configWatcher.start()
+	}
+
 	logger.Debug("SDK initialized successfully")
 	return nil
 }
@@ -338,6 +473,9 @@ func (sdk *FabricSDK) Close() {
 		pvdr.Close()
 	}
 	sdk.provider.InfraProvider().Close()
+	if sdk.configWatcher != nil {
+		sdk.configWatcher.stop()
+	}
 }
 
 // CloseContext frees up caches being maintained by the SDK for the given context
@@ -409,6 +547,21 @@ func (sdk *FabricSDK) initializeCryptoSuite(cryptoSuiteConfig core.CryptoSuiteCo
 	return nil
 }
 
+// createUserStore creates the user store with the injected provider, or the MSP implementation if there is none
+func (sdk *FabricSDK) createUserStore(identityConfig msp.IdentityConfig) (msp.UserStore, error) {
+	if sdk.opts.userStoreProvider == nil {
+		return sdk.opts.MSP.CreateUserStore(identityConfig)
+	}
+	userStore, err := sdk.opts.userStoreProvider(identityConfig)
+	if err != nil {
+		return nil, errors.WithMessage(err, "user store provider failed")
+	}
+	if userStore == nil {
+		return nil, errors.New("user store provider returned nil user store")
+	}
+	return userStore, nil
+}
+
 //loadConfigs load config from config backend when configs are not provided through opts
 func (sdk *FabricSDK) loadConfigs(configProvider core.ConfigProvider) (*configs, error) {
 	c := &configs{
@@ -434,6 +587,10 @@ func (sdk *FabricSDK) loadConfigs(configProvider core.ConfigProvider) (*configs,
 	if err != nil {
 		return nil, errors.WithMessage(err, "unable to load crypto suite config")
 	}
+	// validate the crypto suite config before the crypto suite is initialized with it
+	if err := configErrors(ValidateConfig(c.cryptoSuiteConfig, nil, nil)); err != nil {
+		return nil, errors.WithMessage(err, "invalid configuration")
+	}
 
 	//Initialize cryptosuite once crypto Suite config is available
 	err = sdk.initializeCryptoSuite(c.cryptoSuiteConfig)
@@ -446,12 +603,37 @@ func (sdk *FabricSDK) loadConfigs(configProvider core.ConfigProvider) (*configs,
 	if err != nil {
 		return nil, errors.WithMessage(err, "unable to load endpoint config")
 	}
+	if sdk.opts.configWatcher != nil {
+		if sdk.opts.endpointConfig != nil {
+			return nil, errors.New("config watcher cannot be used with an endpoint config passed through options")
+		}
+		if configProvider == nil {
+			return nil, errors.New("config watcher requires a config provider")
+		}
+		sdk.configWatcher, err = newConfigWatcher(sdk.opts.configWatcher, c.endpointConfig, sdk.endpointConfigLoader(configProvider))
+		if err != nil {
+			return nil, errors.WithMessage(err, "unable to create config watcher")
+		}
+		c.endpointConfig = sdk.configWatcher.endpointConfig
+	} else if notifier := configChangeNotifier(configBackend); notifier != nil && sdk.opts.endpointConfig == nil {
+		sdk.configWatcher = newNotifierConfigWatcher(notifier, c.endpointConfig, sdk.endpointConfigLoader(configProvider))
+		c.endpointConfig = sdk.configWatcher.endpointConfig
+	}
+	if len(sdk.opts.trustAnchors) > 0 {
+		c.endpointConfig = &trustAnchorConfig{EndpointConfig: c.endpointConfig, certs: sdk.opts.trustAnchors}
+	}
+	if len(sdk.opts.tlsCertPins) > 0 {
+		c.endpointConfig = &tlsCertPinConfig{EndpointConfig: c.endpointConfig, pins: sdk.opts.tlsCertPins}
+	}
 
 	// load identity config
 	c.identityConfig, err = sdk.loadIdentityConfig(configBackend...)
 	if err != nil {
 		return nil, errors.WithMessage(err, "unable to load identity config")
 	}
+	if err := configErrors(ValidateConfig(nil, c.endpointConfig, c.identityConfig)); err != nil {
+		return nil, errors.WithMessage(err, "invalid configuration")
+	}
 
 	// load metrics config
 	c.metricsConfig, err = sdk.loadMetricsConfig(configBackend...)
@@ -464,6 +646,18 @@ func (sdk *FabricSDK) loadConfigs(configProvider core.ConfigProvider) (*configs,
 	return c, nil
 }
 
+// endpointConfigLoader returns a loader of the endpoint config that runs the config provider again,
+// so that a reloaded config is wrapped in the same way as the config loaded by the SDK
+func (sdk *FabricSDK) endpointConfigLoader(configProvider core.ConfigProvider) endpointConfigLoader {
+	return func() (fab.EndpointConfig, error) {
+		configBackend, err := configProvider()
+		if err != nil {
+			return nil, errors.WithMessage(err, "unable to load config backend")
+		}
+		return sdk.loadEndpointConfig(configBackend...)
+	}
+}
+
 //loadEndpointConfig loads config from config backend when configs are not provided through opts or override missing interfaces from opts with config backend
 func (sdk *FabricSDK) loadEndpointConfig(configBackend ...core.ConfigBackend) (fab.EndpointConfig, error) {
 	endpointConfigOpt, ok := sdk.opts.endpointConfig.(*fabImpl.EndpointConfigOptions)
@@ -9,6 +9,10 @@ SPDX-License-Identifier: Apache-2.0
 package fabsdk
 
 import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
 	"os"
 	"reflect"
 	"sync"
@@ -22,9 +26,12 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/client/resmgmt"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/context"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	mspctx "github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	context2 "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	contextImpl "github.com/hyperledger/fabric-sdk-go/pkg/context"
 	configImpl "github.com/hyperledger/fabric-sdk-go/pkg/core/config"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm"
+	commtls "github.com/hyperledger/fabric-sdk-go/pkg/core/config/comm/tls"
 	discmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/factory/defsvc"
@@ -34,10 +41,13 @@ import (
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 )
 
 const (
 	sdkConfigFile      = "../../test/fixtures/config/config_test.yaml"
+	trustAnchorFile    = "../msp/testdata/root.pem"
 	sdkValidClientUser = "User1"
 	sdkValidClientOrg1 = "org1"
 )
@@ -127,6 +137,96 @@ func TestWithMSPPkg(t *testing.T) {
 	}
 }
 
+func TestWithUserStoreProvider(t *testing.T) {
+	c := configImpl.FromFile(sdkConfigFile)
+
+	userStore := msp.NewMemoryUserStore()
+	provider := func(config mspctx.IdentityConfig) (mspctx.UserStore, error) {
+		assert.NotNil(t, config)
+		return userStore, nil
+	}
+
+	sdk, err := New(c, WithUserStoreProvider(provider))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	ctx, err := sdk.Context()()
+	require.NoError(t, err)
+	assert.Equal(t, userStore, ctx.UserStore())
+
+	_, err = New(c, WithUserStoreProvider(func(config mspctx.IdentityConfig) (mspctx.UserStore, error) {
+		return nil, errors.New("store unavailable")
+	}))
+	assert.Error(t, err)
+
+	_, err = New(c, WithUserStoreProvider(nil))
+	assert.Error(t, err)
+}
+
+func TestWithGRPCPoolSize(t *testing.T) {
+	c := configImpl.FromFile(sdkConfigFile)
+
+	sdk, err := New(c, WithGRPCPoolSize(10), WithGRPCKeepalive(keepalive.ClientParameters{Time: time.Minute}))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	_, err = New(c, WithGRPCPoolSize(0))
+	assert.Error(t, err)
+}
+
+func TestWithPeerGRPCOptions(t *testing.T) {
+	c := configImpl.FromFile(sdkConfigFile)
+
+	sdk, err := New(c, WithPeerGRPCOptions("peer0.org1.example.com:7051", grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(1024*1024))))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	_, err = New(c, WithPeerGRPCOptions(""))
+	assert.Error(t, err)
+}
+
+func TestWithTLSCertPin(t *testing.T) {
+	c := configImpl.FromFile(sdkConfigFile)
+	fingerprint := sha256.Sum256([]byte("peer0 TLS certificate"))
+
+	sdk, err := New(c, WithTLSCertPin("grpcs://peer0.org1.example.com:7051", fingerprint[:]))
+	require.NoError(t, err)
+	defer sdk.Close()
+
+	ctx, err := sdk.Context()()
+	require.NoError(t, err)
+	pinner, ok := ctx.EndpointConfig().(comm.TLSCertPinner)
+	require.True(t, ok, "expecting endpoint config to implement TLSCertPinner")
+	assert.Equal(t, fingerprint[:], pinner.TLSCertPin("peer0.org1.example.com:7051"))
+	assert.Nil(t, pinner.TLSCertPin("peer1.org1.example.com:7051"))
+
+	_, err = New(c, WithTLSCertPin("peer0.org1.example.com:7051", []byte("fingerprint")))
+	assert.Error(t, err)
+}
+
+func TestWithAdditionalTrustAnchor(t *testing.T) {
+	bundle, err := ioutil.ReadFile(trustAnchorFile)
+	require.NoError(t, err)
+	block, _ := pem.Decode(bundle)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	opts := options{}
+	require.NoError(t, WithAdditionalTrustAnchor(bundle)(&opts))
+	assert.Equal(t, []*x509.Certificate{cert}, opts.trustAnchors)
+
+	assert.Error(t, WithAdditionalTrustAnchor([]byte("not a PEM bundle"))(&opts))
+	assert.Error(t, WithAdditionalTrustAnchor(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("invalid")}))(&opts))
+
+	certPool, err := commtls.NewCertPool(false)
+	require.NoError(t, err)
+	config := &trustAnchorConfig{EndpointConfig: &mocks.MockConfig{CustomTLSCACertPool: certPool}, certs: opts.trustAnchors}
+	pool, err := config.TLSCACertPool().Get()
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{cert.RawSubject}, pool.Subjects())
+}
+
 func TestWithServicePkg(t *testing.T) {
 	// Test New SDK with valid config file
 	c := configImpl.FromFile(sdkConfigFile)
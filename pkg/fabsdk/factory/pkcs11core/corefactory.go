@@ -0,0 +1,30 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package pkcs11core provides an SDK core provider factory with a PKCS#11 crypto suite. It is kept out of the
+// default factory since the PKCS#11 crypto suite requires cgo and libltdl.
+package pkcs11core
+
+import (
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/pkcs11"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk/factory/defcore"
+)
+
+// ProviderFactory is the default SDK core provider factory with a PKCS#11 crypto suite
+type ProviderFactory struct {
+	defcore.ProviderFactory
+}
+
+// NewProviderFactory returns the SDK core provider factory with a PKCS#11 crypto suite
+func NewProviderFactory() *ProviderFactory {
+	return &ProviderFactory{}
+}
+
+// CreateCryptoSuiteProvider returns a new PKCS#11 implementation of BCCSP
+func (f *ProviderFactory) CreateCryptoSuiteProvider(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
+	return pkcs11.GetSuiteByConfig(config)
+}
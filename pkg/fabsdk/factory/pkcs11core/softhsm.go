@@ -0,0 +1,175 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11core
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/pkcs11"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fabsdk"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk")
+
+const (
+	// SoftHSMPin is the user PIN of the SoftHSM2 token used by WithSoftPKCS11
+	SoftHSMPin = "1234"
+	// SoftHSMLabel is the label of the SoftHSM2 token used by WithSoftPKCS11
+	SoftHSMLabel = "ForFabric"
+
+	softHSMConfEnv = "SOFTHSM2_CONF"
+)
+
+// softHSMLibPaths are the install locations of the SoftHSM2 library
+var softHSMLibPaths = []string{
+	"/usr/lib/softhsm/libsofthsm2.so",                            // Debian
+	"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",           // Ubuntu
+	"/usr/lib/s390x-linux-gnu/softhsm/libsofthsm2.so",            // Ubuntu
+	"/usr/lib/powerpc64le-linux-gnu/softhsm/libsofthsm2.so",      // Power
+	"/usr/local/lib/softhsm/libsofthsm2.so",                      // Source install
+	"/usr/local/Cellar/softhsm/2.1.0/lib/softhsm/libsofthsm2.so", // MacOS
+}
+
+// WithSoftPKCS11 configures the SDK to use the PKCS#11 crypto suite with a SoftHSM2 token, for CI
+// environments where real HSMs are unavailable. The token is the token labeled SoftHSMLabel with the
+// user PIN SoftHSMPin in the given token directory, e.g. as initialized with:
+//  softhsm2-util --init-token --slot 0 --label ForFabric --so-pin 1234 --pin 1234
+// Unless SOFTHSM2_CONF is already set, the SOFTHSM2_CONF environment variable of the process is set
+// to a SoftHSM2 config with the token directory. Since the config applies to the whole process, all
+// SDK instances of the process must use the same token directory. The SDK fails to initialize if
+// SoftHSM2 is not installed, rather than falling back to the software crypto suite.
+//  Parameters:
+//  tokenPath is the SoftHSM2 token directory
+//
+//  Returns:
+//  the SDK option
+func WithSoftPKCS11(tokenPath string) fabsdk.Option {
+	return fabsdk.WithCorePkg(&softHSMProviderFactory{tokenPath: tokenPath})
+}
+
+// softHSMProviderFactory creates a PKCS#11 crypto suite with the SoftHSM2 token
+type softHSMProviderFactory struct {
+	ProviderFactory
+	tokenPath string
+}
+
+// CreateCryptoSuiteProvider returns a PKCS#11 implementation of BCCSP with the SoftHSM2 token
+func (f *softHSMProviderFactory) CreateCryptoSuiteProvider(config core.CryptoSuiteConfig) (core.CryptoSuite, error) {
+	lib := findSoftHSMLib()
+	if lib == "" {
+		return nil, errors.Errorf("SoftHSM2 is not installed: libsofthsm2.so was not found in [%s]", strings.Join(softHSMLibPaths, ", "))
+	}
+	if err := configureSoftHSM(f.tokenPath); err != nil {
+		return nil, err
+	}
+	logger.Debugf("Using SoftHSM2 library [%s] with token directory [%s]", lib, f.tokenPath)
+	return pkcs11.GetSuiteByConfig(&softHSMConfig{CryptoSuiteConfig: config, lib: lib})
+}
+
+func findSoftHSMLib() string {
+	for _, path := range softHSMLibPaths {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// softHSMSetup records the SoftHSM2 configuration of the process. SoftHSM2 reads the config file named by
+// SOFTHSM2_CONF whenever the library is initialized, which includes reinitializations of the PKCS#11 context,
+// so the config is written once per process and kept for the lifetime of the process.
+type softHSMSetup struct {
+	once      sync.Once
+	tokenPath string
+	err       error
+}
+
+var softHSM softHSMSetup
+
+// configureSoftHSM points SoftHSM2 to a config with the given token directory, unless SOFTHSM2_CONF is already set
+func configureSoftHSM(tokenPath string) error {
+	tokenPath, err := filepath.Abs(tokenPath)
+	if err != nil {
+		return errors.Wrapf(err, "invalid SoftHSM2 token directory [%s]", tokenPath)
+	}
+	if info, err := os.Stat(tokenPath); err != nil || !info.IsDir() {
+		return errors.Errorf("SoftHSM2 token directory [%s] not found", tokenPath)
+	}
+
+	softHSM.once.Do(func() {
+		softHSM.tokenPath = tokenPath
+		if conf, ok := os.LookupEnv(softHSMConfEnv); ok {
+			logger.Warnf("Using SoftHSM2 config [%s] from %s: token directory [%s] is ignored", conf, softHSMConfEnv, tokenPath)
+			return
+		}
+		softHSM.err = writeSoftHSMConf(tokenPath)
+	})
+	if softHSM.err != nil {
+		return softHSM.err
+	}
+	if softHSM.tokenPath != tokenPath {
+		return errors.Errorf("SoftHSM2 is already configured with token directory [%s]", softHSM.tokenPath)
+	}
+	return nil
+}
+
+// writeSoftHSMConf writes a SoftHSM2 config with the given token directory and sets SOFTHSM2_CONF to it. The
+// config file is named after the token directory, so that it is reused rather than accumulated across runs.
+func writeSoftHSMConf(tokenPath string) error {
+	confPath := softHSMConfPath(tokenPath)
+	content := fmt.Sprintf("directories.tokendir = %s\nobjectstore.backend = file\n", tokenPath)
+	if err := ioutil.WriteFile(confPath, []byte(content), 0600); err != nil {
+		os.Remove(confPath) // nolint: errcheck
+		return errors.Wrap(err, "writing SoftHSM2 config failed")
+	}
+	if err := os.Setenv(softHSMConfEnv, confPath); err != nil {
+		os.Remove(confPath) // nolint: errcheck
+		return errors.Wrap(err, "setting SoftHSM2 config failed")
+	}
+	return nil
+}
+
+// softHSMConfPath returns the path of the SoftHSM2 config of the given token directory
+func softHSMConfPath(tokenPath string) string {
+	hash := sha256.Sum256([]byte(tokenPath))
+	return filepath.Join(os.TempDir(), fmt.Sprintf("softhsm2-%x.conf", hash[:8]))
+}
+
+// softHSMConfig overrides the PKCS#11 settings of the crypto suite config with the SoftHSM2 token
+type softHSMConfig struct {
+	core.CryptoSuiteConfig
+	lib string
+}
+
+// SecurityProvider returns pkcs11
+func (c *softHSMConfig) SecurityProvider() string {
+	return "pkcs11"
+}
+
+// SecurityProviderLibPath returns the path of the SoftHSM2 library
+func (c *softHSMConfig) SecurityProviderLibPath() string {
+	return c.lib
+}
+
+// SecurityProviderPin returns SoftHSMPin
+func (c *softHSMConfig) SecurityProviderPin() string {
+	return SoftHSMPin
+}
+
+// SecurityProviderLabel returns SoftHSMLabel
+func (c *softHSMConfig) SecurityProviderLabel() string {
+	return SoftHSMLabel
+}
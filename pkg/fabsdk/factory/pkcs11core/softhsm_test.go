@@ -0,0 +1,96 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSoftHSMNotInstalled(t *testing.T) {
+	defer func(paths []string) { softHSMLibPaths = paths }(softHSMLibPaths)
+	softHSMLibPaths = []string{"/nonexistent/libsofthsm2.so"}
+
+	factory := &softHSMProviderFactory{tokenPath: os.TempDir()}
+	_, err := factory.CreateCryptoSuiteProvider(mocks.NewMockCryptoConfig())
+	assert.EqualError(t, err, "SoftHSM2 is not installed: libsofthsm2.so was not found in [/nonexistent/libsofthsm2.so]")
+}
+
+func TestConfigureSoftHSM(t *testing.T) {
+	defer resetSoftHSM(t)()
+	os.Unsetenv(softHSMConfEnv)
+
+	tokenPath, err := ioutil.TempDir("", "tokens")
+	require.NoError(t, err)
+	defer os.RemoveAll(tokenPath)
+
+	require.NoError(t, configureSoftHSM(tokenPath))
+	confPath := os.Getenv(softHSMConfEnv)
+	defer os.Remove(confPath)
+	assert.Equal(t, softHSMConfPath(tokenPath), confPath)
+	conf, err := ioutil.ReadFile(confPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(conf), "directories.tokendir = "+tokenPath+"\n")
+
+	// The config is written once per process
+	require.NoError(t, os.Remove(confPath))
+	require.NoError(t, configureSoftHSM(tokenPath))
+	_, err = os.Stat(confPath)
+	assert.True(t, os.IsNotExist(err), "expecting the config not to be written again")
+
+	otherPath, err := ioutil.TempDir("", "tokens")
+	require.NoError(t, err)
+	defer os.RemoveAll(otherPath)
+	err = configureSoftHSM(otherPath)
+	assert.EqualError(t, err, "SoftHSM2 is already configured with token directory ["+tokenPath+"]")
+
+	err = configureSoftHSM(filepath.Join(tokenPath, "missing"))
+	assert.EqualError(t, err, "SoftHSM2 token directory ["+filepath.Join(tokenPath, "missing")+"] not found")
+}
+
+func TestConfigureSoftHSMExistingConf(t *testing.T) {
+	defer resetSoftHSM(t)()
+	os.Setenv(softHSMConfEnv, "/etc/softhsm/softhsm2.conf")
+
+	tokenPath, err := ioutil.TempDir("", "tokens")
+	require.NoError(t, err)
+	defer os.RemoveAll(tokenPath)
+
+	require.NoError(t, configureSoftHSM(tokenPath))
+	assert.Equal(t, "/etc/softhsm/softhsm2.conf", os.Getenv(softHSMConfEnv))
+	_, err = os.Stat(softHSMConfPath(tokenPath))
+	assert.True(t, os.IsNotExist(err), "expecting no config to be written")
+}
+
+func TestSoftHSMConfig(t *testing.T) {
+	config := &softHSMConfig{CryptoSuiteConfig: mocks.NewMockCryptoConfig(), lib: "/usr/lib/softhsm/libsofthsm2.so"}
+	assert.Equal(t, "pkcs11", config.SecurityProvider())
+	assert.Equal(t, "/usr/lib/softhsm/libsofthsm2.so", config.SecurityProviderLibPath())
+	assert.Equal(t, SoftHSMPin, config.SecurityProviderPin())
+	assert.Equal(t, SoftHSMLabel, config.SecurityProviderLabel())
+	assert.Equal(t, mocks.NewMockCryptoConfig().SecurityAlgorithm(), config.SecurityAlgorithm())
+}
+
+// resetSoftHSM clears the SoftHSM2 configuration of the process, and returns a function that restores it
+func resetSoftHSM(t *testing.T) func() {
+	conf, ok := os.LookupEnv(softHSMConfEnv)
+	softHSM = softHSMSetup{}
+	return func() {
+		softHSM = softHSMSetup{}
+		if ok {
+			require.NoError(t, os.Setenv(softHSMConfEnv, conf))
+		} else {
+			require.NoError(t, os.Unsetenv(softHSMConfEnv))
+		}
+	}
+}
@@ -8,10 +8,13 @@ package chpvdr
 
 import (
 	"crypto/sha256"
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/options"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
 )
 
 // ctxtCacheKey is a lazy cache key for the context cache
@@ -99,6 +102,14 @@ func (k *eventCacheKey) String() string {
 
 type params struct {
 	permitBlockEvents bool
+	seekType          seek.Type
+	fromBlock         uint64
+
+	// reconnect options result in a separate event service, since they apply to its connection
+	reconnInitialDelay  time.Duration
+	reconnMaxDelay      time.Duration
+	reconnBackoffFactor float64
+	reconnEventCh       chan *fab.ReconnectEvent
 }
 
 func defaultParams() *params {
@@ -109,8 +120,38 @@ func (p *params) PermitBlockEvents() {
 	p.permitBlockEvents = true
 }
 
+func (p *params) SetSeekType(value seek.Type) {
+	p.seekType = value
+}
+
+func (p *params) SetFromBlock(value uint64) {
+	p.fromBlock = value
+}
+
+func (p *params) SetReconnectDelay(initial, max time.Duration, factor float64) {
+	p.reconnInitialDelay = initial
+	p.reconnMaxDelay = max
+	p.reconnBackoffFactor = factor
+}
+
+func (p *params) SetReconnectEventCh(value chan *fab.ReconnectEvent) {
+	p.reconnEventCh = value
+}
+
 func (p *params) getOptKey() string {
 	//	Construct opts portion
 	optKey := "blockEvents:" + strconv.FormatBool(p.permitBlockEvents)
+	if p.seekType != "" {
+		optKey += ",seekType:" + string(p.seekType)
+		if p.seekType == seek.FromBlock {
+			optKey += ",fromBlock:" + strconv.FormatUint(p.fromBlock, 10)
+		}
+	}
+	if p.reconnBackoffFactor > 0 {
+		optKey += fmt.Sprintf(",reconnectDelay:%s/%s/%g", p.reconnInitialDelay, p.reconnMaxDelay, p.reconnBackoffFactor)
+	}
+	if p.reconnEventCh != nil {
+		optKey += fmt.Sprintf(",reconnectEvents:%p", p.reconnEventCh)
+	}
 	return optKey
 }
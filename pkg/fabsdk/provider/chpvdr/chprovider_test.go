@@ -25,6 +25,9 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/chconfig"
 	discmocks "github.com/hyperledger/fabric-sdk-go/pkg/fab/discovery/mocks"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/client"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/events/deliverclient/seek"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
 	mspmocks "github.com/hyperledger/fabric-sdk-go/pkg/msp/test/mockmsp"
 	"github.com/stretchr/testify/assert"
@@ -391,3 +394,32 @@ func getChannelProvider(t *testing.T, providers context.Providers, opts ...optio
 
 	return cp
 }
+
+func TestEventCacheKey(t *testing.T) {
+	chCfg := chconfig.NewChannelCfg("mychannel")
+
+	key := func(opts ...options.Opt) string {
+		k, err := newEventCacheKey(chCfg, opts...)
+		require.NoError(t, err)
+		return k.String()
+	}
+
+	assert.Equal(t, key(), key(deliverclient.WithBlockNum(10)), "block number should be ignored without seek type")
+	assert.NotEqual(t, key(), key(deliverclient.WithSeekType(seek.Oldest)))
+	assert.Equal(t,
+		key(deliverclient.WithSeekType(seek.FromBlock), deliverclient.WithBlockNum(10)),
+		key(deliverclient.WithSeekType(seek.FromBlock), deliverclient.WithBlockNum(10)))
+	assert.NotEqual(t,
+		key(deliverclient.WithSeekType(seek.FromBlock), deliverclient.WithBlockNum(10)),
+		key(deliverclient.WithSeekType(seek.FromBlock), deliverclient.WithBlockNum(11)))
+
+	reconnectDelay := client.WithReconnectDelay(time.Second, 10*time.Second, 2)
+	assert.NotEqual(t, key(), key(reconnectDelay))
+	assert.Equal(t, key(reconnectDelay), key(client.WithReconnectDelay(time.Second, 10*time.Second, 2)))
+
+	reconnectch1 := make(chan *fab.ReconnectEvent)
+	reconnectch2 := make(chan *fab.ReconnectEvent)
+	assert.Equal(t, key(client.WithReconnectEvent(reconnectch1)), key(client.WithReconnectEvent(reconnectch1)))
+	assert.NotEqual(t, key(client.WithReconnectEvent(reconnectch1)), key(client.WithReconnectEvent(reconnectch2)),
+		"expected a separate event service for each reconnect event channel")
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package chpvdr
 
 import (
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -27,7 +28,9 @@ type eventClientProvider func() (fab.EventClient, error)
 // The EventClientRef implements all of the functions of fab.EventService, so the
 // EventClientRef may be used wherever an EventService is required.
 type EventClientRef struct {
+	lock        sync.RWMutex // held for writing while the reference is replaced by CloseIfIdle
 	ref         *lazyref.Reference
+	idleTimeout time.Duration
 	provider    eventClientProvider
 	eventClient fab.EventClient
 	closed      int32
@@ -36,22 +39,27 @@ type EventClientRef struct {
 // NewEventClientRef returns a new EventClientRef
 func NewEventClientRef(idleTimeout time.Duration, evtClientProvider eventClientProvider) *EventClientRef {
 	clientRef := &EventClientRef{
-		provider: evtClientProvider,
+		provider:    evtClientProvider,
+		idleTimeout: idleTimeout,
 	}
 
-	if idleTimeout == 0 {
-		idleTimeout = defaultTimeout
+	if clientRef.idleTimeout == 0 {
+		clientRef.idleTimeout = defaultTimeout
 	}
 
-	clientRef.ref = lazyref.New(
-		clientRef.initializer(),
-		lazyref.WithFinalizer(clientRef.finalizer()),
-		lazyref.WithIdleExpiration(idleTimeout),
-	)
+	clientRef.ref = clientRef.newReference()
 
 	return clientRef
 }
 
+func (ref *EventClientRef) newReference() *lazyref.Reference {
+	return lazyref.New(
+		ref.initializer(),
+		lazyref.WithFinalizer(ref.finalizer()),
+		lazyref.WithIdleExpiration(ref.idleTimeout),
+	)
+}
+
 // Close immediately closes the connection.
 func (ref *EventClientRef) Close() {
 	if !atomic.CompareAndSwapInt32(&ref.closed, 0, 1) {
@@ -60,9 +68,35 @@ func (ref *EventClientRef) Close() {
 	}
 
 	logger.Debug("Closing the event client")
+	ref.lock.RLock()
+	defer ref.lock.RUnlock()
 	ref.ref.Close()
 }
 
+// CloseIfIdle immediately closes the connection if there are no outstanding registrations, rather than
+// when the idle timeout is reached. Unlike Close, the EventClientRef may still be used: a new event client
+// is created the next time it is accessed.
+// Returns false if the connection was not closed since there was at least one registration.
+func (ref *EventClientRef) CloseIfIdle() bool {
+	ref.lock.Lock()
+	defer ref.lock.Unlock()
+
+	if ref.Closed() || ref.eventClient == nil {
+		return true
+	}
+	if !ref.eventClient.CloseIfIdle() {
+		logger.Debug("Event client was not closed since there are outstanding registrations")
+		return false
+	}
+
+	logger.Debug("Closed idle event client")
+	ref.eventClient = nil
+	previous := ref.ref
+	ref.ref = ref.newReference()
+	previous.Close()
+	return true
+}
+
 // Closed returns true if the event client is closed
 func (ref *EventClientRef) Closed() bool {
 	return atomic.LoadInt32(&ref.closed) == 1
@@ -70,6 +104,9 @@ func (ref *EventClientRef) Closed() bool {
 
 // RegisterBlockEvent registers for block events.
 func (ref *EventClientRef) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Registration, <-chan *fab.BlockEvent, error) {
+	ref.lock.RLock()
+	defer ref.lock.RUnlock()
+
 	service, err := ref.get()
 	if err != nil {
 		return nil, nil, err
@@ -79,6 +116,9 @@ func (ref *EventClientRef) RegisterBlockEvent(filter ...fab.BlockFilter) (fab.Re
 
 // RegisterFilteredBlockEvent registers for filtered block events.
 func (ref *EventClientRef) RegisterFilteredBlockEvent() (fab.Registration, <-chan *fab.FilteredBlockEvent, error) {
+	ref.lock.RLock()
+	defer ref.lock.RUnlock()
+
 	service, err := ref.get()
 	if err != nil {
 		return nil, nil, err
@@ -88,6 +128,9 @@ func (ref *EventClientRef) RegisterFilteredBlockEvent() (fab.Registration, <-cha
 
 // RegisterChaincodeEvent registers for chaincode events.
 func (ref *EventClientRef) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	ref.lock.RLock()
+	defer ref.lock.RUnlock()
+
 	service, err := ref.get()
 	if err != nil {
 		return nil, nil, err
@@ -97,6 +140,9 @@ func (ref *EventClientRef) RegisterChaincodeEvent(ccID, eventFilter string) (fab
 
 // RegisterTxStatusEvent registers for transaction status events.
 func (ref *EventClientRef) RegisterTxStatusEvent(txID string) (fab.Registration, <-chan *fab.TxStatusEvent, error) {
+	ref.lock.RLock()
+	defer ref.lock.RUnlock()
+
 	service, err := ref.get()
 	if err != nil {
 		return nil, nil, err
@@ -106,6 +152,9 @@ func (ref *EventClientRef) RegisterTxStatusEvent(txID string) (fab.Registration,
 
 // Unregister removes the given registration and closes the event channel.
 func (ref *EventClientRef) Unregister(reg fab.Registration) {
+	ref.lock.RLock()
+	defer ref.lock.RUnlock()
+
 	if service, err := ref.get(); err != nil {
 		logger.Warnf("Error unregistering event registration: %s", err)
 	} else {
@@ -0,0 +1,99 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chpvdr
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
+	"github.com/hyperledger/fabric-sdk-go/pkg/fab/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventClientRefCloseIfIdle(t *testing.T) {
+	var clients []*countingEventClient
+	ref := NewEventClientRef(time.Minute, func() (fab.EventClient, error) {
+		client := &countingEventClient{MockEventService: mocks.NewMockEventService()}
+		clients = append(clients, client)
+		return client, nil
+	})
+	defer ref.Close()
+
+	assert.True(t, ref.CloseIfIdle(), "expecting a ref without event client to be idle")
+
+	reg, _, err := ref.RegisterChaincodeEvent("cc", "event")
+	require.NoError(t, err)
+	require.Len(t, clients, 1)
+
+	assert.False(t, ref.CloseIfIdle(), "expecting the event client not to be closed while there are registrations")
+	assert.False(t, clients[0].isClosed())
+
+	ref.Unregister(reg)
+	assert.True(t, ref.CloseIfIdle())
+	assert.True(t, clients[0].isClosed())
+	assert.False(t, ref.Closed(), "expecting the ref to remain usable")
+
+	// A new event client is created when the ref is used again
+	_, _, err = ref.RegisterChaincodeEvent("cc", "event")
+	require.NoError(t, err)
+	require.Len(t, clients, 2)
+	assert.False(t, clients[1].isClosed())
+}
+
+// countingEventClient is an event client that counts its chaincode registrations
+type countingEventClient struct {
+	*mocks.MockEventService
+	lock          sync.Mutex
+	registrations int
+	closed        bool
+}
+
+func (c *countingEventClient) RegisterChaincodeEvent(ccID, eventFilter string) (fab.Registration, <-chan *fab.CCEvent, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.registrations++
+	return c.MockEventService.RegisterChaincodeEvent(ccID, eventFilter)
+}
+
+func (c *countingEventClient) Unregister(reg fab.Registration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.registrations--
+}
+
+func (c *countingEventClient) Connect() error {
+	return nil
+}
+
+func (c *countingEventClient) Close() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.closed = true
+}
+
+func (c *countingEventClient) CloseIfIdle() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.registrations > 0 {
+		return false
+	}
+	c.closed = true
+	return true
+}
+
+func (c *countingEventClient) TransferRegistrations(close bool) (fab.EventSnapshot, error) {
+	return nil, nil
+}
+
+func (c *countingEventClient) isClosed() bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.closed
+}
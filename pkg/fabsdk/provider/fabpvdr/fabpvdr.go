@@ -14,6 +14,8 @@ import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/orderer"
 	peerImpl "github.com/hyperledger/fabric-sdk-go/pkg/fab/peer"
 	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 )
 
 var logger = logging.NewLogger("fabsdk")
@@ -46,6 +48,21 @@ func (f *InfraProvider) Close() {
 	f.commManager.Close()
 }
 
+// SetMaxConnections sets the maximum number of cached GRPC connections
+func (f *InfraProvider) SetMaxConnections(value int) {
+	f.commManager.SetMaxConnections(value)
+}
+
+// SetKeepAliveParams sets the default GRPC keep-alive parameters of the connections
+func (f *InfraProvider) SetKeepAliveParams(value keepalive.ClientParameters) {
+	f.commManager.SetKeepAliveParams(value)
+}
+
+// SetPeerDialOptions sets the GRPC dial options of the connections to the given peer (or orderer) URL
+func (f *InfraProvider) SetPeerDialOptions(url string, opts []grpc.DialOption) {
+	f.commManager.SetPeerDialOptions(url, opts)
+}
+
 // CommManager provides comm support such as GRPC onnections
 func (f *InfraProvider) CommManager() fab.CommManager {
 	return f.commManager
@@ -7,7 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package api
 
 import (
+	"context"
 	"errors"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 )
 
 var (
@@ -22,6 +25,8 @@ type CAClient interface {
 	Register(request *RegistrationRequest) (string, error)
 	Revoke(request *RevocationRequest) (*RevocationResponse, error)
 	GetCAInfo() (*GetCAInfoResponse, error)
+	GetCRL(caname string) ([]byte, error)
+	GetIdemixCredential(enrollmentID, caname string, provider IdemixProvider) (*IdemixCredentialResponse, error)
 	CreateIdentity(request *IdentityRequest) (*IdentityResponse, error)
 	GetIdentity(id, caname string) (*IdentityResponse, error)
 	ModifyIdentity(request *IdentityRequest) (*IdentityResponse, error)
@@ -34,6 +39,12 @@ type CAClient interface {
 	RemoveAffiliation(request *AffiliationRequest) (*AffiliationResponse, error)
 }
 
+// ContextCAClient is implemented by CA clients whose requests can be bound to a context
+type ContextCAClient interface {
+	// WithContext returns a CA client whose requests are cancelled when the context is done
+	WithContext(ctx context.Context) (CAClient, error)
+}
+
 // AttributeRequest is a request for an attribute.
 type AttributeRequest struct {
 	Name     string
@@ -77,6 +88,27 @@ type EnrollmentRequest struct {
 	// The type of the enrollment request: x509 or idemix
 	// The default is a request for an X509 enrollment certificate
 	Type string
+	// Key is an existing private key to enroll with. If set, no new
+	// key pair is generated and the CSR is signed with this key.
+	Key core.Key
+	// CSR overrides the defaults of the certificate signing request
+	CSR *CSRInfo
+}
+
+// CSRInfo is the certificate signing request information
+type CSRInfo struct {
+	// CN is the subject common name. The default is the enrollment ID.
+	CN string
+	// Hosts are the subject alternative names. The default is the local host name.
+	Hosts []string
+	// KeyRequest is the algorithm and size of the key pair to generate
+	KeyRequest *KeyRequest
+}
+
+// KeyRequest is the algorithm and size of a key pair to generate
+type KeyRequest struct {
+	Algo string
+	Size int
 }
 
 // ReenrollmentRequest is a request to reenroll an identity.
@@ -91,6 +123,8 @@ type ReenrollmentRequest struct {
 	// AttrReqs are requests for attributes to add to the certificate.
 	// Each attribute is added only if the requestor owns the attribute.
 	AttrReqs []*AttributeRequest
+	// CSR holds the CSR overrides of the re-enrollment, if any
+	CSR *CSRInfo
 }
 
 // Attribute defines additional attributes that may be passed along during registration
@@ -115,6 +149,8 @@ type RevocationRequest struct {
 	Reason string
 	// CAName is the name of the CA to connect to
 	CAName string
+	// GenCRL specifies whether to include the updated CRL in the response
+	GenCRL bool
 }
 
 // RevocationResponse represents response from the server for a revocation request
@@ -240,6 +276,31 @@ type IdentityInfo struct {
 	MaxEnrollments int
 }
 
+// IdemixProvider implements the Idemix cryptography that is needed to obtain and verify Idemix
+// credentials, e.g. with the idemix package of Fabric. The SDK does not include an Idemix implementation.
+type IdemixProvider interface {
+	// NewCredentialRequest creates a credential request for the nonce issued by the CA, with a new user
+	// secret key. It returns the JSON encoding of the credential request, as expected by the
+	// /idemix/credential endpoint of the CA, and the user secret key.
+	NewCredentialRequest(issuerPublicKey, nonce []byte) (credRequest []byte, secretKey []byte, err error)
+	// VerifyProof verifies that proof is a valid Idemix signature of nonce that discloses the given attribute values
+	VerifyProof(issuerPublicKey, proof, nonce []byte, attrs []string) (bool, error)
+}
+
+// IdemixCredentialResponse is the Idemix credential issued by the CA
+type IdemixCredentialResponse struct {
+	// Credential is the proto bytes of the Idemix credential
+	Credential []byte
+	// SecretKey is the user secret key of the credential
+	SecretKey []byte
+	// Attrs are the attributes of the credential
+	Attrs map[string]interface{}
+	// CRI is the proto bytes of the credential revocation information
+	CRI []byte
+	// IssuerPublicKey is the Idemix issuer public key of the CA
+	IssuerPublicKey []byte
+}
+
 // GetCAInfoResponse is the response from the GetCAInfo call
 type GetCAInfoResponse struct {
 	// CAName is the name of the CA
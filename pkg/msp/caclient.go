@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	reqContext "context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -32,8 +35,38 @@ type CAClientImpl struct {
 	registrar       msp.EnrollCredentials
 }
 
+// CAClientOption describes a functional parameter for NewCAClient
+type CAClientOption func(*CAClientImpl) error
+
+// WithCAName targets the named CA of a Fabric CA server hosting multiple CAs,
+// instead of the CA name configured for the organization
+func WithCAName(caName string) CAClientOption {
+	return func(c *CAClientImpl) error {
+		if caName == "" {
+			return errors.New("CA name is empty")
+		}
+		c.caName = caName
+		c.adapter.caClient.Config.CAName = caName
+		return nil
+	}
+}
+
+// WithTLSClientCert sets the certificate presented to the CA when it requires mutual TLS,
+// instead of the client key and certificate configured for the organization's CA.
+// The key pair of the SDK identity may be reused for this purpose, provided the certificate
+// is issued by a CA trusted by the Fabric CA server for TLS client authentication.
+func WithTLSClientCert(cert tls.Certificate) CAClientOption {
+	return func(c *CAClientImpl) error {
+		if len(cert.Certificate) == 0 || cert.PrivateKey == nil {
+			return errors.New("TLS client certificate and private key are required")
+		}
+		c.adapter.caClient.Config.TLSClientCert = &cert
+		return nil
+	}
+}
+
 // NewCAClient creates a new CA CAClient instance
-func NewCAClient(orgName string, ctx contextApi.Client) (*CAClientImpl, error) {
+func NewCAClient(orgName string, ctx contextApi.Client, opts ...CAClientOption) (*CAClientImpl, error) {
 
 	if orgName == "" {
 		orgName = ctx.IdentityConfig().Client().Organization
@@ -86,9 +119,37 @@ func NewCAClient(orgName string, ctx contextApi.Client) (*CAClientImpl, error) {
 		adapter:         adapter,
 		registrar:       registrar,
 	}
+
+	for _, opt := range opts {
+		if err := opt(mgr); err != nil {
+			return nil, errors.WithMessage(err, "failed to create CA client")
+		}
+	}
+
+	// The Fabric CA client is initialized after the options since they may change its TLS config
+	if err := adapter.init(); err != nil {
+		return nil, errors.Wrapf(err, "error initializing CA [%s]", caName)
+	}
 	return mgr, nil
 }
 
+// WithContext returns a copy of the CA client whose requests to the CA are bound to the given
+// context, so that requests still in flight when the context is done are cancelled
+func (c *CAClientImpl) WithContext(ctx reqContext.Context) (api.CAClient, error) {
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+
+	caClient, err := c.adapter.caClient.WithContext(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to bind CA client to context")
+	}
+
+	client := *c
+	client.adapter = &fabricCAAdapter{config: c.adapter.config, cryptoSuite: c.adapter.cryptoSuite, caClient: caClient}
+	return &client, nil
+}
+
 // Enroll a registered user in order to receive a signed X509 certificate.
 // A new key pair is generated for the user. The private key and the
 // enrollment certificate issued by the CA are stored in SDK stores.
@@ -107,6 +168,19 @@ func (c *CAClientImpl) Enroll(request *api.EnrollmentRequest) error {
 	if request.Secret == "" {
 		return errors.New("enrollmentSecret is required")
 	}
+	if request.Key != nil {
+		if !request.Key.Private() {
+			return errors.New("existing key must be a private key")
+		}
+		// The key must be resolvable by SKI so that the identity manager
+		// can match it with the enrollment certificate later on
+		if _, err := c.cryptoSuite.GetKey(request.Key.SKI()); err != nil {
+			return errors.WithMessage(err, "existing key not found in crypto suite key store")
+		}
+		if request.CSR != nil && request.CSR.KeyRequest != nil {
+			return errors.New("key request cannot be combined with an existing key")
+		}
+	}
 	// TODO add attributes
 	cert, err := c.adapter.Enroll(request)
 	if err != nil {
@@ -347,6 +421,84 @@ func (c *CAClientImpl) Revoke(request *api.RevocationRequest) (*api.RevocationRe
 	return resp, nil
 }
 
+// GetCRL returns the PEM-encoded certificate revocation list of the CA
+func (c *CAClientImpl) GetCRL(caname string) ([]byte, error) {
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if c.registrar.EnrollID == "" {
+		return nil, api.ErrCARegistrarNotFound
+	}
+
+	registrar, err := c.getRegistrar(c.registrar.EnrollID, c.registrar.EnrollSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.adapter.GetCRL(registrar.PrivateKey(), registrar.EnrollmentCertificate(), caname)
+}
+
+// GetIdemixCredential gets an Idemix credential for an enrolled user from the CA and stores it
+// in the user store, if the user store implements UserIdemixCredentialStore. The user secret key
+// is not stored: it is only returned, and it is up to the caller to keep it secret.
+// enrollmentID: enrollment ID of the user, whose enrollment certificate authenticates the request
+// caname: name of the CA
+// provider: creates the credential request
+func (c *CAClientImpl) GetIdemixCredential(enrollmentID, caname string, provider api.IdemixProvider) (*api.IdemixCredentialResponse, error) {
+	if c.adapter == nil {
+		return nil, fmt.Errorf("no CAs configured for organization: %s", c.orgName)
+	}
+	if enrollmentID == "" {
+		return nil, errors.New("user name missing")
+	}
+	if provider == nil {
+		return nil, errors.New("Idemix provider is required")
+	}
+
+	user, err := c.identityManager.GetSigningIdentity(enrollmentID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve user: %s", enrollmentID)
+	}
+
+	resp, err := c.adapter.GetIdemixCredential(user.PrivateKey(), user.EnrollmentCertificate(), caname, provider)
+	if err != nil {
+		return nil, errors.Wrap(err, "get Idemix credential failed")
+	}
+
+	credentialStore, ok := c.userStore.(msp.UserIdemixCredentialStore)
+	if !ok {
+		logger.Warnf("User store does not support Idemix credentials; the Idemix credential of [%s] is not stored", enrollmentID)
+		return resp, nil
+	}
+	credential, err := marshalIdemixCredential(resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshal Idemix credential failed")
+	}
+	if err := credentialStore.StoreIdemixCredential(msp.IdentityIdentifier{ID: enrollmentID, MSPID: c.orgMSPID}, credential); err != nil {
+		return nil, errors.Wrap(err, "storing Idemix credential failed")
+	}
+	return resp, nil
+}
+
+// storedIdemixCredential is the part of an Idemix credential that is stored in the user store.
+// It leaves out the user secret key, since user stores keep their content in plaintext.
+type storedIdemixCredential struct {
+	Credential      []byte
+	Attrs           map[string]interface{}
+	CRI             []byte
+	IssuerPublicKey []byte
+}
+
+// marshalIdemixCredential returns the JSON encoding of the Idemix credential to store, without the secret key
+func marshalIdemixCredential(resp *api.IdemixCredentialResponse) ([]byte, error) {
+	return json.Marshal(&storedIdemixCredential{
+		Credential:      resp.Credential,
+		Attrs:           resp.Attrs,
+		CRI:             resp.CRI,
+		IssuerPublicKey: resp.IssuerPublicKey,
+	})
+}
+
 // GetCAInfo returns generic CA information
 func (c *CAClientImpl) GetCAInfo() (*api.GetCAInfoResponse, error) {
 	if c.adapter == nil {
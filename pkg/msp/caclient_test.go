@@ -7,6 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"crypto/ecdsa"
+	"crypto/tls"
+	"encoding/json"
+	"reflect"
 	"testing"
 
 	"fmt"
@@ -92,6 +96,32 @@ func reenrollWithAppropriateUser(f textFixture, t *testing.T, enrolledUserData *
 }
 
 // TestWrongURL tests creation of CAClient with wrong URL
+func TestMarshalIdemixCredential(t *testing.T) {
+	resp := &api.IdemixCredentialResponse{
+		Credential:      []byte("credential"),
+		SecretKey:       []byte("secret key"),
+		Attrs:           map[string]interface{}{"OU": "org1"},
+		CRI:             []byte("cri"),
+		IssuerPublicKey: []byte("issuer public key"),
+	}
+	credential, err := marshalIdemixCredential(resp)
+	if err != nil {
+		t.Fatalf("marshalIdemixCredential failed: %s", err)
+	}
+	if strings.Contains(string(credential), "SecretKey") {
+		t.Fatalf("Stored Idemix credential must not include the secret key: %s", credential)
+	}
+
+	stored := &api.IdemixCredentialResponse{}
+	if err := json.Unmarshal(credential, stored); err != nil {
+		t.Fatalf("Unmarshal of stored Idemix credential failed: %s", err)
+	}
+	resp.SecretKey = nil
+	if !reflect.DeepEqual(resp, stored) {
+		t.Fatalf("Unexpected stored Idemix credential %+v", stored)
+	}
+}
+
 func TestWrongURL(t *testing.T) {
 
 	f := textFixture{}
@@ -680,6 +710,45 @@ func TestGetCAInfo(t *testing.T) {
 	}
 }
 
+func TestCAClientWithCAName(t *testing.T) {
+	f := textFixture{}
+	f.setup()
+	defer f.close()
+
+	c, err := NewCAClient(org1, f.clientContext, WithCAName("ca2"))
+	if err != nil {
+		t.Fatalf("NewCAClient returned error: %s", err)
+	}
+	if c.caName != "ca2" || c.adapter.caClient.Config.CAName != "ca2" {
+		t.Fatalf("expecting CA name ca2, got %s/%s", c.caName, c.adapter.caClient.Config.CAName)
+	}
+
+	_, err = NewCAClient(org1, f.clientContext, WithCAName(""))
+	if err == nil {
+		t.Fatal("NewCAClient should have failed for empty CA name")
+	}
+}
+
+func TestCAClientWithTLSClientCert(t *testing.T) {
+	f := textFixture{}
+	f.setup()
+	defer f.close()
+
+	cert := tls.Certificate{Certificate: [][]byte{[]byte("cert")}, PrivateKey: &ecdsa.PrivateKey{}}
+	c, err := NewCAClient(org1, f.clientContext, WithTLSClientCert(cert))
+	if err != nil {
+		t.Fatalf("NewCAClient returned error: %s", err)
+	}
+	if c.adapter.caClient.Config.TLSClientCert == nil || !reflect.DeepEqual(*c.adapter.caClient.Config.TLSClientCert, cert) {
+		t.Fatal("expecting TLS client certificate to be set on the CA client config")
+	}
+
+	_, err = NewCAClient(org1, f.clientContext, WithTLSClientCert(tls.Certificate{}))
+	if err == nil {
+		t.Fatal("NewCAClient should have failed for empty TLS client certificate")
+	}
+}
+
 func getCustomBackend(configPath string) ([]core.ConfigBackend, error) {
 
 	configBackends, err := config.FromFile(configPath)()
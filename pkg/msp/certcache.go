@@ -0,0 +1,88 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/x509"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultCertCacheTTL = time.Minute
+
+// certCache holds the certificates read from the MSP cert store, keyed by enrollment ID.
+// An entry is discarded once its TTL has elapsed or the modification time of its file has changed.
+type certCache struct {
+	ttl     time.Duration
+	mutex   sync.RWMutex
+	entries map[string]certCacheEntry
+}
+
+type certCacheEntry struct {
+	raw     []byte
+	cert    *x509.Certificate
+	path    string
+	modTime time.Time
+	expiry  time.Time
+}
+
+func newCertCache(ttl time.Duration) *certCache {
+	return &certCache{
+		ttl:     ttl,
+		entries: make(map[string]certCacheEntry),
+	}
+}
+
+// get returns the PEM-encoded and parsed certificate of the given user if it is cached and still current
+func (c *certCache) get(username string) ([]byte, *x509.Certificate, bool) {
+	if c.ttl <= 0 {
+		return nil, nil, false
+	}
+
+	c.mutex.RLock()
+	entry, ok := c.entries[username]
+	c.mutex.RUnlock()
+	if !ok {
+		return nil, nil, false
+	}
+
+	if time.Now().After(entry.expiry) || !fileUnchanged(entry.path, entry.modTime) {
+		c.mutex.Lock()
+		// The entry may have been replaced in the meantime
+		if current, ok := c.entries[username]; ok && current.expiry == entry.expiry {
+			delete(c.entries, username)
+		}
+		c.mutex.Unlock()
+		return nil, nil, false
+	}
+	return entry.raw, entry.cert, true
+}
+
+// put caches the certificate of the given user, read from the file at path.
+// modTime is the modification time of the file before it was read.
+func (c *certCache) put(username string, raw []byte, cert *x509.Certificate, path string, modTime time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.entries[username] = certCacheEntry{
+		raw:     raw,
+		cert:    cert,
+		path:    path,
+		modTime: modTime,
+		expiry:  time.Now().Add(c.ttl),
+	}
+}
+
+func fileUnchanged(path string, modTime time.Time) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.ModTime().Equal(modTime)
+}
@@ -0,0 +1,81 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certcache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "user1-cert.pem")
+	require.NoError(t, ioutil.WriteFile(path, []byte("cert"), 0600))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+
+	cert := &x509.Certificate{}
+	cache := newCertCache(time.Hour)
+
+	_, _, ok := cache.get("user1")
+	assert.False(t, ok)
+
+	cache.put("user1", []byte("cert"), cert, path, info.ModTime())
+	raw, cached, ok := cache.get("user1")
+	require.True(t, ok)
+	assert.Equal(t, []byte("cert"), raw)
+	assert.Equal(t, cert, cached)
+
+	// Modifying the file invalidates the entry
+	require.NoError(t, os.Chtimes(path, time.Now(), info.ModTime().Add(time.Second)))
+	_, _, ok = cache.get("user1")
+	assert.False(t, ok)
+
+	// Removing the file invalidates the entry
+	cache.put("user1", []byte("cert"), cert, path, info.ModTime())
+	require.NoError(t, os.Remove(path))
+	_, _, ok = cache.get("user1")
+	assert.False(t, ok)
+}
+
+func TestCertCacheTTL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "certcache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "user1-cert.pem")
+	require.NoError(t, ioutil.WriteFile(path, []byte("cert"), 0600))
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+
+	cache := newCertCache(time.Nanosecond)
+	cache.put("user1", []byte("cert"), &x509.Certificate{}, path, info.ModTime())
+	time.Sleep(time.Millisecond)
+	_, _, ok := cache.get("user1")
+	assert.False(t, ok)
+	assert.Empty(t, cache.entries)
+
+	// A TTL of zero disables the cache
+	cache = newCertCache(0)
+	cache.put("user1", []byte("cert"), &x509.Certificate{}, path, info.ModTime())
+	_, _, ok = cache.get("user1")
+	assert.False(t, ok)
+
+	mgr := &IdentityManager{}
+	assert.NoError(t, WithCertCacheTTL(0)(mgr))
+	assert.Error(t, WithCertCacheTTL(-time.Second)(mgr))
+}
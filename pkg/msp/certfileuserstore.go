@@ -7,6 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strings"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/fab/keyvaluestore"
@@ -20,8 +25,39 @@ type CertFileUserStore struct {
 	store core.KVStore
 }
 
+const (
+	certFileSuffix   = "-cert.pem"
+	labelFileSuffix  = "-label.json"
+	idemixFileSuffix = "-idemix.json"
+)
+
+// userLabel is the content of the label side-car file of a user
+type userLabel struct {
+	Label string `json:"label"`
+}
+
 func storeKeyFromUserIdentifier(key msp.IdentityIdentifier) string {
-	return key.ID + "@" + key.MSPID + "-cert.pem"
+	return key.ID + "@" + key.MSPID + certFileSuffix
+}
+
+func labelStoreKeyFromUserIdentifier(key msp.IdentityIdentifier) string {
+	return key.ID + "@" + key.MSPID + labelFileSuffix
+}
+
+func idemixStoreKeyFromUserIdentifier(key msp.IdentityIdentifier) string {
+	return key.ID + "@" + key.MSPID + idemixFileSuffix
+}
+
+func userIdentifierFromStoreKey(key string) (msp.IdentityIdentifier, bool) {
+	if !strings.HasSuffix(key, certFileSuffix) {
+		return msp.IdentityIdentifier{}, false
+	}
+	key = strings.TrimSuffix(key, certFileSuffix)
+	i := strings.LastIndex(key, "@")
+	if i <= 0 || i == len(key)-1 {
+		return msp.IdentityIdentifier{}, false
+	}
+	return msp.IdentityIdentifier{ID: key[:i], MSPID: key[i+1:]}, true
 }
 
 // NewCertFileUserStore1 creates a new instance of CertFileUserStore
@@ -58,14 +94,71 @@ func (s *CertFileUserStore) Load(key msp.IdentityIdentifier) (*msp.UserData, err
 	if !ok {
 		return nil, errors.New("user is not of proper type")
 	}
+	label, err := s.loadLabel(key)
+	if err != nil {
+		return nil, err
+	}
 	userData := &msp.UserData{
 		MSPID:                 key.MSPID,
 		ID:                    key.ID,
 		EnrollmentCertificate: certBytes,
+		Label:                 label,
 	}
 	return userData, nil
 }
 
+func (s *CertFileUserStore) loadLabel(key msp.IdentityIdentifier) (string, error) {
+	value, err := s.store.Load(labelStoreKeyFromUserIdentifier(key))
+	if err != nil {
+		if err == core.ErrKeyValueNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	labelBytes, ok := value.([]byte)
+	if !ok {
+		return "", errors.New("user label is not of proper type")
+	}
+	var label userLabel
+	if err := json.Unmarshal(labelBytes, &label); err != nil {
+		return "", errors.Wrap(err, "unmarshal user label failed")
+	}
+	return label.Label, nil
+}
+
+// StoreLabel stores the label of a User in a JSON file next to its cert file
+func (s *CertFileUserStore) StoreLabel(key msp.IdentityIdentifier, label string) error {
+	if label == "" {
+		return s.store.Delete(labelStoreKeyFromUserIdentifier(key))
+	}
+	labelBytes, err := json.Marshal(&userLabel{Label: label})
+	if err != nil {
+		return errors.Wrap(err, "marshal user label failed")
+	}
+	return s.store.Store(labelStoreKeyFromUserIdentifier(key), labelBytes)
+}
+
+// StoreIdemixCredential stores the Idemix credential of a User in a file next to its cert file
+func (s *CertFileUserStore) StoreIdemixCredential(key msp.IdentityIdentifier, credential []byte) error {
+	return s.store.Store(idemixStoreKeyFromUserIdentifier(key), credential)
+}
+
+// LoadIdemixCredential returns the Idemix credential of a User, or nil if it has none
+func (s *CertFileUserStore) LoadIdemixCredential(key msp.IdentityIdentifier) ([]byte, error) {
+	value, err := s.store.Load(idemixStoreKeyFromUserIdentifier(key))
+	if err != nil {
+		if err == core.ErrKeyValueNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	credential, ok := value.([]byte)
+	if !ok {
+		return nil, errors.New("user Idemix credential is not of proper type")
+	}
+	return credential, nil
+}
+
 // Store stores a User into store
 func (s *CertFileUserStore) Store(user *msp.UserData) error {
 	key := storeKeyFromUserIdentifier(msp.IdentityIdentifier{MSPID: user.MSPID, ID: user.ID})
@@ -74,5 +167,43 @@ func (s *CertFileUserStore) Store(user *msp.UserData) error {
 
 // Delete deletes a User from store
 func (s *CertFileUserStore) Delete(key msp.IdentityIdentifier) error {
+	if err := s.store.Delete(labelStoreKeyFromUserIdentifier(key)); err != nil {
+		return err
+	}
+	if err := s.store.Delete(idemixStoreKeyFromUserIdentifier(key)); err != nil {
+		return err
+	}
 	return s.store.Delete(storeKeyFromUserIdentifier(key))
 }
+
+// LoadAll returns all Users stored in the store.
+// Only stores backed by a FileKeyValueStore can be enumerated.
+func (s *CertFileUserStore) LoadAll() ([]*msp.UserData, error) {
+	fileStore, ok := s.store.(*keyvaluestore.FileKeyValueStore)
+	if !ok {
+		return nil, errors.New("user store does not support enumeration")
+	}
+	files, err := ioutil.ReadDir(fileStore.GetPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading user store directory failed")
+	}
+	var users []*msp.UserData
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		key, ok := userIdentifierFromStoreKey(file.Name())
+		if !ok {
+			continue
+		}
+		user, err := s.Load(key)
+		if err != nil {
+			return nil, errors.WithMessage(err, "loading user failed")
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
@@ -124,6 +124,161 @@ func checkNonExistingKey(store *CertFileUserStore, t *testing.T) {
 	}
 }
 
+func TestLoadAll(t *testing.T) {
+
+	cleanupTestPath(t, storePathRoot)
+	defer cleanupTestPath(t, storePathRoot)
+
+	store, err := NewCertFileUserStore(storePath)
+	if err != nil {
+		t.Fatalf("NewFileKeyValueStore failed [%s]", err)
+	}
+
+	users, err := store.LoadAll()
+	if err != nil || len(users) != 0 {
+		t.Fatalf("LoadAll on empty store should return no users [%v, %s]", users, err)
+	}
+
+	user1 := &msp.UserData{
+		MSPID:                 "Org1",
+		ID:                    "user1@org1.example.com",
+		EnrollmentCertificate: []byte(testCert1),
+	}
+	user2 := &msp.UserData{
+		MSPID:                 "Org2",
+		ID:                    "user2",
+		EnrollmentCertificate: []byte(testCert2),
+	}
+
+	createStore(store, user1, t, user2)
+
+	users, err = store.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll failed [%s]", err)
+	}
+	if len(users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(users))
+	}
+	for _, user := range users {
+		expected := user1
+		if user.ID == user2.ID {
+			expected = user2
+		}
+		if user.ID != expected.ID || user.MSPID != expected.MSPID || !bytes.Equal(user.EnrollmentCertificate, expected.EnrollmentCertificate) {
+			t.Fatalf("unexpected user loaded [%s@%s]", user.ID, user.MSPID)
+		}
+	}
+}
+
+func TestStoreLabel(t *testing.T) {
+
+	cleanupTestPath(t, storePathRoot)
+	defer cleanupTestPath(t, storePathRoot)
+
+	store, err := NewCertFileUserStore(storePath)
+	if err != nil {
+		t.Fatalf("NewFileKeyValueStore failed [%s]", err)
+	}
+
+	user1 := &msp.UserData{
+		MSPID:                 "Org1",
+		ID:                    "user1",
+		EnrollmentCertificate: []byte(testCert1),
+	}
+	if err = store.Store(user1); err != nil {
+		t.Fatalf("Store %s failed [%s]", user1.ID, err)
+	}
+	if err = store.StoreLabel(userIdentifier(user1), "Alice"); err != nil {
+		t.Fatalf("StoreLabel %s failed [%s]", user1.ID, err)
+	}
+
+	// Storing the user again keeps the label
+	if err = store.Store(user1); err != nil {
+		t.Fatalf("Store %s failed [%s]", user1.ID, err)
+	}
+	userData, err := store.Load(userIdentifier(user1))
+	if err != nil {
+		t.Fatalf("Load %s failed [%s]", user1.ID, err)
+	}
+	if userData.Label != "Alice" {
+		t.Fatalf("Expected label Alice, got [%s]", userData.Label)
+	}
+
+	// The label side-car is not listed as a user
+	users, err := store.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll failed [%s]", err)
+	}
+	if len(users) != 1 || users[0].Label != "Alice" {
+		t.Fatalf("Expected one user with label, got %v", users)
+	}
+
+	if err = store.StoreLabel(userIdentifier(user1), ""); err != nil {
+		t.Fatalf("StoreLabel %s failed [%s]", user1.ID, err)
+	}
+	userData, err = store.Load(userIdentifier(user1))
+	if err != nil {
+		t.Fatalf("Load %s failed [%s]", user1.ID, err)
+	}
+	if userData.Label != "" {
+		t.Fatalf("Expected label to be removed, got [%s]", userData.Label)
+	}
+}
+
+func TestStoreIdemixCredential(t *testing.T) {
+
+	cleanupTestPath(t, storePathRoot)
+	defer cleanupTestPath(t, storePathRoot)
+
+	store, err := NewCertFileUserStore(storePath)
+	if err != nil {
+		t.Fatalf("NewFileKeyValueStore failed [%s]", err)
+	}
+
+	user1 := &msp.UserData{
+		MSPID:                 "Org1",
+		ID:                    "user1",
+		EnrollmentCertificate: []byte(testCert1),
+	}
+	if err = store.Store(user1); err != nil {
+		t.Fatalf("Store %s failed [%s]", user1.ID, err)
+	}
+
+	credential, err := store.LoadIdemixCredential(userIdentifier(user1))
+	if err != nil || credential != nil {
+		t.Fatalf("Expected no Idemix credential, got [%s] [%v]", credential, err)
+	}
+
+	if err = store.StoreIdemixCredential(userIdentifier(user1), []byte(`{"Credential":"Y3JlZA=="}`)); err != nil {
+		t.Fatalf("StoreIdemixCredential %s failed [%s]", user1.ID, err)
+	}
+	credential, err = store.LoadIdemixCredential(userIdentifier(user1))
+	if err != nil {
+		t.Fatalf("LoadIdemixCredential %s failed [%s]", user1.ID, err)
+	}
+	if string(credential) != `{"Credential":"Y3JlZA=="}` {
+		t.Fatalf("Unexpected Idemix credential [%s]", credential)
+	}
+
+	// The Idemix credential side-car is not listed as a user
+	users, err := store.LoadAll()
+	if err != nil {
+		t.Fatalf("LoadAll failed [%s]", err)
+	}
+	if len(users) != 1 {
+		t.Fatalf("Expected one user, got %v", users)
+	}
+
+	// Deleting the user deletes its Idemix credential
+	if err = store.Delete(userIdentifier(user1)); err != nil {
+		t.Fatalf("Delete %s failed [%s]", user1.ID, err)
+	}
+	credential, err = store.LoadIdemixCredential(userIdentifier(user1))
+	if err != nil || credential != nil {
+		t.Fatalf("Expected Idemix credential to be deleted, got [%s] [%v]", credential, err)
+	}
+}
+
 func TestCreateNewStore(t *testing.T) {
 
 	_, err := NewCertFileUserStore("")
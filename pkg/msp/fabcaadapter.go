@@ -7,20 +7,40 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
 	"github.com/pkg/errors"
 
 	"encoding/json"
 
+	"github.com/cloudflare/cfssl/csr"
 	caapi "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/api"
 	calib "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/lib"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/lib/client/credential"
 	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/lib/client/credential/x509"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/lib/common"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/sdkpatch/cryptosuitebridge"
+	fabricCaUtil "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
 	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/endpoint"
 	"github.com/hyperledger/fabric-sdk-go/pkg/msp/api"
 )
 
+// caErrGettingUser is the Fabric CA error code returned when an identity cannot be retrieved
+const caErrGettingUser = 63
+
+// isIdentityNotFound checks whether the CA rejected the request because the identity does not exist.
+// The Fabric CA client only reports server errors as text, so the error message is inspected.
+func isIdentityNotFound(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, fmt.Sprintf("Error Code: %d ", caErrGettingUser)) ||
+		strings.Contains(msg, fmt.Sprintf("status code %d ", http.StatusNotFound))
+}
+
 // fabricCAAdapter translates between SDK lingo and native Fabric CA API
 type fabricCAAdapter struct {
 	config      msp.IdentityConfig
@@ -43,6 +63,15 @@ func newFabricCAAdapter(orgName string, cryptoSuite core.CryptoSuite, config msp
 	return a, nil
 }
 
+// init initializes the Fabric CA client. It must be called once the client config is complete.
+func (c *fabricCAAdapter) init() error {
+	err := c.caClient.Init()
+	if err != nil {
+		return errors.Wrap(err, "CA Client init failed")
+	}
+	return nil
+}
+
 // Enroll handles enrollment.
 func (c *fabricCAAdapter) Enroll(request *api.EnrollmentRequest) ([]byte, error) {
 
@@ -66,6 +95,12 @@ func (c *fabricCAAdapter) Enroll(request *api.EnrollmentRequest) ([]byte, error)
 		careq.AttrReqs = attrs
 	}
 
+	careq.CSR = caCSRInfo(request.CSR)
+
+	if request.Key != nil {
+		return c.enrollWithKey(request.Key, careq)
+	}
+
 	caresp, err := c.caClient.Enroll(careq)
 	if err != nil {
 		return nil, errors.WithMessage(err, "enroll failed")
@@ -73,6 +108,67 @@ func (c *fabricCAAdapter) Enroll(request *api.EnrollmentRequest) ([]byte, error)
 	return caresp.Identity.GetECert().Cert(), nil
 }
 
+// enrollWithKey handles enrollment with an existing key pair.
+// The CSR is signed with the provided private key instead of a newly generated one.
+func (c *fabricCAAdapter) enrollWithKey(key core.Key, careq *caapi.EnrollmentRequest) ([]byte, error) {
+
+	logger.Debugf("Enrolling user [%s] with existing key", careq.Name)
+
+	signer, err := cryptosuitebridge.NewCspSigner(c.cryptoSuite, key)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create signer from key")
+	}
+
+	cr := &csr.CertificateRequest{CN: careq.Name}
+	if careq.CSR != nil {
+		if careq.CSR.CN != "" {
+			cr.CN = careq.CSR.CN
+		}
+		cr.Hosts = careq.CSR.Hosts
+	}
+
+	csrPEM, err := csr.Generate(signer, cr)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to generate CSR")
+	}
+
+	reqNet := &caapi.EnrollmentRequestNet{
+		CAName:   careq.CAName,
+		AttrReqs: careq.AttrReqs,
+	}
+	reqNet.SignRequest.Request = string(csrPEM)
+	reqNet.SignRequest.Profile = careq.Profile
+	reqNet.SignRequest.Label = careq.Label
+
+	body, err := json.Marshal(reqNet)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal enrollment request")
+	}
+
+	caURL, err := calib.NormalizeURL(c.caClient.Config.URL)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid CA URL")
+	}
+
+	post, err := http.NewRequest(http.MethodPost, caURL.String()+"/enroll", bytes.NewReader(body))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create enrollment request")
+	}
+	post.SetBasicAuth(careq.Name, careq.Secret)
+
+	var result common.EnrollmentResponseNet
+	err = c.caClient.SendReq(post, &result)
+	if err != nil {
+		return nil, errors.WithMessage(err, "enroll failed")
+	}
+
+	cert, err := fabricCaUtil.B64Decode(result.Cert)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid response format from CA")
+	}
+	return cert, nil
+}
+
 // Reenroll handles re-enrollment
 func (c *fabricCAAdapter) Reenroll(key core.Key, cert []byte, request *api.ReenrollmentRequest) ([]byte, error) {
 
@@ -82,6 +178,7 @@ func (c *fabricCAAdapter) Reenroll(key core.Key, cert []byte, request *api.Reenr
 		CAName:  c.caClient.Config.CAName,
 		Profile: request.Profile,
 		Label:   request.Label,
+		CSR:     caCSRInfo(request.CSR),
 	}
 	if len(request.AttrReqs) > 0 {
 		attrs := make([]*caapi.AttributeRequest, len(request.AttrReqs))
@@ -104,6 +201,18 @@ func (c *fabricCAAdapter) Reenroll(key core.Key, cert []byte, request *api.Reenr
 	return caresp.Identity.GetECert().Cert(), nil
 }
 
+// caCSRInfo returns the Fabric CA CSR info of the given CSR overrides
+func caCSRInfo(csrInfo *api.CSRInfo) *caapi.CSRInfo {
+	if csrInfo == nil {
+		return nil
+	}
+	info := &caapi.CSRInfo{CN: csrInfo.CN, Hosts: csrInfo.Hosts}
+	if csrInfo.KeyRequest != nil {
+		info.KeyRequest = &caapi.BasicKeyRequest{Algo: csrInfo.KeyRequest.Algo, Size: csrInfo.KeyRequest.Size}
+	}
+	return info
+}
+
 // Register handles user registration
 // key: registrar private key
 // cert: registrar enrollment certificate
@@ -149,6 +258,7 @@ func (c *fabricCAAdapter) Revoke(key core.Key, cert []byte, request *api.Revocat
 		Serial: request.Serial,
 		AKI:    request.AKI,
 		Reason: request.Reason,
+		GenCRL: request.GenCRL,
 	}
 
 	registrar, err := c.newIdentity(key, cert)
@@ -176,6 +286,103 @@ func (c *fabricCAAdapter) Revoke(key core.Key, cert []byte, request *api.Revocat
 	}, nil
 }
 
+// GetCRL generates the CRL of the CA
+// key: registrar private key
+// cert: registrar enrollment certificate
+func (c *fabricCAAdapter) GetCRL(key core.Key, cert []byte, caname string) ([]byte, error) {
+	logger.Debugf("Get CRL [%s]", caname)
+
+	reqBody, err := json.Marshal(&caapi.GenCRLRequest{CAName: caname})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal CRL request")
+	}
+
+	registrar, err := c.newIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	var result struct {
+		CRL string
+	}
+	err = registrar.Post("gencrl", reqBody, &result, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get CRL")
+	}
+
+	crl, err := fabricCaUtil.B64Decode(result.CRL)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode CRL")
+	}
+	return crl, nil
+}
+
+// idemixCredentialRequestNet is the request to the /idemix/credential endpoint of the CA.
+// The first request, without a credential request, returns the nonce of the credential request.
+type idemixCredentialRequestNet struct {
+	CredRequest json.RawMessage `json:"request,omitempty"`
+	CAName      string          `json:"caname,omitempty"`
+}
+
+// GetIdemixCredential gets an Idemix credential from the CA, using the x509 identity of the user
+// to authenticate the requests. The credential request is created by provider.
+// key: user private key
+// cert: user enrollment certificate
+func (c *fabricCAAdapter) GetIdemixCredential(key core.Key, cert []byte, caname string, provider api.IdemixProvider) (*api.IdemixCredentialResponse, error) {
+	logger.Debugf("Get Idemix credential [%s]", caname)
+
+	identity, err := c.newIdentity(key, cert)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CA signing identity")
+	}
+
+	reqBody, err := json.Marshal(&idemixCredentialRequestNet{CAName: caname})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal Idemix nonce request")
+	}
+	var nonceResult common.IdemixEnrollmentResponseNet
+	if err := identity.Post("idemix/credential", reqBody, &nonceResult, nil); err != nil {
+		return nil, errors.Wrap(err, "failed to get Idemix credential request nonce")
+	}
+	nonce, err := fabricCaUtil.B64Decode(nonceResult.Nonce)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode Idemix credential request nonce")
+	}
+	issuerPublicKey, err := fabricCaUtil.B64Decode(nonceResult.CAInfo.IssuerPublicKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode Idemix issuer public key")
+	}
+
+	credRequest, secretKey, err := provider.NewCredentialRequest(issuerPublicKey, nonce)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create Idemix credential request")
+	}
+	reqBody, err = json.Marshal(&idemixCredentialRequestNet{CredRequest: credRequest, CAName: caname})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal Idemix credential request")
+	}
+	var result common.IdemixEnrollmentResponseNet
+	if err := identity.Post("idemix/credential", reqBody, &result, nil); err != nil {
+		return nil, errors.Wrap(err, "failed to get Idemix credential")
+	}
+
+	credential, err := fabricCaUtil.B64Decode(result.Credential)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode Idemix credential")
+	}
+	cri, err := fabricCaUtil.B64Decode(result.CRI)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode Idemix credential revocation information")
+	}
+	return &api.IdemixCredentialResponse{
+		Credential:      credential,
+		SecretKey:       secretKey,
+		Attrs:           result.Attrs,
+		CRI:             cri,
+		IssuerPublicKey: issuerPublicKey,
+	}, nil
+}
+
 // GetCAInfo returns generic CA information
 func (c *fabricCAAdapter) GetCAInfo(caname string) (*api.GetCAInfoResponse, error) {
 	logger.Debugf("Get CA info [%s]", caname)
@@ -332,6 +539,9 @@ func (c *fabricCAAdapter) GetIdentity(key core.Key, cert []byte, id, caname stri
 
 	response, err := registrar.GetIdentity(id, caname)
 	if err != nil {
+		if isIdentityNotFound(err) {
+			return nil, msp.ErrUserNotFound
+		}
 		return nil, errors.Wrap(err, "failed to get identity")
 	}
 
@@ -619,10 +829,5 @@ func createFabricCAClient(org string, cryptoSuite core.CryptoSuite, config msp.I
 	//Factory opts
 	c.Config.CSP = cryptoSuite
 
-	err := c.Init()
-	if err != nil {
-		return nil, errors.Wrap(err, "CA Client init failed")
-	}
-
 	return c, nil
 }
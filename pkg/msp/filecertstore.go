@@ -19,7 +19,6 @@ import (
 
 // NewFileCertStore ...
 func NewFileCertStore(cryptoConfigMSPPath string) (core.KVStore, error) {
-	_, orgName := path.Split(path.Dir(path.Dir(path.Dir(cryptoConfigMSPPath))))
 	opts := &keyvaluestore.FileKeyValueStoreOptions{
 		Path: cryptoConfigMSPPath,
 		KeySerializer: func(key interface{}) (string, error) {
@@ -27,15 +26,21 @@ func NewFileCertStore(cryptoConfigMSPPath string) (core.KVStore, error) {
 			if !ok {
 				return "", errors.New("converting key to CertKey failed")
 			}
-			if ck == nil || ck.MSPID == "" || ck.ID == "" {
-				return "", errors.New("invalid key")
-			}
-
-			// TODO: refactor to case insensitive or remove eventually.
-			r := strings.NewReplacer("{userName}", ck.ID, "{username}", ck.ID)
-			certDir := path.Join(r.Replace(cryptoConfigMSPPath), "signcerts")
-			return path.Join(certDir, fmt.Sprintf("%s@%s-cert.pem", ck.ID, orgName)), nil
+			return certFilePath(cryptoConfigMSPPath, ck)
 		},
 	}
 	return keyvaluestore.New(opts)
 }
+
+// certFilePath returns the path of the cert file of the given identity in the MSP directory
+func certFilePath(cryptoConfigMSPPath string, ck *msp.IdentityIdentifier) (string, error) {
+	if ck == nil || ck.MSPID == "" || ck.ID == "" {
+		return "", errors.New("invalid key")
+	}
+
+	_, orgName := path.Split(path.Dir(path.Dir(path.Dir(cryptoConfigMSPPath))))
+	// TODO: refactor to case insensitive or remove eventually.
+	r := strings.NewReplacer("{userName}", ck.ID, "{username}", ck.ID)
+	certDir := path.Join(r.Replace(cryptoConfigMSPPath), "signcerts")
+	return path.Join(certDir, fmt.Sprintf("%s@%s-cert.pem", ck.ID, orgName)), nil
+}
@@ -7,9 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/sdkpatch/cryptosuitebridge"
 	fabricCaUtil "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
@@ -32,6 +37,7 @@ func newUser(userData *msp.UserData, cryptoSuite core.CryptoSuite) (*User, error
 		mspID:                 userData.MSPID,
 		enrollmentCertificate: userData.EnrollmentCertificate,
 		privateKey:            pk,
+		label:                 userData.Label,
 	}
 	return u, nil
 }
@@ -114,9 +120,10 @@ func (mgr *IdentityManager) GetUser(username string) (*User, error) { //nolint
 	}
 
 	if u == nil {
+		var x509Cert *x509.Certificate
 		certBytes := mgr.getEmbeddedCertBytes(username)
 		if certBytes == nil {
-			certBytes, err = mgr.getCertBytesFromCertStore(username)
+			certBytes, x509Cert, err = mgr.getCertFromCertStore(username)
 			if err != nil && err != msp.ErrUserNotFound {
 				return nil, errors.WithMessage(err, "fetching cert from store failed")
 			}
@@ -129,7 +136,7 @@ func (mgr *IdentityManager) GetUser(username string) (*User, error) { //nolint
 			return nil, errors.WithMessage(err, "fetching embedded private key failed")
 		}
 		if privateKey == nil {
-			privateKey, err = mgr.getPrivateKeyFromCert(username, certBytes)
+			privateKey, err = mgr.getPrivateKeyFromCert(username, certBytes, x509Cert)
 			if err != nil {
 				return nil, errors.WithMessage(err, "getting private key from cert failed")
 			}
@@ -194,6 +201,41 @@ func (mgr *IdentityManager) getPrivateKeyPemFromKeyStore(username string, ski []
 	return keyBytes, nil
 }
 
+// getCertFromCertStore returns the PEM-encoded cert of the user from the cert store, along with
+// the parsed cert if it is valid. Valid certs are cached until their file is modified.
+func (mgr *IdentityManager) getCertFromCertStore(username string) ([]byte, *x509.Certificate, error) {
+	if certBytes, cert, ok := mgr.certCache.get(username); ok {
+		return certBytes, cert, nil
+	}
+
+	// The file is checked before it is read, so that a concurrent modification invalidates the entry
+	var modTime time.Time
+	path, err := certFilePath(mgr.mspCertPath, &msp.IdentityIdentifier{ID: username, MSPID: mgr.orgMSPID})
+	if err == nil {
+		if info, err := os.Stat(path); err == nil {
+			modTime = info.ModTime()
+		}
+	}
+
+	certBytes, err := mgr.getCertBytesFromCertStore(username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, _ := pem.Decode(certBytes)
+	if block == nil {
+		return certBytes, nil, nil
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return certBytes, nil, nil
+	}
+	if !modTime.IsZero() {
+		mgr.certCache.put(username, certBytes, cert, path, modTime)
+	}
+	return certBytes, cert, nil
+}
+
 func (mgr *IdentityManager) getCertBytesFromCertStore(username string) ([]byte, error) {
 	if mgr.mspCertStore == nil {
 		return nil, msp.ErrUserNotFound
@@ -215,11 +257,18 @@ func (mgr *IdentityManager) getCertBytesFromCertStore(username string) ([]byte,
 	return certBytes, nil
 }
 
-func (mgr *IdentityManager) getPrivateKeyFromCert(username string, cert []byte) (core.Key, error) {
+// getPrivateKeyFromCert returns the private key matching the cert. x509Cert is the parsed cert, if available.
+func (mgr *IdentityManager) getPrivateKeyFromCert(username string, cert []byte, x509Cert *x509.Certificate) (core.Key, error) {
 	if cert == nil {
 		return nil, errors.New("cert is nil")
 	}
-	pubKey, err := cryptoutil.GetPublicKeyFromCert(cert, mgr.cryptoSuite)
+	var pubKey core.Key
+	var err error
+	if x509Cert != nil {
+		pubKey, err = mgr.cryptoSuite.KeyImport(x509Cert, cryptosuitebridge.GetX509PublicKeyImportOpts(true))
+	} else {
+		pubKey, err = cryptoutil.GetPublicKeyFromCert(cert, mgr.cryptoSuite)
+	}
 	if err != nil {
 		return nil, errors.WithMessage(err, "fetching public key from cert failed")
 	}
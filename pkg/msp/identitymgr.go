@@ -7,8 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"crypto/x509"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -26,11 +29,29 @@ type IdentityManager struct {
 	embeddedUsers   map[string]fab.CertKeyPair
 	mspPrivKeyStore core.KVStore
 	mspCertStore    core.KVStore
+	mspCertPath     string
+	certCache       *certCache
 	userStore       msp.UserStore
 }
 
+// IdentityManagerOption describes a functional parameter for NewIdentityManager
+type IdentityManagerOption func(*IdentityManager) error
+
+// WithCertCacheTTL sets how long the certificates read from the organization's MSP directory
+// are cached (default one minute). A cached certificate is read again as soon as its file
+// is modified. A TTL of zero disables the cache.
+func WithCertCacheTTL(ttl time.Duration) IdentityManagerOption {
+	return func(mgr *IdentityManager) error {
+		if ttl < 0 {
+			return errors.New("cert cache TTL must not be negative")
+		}
+		mgr.certCache = newCertCache(ttl)
+		return nil
+	}
+}
+
 // NewIdentityManager creates a new instance of IdentityManager
-func NewIdentityManager(orgName string, userStore msp.UserStore, cryptoSuite core.CryptoSuite, endpointConfig fab.EndpointConfig) (*IdentityManager, error) {
+func NewIdentityManager(orgName string, userStore msp.UserStore, cryptoSuite core.CryptoSuite, endpointConfig fab.EndpointConfig, opts ...IdentityManagerOption) (*IdentityManager, error) {
 
 	netConfig := endpointConfig.NetworkConfig()
 	// viper keys are case insensitive
@@ -71,9 +92,130 @@ func NewIdentityManager(orgName string, userStore msp.UserStore, cryptoSuite cor
 		cryptoSuite:     cryptoSuite,
 		mspPrivKeyStore: mspPrivKeyStore,
 		mspCertStore:    mspCertStore,
+		mspCertPath:     orgCryptoPathTemplate,
+		certCache:       newCertCache(defaultCertCacheTTL),
 		embeddedUsers:   orgConfig.Users,
 		userStore:       userStore,
 		// CA Client state is created lazily, when (if) needed
 	}
+
+	for _, opt := range opts {
+		if err := opt(mgr); err != nil {
+			return nil, errors.WithMessage(err, "failed to create identity manager")
+		}
+	}
 	return mgr, nil
 }
+
+// ListUsers returns the sorted enrollment IDs of the organization's users that have both an
+// enrollment certificate and a private key. Users are collected from the embedded users of the
+// network config, the user store (which must implement UserStoreLister) and the MSP directory.
+func (mgr *IdentityManager) ListUsers() ([]string, error) {
+	names := make(map[string]bool)
+
+	for name, user := range mgr.embeddedUsers {
+		if user.Cert != nil && user.Key != nil {
+			names[name] = true
+		}
+	}
+
+	if mgr.userStore != nil {
+		lister, ok := mgr.userStore.(msp.UserStoreLister)
+		if !ok {
+			return nil, errors.New("user store does not support listing users")
+		}
+		users, err := lister.LoadAll()
+		if err != nil {
+			return nil, errors.WithMessage(err, "loading users from store failed")
+		}
+		for _, user := range users {
+			if user.MSPID == mgr.orgMSPID && mgr.hasPrivateKey(user.ID, user.EnrollmentCertificate, nil) {
+				names[user.ID] = true
+			}
+		}
+	}
+
+	for _, name := range mgr.mspDirUserNames() {
+		certBytes, cert, err := mgr.getCertFromCertStore(name)
+		if err != nil {
+			logger.Debugf("Skipping user [%s] of MSP directory: %s", name, err)
+			continue
+		}
+		if mgr.hasPrivateKey(name, certBytes, cert) {
+			names[name] = true
+		}
+	}
+
+	list := make([]string, 0, len(names))
+	for name := range names {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+// hasPrivateKey checks whether the private key matching the user's cert is available
+func (mgr *IdentityManager) hasPrivateKey(username string, certBytes []byte, cert *x509.Certificate) bool {
+	key, err := mgr.getPrivateKeyFromCert(username, certBytes, cert)
+	return err == nil && key != nil && key.Private()
+}
+
+// mspDirUserNames returns the user names found in the MSP directory, if its path contains a user name placeholder
+func (mgr *IdentityManager) mspDirUserNames() []string {
+	if mgr.mspCertStore == nil {
+		return nil
+	}
+	var placeholder string
+	for _, p := range []string{"{username}", "{userName}"} {
+		if strings.Contains(mgr.mspCertPath, p) {
+			placeholder = p
+			break
+		}
+	}
+	if placeholder == "" {
+		return nil
+	}
+
+	i := strings.Index(mgr.mspCertPath, placeholder)
+	prefix, suffix := mgr.mspCertPath[:i], mgr.mspCertPath[i+len(placeholder):]
+	matches, err := filepath.Glob(prefix + "*" + suffix)
+	if err != nil {
+		logger.Warnf("Failed to scan MSP directory [%s]: %s", mgr.mspCertPath, err)
+		return nil
+	}
+
+	var names []string
+	for _, m := range matches {
+		name := strings.TrimSuffix(strings.TrimPrefix(m, prefix), suffix)
+		if name != "" && !strings.ContainsRune(name, filepath.Separator) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// SetUserLabel sets the label of a user in the user store, which must implement UserLabelStore.
+// An empty label removes the label.
+func (mgr *IdentityManager) SetUserLabel(enrollmentID, label string) error {
+	labelStore, ok := mgr.userStore.(msp.UserLabelStore)
+	if !ok {
+		return errors.New("user store does not support labels")
+	}
+	id := msp.IdentityIdentifier{ID: enrollmentID, MSPID: mgr.orgMSPID}
+	if _, err := mgr.userStore.Load(id); err != nil {
+		return err
+	}
+	return labelStore.StoreLabel(id, label)
+}
+
+// GetUserLabel returns the label of a user in the user store, or an empty string if it has no label
+func (mgr *IdentityManager) GetUserLabel(enrollmentID string) (string, error) {
+	if mgr.userStore == nil {
+		return "", msp.ErrUserNotFound
+	}
+	userData, err := mgr.userStore.Load(msp.IdentityIdentifier{ID: enrollmentID, MSPID: mgr.orgMSPID})
+	if err != nil {
+		return "", err
+	}
+	return userData.Label, nil
+}
@@ -7,9 +7,26 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	fabricCaUtil "github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric-ca/util"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/fab"
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/config/cryptoutil"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TODO Add tests
@@ -24,3 +41,95 @@ func TestManagerInterfaces(t *testing.T) {
 		t.Fatal("this shouldn't happen.")
 	}
 }
+
+func TestListUsers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "listusers")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cs, err := sw.GetSuite(256, "SHA2", NewMemoryKeyStore(nil))
+	require.NoError(t, err)
+
+	mspPath := filepath.Join(dir, "peerOrganizations/org1.example.com/users/{username}@org1.example.com/msp")
+	keyStore, err := NewFileKeyStore(mspPath)
+	require.NoError(t, err)
+	certStore, err := NewFileCertStore(mspPath)
+	require.NoError(t, err)
+
+	userStore := NewMemoryUserStore()
+	mgr := &IdentityManager{
+		orgMSPID:        "Org1MSP",
+		cryptoSuite:     cs,
+		embeddedUsers:   map[string]fab.CertKeyPair{"admin": {Cert: []byte("cert"), Key: []byte("key")}, "nokey": {Cert: []byte("cert")}},
+		mspPrivKeyStore: keyStore,
+		mspCertStore:    certStore,
+		mspCertPath:     mspPath,
+		certCache:       newCertCache(time.Minute),
+		userStore:       userStore,
+	}
+
+	// User in the user store with a private key in the crypto suite
+	cert, key := newTestCertAndKey(t)
+	_, err = fabricCaUtil.ImportBCCSPKeyFromPEMBytes(key, cs, false)
+	require.NoError(t, err)
+	require.NoError(t, userStore.Store(&msp.UserData{ID: "user1", MSPID: "Org1MSP", EnrollmentCertificate: cert}))
+
+	// Users without a private key or of another organization are not listed
+	cert2, _ := newTestCertAndKey(t)
+	require.NoError(t, userStore.Store(&msp.UserData{ID: "user2", MSPID: "Org1MSP", EnrollmentCertificate: cert2}))
+	require.NoError(t, userStore.Store(&msp.UserData{ID: "user3", MSPID: "Org2MSP", EnrollmentCertificate: cert}))
+
+	// User in the MSP directory
+	cert4, key4 := newTestCertAndKey(t)
+	pubKey, err := cryptoutil.GetPublicKeyFromCert(cert4, cs)
+	require.NoError(t, err)
+	require.NoError(t, certStore.Store(&msp.IdentityIdentifier{ID: "user4", MSPID: "Org1MSP"}, cert4))
+	require.NoError(t, keyStore.Store(&msp.PrivKeyKey{ID: "user4", MSPID: "Org1MSP", SKI: pubKey.SKI()}, key4))
+
+	users, err := mgr.ListUsers()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admin", "user1", "user4"}, users)
+
+	mgr.userStore = struct{ msp.UserStore }{userStore}
+	_, err = mgr.ListUsers()
+	assert.Error(t, err)
+}
+
+func TestUserLabel(t *testing.T) {
+	userStore := NewMemoryUserStore()
+	mgr := &IdentityManager{orgMSPID: "Org1MSP", userStore: userStore}
+
+	assert.Equal(t, msp.ErrUserNotFound, mgr.SetUserLabel("user1", "Alice"))
+
+	require.NoError(t, userStore.Store(&msp.UserData{ID: "user1", MSPID: "Org1MSP", EnrollmentCertificate: []byte("cert")}))
+	label, err := mgr.GetUserLabel("user1")
+	require.NoError(t, err)
+	assert.Empty(t, label)
+
+	require.NoError(t, mgr.SetUserLabel("user1", "Alice"))
+	label, err = mgr.GetUserLabel("user1")
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", label)
+
+	_, err = mgr.GetUserLabel("user2")
+	assert.Equal(t, msp.ErrUserNotFound, err)
+
+	mgr.userStore = struct{ msp.UserStore }{userStore}
+	assert.Error(t, mgr.SetUserLabel("user1", "Bob"))
+}
+
+func newTestCertAndKey(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+}
@@ -45,6 +45,7 @@ type textFixture struct {
 	userStore               msp.UserStore
 	caClient                mspapi.CAClient
 	identityManagerProvider msp.IdentityManagerProvider
+	clientContext           *context.Client
 }
 
 var caServer = &mockmsp.MockFabricCAServer{}
@@ -123,6 +124,7 @@ func (f *textFixture) setup(configBackend ...core.ConfigBackend) { //nolint
 		context.WithIdentityConfig(f.identityConfig))
 
 	ctx := &context.Client{Providers: ctxProvider}
+	f.clientContext = ctx
 
 	if err != nil {
 		panic(fmt.Sprintf("failed to created context for test setup: %s", err))
@@ -7,36 +7,151 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+
 	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
 )
 
-// MemoryUserStore is in-memory implementation of UserStore
+// MemoryUserStore is in-memory implementation of UserStore.
+// It is safe for concurrent use.
 type MemoryUserStore struct {
-	store map[string][]byte
+	store  sync.Map
+	labels sync.Map
+}
+
+// memoryUser is the JSON representation of a user in MemoryUserStore
+type memoryUser struct {
+	ID    string `json:"id"`
+	MSPID string `json:"mspId"`
+	// EnrollmentCertificate is the PEM-encoded enrollment certificate
+	EnrollmentCertificate string `json:"enrollmentCertificate"`
+	Label                 string `json:"label,omitempty"`
 }
 
 // NewMemoryUserStore creates a new MemoryUserStore instance
 func NewMemoryUserStore() *MemoryUserStore {
-	store := make(map[string][]byte)
-	return &MemoryUserStore{store: store}
+	return &MemoryUserStore{}
+}
+
+func memoryUserStoreKey(id msp.IdentityIdentifier) string {
+	return id.ID + "@" + id.MSPID
 }
 
 // Store stores a user into store
 func (s *MemoryUserStore) Store(user *msp.UserData) error {
-	s.store[user.ID+"@"+user.MSPID] = user.EnrollmentCertificate
+	s.store.Store(memoryUserStoreKey(msp.IdentityIdentifier{ID: user.ID, MSPID: user.MSPID}), user.EnrollmentCertificate)
 	return nil
 }
 
 // Load loads a user from store
 func (s *MemoryUserStore) Load(id msp.IdentityIdentifier) (*msp.UserData, error) {
-	cert, ok := s.store[id.ID+"@"+id.MSPID]
+	cert, ok := s.store.Load(memoryUserStoreKey(id))
 	if !ok {
 		return nil, msp.ErrUserNotFound
 	}
 	userData := msp.UserData{
 		ID:                    id.ID,
 		MSPID:                 id.MSPID,
-		EnrollmentCertificate: cert,
+		EnrollmentCertificate: cert.([]byte),
+		Label:                 s.label(memoryUserStoreKey(id)),
 	}
 	return &userData, nil
 }
+
+// StoreLabel stores the label of a user
+func (s *MemoryUserStore) StoreLabel(id msp.IdentityIdentifier, label string) error {
+	if label == "" {
+		s.labels.Delete(memoryUserStoreKey(id))
+		return nil
+	}
+	s.labels.Store(memoryUserStoreKey(id), label)
+	return nil
+}
+
+func (s *MemoryUserStore) label(key string) string {
+	label, ok := s.labels.Load(key)
+	if !ok {
+		return ""
+	}
+	return label.(string)
+}
+
+// Delete deletes a user from store
+func (s *MemoryUserStore) Delete(id msp.IdentityIdentifier) error {
+	s.store.Delete(memoryUserStoreKey(id))
+	s.labels.Delete(memoryUserStoreKey(id))
+	return nil
+}
+
+// LoadAll loads all users from store
+func (s *MemoryUserStore) LoadAll() ([]*msp.UserData, error) {
+	var users []*msp.UserData
+	s.store.Range(func(key, cert interface{}) bool {
+		k := key.(string)
+		i := strings.LastIndex(k, "@")
+		users = append(users, &msp.UserData{
+			ID:                    k[:i],
+			MSPID:                 k[i+1:],
+			EnrollmentCertificate: cert.([]byte),
+			Label:                 s.label(k),
+		})
+		return true
+	})
+	return users, nil
+}
+
+// MarshalJSON encodes the users in the store as a JSON array, sorted by MSP ID and user ID
+func (s *MemoryUserStore) MarshalJSON() ([]byte, error) {
+	users, err := s.LoadAll()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if users[i].MSPID != users[j].MSPID {
+			return users[i].MSPID < users[j].MSPID
+		}
+		return users[i].ID < users[j].ID
+	})
+
+	jsonUsers := make([]memoryUser, 0, len(users))
+	for _, user := range users {
+		jsonUsers = append(jsonUsers, memoryUser{
+			ID:                    user.ID,
+			MSPID:                 user.MSPID,
+			EnrollmentCertificate: string(user.EnrollmentCertificate),
+			Label:                 user.Label,
+		})
+	}
+	return json.Marshal(jsonUsers)
+}
+
+// UnmarshalJSON adds the users of a JSON array produced by MarshalJSON to the store,
+// e.g. to seed a test fixture
+func (s *MemoryUserStore) UnmarshalJSON(data []byte) error {
+	var jsonUsers []memoryUser
+	if err := json.Unmarshal(data, &jsonUsers); err != nil {
+		return errors.Wrap(err, "failed to unmarshal users")
+	}
+	for _, user := range jsonUsers {
+		if user.ID == "" || user.MSPID == "" {
+			return errors.New("user ID and MSP ID are required")
+		}
+		err := s.Store(&msp.UserData{
+			ID:                    user.ID,
+			MSPID:                 user.MSPID,
+			EnrollmentCertificate: []byte(user.EnrollmentCertificate),
+		})
+		if err != nil {
+			return err
+		}
+		err = s.StoreLabel(msp.IdentityIdentifier{ID: user.ID, MSPID: user.MSPID}, user.Label)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
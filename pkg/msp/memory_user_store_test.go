@@ -0,0 +1,67 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryUserStoreConcurrency(t *testing.T) {
+	store := NewMemoryUserStore()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id := msp.IdentityIdentifier{ID: fmt.Sprintf("user%d", i), MSPID: "Org1MSP"}
+			assert.NoError(t, store.Store(&msp.UserData{ID: id.ID, MSPID: id.MSPID, EnrollmentCertificate: []byte("cert")}))
+			_, err := store.Load(id)
+			assert.NoError(t, err)
+			_, err = store.LoadAll()
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	users, err := store.LoadAll()
+	require.NoError(t, err)
+	assert.Len(t, users, 10)
+
+	require.NoError(t, store.Delete(msp.IdentityIdentifier{ID: "user1", MSPID: "Org1MSP"}))
+	_, err = store.Load(msp.IdentityIdentifier{ID: "user1", MSPID: "Org1MSP"})
+	assert.Equal(t, msp.ErrUserNotFound, err)
+}
+
+func TestMemoryUserStoreJSON(t *testing.T) {
+	store := NewMemoryUserStore()
+	require.NoError(t, store.Store(&msp.UserData{ID: "user2", MSPID: "Org1MSP", EnrollmentCertificate: []byte("cert2")}))
+	require.NoError(t, store.Store(&msp.UserData{ID: "user1", MSPID: "Org1MSP", EnrollmentCertificate: []byte("cert1")}))
+
+	data, err := json.Marshal(store)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"id": "user1", "mspId": "Org1MSP", "enrollmentCertificate": "cert1"},
+		{"id": "user2", "mspId": "Org1MSP", "enrollmentCertificate": "cert2"}
+	]`, string(data))
+
+	seeded := NewMemoryUserStore()
+	require.NoError(t, json.Unmarshal(data, seeded))
+	user, err := seeded.Load(msp.IdentityIdentifier{ID: "user2", MSPID: "Org1MSP"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("cert2"), user.EnrollmentCertificate)
+
+	assert.Error(t, json.Unmarshal([]byte(`[{"id": "user1"}]`), seeded))
+	assert.Error(t, json.Unmarshal([]byte(`{}`), seeded))
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package mockmsp
 
 import (
+	"encoding/json"
 	"net"
 	"net/http"
 	"time"
@@ -43,6 +44,15 @@ AiaiI2BjxnL3/TetJ8iFJYZyWvK//an13WV/AiARBJd/pI5A7KZgQxJhXmmR8bie
 XdsmTcdRvJ3TS/6HCA==
 -----END CERTIFICATE-----`
 
+// CRL returned by the mock server, revoking certificate with serial 1234
+const crl = `-----BEGIN X509 CRL-----
+MIHjMIGJAgEBMAoGCCqGSM49BAMCMB4xHDAaBgNVBAMTE2NhLm9yZzEuZXhhbXBs
+ZS5jb20XDTE4MDYwMTAwMDAwMFoXDTM4MDEwMTAwMDAwMFowFTATAgIE0hcNMTgw
+NjAxMDAwMDAwWqAjMCEwHwYDVR0jBBgwFoAUZO3c/tZlojAUQkRX4QfPezzOdK8w
+CgYIKoZIzj0EAwIDSQAwRgIhAOFRWOyQHuet70E9PClsokXCuRL0yqLw9V8yxa6/
+7Q+cAiEAuaSqjucOHxErGa2KIr3em3c+XlH67XrTKoGF+Lqsqvk=
+-----END X509 CRL-----`
+
 var logger = logging.NewLogger("fabsdk/msp")
 
 // The enrollment response from the server
@@ -87,9 +97,11 @@ func (s *MockFabricCAServer) Start(lis net.Listener, cryptoSuite core.CryptoSuit
 	http.HandleFunc("/revoke", s.revoke)
 	http.HandleFunc("/identities", s.identities)
 	http.HandleFunc("/identities/123", s.identity)
+	http.HandleFunc("/identities/", s.unknownIdentity)
 	http.HandleFunc("/affiliations", s.affiliations)
 	http.HandleFunc("/affiliations/123", s.affiliation)
 	http.HandleFunc("/cainfo", s.cainfo)
+	http.HandleFunc("/gencrl", s.gencrl)
 
 	server := &http.Server{
 		Addr:      addr,
@@ -137,6 +149,14 @@ func (s *MockFabricCAServer) revoke(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// Generate CRL
+func (s *MockFabricCAServer) gencrl(w http.ResponseWriter, req *http.Request) {
+	resp := &api.GenCRLResponse{CRL: []byte(crl)}
+	if err := cfsslapi.SendResponse(w, resp); err != nil {
+		logger.Error(err)
+	}
+}
+
 // Enroll user
 func (s *MockFabricCAServer) enroll(w http.ResponseWriter, req *http.Request) {
 	if err := s.addKeyToKeyStore([]byte(privateKey)); err != nil {
@@ -207,6 +227,15 @@ func (s *MockFabricCAServer) identities(w http.ResponseWriter, req *http.Request
 
 }
 
+// Handler for identities that are not known to the mock server
+func (s *MockFabricCAServer) unknownIdentity(w http.ResponseWriter, req *http.Request) {
+	w.WriteHeader(http.StatusNotFound)
+	resp := cfsslapi.NewErrorResponse("Failed to get user", 63)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		logger.Error(err)
+	}
+}
+
 func (s *MockFabricCAServer) affiliations(w http.ResponseWriter, req *http.Request) {
 	switch req.Method {
 	case http.MethodPost:
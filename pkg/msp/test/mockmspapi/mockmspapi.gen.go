@@ -138,6 +138,36 @@ func (mr *MockCAClientMockRecorder) GetCAInfo() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCAInfo", reflect.TypeOf((*MockCAClient)(nil).GetCAInfo))
 }
 
+// GetCRL mocks base method
+func (m *MockCAClient) GetCRL(arg0 string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCRL", arg0)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCRL indicates an expected call of GetCRL
+func (mr *MockCAClientMockRecorder) GetCRL(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCRL", reflect.TypeOf((*MockCAClient)(nil).GetCRL), arg0)
+}
+
+// GetIdemixCredential mocks base method
+func (m *MockCAClient) GetIdemixCredential(arg0, arg1 string, arg2 api.IdemixProvider) (*api.IdemixCredentialResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIdemixCredential", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*api.IdemixCredentialResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIdemixCredential indicates an expected call of GetIdemixCredential
+func (mr *MockCAClientMockRecorder) GetIdemixCredential(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIdemixCredential", reflect.TypeOf((*MockCAClient)(nil).GetIdemixCredential), arg0, arg1, arg2)
+}
+
 // GetIdentity mocks base method
 func (m *MockCAClient) GetIdentity(arg0, arg1 string) (*api.IdentityResponse, error) {
 	m.ctrl.T.Helper()
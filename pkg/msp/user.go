@@ -21,6 +21,7 @@ type User struct {
 	mspID                 string
 	enrollmentCertificate []byte
 	privateKey            core.Key
+	label                 string
 }
 
 // Identifier returns user identifier
@@ -51,6 +52,11 @@ func (u *User) EnrollmentCertificate() []byte {
 	return u.enrollmentCertificate
 }
 
+// Label returns the display name of the user, if one is set in the user store
+func (u *User) Label() string {
+	return u.label
+}
+
 // PrivateKey returns the crypto suite representation of the private key
 func (u *User) PrivateKey() core.Key {
 	return u.privateKey
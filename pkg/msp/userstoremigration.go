@@ -0,0 +1,94 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/errors/multi"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+)
+
+// MigrateUserStore copies all users from src to dst and returns the number of users copied.
+// src must implement UserStoreLister and dst must implement UserStoreDeleter.
+// Labels are copied if dst implements UserLabelStore.
+// If a user cannot be copied, the users already written to dst are rolled back:
+// users that did not exist in dst are deleted and overwritten users are restored.
+func MigrateUserStore(src msp.UserStore, dst msp.UserStore) (int, error) {
+	lister, ok := src.(msp.UserStoreLister)
+	if !ok {
+		return 0, errors.New("source user store does not support listing users")
+	}
+	deleter, ok := dst.(msp.UserStoreDeleter)
+	if !ok {
+		return 0, errors.New("destination user store does not support deleting users")
+	}
+
+	users, err := lister.LoadAll()
+	if err != nil {
+		return 0, errors.WithMessage(err, "failed to load users from source user store")
+	}
+
+	var written []migratedUser
+	for _, user := range users {
+		id := msp.IdentityIdentifier{ID: user.ID, MSPID: user.MSPID}
+		previous, err := dst.Load(id)
+		if err != nil && err != msp.ErrUserNotFound {
+			err = errors.WithMessage(err, fmt.Sprintf("failed to load user [%s] from destination user store", id.ID))
+			return 0, rollbackMigration(dst, deleter, written, err)
+		}
+		if err := dst.Store(user); err != nil {
+			err = errors.WithMessage(err, fmt.Sprintf("failed to store user [%s] in destination user store", id.ID))
+			return 0, rollbackMigration(dst, deleter, written, err)
+		}
+		written = append(written, migratedUser{id: id, previous: previous})
+		if err := migrateLabel(dst, id, user.Label); err != nil {
+			err = errors.WithMessage(err, fmt.Sprintf("failed to store label of user [%s] in destination user store", id.ID))
+			return 0, rollbackMigration(dst, deleter, written, err)
+		}
+	}
+
+	logger.Debugf("Migrated %d users", len(written))
+	return len(written), nil
+}
+
+// migratedUser is a user written to the destination store, with the user it replaced, if any
+type migratedUser struct {
+	id       msp.IdentityIdentifier
+	previous *msp.UserData
+}
+
+// rollbackMigration undoes the writes to dst in reverse order and returns cause
+// combined with any error encountered during the rollback
+func rollbackMigration(dst msp.UserStore, deleter msp.UserStoreDeleter, written []migratedUser, cause error) error {
+	errs := cause
+	for i := len(written) - 1; i >= 0; i-- {
+		var err error
+		if previous := written[i].previous; previous != nil {
+			err = dst.Store(previous)
+			if err == nil {
+				err = migrateLabel(dst, written[i].id, previous.Label)
+			}
+		} else {
+			err = deleter.Delete(written[i].id)
+		}
+		if err != nil {
+			errs = multi.Append(errs, errors.WithMessage(err, fmt.Sprintf("failed to roll back user [%s]", written[i].id.ID)))
+		}
+	}
+	return errs
+}
+
+// migrateLabel stores the label of a user if the destination store supports labels
+func migrateLabel(dst msp.UserStore, id msp.IdentityIdentifier, label string) error {
+	labelStore, ok := dst.(msp.UserLabelStore)
+	if !ok {
+		return nil
+	}
+	return labelStore.StoreLabel(id, label)
+}
@@ -0,0 +1,79 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msp
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/msp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingUserStore fails to store the user with the given ID
+type failingUserStore struct {
+	*MemoryUserStore
+	failID string
+}
+
+func (s *failingUserStore) Store(user *msp.UserData) error {
+	if user.ID == s.failID {
+		return errors.New("store failed")
+	}
+	return s.MemoryUserStore.Store(user)
+}
+
+func TestMigrateUserStore(t *testing.T) {
+	src := NewMemoryUserStore()
+	require.NoError(t, src.Store(&msp.UserData{ID: "user1", MSPID: "Org1MSP", EnrollmentCertificate: []byte("cert1")}))
+	require.NoError(t, src.Store(&msp.UserData{ID: "user2", MSPID: "Org1MSP", EnrollmentCertificate: []byte("cert2")}))
+	require.NoError(t, src.StoreLabel(msp.IdentityIdentifier{ID: "user2", MSPID: "Org1MSP"}, "Bob"))
+
+	dst := NewMemoryUserStore()
+	n, err := MigrateUserStore(src, dst)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	user, err := dst.Load(msp.IdentityIdentifier{ID: "user2", MSPID: "Org1MSP"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("cert2"), user.EnrollmentCertificate)
+	assert.Equal(t, "Bob", user.Label)
+}
+
+func TestMigrateUserStoreRollback(t *testing.T) {
+	src := NewMemoryUserStore()
+	require.NoError(t, src.Store(&msp.UserData{ID: "user1", MSPID: "Org1MSP", EnrollmentCertificate: []byte("cert1")}))
+	require.NoError(t, src.Store(&msp.UserData{ID: "user2", MSPID: "Org1MSP", EnrollmentCertificate: []byte("cert2")}))
+	require.NoError(t, src.Store(&msp.UserData{ID: "user3", MSPID: "Org1MSP", EnrollmentCertificate: []byte("cert3")}))
+
+	dst := &failingUserStore{MemoryUserStore: NewMemoryUserStore(), failID: "user3"}
+	require.NoError(t, dst.MemoryUserStore.Store(&msp.UserData{ID: "user1", MSPID: "Org1MSP", EnrollmentCertificate: []byte("old")}))
+
+	n, err := MigrateUserStore(src, dst)
+	require.Error(t, err)
+	assert.Equal(t, 0, n)
+
+	// Overwritten users are restored and new users are deleted
+	user, err := dst.Load(msp.IdentityIdentifier{ID: "user1", MSPID: "Org1MSP"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("old"), user.EnrollmentCertificate)
+	_, err = dst.Load(msp.IdentityIdentifier{ID: "user2", MSPID: "Org1MSP"})
+	assert.Equal(t, msp.ErrUserNotFound, err)
+}
+
+func TestMigrateUserStoreUnsupported(t *testing.T) {
+	dst := NewMemoryUserStore()
+
+	// The destination must support deleting
+	_, err := MigrateUserStore(NewMemoryUserStore(), struct{ msp.UserStore }{dst})
+	assert.Error(t, err)
+
+	// The source must support listing
+	_, err = MigrateUserStore(struct{ msp.UserStore }{dst}, dst)
+	assert.Error(t, err)
+}
@@ -0,0 +1,189 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vault
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const requestTimeout = 30 * time.Second
+
+// Client is a client of the HTTP API of HashiCorp Vault
+type Client struct {
+	address    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient returns a Vault client.
+//  Parameters:
+//  address is the address of the Vault server (e.g. https://vault.example.com:8200)
+//  token is the Vault token used to authenticate the requests
+//  tlsConfig is the TLS config of the connections to Vault (nil for the default TLS config)
+//
+//  Returns:
+//  the Vault client
+func NewClient(address, token string, tlsConfig *tls.Config) (*Client, error) {
+	u, err := url.Parse(address)
+	if err != nil || u.Host == "" {
+		return nil, errors.Errorf("invalid Vault address [%s]", address)
+	}
+	if token == "" {
+		return nil, errors.New("Vault token is required")
+	}
+
+	return &Client{
+		address: strings.TrimSuffix(address, "/"),
+		token:   token,
+		httpClient: &http.Client{
+			Timeout:   requestTimeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+	}, nil
+}
+
+type transitKey struct {
+	Keys map[string]struct {
+		PublicKey string `json:"public_key"`
+	} `json:"keys"`
+	LatestVersion int    `json:"latest_version"`
+	Type          string `json:"type"`
+}
+
+// createKey creates a key of the given type (e.g. ecdsa-p256) in the Transit secrets engine mounted at mountPath
+func (c *Client) createKey(mountPath, name, keyType string) error {
+	return c.do(http.MethodPost, mountPath+"/keys/"+name, map[string]interface{}{"type": keyType}, nil)
+}
+
+// publicKey returns the PEM public key of the latest version of the given key
+func (c *Client) publicKey(mountPath, name string) (string, error) {
+	var key transitKey
+	if err := c.do(http.MethodGet, mountPath+"/keys/"+name, nil, &key); err != nil {
+		return "", err
+	}
+
+	version, ok := key.Keys[strconv.Itoa(key.LatestVersion)]
+	if !ok || version.PublicKey == "" {
+		return "", errors.Errorf("Vault key [%s] of type [%s] has no public key", name, key.Type)
+	}
+	return version.PublicKey, nil
+}
+
+// listKeys returns the names of the keys of the Transit secrets engine mounted at mountPath
+func (c *Client) listKeys(mountPath string) ([]string, error) {
+	var keys struct {
+		Keys []string `json:"keys"`
+	}
+	if err := c.do("LIST", mountPath+"/keys", nil, &keys); err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return keys.Keys, nil
+}
+
+// sign signs the digest with the given key, and returns the ASN.1 DER signature
+func (c *Client) sign(mountPath, name, hashAlgorithm string, digest []byte) ([]byte, error) {
+	req := map[string]interface{}{
+		"input":                base64.StdEncoding.EncodeToString(digest),
+		"prehashed":            true,
+		"marshaling_algorithm": "asn1",
+	}
+	var resp struct {
+		Signature string `json:"signature"`
+	}
+	if err := c.do(http.MethodPost, mountPath+"/sign/"+name+"/"+hashAlgorithm, req, &resp); err != nil {
+		return nil, err
+	}
+
+	// Signatures are of the form vault:v<key version>:<base64 signature>
+	parts := strings.SplitN(resp.Signature, ":", 3)
+	if len(parts) != 3 || parts[0] != "vault" {
+		return nil, errors.Errorf("invalid signature returned by Vault [%s]", resp.Signature)
+	}
+	signature, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding signature returned by Vault failed")
+	}
+	return signature, nil
+}
+
+type statusError struct {
+	status int
+	errors []string
+}
+
+func (e *statusError) Error() string {
+	return "Vault returned status " + strconv.Itoa(e.status) + ": " + strings.Join(e.errors, ", ")
+}
+
+func isNotFound(err error) bool {
+	statusErr, ok := errors.Cause(err).(*statusError)
+	return ok && statusErr.status == http.StatusNotFound
+}
+
+// do sends a request to the Vault API, and unmarshals the data of the response into data
+func (c *Client) do(method, path string, body interface{}, data interface{}) error {
+	var reqBody []byte
+	if body != nil {
+		var err error
+		if reqBody, err = json.Marshal(body); err != nil {
+			return errors.Wrap(err, "marshal of Vault request failed")
+		}
+	}
+
+	req, err := http.NewRequest(method, c.address+"/v1/"+strings.Trim(path, "/"), bytes.NewReader(reqBody))
+	if err != nil {
+		return errors.Wrap(err, "creating Vault request failed")
+	}
+	req.Header.Set("X-Vault-Token", c.token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "Vault request failed")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "reading Vault response failed")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		json.Unmarshal(respBody, &vaultErr) // nolint: errcheck
+		return errors.WithStack(&statusError{status: resp.StatusCode, errors: vaultErr.Errors})
+	}
+
+	if data == nil || len(respBody) == 0 {
+		return nil
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(respBody, &envelope); err != nil {
+		return errors.Wrap(err, "unmarshal of Vault response failed")
+	}
+	return errors.Wrap(json.Unmarshal(envelope.Data, data), "unmarshal of Vault response data failed")
+}
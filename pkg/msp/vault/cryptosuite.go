@@ -0,0 +1,263 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package vault provides a crypto suite that keeps private keys in the Transit secrets engine of HashiCorp Vault.
+package vault
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"hash"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/logging"
+	"github.com/hyperledger/fabric-sdk-go/pkg/common/providers/core"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite/bccsp/sw"
+	"github.com/pkg/errors"
+)
+
+var logger = logging.NewLogger("fabsdk/msp")
+
+const keyNamePrefix = "fabric-"
+
+// NewVaultCryptoStore returns a crypto suite whose private keys are generated and kept in the Transit secrets
+// engine of Vault. KeyGen creates a key in Vault and returns a handle of the key, and Sign delegates to the sign
+// API of Vault, so that private keys never leave Vault. Public keys are imported and verified in software.
+//  Parameters:
+//  client is the Vault client
+//  mountPath is the path at which the Transit secrets engine is mounted (e.g. transit)
+//
+//  Returns:
+//  the crypto suite
+func NewVaultCryptoStore(client *Client, mountPath string) (core.CryptoSuite, error) {
+	if client == nil {
+		return nil, errors.New("Vault client is required")
+	}
+	mountPath = strings.Trim(mountPath, "/")
+	if mountPath == "" {
+		return nil, errors.New("Transit secrets engine mount path is required")
+	}
+
+	swSuite, err := sw.GetSuiteWithDefaultEphemeral()
+	if err != nil {
+		return nil, errors.WithMessage(err, "initializing software crypto suite failed")
+	}
+
+	return &cryptoSuite{
+		client:    client,
+		mountPath: mountPath,
+		swSuite:   swSuite,
+		keys:      make(map[string]*key),
+	}, nil
+}
+
+type cryptoSuite struct {
+	client    *Client
+	mountPath string
+	swSuite   core.CryptoSuite
+	lock      sync.RWMutex
+	// keys are the Vault keys by hex SKI
+	keys map[string]*key
+}
+
+// KeyGen generates an ECDSA key in Vault
+func (c *cryptoSuite) KeyGen(opts core.KeyGenOpts) (core.Key, error) {
+	if opts == nil {
+		return nil, errors.New("key generation options are required")
+	}
+
+	var keyType string
+	switch opts.Algorithm() {
+	case bccsp.ECDSA, bccsp.ECDSAP256:
+		keyType = "ecdsa-p256"
+	case bccsp.ECDSAP384:
+		keyType = "ecdsa-p384"
+	default:
+		return nil, errors.Errorf("unsupported key generation algorithm [%s]", opts.Algorithm())
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "generating key name failed")
+	}
+	name := keyNamePrefix + hex.EncodeToString(nonce)
+
+	if err := c.client.createKey(c.mountPath, name, keyType); err != nil {
+		return nil, errors.WithMessage(err, "creating key in Vault failed")
+	}
+	k, err := c.loadKey(name)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("Generated key [%s] in Vault", name)
+	return k, nil
+}
+
+// KeyImport imports a public key. Private keys cannot be imported.
+func (c *cryptoSuite) KeyImport(raw interface{}, opts core.KeyImportOpts) (core.Key, error) {
+	k, err := c.swSuite.KeyImport(raw, opts)
+	if err != nil {
+		return nil, err
+	}
+	if k.Private() || k.Symmetric() {
+		return nil, errors.New("only public keys can be imported into the Vault crypto suite")
+	}
+	return k, nil
+}
+
+// GetKey returns the Vault key of the given SKI
+func (c *cryptoSuite) GetKey(ski []byte) (core.Key, error) {
+	c.lock.RLock()
+	k, ok := c.keys[hex.EncodeToString(ski)]
+	c.lock.RUnlock()
+	if ok {
+		return k, nil
+	}
+
+	// The key may have been generated by another instance of the crypto suite
+	names, err := c.client.listKeys(c.mountPath)
+	if err != nil {
+		return nil, errors.WithMessage(err, "listing keys in Vault failed")
+	}
+	for _, name := range names {
+		if !strings.HasPrefix(name, keyNamePrefix) || c.isLoaded(name) {
+			continue
+		}
+		k, err := c.loadKey(name)
+		if err != nil {
+			logger.Debugf("Key [%s] in Vault is skipped: %s", name, err)
+			continue
+		}
+		if bytes.Equal(k.SKI(), ski) {
+			return k, nil
+		}
+	}
+	return nil, errors.Errorf("key with SKI [%x] not found in Vault", ski)
+}
+
+// Hash hashes msg in software
+func (c *cryptoSuite) Hash(msg []byte, opts core.HashOpts) ([]byte, error) {
+	return c.swSuite.Hash(msg, opts)
+}
+
+// GetHash returns a hash function in software
+func (c *cryptoSuite) GetHash(opts core.HashOpts) (hash.Hash, error) {
+	return c.swSuite.GetHash(opts)
+}
+
+// Sign signs the digest with a Vault key
+func (c *cryptoSuite) Sign(k core.Key, digest []byte, opts core.SignerOpts) ([]byte, error) {
+	vaultKey, ok := k.(*key)
+	if !ok {
+		return nil, errors.New("only keys generated in Vault can be used for signing")
+	}
+
+	var hashAlgorithm string
+	switch len(digest) {
+	case 32:
+		hashAlgorithm = "sha2-256"
+	case 48:
+		hashAlgorithm = "sha2-384"
+	default:
+		return nil, errors.Errorf("invalid digest length [%d]", len(digest))
+	}
+
+	signature, err := c.client.sign(c.mountPath, vaultKey.name, hashAlgorithm, digest)
+	if err != nil {
+		return nil, errors.WithMessage(err, "signing with Vault failed")
+	}
+	// Fabric only accepts ECDSA signatures with a low S value
+	return utils.SignatureToLowS(vaultKey.ecdsaPublicKey, signature)
+}
+
+// Verify verifies the signature in software
+func (c *cryptoSuite) Verify(k core.Key, signature, digest []byte, opts core.SignerOpts) (bool, error) {
+	if vaultKey, ok := k.(*key); ok {
+		k = vaultKey.publicKey
+	}
+	return c.swSuite.Verify(k, signature, digest, opts)
+}
+
+func (c *cryptoSuite) isLoaded(name string) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	for _, k := range c.keys {
+		if k.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// loadKey reads the public key of a Vault key, and returns the handle of the key
+func (c *cryptoSuite) loadKey(name string) (*key, error) {
+	publicKeyPEM, err := c.client.publicKey(c.mountPath, name)
+	if err != nil {
+		return nil, errors.WithMessage(err, "reading public key from Vault failed")
+	}
+	block, _ := pem.Decode([]byte(publicKeyPEM))
+	if block == nil {
+		return nil, errors.Errorf("invalid public key of Vault key [%s]", name)
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid public key of Vault key [%s]", name)
+	}
+	ecdsaPublicKey, ok := pub.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("Vault key [%s] is not an ECDSA key", name)
+	}
+
+	publicKey, err := c.swSuite.KeyImport(ecdsaPublicKey, &bccsp.ECDSAGoPublicKeyImportOpts{Temporary: true})
+	if err != nil {
+		return nil, errors.WithMessage(err, "importing public key failed")
+	}
+
+	k := &key{name: name, publicKey: publicKey, ecdsaPublicKey: ecdsaPublicKey}
+	c.lock.Lock()
+	c.keys[hex.EncodeToString(k.SKI())] = k
+	c.lock.Unlock()
+	return k, nil
+}
+
+// key is the handle of a private key in Vault
+type key struct {
+	name           string
+	publicKey      core.Key
+	ecdsaPublicKey *ecdsa.PublicKey
+}
+
+// Bytes returns an error since private keys cannot be exported from Vault
+func (k *key) Bytes() ([]byte, error) {
+	return nil, errors.New("private keys cannot be exported from Vault")
+}
+
+// SKI returns the subject key identifier of the key
+func (k *key) SKI() []byte {
+	return k.publicKey.SKI()
+}
+
+// Symmetric returns false
+func (k *key) Symmetric() bool {
+	return false
+}
+
+// Private returns true
+func (k *key) Private() bool {
+	return true
+}
+
+// PublicKey returns the public key of the key
+func (k *key) PublicKey() (core.Key, error) {
+	return k.publicKey, nil
+}
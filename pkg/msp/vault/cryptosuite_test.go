@@ -0,0 +1,216 @@
+/*
+Copyright SecureKey Technologies Inc. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package vault
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric-sdk-go/internal/github.com/hyperledger/fabric/bccsp/utils"
+	"github.com/hyperledger/fabric-sdk-go/pkg/core/cryptosuite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "s.testtoken"
+
+func TestVaultCryptoStore(t *testing.T) {
+	vault := newMockTransit()
+	server := httptest.NewServer(vault)
+	defer server.Close()
+
+	client, err := NewClient(server.URL, testToken, nil)
+	require.NoError(t, err)
+	cs, err := NewVaultCryptoStore(client, "/transit/")
+	require.NoError(t, err)
+
+	k, err := cs.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	require.NoError(t, err)
+	assert.True(t, k.Private())
+	assert.False(t, k.Symmetric())
+	assert.NotEmpty(t, k.SKI())
+	_, err = k.Bytes()
+	assert.Error(t, err, "expecting private key not to be exportable")
+	require.Len(t, vault.keys, 1)
+
+	digest, err := cs.Hash([]byte("message"), cryptosuite.GetSHA256Opts())
+	require.NoError(t, err)
+	signature, err := cs.Sign(k, digest, nil)
+	require.NoError(t, err)
+
+	// Vault may return signatures with a high S value
+	publicKey := vault.publicKey(t)
+	_, s, err := utils.UnmarshalECDSASignature(signature)
+	require.NoError(t, err)
+	lowS, err := utils.IsLowS(publicKey, s)
+	require.NoError(t, err)
+	assert.True(t, lowS, "expecting signature with a low S value")
+
+	valid, err := cs.Verify(k, signature, digest, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// The public key is imported in software
+	pub, err := k.PublicKey()
+	require.NoError(t, err)
+	assert.Equal(t, k.SKI(), pub.SKI())
+	pubDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	imported, err := cs.KeyImport(pubDER, &bccsp.ECDSAPKIXPublicKeyImportOpts{Temporary: true})
+	require.NoError(t, err)
+	valid, err = cs.Verify(imported, signature, digest, nil)
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	// Keys are found by SKI, including keys generated by other instances of the crypto suite
+	found, err := cs.GetKey(k.SKI())
+	require.NoError(t, err)
+	assert.Equal(t, k, found)
+	other, err := NewVaultCryptoStore(client, "transit")
+	require.NoError(t, err)
+	found, err = other.GetKey(k.SKI())
+	require.NoError(t, err)
+	assert.Equal(t, k.SKI(), found.SKI())
+	_, err = other.GetKey([]byte("unknown"))
+	assert.EqualError(t, err, "key with SKI [756e6b6e6f776e] not found in Vault")
+}
+
+func TestVaultCryptoStoreErrors(t *testing.T) {
+	_, err := NewClient("vault:8200", testToken, nil)
+	assert.EqualError(t, err, "invalid Vault address [vault:8200]")
+	_, err = NewClient("https://vault:8200", "", nil)
+	assert.EqualError(t, err, "Vault token is required")
+	_, err = NewVaultCryptoStore(nil, "transit")
+	assert.EqualError(t, err, "Vault client is required")
+
+	server := httptest.NewServer(newMockTransit())
+	defer server.Close()
+
+	client, err := NewClient(server.URL, "s.invalid", nil)
+	require.NoError(t, err)
+	cs, err := NewVaultCryptoStore(client, "transit")
+	require.NoError(t, err)
+
+	_, err = cs.KeyGen(cryptosuite.GetECDSAP256KeyGenOpts(false))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Vault returned status 403: permission denied")
+	_, err = cs.KeyGen(&bccsp.AESKeyGenOpts{})
+	assert.EqualError(t, err, "unsupported key generation algorithm [AES]")
+
+	swSuite := cs.(*cryptoSuite).swSuite
+	swKey, err := swSuite.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: true})
+	require.NoError(t, err)
+	_, err = cs.Sign(swKey, make([]byte, 32), nil)
+	assert.EqualError(t, err, "only keys generated in Vault can be used for signing")
+
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	privateKeyDER, err := x509.MarshalECPrivateKey(privateKey)
+	require.NoError(t, err)
+	_, err = cs.KeyImport(privateKeyDER, &bccsp.ECDSAPrivateKeyImportOpts{Temporary: true})
+	assert.EqualError(t, err, "only public keys can be imported into the Vault crypto suite")
+}
+
+// mockTransit implements the key, sign and list requests of the Transit secrets engine mounted at transit
+type mockTransit struct {
+	lock sync.Mutex
+	keys map[string]*ecdsa.PrivateKey
+}
+
+func newMockTransit() *mockTransit {
+	return &mockTransit{keys: make(map[string]*ecdsa.PrivateKey)}
+}
+
+func (m *mockTransit) publicKey(t *testing.T) *ecdsa.PublicKey {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, k := range m.keys {
+		return &k.PublicKey
+	}
+	t.Fatal("no key in Vault")
+	return nil
+}
+
+func (m *mockTransit) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Vault-Token") != testToken {
+		writeJSON(w, http.StatusForbidden, map[string]interface{}{"errors": []string{"permission denied"}})
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	path := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/transit/"), "/")
+	switch {
+	case r.Method == "LIST" && len(path) == 1 && path[0] == "keys":
+		var names []string
+		for name := range m.keys {
+			names = append(names, name)
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{"keys": names}})
+	case r.Method == http.MethodPost && len(path) == 2 && path[0] == "keys":
+		k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, nil)
+			return
+		}
+		m.keys[path[1]] = k
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && len(path) == 2 && path[0] == "keys" && m.keys[path[1]] != nil:
+		der, _ := x509.MarshalPKIXPublicKey(&m.keys[path[1]].PublicKey)
+		pub := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+			"type":           "ecdsa-p256",
+			"latest_version": 1,
+			"keys":           map[string]interface{}{"1": map[string]interface{}{"public_key": string(pub)}},
+		}})
+	case r.Method == http.MethodPost && len(path) == 3 && path[0] == "sign" && m.keys[path[1]] != nil && path[2] == "sha2-256":
+		var req struct {
+			Input     string `json:"input"`
+			Prehashed bool   `json:"prehashed"`
+		}
+		json.NewDecoder(r.Body).Decode(&req) // nolint: errcheck
+		digest, _ := base64.StdEncoding.DecodeString(req.Input)
+		if !req.Prehashed {
+			sum := sha256.Sum256(digest)
+			digest = sum[:]
+		}
+		k := m.keys[path[1]]
+		rs, s, _ := ecdsa.Sign(rand.Reader, k, digest)
+		// Return the signature with a high S value
+		if s.Cmp(new(big.Int).Rsh(k.Params().N, 1)) <= 0 {
+			s.Sub(k.Params().N, s)
+		}
+		sig, _ := asn1.Marshal(struct{ R, S *big.Int }{rs, s})
+		writeJSON(w, http.StatusOK, map[string]interface{}{"data": map[string]interface{}{
+			"signature": fmt.Sprintf("vault:v1:%s", base64.StdEncoding.EncodeToString(sig)),
+		}})
+	default:
+		writeJSON(w, http.StatusNotFound, map[string]interface{}{"errors": []string{}})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body) // nolint: errcheck
+}
@@ -40,6 +40,7 @@ declare -a FILES=(
     "lib/util.go"
     "lib/serverrevoke.go"
     "lib/sdkpatch_serverstruct.go"
+    "lib/sdkpatch_requestcontext.go"
 
     "lib/streamer/jsonstreamer.go"
 
@@ -130,6 +131,18 @@ sed -i'' -e '/tlsConfig.CipherSuites = tls.DefaultCipherSuites/ a\
 //set the host name override \
 tlsConfig.ServerName = serverName\
 ' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
+sed -i'' -e '/cr.CN = id/ a\
+//set the common name override\
+if req != nil \&\& req.CN != "" {\
+cr.CN = req.CN\
+}\
+' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
+sed -i'' -e '/tlsConfig.ServerName = serverName/ a\
+//set the client certificate override\
+if c.Config.TLSClientCert != nil {\
+tlsConfig.Certificates = append(tlsConfig.Certificates[:0], *c.Config.TLSClientCert)\
+}\
+' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
 # TODO remove below sed call for lib/client.go once Fabric CA v1.3 is not supported by the SDK anymore
 sed -i'' -e '$a\
 \
@@ -201,6 +214,11 @@ gofilter
 sed -i'' -e 's/*factory.FactoryOpts/core.CryptoSuite/g' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
 sed -i'' -e '/core.CryptoSuite `mapstructure:"bccsp" hide:"true"`/ a\
 ServerName string           `help:"CA server name to be used in case of host name override"`\
+\/\/ TLSClientCert overrides the TLS client key and certificate files when mutual authentication is enabled\
+TLSClientCert *stdtls.Certificate `hide:"true"`\
+' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
+sed -i'' -e '/^import (/ a\
+stdtls "crypto/tls"\
 ' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
 
 FILTER_FILENAME="lib/util.go"
@@ -39,6 +39,7 @@ declare -a PKGS=(
     "common/metrics/prometheus"
     "common/metrics/statsd"
     "common/metrics/statsd/goruntime"
+    "common/tools/configtxlator/update"
 
     "core/comm"
     "core/middleware"
@@ -130,6 +131,8 @@ declare -a FILES=(
     "common/metrics/statsd/goruntime/metrics.go"
     "common/metrics/statsd/provider.go"
 
+    "common/tools/configtxlator/update/update.go"
+
     "core/middleware/chain.go"
     "core/middleware/request_id.go"
     "core/middleware/require_cert.go"
@@ -209,6 +212,17 @@ do
 done
 
 sed -i "$START_LINE i pkcs11Ctx *sdkp11.ContextHandle" "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
+sed -i'' -e 's/opts.Ephemeral())$/opts.Ephemeral(), sdkp11.ObjectAttributes{})/g' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
+sed -i'' -e '/case \*bccsp.ECDSAP384KeyGenOpts:/ i \
+case *sdkp11.ECDSATemplateKeyGenOpts:\
+ski, pub, err := csp.generateECKey(csp.conf.ellipticCurve, opts.Ephemeral(), opts.(*sdkp11.ECDSATemplateKeyGenOpts).Template)\
+if err != nil {\
+return nil, errors.Wrapf(err, "Failed generating ECDSA key from template")\
+}\
+\
+k = \&ecdsaPrivateKey{ski, ecdsaPublicKey{ski, pub}}\
+\
+' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
 
 FILTER_FILENAME="bccsp/pkcs11/pkcs11.go"
 sed -i'' -e '/"github.com\/hyperledger"/a "time"/' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
@@ -260,6 +274,18 @@ sed -i'' -e 's/func ecPoint(p11lib \*pkcs11.Ctx,/func ecPoint(p11lib \*sdkp11.Co
 sed -i'' -e 's/attr, err := csp.pkcs11Ctx.GetAttributeValue(session, key, template)/attr, err := p11lib.GetAttributeValue(session, key, template)/g' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
 sed -i'' -e 's/attr, err := csp.pkcs11Ctx.GetAttributeValue(session, obj, template)/attr, err := p11lib.GetAttributeValue(session, obj, template)/g' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
 sed -i'' -e '/privateKey, err := csp.pkcs11Ctx.FindKeyPairFromSKI/a defer timeTrack(time.Now(), fmt.Sprintf("signing [session: %d]", session))' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
+sed -i'' -e 's/func (csp \*impl) generateECKey(curve asn1.ObjectIdentifier, ephemeral bool)/func (csp \*impl) generateECKey(curve asn1.ObjectIdentifier, ephemeral bool, template sdkp11.ObjectAttributes)/g' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
+sed -i'' -e '/pub, prv, err := csp.pkcs11Ctx.GenerateKeyPair(session,/ i \
+pubkeyT, err = sdkp11.MergeAttributes(pubkeyT, template.Public)\
+if err != nil {\
+return nil, nil, fmt.Errorf("P11: invalid public key template [%s]", err)\
+}\
+prvkeyT, err = sdkp11.MergeAttributes(prvkeyT, template.Private)\
+if err != nil {\
+return nil, nil, fmt.Errorf("P11: invalid private key template [%s]", err)\
+}\
+\
+' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
 
 echo "Filtering Go sources for allowed functions ..."
 FILTERS_ENABLED="fn"
@@ -317,6 +343,9 @@ FILTER_FILENAME="core/ledger/kvledger/txmgmt/version/version.go"
 FILTER_FN=
 gofilter
 
+FILTER_FILENAME="common/tools/configtxlator/update/update.go"
+sed -i'' -e 's/cb.NewConfigGroup()/\&cb.ConfigGroup{}/g' "${TMP_PROJECT_PATH}/${FILTER_FILENAME}"
+
 FILTER_FILENAME="discovery/client/signer.go"
 cat >> ${TMP_PROJECT_PATH}/${FILTER_FILENAME} <<EOF
 
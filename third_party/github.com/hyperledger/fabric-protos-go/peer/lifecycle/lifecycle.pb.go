@@ -0,0 +1,1782 @@
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+It is generated from the Fabric 2.x protos (fabric-protos-go fee30f3ccd23) and is not
+managed by the third_party pinning scripts; see the README in this directory.
+*/
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: peer/lifecycle/lifecycle.proto
+
+package lifecycle // import "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import common "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// InstallChaincodeArgs is the message used as the argument to
+// '_lifecycle.InstallChaincode'.
+type InstallChaincodeArgs struct {
+	ChaincodeInstallPackage []byte   `protobuf:"bytes,1,opt,name=chaincode_install_package,json=chaincodeInstallPackage,proto3" json:"chaincode_install_package,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{} `json:"-"`
+	XXX_unrecognized        []byte   `json:"-"`
+	XXX_sizecache           int32    `json:"-"`
+}
+
+func (m *InstallChaincodeArgs) Reset()         { *m = InstallChaincodeArgs{} }
+func (m *InstallChaincodeArgs) String() string { return proto.CompactTextString(m) }
+func (*InstallChaincodeArgs) ProtoMessage()    {}
+func (*InstallChaincodeArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{0}
+}
+func (m *InstallChaincodeArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InstallChaincodeArgs.Unmarshal(m, b)
+}
+func (m *InstallChaincodeArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InstallChaincodeArgs.Marshal(b, m, deterministic)
+}
+func (dst *InstallChaincodeArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InstallChaincodeArgs.Merge(dst, src)
+}
+func (m *InstallChaincodeArgs) XXX_Size() int {
+	return xxx_messageInfo_InstallChaincodeArgs.Size(m)
+}
+func (m *InstallChaincodeArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_InstallChaincodeArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InstallChaincodeArgs proto.InternalMessageInfo
+
+func (m *InstallChaincodeArgs) GetChaincodeInstallPackage() []byte {
+	if m != nil {
+		return m.ChaincodeInstallPackage
+	}
+	return nil
+}
+
+// InstallChaincodeArgs is the message returned by
+// '_lifecycle.InstallChaincode'.
+type InstallChaincodeResult struct {
+	PackageId            string   `protobuf:"bytes,1,opt,name=package_id,json=packageId,proto3" json:"package_id,omitempty"`
+	Label                string   `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InstallChaincodeResult) Reset()         { *m = InstallChaincodeResult{} }
+func (m *InstallChaincodeResult) String() string { return proto.CompactTextString(m) }
+func (*InstallChaincodeResult) ProtoMessage()    {}
+func (*InstallChaincodeResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{1}
+}
+func (m *InstallChaincodeResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InstallChaincodeResult.Unmarshal(m, b)
+}
+func (m *InstallChaincodeResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InstallChaincodeResult.Marshal(b, m, deterministic)
+}
+func (dst *InstallChaincodeResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InstallChaincodeResult.Merge(dst, src)
+}
+func (m *InstallChaincodeResult) XXX_Size() int {
+	return xxx_messageInfo_InstallChaincodeResult.Size(m)
+}
+func (m *InstallChaincodeResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_InstallChaincodeResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InstallChaincodeResult proto.InternalMessageInfo
+
+func (m *InstallChaincodeResult) GetPackageId() string {
+	if m != nil {
+		return m.PackageId
+	}
+	return ""
+}
+
+func (m *InstallChaincodeResult) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+// QueryInstalledChaincodeArgs is the message used as arguments
+// '_lifecycle.QueryInstalledChaincode'
+type QueryInstalledChaincodeArgs struct {
+	PackageId            string   `protobuf:"bytes,1,opt,name=package_id,json=packageId,proto3" json:"package_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryInstalledChaincodeArgs) Reset()         { *m = QueryInstalledChaincodeArgs{} }
+func (m *QueryInstalledChaincodeArgs) String() string { return proto.CompactTextString(m) }
+func (*QueryInstalledChaincodeArgs) ProtoMessage()    {}
+func (*QueryInstalledChaincodeArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{2}
+}
+func (m *QueryInstalledChaincodeArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryInstalledChaincodeArgs.Unmarshal(m, b)
+}
+func (m *QueryInstalledChaincodeArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryInstalledChaincodeArgs.Marshal(b, m, deterministic)
+}
+func (dst *QueryInstalledChaincodeArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryInstalledChaincodeArgs.Merge(dst, src)
+}
+func (m *QueryInstalledChaincodeArgs) XXX_Size() int {
+	return xxx_messageInfo_QueryInstalledChaincodeArgs.Size(m)
+}
+func (m *QueryInstalledChaincodeArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryInstalledChaincodeArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryInstalledChaincodeArgs proto.InternalMessageInfo
+
+func (m *QueryInstalledChaincodeArgs) GetPackageId() string {
+	if m != nil {
+		return m.PackageId
+	}
+	return ""
+}
+
+// QueryInstalledChaincodeResult is the message returned by
+// '_lifecycle.QueryInstalledChaincode'
+type QueryInstalledChaincodeResult struct {
+	PackageId            string                                               `protobuf:"bytes,1,opt,name=package_id,json=packageId,proto3" json:"package_id,omitempty"`
+	Label                string                                               `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	References           map[string]*QueryInstalledChaincodeResult_References `protobuf:"bytes,3,rep,name=references,proto3" json:"references,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                                             `json:"-"`
+	XXX_unrecognized     []byte                                               `json:"-"`
+	XXX_sizecache        int32                                                `json:"-"`
+}
+
+func (m *QueryInstalledChaincodeResult) Reset()         { *m = QueryInstalledChaincodeResult{} }
+func (m *QueryInstalledChaincodeResult) String() string { return proto.CompactTextString(m) }
+func (*QueryInstalledChaincodeResult) ProtoMessage()    {}
+func (*QueryInstalledChaincodeResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{3}
+}
+func (m *QueryInstalledChaincodeResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryInstalledChaincodeResult.Unmarshal(m, b)
+}
+func (m *QueryInstalledChaincodeResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryInstalledChaincodeResult.Marshal(b, m, deterministic)
+}
+func (dst *QueryInstalledChaincodeResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryInstalledChaincodeResult.Merge(dst, src)
+}
+func (m *QueryInstalledChaincodeResult) XXX_Size() int {
+	return xxx_messageInfo_QueryInstalledChaincodeResult.Size(m)
+}
+func (m *QueryInstalledChaincodeResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryInstalledChaincodeResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryInstalledChaincodeResult proto.InternalMessageInfo
+
+func (m *QueryInstalledChaincodeResult) GetPackageId() string {
+	if m != nil {
+		return m.PackageId
+	}
+	return ""
+}
+
+func (m *QueryInstalledChaincodeResult) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *QueryInstalledChaincodeResult) GetReferences() map[string]*QueryInstalledChaincodeResult_References {
+	if m != nil {
+		return m.References
+	}
+	return nil
+}
+
+type QueryInstalledChaincodeResult_References struct {
+	Chaincodes           []*QueryInstalledChaincodeResult_Chaincode `protobuf:"bytes,1,rep,name=chaincodes,proto3" json:"chaincodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                                   `json:"-"`
+	XXX_unrecognized     []byte                                     `json:"-"`
+	XXX_sizecache        int32                                      `json:"-"`
+}
+
+func (m *QueryInstalledChaincodeResult_References) Reset() {
+	*m = QueryInstalledChaincodeResult_References{}
+}
+func (m *QueryInstalledChaincodeResult_References) String() string { return proto.CompactTextString(m) }
+func (*QueryInstalledChaincodeResult_References) ProtoMessage()    {}
+func (*QueryInstalledChaincodeResult_References) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{3, 1}
+}
+func (m *QueryInstalledChaincodeResult_References) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryInstalledChaincodeResult_References.Unmarshal(m, b)
+}
+func (m *QueryInstalledChaincodeResult_References) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryInstalledChaincodeResult_References.Marshal(b, m, deterministic)
+}
+func (dst *QueryInstalledChaincodeResult_References) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryInstalledChaincodeResult_References.Merge(dst, src)
+}
+func (m *QueryInstalledChaincodeResult_References) XXX_Size() int {
+	return xxx_messageInfo_QueryInstalledChaincodeResult_References.Size(m)
+}
+func (m *QueryInstalledChaincodeResult_References) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryInstalledChaincodeResult_References.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryInstalledChaincodeResult_References proto.InternalMessageInfo
+
+func (m *QueryInstalledChaincodeResult_References) GetChaincodes() []*QueryInstalledChaincodeResult_Chaincode {
+	if m != nil {
+		return m.Chaincodes
+	}
+	return nil
+}
+
+type QueryInstalledChaincodeResult_Chaincode struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version              string   `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryInstalledChaincodeResult_Chaincode) Reset() {
+	*m = QueryInstalledChaincodeResult_Chaincode{}
+}
+func (m *QueryInstalledChaincodeResult_Chaincode) String() string { return proto.CompactTextString(m) }
+func (*QueryInstalledChaincodeResult_Chaincode) ProtoMessage()    {}
+func (*QueryInstalledChaincodeResult_Chaincode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{3, 2}
+}
+func (m *QueryInstalledChaincodeResult_Chaincode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryInstalledChaincodeResult_Chaincode.Unmarshal(m, b)
+}
+func (m *QueryInstalledChaincodeResult_Chaincode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryInstalledChaincodeResult_Chaincode.Marshal(b, m, deterministic)
+}
+func (dst *QueryInstalledChaincodeResult_Chaincode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryInstalledChaincodeResult_Chaincode.Merge(dst, src)
+}
+func (m *QueryInstalledChaincodeResult_Chaincode) XXX_Size() int {
+	return xxx_messageInfo_QueryInstalledChaincodeResult_Chaincode.Size(m)
+}
+func (m *QueryInstalledChaincodeResult_Chaincode) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryInstalledChaincodeResult_Chaincode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryInstalledChaincodeResult_Chaincode proto.InternalMessageInfo
+
+func (m *QueryInstalledChaincodeResult_Chaincode) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *QueryInstalledChaincodeResult_Chaincode) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+// GetInstalledChaincodePackageArgs is the message used as the argument to
+// '_lifecycle.GetInstalledChaincodePackage'.
+type GetInstalledChaincodePackageArgs struct {
+	PackageId            string   `protobuf:"bytes,1,opt,name=package_id,json=packageId,proto3" json:"package_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetInstalledChaincodePackageArgs) Reset()         { *m = GetInstalledChaincodePackageArgs{} }
+func (m *GetInstalledChaincodePackageArgs) String() string { return proto.CompactTextString(m) }
+func (*GetInstalledChaincodePackageArgs) ProtoMessage()    {}
+func (*GetInstalledChaincodePackageArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{4}
+}
+func (m *GetInstalledChaincodePackageArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetInstalledChaincodePackageArgs.Unmarshal(m, b)
+}
+func (m *GetInstalledChaincodePackageArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetInstalledChaincodePackageArgs.Marshal(b, m, deterministic)
+}
+func (dst *GetInstalledChaincodePackageArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetInstalledChaincodePackageArgs.Merge(dst, src)
+}
+func (m *GetInstalledChaincodePackageArgs) XXX_Size() int {
+	return xxx_messageInfo_GetInstalledChaincodePackageArgs.Size(m)
+}
+func (m *GetInstalledChaincodePackageArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetInstalledChaincodePackageArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetInstalledChaincodePackageArgs proto.InternalMessageInfo
+
+func (m *GetInstalledChaincodePackageArgs) GetPackageId() string {
+	if m != nil {
+		return m.PackageId
+	}
+	return ""
+}
+
+// GetInstalledChaincodePackageResult is the message returned by
+// '_lifecycle.GetInstalledChaincodePackage'.
+type GetInstalledChaincodePackageResult struct {
+	ChaincodeInstallPackage []byte   `protobuf:"bytes,1,opt,name=chaincode_install_package,json=chaincodeInstallPackage,proto3" json:"chaincode_install_package,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{} `json:"-"`
+	XXX_unrecognized        []byte   `json:"-"`
+	XXX_sizecache           int32    `json:"-"`
+}
+
+func (m *GetInstalledChaincodePackageResult) Reset()         { *m = GetInstalledChaincodePackageResult{} }
+func (m *GetInstalledChaincodePackageResult) String() string { return proto.CompactTextString(m) }
+func (*GetInstalledChaincodePackageResult) ProtoMessage()    {}
+func (*GetInstalledChaincodePackageResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{5}
+}
+func (m *GetInstalledChaincodePackageResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetInstalledChaincodePackageResult.Unmarshal(m, b)
+}
+func (m *GetInstalledChaincodePackageResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetInstalledChaincodePackageResult.Marshal(b, m, deterministic)
+}
+func (dst *GetInstalledChaincodePackageResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetInstalledChaincodePackageResult.Merge(dst, src)
+}
+func (m *GetInstalledChaincodePackageResult) XXX_Size() int {
+	return xxx_messageInfo_GetInstalledChaincodePackageResult.Size(m)
+}
+func (m *GetInstalledChaincodePackageResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetInstalledChaincodePackageResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetInstalledChaincodePackageResult proto.InternalMessageInfo
+
+func (m *GetInstalledChaincodePackageResult) GetChaincodeInstallPackage() []byte {
+	if m != nil {
+		return m.ChaincodeInstallPackage
+	}
+	return nil
+}
+
+// QueryInstalledChaincodesArgs currently is an empty argument to
+// '_lifecycle.QueryInstalledChaincodes'.   In the future, it may be
+// extended to have parameters.
+type QueryInstalledChaincodesArgs struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryInstalledChaincodesArgs) Reset()         { *m = QueryInstalledChaincodesArgs{} }
+func (m *QueryInstalledChaincodesArgs) String() string { return proto.CompactTextString(m) }
+func (*QueryInstalledChaincodesArgs) ProtoMessage()    {}
+func (*QueryInstalledChaincodesArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{6}
+}
+func (m *QueryInstalledChaincodesArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryInstalledChaincodesArgs.Unmarshal(m, b)
+}
+func (m *QueryInstalledChaincodesArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryInstalledChaincodesArgs.Marshal(b, m, deterministic)
+}
+func (dst *QueryInstalledChaincodesArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryInstalledChaincodesArgs.Merge(dst, src)
+}
+func (m *QueryInstalledChaincodesArgs) XXX_Size() int {
+	return xxx_messageInfo_QueryInstalledChaincodesArgs.Size(m)
+}
+func (m *QueryInstalledChaincodesArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryInstalledChaincodesArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryInstalledChaincodesArgs proto.InternalMessageInfo
+
+// QueryInstalledChaincodesResult is the message returned by
+// '_lifecycle.QueryInstalledChaincodes'.  It returns a list of installed
+// chaincodes, including a map of channel name to chaincode name and version
+// pairs of chaincode definitions that reference this chaincode package.
+type QueryInstalledChaincodesResult struct {
+	InstalledChaincodes  []*QueryInstalledChaincodesResult_InstalledChaincode `protobuf:"bytes,1,rep,name=installed_chaincodes,json=installedChaincodes,proto3" json:"installed_chaincodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                                             `json:"-"`
+	XXX_unrecognized     []byte                                               `json:"-"`
+	XXX_sizecache        int32                                                `json:"-"`
+}
+
+func (m *QueryInstalledChaincodesResult) Reset()         { *m = QueryInstalledChaincodesResult{} }
+func (m *QueryInstalledChaincodesResult) String() string { return proto.CompactTextString(m) }
+func (*QueryInstalledChaincodesResult) ProtoMessage()    {}
+func (*QueryInstalledChaincodesResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{7}
+}
+func (m *QueryInstalledChaincodesResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryInstalledChaincodesResult.Unmarshal(m, b)
+}
+func (m *QueryInstalledChaincodesResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryInstalledChaincodesResult.Marshal(b, m, deterministic)
+}
+func (dst *QueryInstalledChaincodesResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryInstalledChaincodesResult.Merge(dst, src)
+}
+func (m *QueryInstalledChaincodesResult) XXX_Size() int {
+	return xxx_messageInfo_QueryInstalledChaincodesResult.Size(m)
+}
+func (m *QueryInstalledChaincodesResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryInstalledChaincodesResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryInstalledChaincodesResult proto.InternalMessageInfo
+
+func (m *QueryInstalledChaincodesResult) GetInstalledChaincodes() []*QueryInstalledChaincodesResult_InstalledChaincode {
+	if m != nil {
+		return m.InstalledChaincodes
+	}
+	return nil
+}
+
+type QueryInstalledChaincodesResult_InstalledChaincode struct {
+	PackageId            string                                                `protobuf:"bytes,1,opt,name=package_id,json=packageId,proto3" json:"package_id,omitempty"`
+	Label                string                                                `protobuf:"bytes,2,opt,name=label,proto3" json:"label,omitempty"`
+	References           map[string]*QueryInstalledChaincodesResult_References `protobuf:"bytes,3,rep,name=references,proto3" json:"references,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                                              `json:"-"`
+	XXX_unrecognized     []byte                                                `json:"-"`
+	XXX_sizecache        int32                                                 `json:"-"`
+}
+
+func (m *QueryInstalledChaincodesResult_InstalledChaincode) Reset() {
+	*m = QueryInstalledChaincodesResult_InstalledChaincode{}
+}
+func (m *QueryInstalledChaincodesResult_InstalledChaincode) String() string {
+	return proto.CompactTextString(m)
+}
+func (*QueryInstalledChaincodesResult_InstalledChaincode) ProtoMessage() {}
+func (*QueryInstalledChaincodesResult_InstalledChaincode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{7, 0}
+}
+func (m *QueryInstalledChaincodesResult_InstalledChaincode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryInstalledChaincodesResult_InstalledChaincode.Unmarshal(m, b)
+}
+func (m *QueryInstalledChaincodesResult_InstalledChaincode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryInstalledChaincodesResult_InstalledChaincode.Marshal(b, m, deterministic)
+}
+func (dst *QueryInstalledChaincodesResult_InstalledChaincode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryInstalledChaincodesResult_InstalledChaincode.Merge(dst, src)
+}
+func (m *QueryInstalledChaincodesResult_InstalledChaincode) XXX_Size() int {
+	return xxx_messageInfo_QueryInstalledChaincodesResult_InstalledChaincode.Size(m)
+}
+func (m *QueryInstalledChaincodesResult_InstalledChaincode) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryInstalledChaincodesResult_InstalledChaincode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryInstalledChaincodesResult_InstalledChaincode proto.InternalMessageInfo
+
+func (m *QueryInstalledChaincodesResult_InstalledChaincode) GetPackageId() string {
+	if m != nil {
+		return m.PackageId
+	}
+	return ""
+}
+
+func (m *QueryInstalledChaincodesResult_InstalledChaincode) GetLabel() string {
+	if m != nil {
+		return m.Label
+	}
+	return ""
+}
+
+func (m *QueryInstalledChaincodesResult_InstalledChaincode) GetReferences() map[string]*QueryInstalledChaincodesResult_References {
+	if m != nil {
+		return m.References
+	}
+	return nil
+}
+
+type QueryInstalledChaincodesResult_References struct {
+	Chaincodes           []*QueryInstalledChaincodesResult_Chaincode `protobuf:"bytes,1,rep,name=chaincodes,proto3" json:"chaincodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                                    `json:"-"`
+	XXX_unrecognized     []byte                                      `json:"-"`
+	XXX_sizecache        int32                                       `json:"-"`
+}
+
+func (m *QueryInstalledChaincodesResult_References) Reset() {
+	*m = QueryInstalledChaincodesResult_References{}
+}
+func (m *QueryInstalledChaincodesResult_References) String() string {
+	return proto.CompactTextString(m)
+}
+func (*QueryInstalledChaincodesResult_References) ProtoMessage() {}
+func (*QueryInstalledChaincodesResult_References) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{7, 1}
+}
+func (m *QueryInstalledChaincodesResult_References) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryInstalledChaincodesResult_References.Unmarshal(m, b)
+}
+func (m *QueryInstalledChaincodesResult_References) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryInstalledChaincodesResult_References.Marshal(b, m, deterministic)
+}
+func (dst *QueryInstalledChaincodesResult_References) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryInstalledChaincodesResult_References.Merge(dst, src)
+}
+func (m *QueryInstalledChaincodesResult_References) XXX_Size() int {
+	return xxx_messageInfo_QueryInstalledChaincodesResult_References.Size(m)
+}
+func (m *QueryInstalledChaincodesResult_References) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryInstalledChaincodesResult_References.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryInstalledChaincodesResult_References proto.InternalMessageInfo
+
+func (m *QueryInstalledChaincodesResult_References) GetChaincodes() []*QueryInstalledChaincodesResult_Chaincode {
+	if m != nil {
+		return m.Chaincodes
+	}
+	return nil
+}
+
+type QueryInstalledChaincodesResult_Chaincode struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version              string   `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryInstalledChaincodesResult_Chaincode) Reset() {
+	*m = QueryInstalledChaincodesResult_Chaincode{}
+}
+func (m *QueryInstalledChaincodesResult_Chaincode) String() string { return proto.CompactTextString(m) }
+func (*QueryInstalledChaincodesResult_Chaincode) ProtoMessage()    {}
+func (*QueryInstalledChaincodesResult_Chaincode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{7, 2}
+}
+func (m *QueryInstalledChaincodesResult_Chaincode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryInstalledChaincodesResult_Chaincode.Unmarshal(m, b)
+}
+func (m *QueryInstalledChaincodesResult_Chaincode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryInstalledChaincodesResult_Chaincode.Marshal(b, m, deterministic)
+}
+func (dst *QueryInstalledChaincodesResult_Chaincode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryInstalledChaincodesResult_Chaincode.Merge(dst, src)
+}
+func (m *QueryInstalledChaincodesResult_Chaincode) XXX_Size() int {
+	return xxx_messageInfo_QueryInstalledChaincodesResult_Chaincode.Size(m)
+}
+func (m *QueryInstalledChaincodesResult_Chaincode) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryInstalledChaincodesResult_Chaincode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryInstalledChaincodesResult_Chaincode proto.InternalMessageInfo
+
+func (m *QueryInstalledChaincodesResult_Chaincode) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *QueryInstalledChaincodesResult_Chaincode) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+// ApproveChaincodeDefinitionForMyOrgArgs is the message used as arguments to
+// `_lifecycle.ApproveChaincodeDefinitionForMyOrg`.
+type ApproveChaincodeDefinitionForMyOrgArgs struct {
+	Sequence             int64                           `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Name                 string                          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version              string                          `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	EndorsementPlugin    string                          `protobuf:"bytes,4,opt,name=endorsement_plugin,json=endorsementPlugin,proto3" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin     string                          `protobuf:"bytes,5,opt,name=validation_plugin,json=validationPlugin,proto3" json:"validation_plugin,omitempty"`
+	ValidationParameter  []byte                          `protobuf:"bytes,6,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections          *common.CollectionConfigPackage `protobuf:"bytes,7,opt,name=collections,proto3" json:"collections,omitempty"`
+	InitRequired         bool                            `protobuf:"varint,8,opt,name=init_required,json=initRequired,proto3" json:"init_required,omitempty"`
+	Source               *ChaincodeSource                `protobuf:"bytes,9,opt,name=source,proto3" json:"source,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) Reset() {
+	*m = ApproveChaincodeDefinitionForMyOrgArgs{}
+}
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) String() string { return proto.CompactTextString(m) }
+func (*ApproveChaincodeDefinitionForMyOrgArgs) ProtoMessage()    {}
+func (*ApproveChaincodeDefinitionForMyOrgArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{8}
+}
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs.Unmarshal(m, b)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs.Marshal(b, m, deterministic)
+}
+func (dst *ApproveChaincodeDefinitionForMyOrgArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs.Merge(dst, src)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) XXX_Size() int {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs.Size(m)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs proto.InternalMessageInfo
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetEndorsementPlugin() string {
+	if m != nil {
+		return m.EndorsementPlugin
+	}
+	return ""
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetValidationPlugin() string {
+	if m != nil {
+		return m.ValidationPlugin
+	}
+	return ""
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetValidationParameter() []byte {
+	if m != nil {
+		return m.ValidationParameter
+	}
+	return nil
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetCollections() *common.CollectionConfigPackage {
+	if m != nil {
+		return m.Collections
+	}
+	return nil
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetInitRequired() bool {
+	if m != nil {
+		return m.InitRequired
+	}
+	return false
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetSource() *ChaincodeSource {
+	if m != nil {
+		return m.Source
+	}
+	return nil
+}
+
+type ChaincodeSource struct {
+	// Types that are valid to be assigned to Type:
+	//	*ChaincodeSource_Unavailable_
+	//	*ChaincodeSource_LocalPackage
+	Type                 isChaincodeSource_Type `protobuf_oneof:"Type"`
+	XXX_NoUnkeyedLiteral struct{}               `json:"-"`
+	XXX_unrecognized     []byte                 `json:"-"`
+	XXX_sizecache        int32                  `json:"-"`
+}
+
+func (m *ChaincodeSource) Reset()         { *m = ChaincodeSource{} }
+func (m *ChaincodeSource) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeSource) ProtoMessage()    {}
+func (*ChaincodeSource) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{9}
+}
+func (m *ChaincodeSource) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeSource.Unmarshal(m, b)
+}
+func (m *ChaincodeSource) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeSource.Marshal(b, m, deterministic)
+}
+func (dst *ChaincodeSource) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeSource.Merge(dst, src)
+}
+func (m *ChaincodeSource) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeSource.Size(m)
+}
+func (m *ChaincodeSource) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeSource.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeSource proto.InternalMessageInfo
+
+type isChaincodeSource_Type interface {
+	isChaincodeSource_Type()
+}
+
+type ChaincodeSource_Unavailable_ struct {
+	Unavailable *ChaincodeSource_Unavailable `protobuf:"bytes,1,opt,name=unavailable,proto3,oneof"`
+}
+
+type ChaincodeSource_LocalPackage struct {
+	LocalPackage *ChaincodeSource_Local `protobuf:"bytes,2,opt,name=local_package,json=localPackage,proto3,oneof"`
+}
+
+func (*ChaincodeSource_Unavailable_) isChaincodeSource_Type() {}
+
+func (*ChaincodeSource_LocalPackage) isChaincodeSource_Type() {}
+
+func (m *ChaincodeSource) GetType() isChaincodeSource_Type {
+	if m != nil {
+		return m.Type
+	}
+	return nil
+}
+
+func (m *ChaincodeSource) GetUnavailable() *ChaincodeSource_Unavailable {
+	if x, ok := m.GetType().(*ChaincodeSource_Unavailable_); ok {
+		return x.Unavailable
+	}
+	return nil
+}
+
+func (m *ChaincodeSource) GetLocalPackage() *ChaincodeSource_Local {
+	if x, ok := m.GetType().(*ChaincodeSource_LocalPackage); ok {
+		return x.LocalPackage
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*ChaincodeSource) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _ChaincodeSource_OneofMarshaler, _ChaincodeSource_OneofUnmarshaler, _ChaincodeSource_OneofSizer, []interface{}{
+		(*ChaincodeSource_Unavailable_)(nil),
+		(*ChaincodeSource_LocalPackage)(nil),
+	}
+}
+
+func _ChaincodeSource_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*ChaincodeSource)
+	// Type
+	switch x := m.Type.(type) {
+	case *ChaincodeSource_Unavailable_:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Unavailable); err != nil {
+			return err
+		}
+	case *ChaincodeSource_LocalPackage:
+		b.EncodeVarint(2<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.LocalPackage); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("ChaincodeSource.Type has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _ChaincodeSource_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*ChaincodeSource)
+	switch tag {
+	case 1: // Type.unavailable
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ChaincodeSource_Unavailable)
+		err := b.DecodeMessage(msg)
+		m.Type = &ChaincodeSource_Unavailable_{msg}
+		return true, err
+	case 2: // Type.local_package
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ChaincodeSource_Local)
+		err := b.DecodeMessage(msg)
+		m.Type = &ChaincodeSource_LocalPackage{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _ChaincodeSource_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*ChaincodeSource)
+	// Type
+	switch x := m.Type.(type) {
+	case *ChaincodeSource_Unavailable_:
+		s := proto.Size(x.Unavailable)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *ChaincodeSource_LocalPackage:
+		s := proto.Size(x.LocalPackage)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+type ChaincodeSource_Unavailable struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChaincodeSource_Unavailable) Reset()         { *m = ChaincodeSource_Unavailable{} }
+func (m *ChaincodeSource_Unavailable) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeSource_Unavailable) ProtoMessage()    {}
+func (*ChaincodeSource_Unavailable) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{9, 0}
+}
+func (m *ChaincodeSource_Unavailable) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeSource_Unavailable.Unmarshal(m, b)
+}
+func (m *ChaincodeSource_Unavailable) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeSource_Unavailable.Marshal(b, m, deterministic)
+}
+func (dst *ChaincodeSource_Unavailable) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeSource_Unavailable.Merge(dst, src)
+}
+func (m *ChaincodeSource_Unavailable) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeSource_Unavailable.Size(m)
+}
+func (m *ChaincodeSource_Unavailable) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeSource_Unavailable.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeSource_Unavailable proto.InternalMessageInfo
+
+type ChaincodeSource_Local struct {
+	PackageId            string   `protobuf:"bytes,1,opt,name=package_id,json=packageId,proto3" json:"package_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChaincodeSource_Local) Reset()         { *m = ChaincodeSource_Local{} }
+func (m *ChaincodeSource_Local) String() string { return proto.CompactTextString(m) }
+func (*ChaincodeSource_Local) ProtoMessage()    {}
+func (*ChaincodeSource_Local) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{9, 1}
+}
+func (m *ChaincodeSource_Local) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChaincodeSource_Local.Unmarshal(m, b)
+}
+func (m *ChaincodeSource_Local) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChaincodeSource_Local.Marshal(b, m, deterministic)
+}
+func (dst *ChaincodeSource_Local) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChaincodeSource_Local.Merge(dst, src)
+}
+func (m *ChaincodeSource_Local) XXX_Size() int {
+	return xxx_messageInfo_ChaincodeSource_Local.Size(m)
+}
+func (m *ChaincodeSource_Local) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChaincodeSource_Local.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChaincodeSource_Local proto.InternalMessageInfo
+
+func (m *ChaincodeSource_Local) GetPackageId() string {
+	if m != nil {
+		return m.PackageId
+	}
+	return ""
+}
+
+// ApproveChaincodeDefinitionForMyOrgResult is the message returned by
+// `_lifecycle.ApproveChaincodeDefinitionForMyOrg`. Currently it returns
+// nothing, but may be extended in the future.
+type ApproveChaincodeDefinitionForMyOrgResult struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgResult) Reset() {
+	*m = ApproveChaincodeDefinitionForMyOrgResult{}
+}
+func (m *ApproveChaincodeDefinitionForMyOrgResult) String() string { return proto.CompactTextString(m) }
+func (*ApproveChaincodeDefinitionForMyOrgResult) ProtoMessage()    {}
+func (*ApproveChaincodeDefinitionForMyOrgResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{10}
+}
+func (m *ApproveChaincodeDefinitionForMyOrgResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult.Unmarshal(m, b)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult.Marshal(b, m, deterministic)
+}
+func (dst *ApproveChaincodeDefinitionForMyOrgResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult.Merge(dst, src)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgResult) XXX_Size() int {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult.Size(m)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult proto.InternalMessageInfo
+
+// CommitChaincodeDefinitionArgs is the message used as arguments to
+// `_lifecycle.CommitChaincodeDefinition`.
+type CommitChaincodeDefinitionArgs struct {
+	Sequence             int64                           `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Name                 string                          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version              string                          `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	EndorsementPlugin    string                          `protobuf:"bytes,4,opt,name=endorsement_plugin,json=endorsementPlugin,proto3" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin     string                          `protobuf:"bytes,5,opt,name=validation_plugin,json=validationPlugin,proto3" json:"validation_plugin,omitempty"`
+	ValidationParameter  []byte                          `protobuf:"bytes,6,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections          *common.CollectionConfigPackage `protobuf:"bytes,7,opt,name=collections,proto3" json:"collections,omitempty"`
+	InitRequired         bool                            `protobuf:"varint,8,opt,name=init_required,json=initRequired,proto3" json:"init_required,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *CommitChaincodeDefinitionArgs) Reset()         { *m = CommitChaincodeDefinitionArgs{} }
+func (m *CommitChaincodeDefinitionArgs) String() string { return proto.CompactTextString(m) }
+func (*CommitChaincodeDefinitionArgs) ProtoMessage()    {}
+func (*CommitChaincodeDefinitionArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{11}
+}
+func (m *CommitChaincodeDefinitionArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitChaincodeDefinitionArgs.Unmarshal(m, b)
+}
+func (m *CommitChaincodeDefinitionArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitChaincodeDefinitionArgs.Marshal(b, m, deterministic)
+}
+func (dst *CommitChaincodeDefinitionArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitChaincodeDefinitionArgs.Merge(dst, src)
+}
+func (m *CommitChaincodeDefinitionArgs) XXX_Size() int {
+	return xxx_messageInfo_CommitChaincodeDefinitionArgs.Size(m)
+}
+func (m *CommitChaincodeDefinitionArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitChaincodeDefinitionArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitChaincodeDefinitionArgs proto.InternalMessageInfo
+
+func (m *CommitChaincodeDefinitionArgs) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *CommitChaincodeDefinitionArgs) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CommitChaincodeDefinitionArgs) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *CommitChaincodeDefinitionArgs) GetEndorsementPlugin() string {
+	if m != nil {
+		return m.EndorsementPlugin
+	}
+	return ""
+}
+
+func (m *CommitChaincodeDefinitionArgs) GetValidationPlugin() string {
+	if m != nil {
+		return m.ValidationPlugin
+	}
+	return ""
+}
+
+func (m *CommitChaincodeDefinitionArgs) GetValidationParameter() []byte {
+	if m != nil {
+		return m.ValidationParameter
+	}
+	return nil
+}
+
+func (m *CommitChaincodeDefinitionArgs) GetCollections() *common.CollectionConfigPackage {
+	if m != nil {
+		return m.Collections
+	}
+	return nil
+}
+
+func (m *CommitChaincodeDefinitionArgs) GetInitRequired() bool {
+	if m != nil {
+		return m.InitRequired
+	}
+	return false
+}
+
+// CommitChaincodeDefinitionResult is the message returned by
+// `_lifecycle.CommitChaincodeDefinition`. Currently it returns
+// nothing, but may be extended in the future.
+type CommitChaincodeDefinitionResult struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CommitChaincodeDefinitionResult) Reset()         { *m = CommitChaincodeDefinitionResult{} }
+func (m *CommitChaincodeDefinitionResult) String() string { return proto.CompactTextString(m) }
+func (*CommitChaincodeDefinitionResult) ProtoMessage()    {}
+func (*CommitChaincodeDefinitionResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{12}
+}
+func (m *CommitChaincodeDefinitionResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitChaincodeDefinitionResult.Unmarshal(m, b)
+}
+func (m *CommitChaincodeDefinitionResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitChaincodeDefinitionResult.Marshal(b, m, deterministic)
+}
+func (dst *CommitChaincodeDefinitionResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitChaincodeDefinitionResult.Merge(dst, src)
+}
+func (m *CommitChaincodeDefinitionResult) XXX_Size() int {
+	return xxx_messageInfo_CommitChaincodeDefinitionResult.Size(m)
+}
+func (m *CommitChaincodeDefinitionResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitChaincodeDefinitionResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitChaincodeDefinitionResult proto.InternalMessageInfo
+
+// CheckCommitReadinessArgs is the message used as arguments to
+// `_lifecycle.CheckCommitReadiness`.
+type CheckCommitReadinessArgs struct {
+	Sequence             int64                           `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Name                 string                          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version              string                          `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	EndorsementPlugin    string                          `protobuf:"bytes,4,opt,name=endorsement_plugin,json=endorsementPlugin,proto3" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin     string                          `protobuf:"bytes,5,opt,name=validation_plugin,json=validationPlugin,proto3" json:"validation_plugin,omitempty"`
+	ValidationParameter  []byte                          `protobuf:"bytes,6,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections          *common.CollectionConfigPackage `protobuf:"bytes,7,opt,name=collections,proto3" json:"collections,omitempty"`
+	InitRequired         bool                            `protobuf:"varint,8,opt,name=init_required,json=initRequired,proto3" json:"init_required,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *CheckCommitReadinessArgs) Reset()         { *m = CheckCommitReadinessArgs{} }
+func (m *CheckCommitReadinessArgs) String() string { return proto.CompactTextString(m) }
+func (*CheckCommitReadinessArgs) ProtoMessage()    {}
+func (*CheckCommitReadinessArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{13}
+}
+func (m *CheckCommitReadinessArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckCommitReadinessArgs.Unmarshal(m, b)
+}
+func (m *CheckCommitReadinessArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckCommitReadinessArgs.Marshal(b, m, deterministic)
+}
+func (dst *CheckCommitReadinessArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckCommitReadinessArgs.Merge(dst, src)
+}
+func (m *CheckCommitReadinessArgs) XXX_Size() int {
+	return xxx_messageInfo_CheckCommitReadinessArgs.Size(m)
+}
+func (m *CheckCommitReadinessArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckCommitReadinessArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckCommitReadinessArgs proto.InternalMessageInfo
+
+func (m *CheckCommitReadinessArgs) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *CheckCommitReadinessArgs) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CheckCommitReadinessArgs) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *CheckCommitReadinessArgs) GetEndorsementPlugin() string {
+	if m != nil {
+		return m.EndorsementPlugin
+	}
+	return ""
+}
+
+func (m *CheckCommitReadinessArgs) GetValidationPlugin() string {
+	if m != nil {
+		return m.ValidationPlugin
+	}
+	return ""
+}
+
+func (m *CheckCommitReadinessArgs) GetValidationParameter() []byte {
+	if m != nil {
+		return m.ValidationParameter
+	}
+	return nil
+}
+
+func (m *CheckCommitReadinessArgs) GetCollections() *common.CollectionConfigPackage {
+	if m != nil {
+		return m.Collections
+	}
+	return nil
+}
+
+func (m *CheckCommitReadinessArgs) GetInitRequired() bool {
+	if m != nil {
+		return m.InitRequired
+	}
+	return false
+}
+
+// CheckCommitReadinessResult is the message returned by
+// `_lifecycle.CheckCommitReadiness`. It returns a map of
+// orgs to their approval (true/false) for the definition
+// supplied as args.
+type CheckCommitReadinessResult struct {
+	Approvals            map[string]bool `protobuf:"bytes,1,rep,name=approvals,proto3" json:"approvals,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *CheckCommitReadinessResult) Reset()         { *m = CheckCommitReadinessResult{} }
+func (m *CheckCommitReadinessResult) String() string { return proto.CompactTextString(m) }
+func (*CheckCommitReadinessResult) ProtoMessage()    {}
+func (*CheckCommitReadinessResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{14}
+}
+func (m *CheckCommitReadinessResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CheckCommitReadinessResult.Unmarshal(m, b)
+}
+func (m *CheckCommitReadinessResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CheckCommitReadinessResult.Marshal(b, m, deterministic)
+}
+func (dst *CheckCommitReadinessResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CheckCommitReadinessResult.Merge(dst, src)
+}
+func (m *CheckCommitReadinessResult) XXX_Size() int {
+	return xxx_messageInfo_CheckCommitReadinessResult.Size(m)
+}
+func (m *CheckCommitReadinessResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_CheckCommitReadinessResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CheckCommitReadinessResult proto.InternalMessageInfo
+
+func (m *CheckCommitReadinessResult) GetApprovals() map[string]bool {
+	if m != nil {
+		return m.Approvals
+	}
+	return nil
+}
+
+// QueryApprovedChaincodeDefinitionArgs is the message used as arguments to
+// `_lifecycle.QueryApprovedChaincodeDefinition`.
+type QueryApprovedChaincodeDefinitionArgs struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Sequence             int64    `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryApprovedChaincodeDefinitionArgs) Reset()         { *m = QueryApprovedChaincodeDefinitionArgs{} }
+func (m *QueryApprovedChaincodeDefinitionArgs) String() string { return proto.CompactTextString(m) }
+func (*QueryApprovedChaincodeDefinitionArgs) ProtoMessage()    {}
+func (*QueryApprovedChaincodeDefinitionArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{15}
+}
+func (m *QueryApprovedChaincodeDefinitionArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryApprovedChaincodeDefinitionArgs.Unmarshal(m, b)
+}
+func (m *QueryApprovedChaincodeDefinitionArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryApprovedChaincodeDefinitionArgs.Marshal(b, m, deterministic)
+}
+func (dst *QueryApprovedChaincodeDefinitionArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryApprovedChaincodeDefinitionArgs.Merge(dst, src)
+}
+func (m *QueryApprovedChaincodeDefinitionArgs) XXX_Size() int {
+	return xxx_messageInfo_QueryApprovedChaincodeDefinitionArgs.Size(m)
+}
+func (m *QueryApprovedChaincodeDefinitionArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryApprovedChaincodeDefinitionArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryApprovedChaincodeDefinitionArgs proto.InternalMessageInfo
+
+func (m *QueryApprovedChaincodeDefinitionArgs) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *QueryApprovedChaincodeDefinitionArgs) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+// QueryApprovedChaincodeDefinitionResult is the message returned by
+// `_lifecycle.QueryApprovedChaincodeDefinition`.
+type QueryApprovedChaincodeDefinitionResult struct {
+	Sequence             int64                           `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Version              string                          `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	EndorsementPlugin    string                          `protobuf:"bytes,3,opt,name=endorsement_plugin,json=endorsementPlugin,proto3" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin     string                          `protobuf:"bytes,4,opt,name=validation_plugin,json=validationPlugin,proto3" json:"validation_plugin,omitempty"`
+	ValidationParameter  []byte                          `protobuf:"bytes,5,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections          *common.CollectionConfigPackage `protobuf:"bytes,6,opt,name=collections,proto3" json:"collections,omitempty"`
+	InitRequired         bool                            `protobuf:"varint,7,opt,name=init_required,json=initRequired,proto3" json:"init_required,omitempty"`
+	Source               *ChaincodeSource                `protobuf:"bytes,8,opt,name=source,proto3" json:"source,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *QueryApprovedChaincodeDefinitionResult) Reset() {
+	*m = QueryApprovedChaincodeDefinitionResult{}
+}
+func (m *QueryApprovedChaincodeDefinitionResult) String() string { return proto.CompactTextString(m) }
+func (*QueryApprovedChaincodeDefinitionResult) ProtoMessage()    {}
+func (*QueryApprovedChaincodeDefinitionResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{16}
+}
+func (m *QueryApprovedChaincodeDefinitionResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryApprovedChaincodeDefinitionResult.Unmarshal(m, b)
+}
+func (m *QueryApprovedChaincodeDefinitionResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryApprovedChaincodeDefinitionResult.Marshal(b, m, deterministic)
+}
+func (dst *QueryApprovedChaincodeDefinitionResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryApprovedChaincodeDefinitionResult.Merge(dst, src)
+}
+func (m *QueryApprovedChaincodeDefinitionResult) XXX_Size() int {
+	return xxx_messageInfo_QueryApprovedChaincodeDefinitionResult.Size(m)
+}
+func (m *QueryApprovedChaincodeDefinitionResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryApprovedChaincodeDefinitionResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryApprovedChaincodeDefinitionResult proto.InternalMessageInfo
+
+func (m *QueryApprovedChaincodeDefinitionResult) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *QueryApprovedChaincodeDefinitionResult) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *QueryApprovedChaincodeDefinitionResult) GetEndorsementPlugin() string {
+	if m != nil {
+		return m.EndorsementPlugin
+	}
+	return ""
+}
+
+func (m *QueryApprovedChaincodeDefinitionResult) GetValidationPlugin() string {
+	if m != nil {
+		return m.ValidationPlugin
+	}
+	return ""
+}
+
+func (m *QueryApprovedChaincodeDefinitionResult) GetValidationParameter() []byte {
+	if m != nil {
+		return m.ValidationParameter
+	}
+	return nil
+}
+
+func (m *QueryApprovedChaincodeDefinitionResult) GetCollections() *common.CollectionConfigPackage {
+	if m != nil {
+		return m.Collections
+	}
+	return nil
+}
+
+func (m *QueryApprovedChaincodeDefinitionResult) GetInitRequired() bool {
+	if m != nil {
+		return m.InitRequired
+	}
+	return false
+}
+
+func (m *QueryApprovedChaincodeDefinitionResult) GetSource() *ChaincodeSource {
+	if m != nil {
+		return m.Source
+	}
+	return nil
+}
+
+// QueryChaincodeDefinitionArgs is the message used as arguments to
+// `_lifecycle.QueryChaincodeDefinition`.
+type QueryChaincodeDefinitionArgs struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryChaincodeDefinitionArgs) Reset()         { *m = QueryChaincodeDefinitionArgs{} }
+func (m *QueryChaincodeDefinitionArgs) String() string { return proto.CompactTextString(m) }
+func (*QueryChaincodeDefinitionArgs) ProtoMessage()    {}
+func (*QueryChaincodeDefinitionArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{17}
+}
+func (m *QueryChaincodeDefinitionArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryChaincodeDefinitionArgs.Unmarshal(m, b)
+}
+func (m *QueryChaincodeDefinitionArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryChaincodeDefinitionArgs.Marshal(b, m, deterministic)
+}
+func (dst *QueryChaincodeDefinitionArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryChaincodeDefinitionArgs.Merge(dst, src)
+}
+func (m *QueryChaincodeDefinitionArgs) XXX_Size() int {
+	return xxx_messageInfo_QueryChaincodeDefinitionArgs.Size(m)
+}
+func (m *QueryChaincodeDefinitionArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryChaincodeDefinitionArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryChaincodeDefinitionArgs proto.InternalMessageInfo
+
+func (m *QueryChaincodeDefinitionArgs) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// QueryChaincodeDefinitionResult is the message returned by
+// `_lifecycle.QueryChaincodeDefinition`.
+type QueryChaincodeDefinitionResult struct {
+	Sequence             int64                           `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Version              string                          `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	EndorsementPlugin    string                          `protobuf:"bytes,3,opt,name=endorsement_plugin,json=endorsementPlugin,proto3" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin     string                          `protobuf:"bytes,4,opt,name=validation_plugin,json=validationPlugin,proto3" json:"validation_plugin,omitempty"`
+	ValidationParameter  []byte                          `protobuf:"bytes,5,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections          *common.CollectionConfigPackage `protobuf:"bytes,6,opt,name=collections,proto3" json:"collections,omitempty"`
+	InitRequired         bool                            `protobuf:"varint,7,opt,name=init_required,json=initRequired,proto3" json:"init_required,omitempty"`
+	Approvals            map[string]bool                 `protobuf:"bytes,8,rep,name=approvals,proto3" json:"approvals,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *QueryChaincodeDefinitionResult) Reset()         { *m = QueryChaincodeDefinitionResult{} }
+func (m *QueryChaincodeDefinitionResult) String() string { return proto.CompactTextString(m) }
+func (*QueryChaincodeDefinitionResult) ProtoMessage()    {}
+func (*QueryChaincodeDefinitionResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{18}
+}
+func (m *QueryChaincodeDefinitionResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryChaincodeDefinitionResult.Unmarshal(m, b)
+}
+func (m *QueryChaincodeDefinitionResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryChaincodeDefinitionResult.Marshal(b, m, deterministic)
+}
+func (dst *QueryChaincodeDefinitionResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryChaincodeDefinitionResult.Merge(dst, src)
+}
+func (m *QueryChaincodeDefinitionResult) XXX_Size() int {
+	return xxx_messageInfo_QueryChaincodeDefinitionResult.Size(m)
+}
+func (m *QueryChaincodeDefinitionResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryChaincodeDefinitionResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryChaincodeDefinitionResult proto.InternalMessageInfo
+
+func (m *QueryChaincodeDefinitionResult) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *QueryChaincodeDefinitionResult) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *QueryChaincodeDefinitionResult) GetEndorsementPlugin() string {
+	if m != nil {
+		return m.EndorsementPlugin
+	}
+	return ""
+}
+
+func (m *QueryChaincodeDefinitionResult) GetValidationPlugin() string {
+	if m != nil {
+		return m.ValidationPlugin
+	}
+	return ""
+}
+
+func (m *QueryChaincodeDefinitionResult) GetValidationParameter() []byte {
+	if m != nil {
+		return m.ValidationParameter
+	}
+	return nil
+}
+
+func (m *QueryChaincodeDefinitionResult) GetCollections() *common.CollectionConfigPackage {
+	if m != nil {
+		return m.Collections
+	}
+	return nil
+}
+
+func (m *QueryChaincodeDefinitionResult) GetInitRequired() bool {
+	if m != nil {
+		return m.InitRequired
+	}
+	return false
+}
+
+func (m *QueryChaincodeDefinitionResult) GetApprovals() map[string]bool {
+	if m != nil {
+		return m.Approvals
+	}
+	return nil
+}
+
+// QueryChaincodeDefinitionsArgs is the message used as arguments to
+// `_lifecycle.QueryChaincodeDefinitions`.
+type QueryChaincodeDefinitionsArgs struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryChaincodeDefinitionsArgs) Reset()         { *m = QueryChaincodeDefinitionsArgs{} }
+func (m *QueryChaincodeDefinitionsArgs) String() string { return proto.CompactTextString(m) }
+func (*QueryChaincodeDefinitionsArgs) ProtoMessage()    {}
+func (*QueryChaincodeDefinitionsArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{19}
+}
+func (m *QueryChaincodeDefinitionsArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryChaincodeDefinitionsArgs.Unmarshal(m, b)
+}
+func (m *QueryChaincodeDefinitionsArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryChaincodeDefinitionsArgs.Marshal(b, m, deterministic)
+}
+func (dst *QueryChaincodeDefinitionsArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryChaincodeDefinitionsArgs.Merge(dst, src)
+}
+func (m *QueryChaincodeDefinitionsArgs) XXX_Size() int {
+	return xxx_messageInfo_QueryChaincodeDefinitionsArgs.Size(m)
+}
+func (m *QueryChaincodeDefinitionsArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryChaincodeDefinitionsArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryChaincodeDefinitionsArgs proto.InternalMessageInfo
+
+// QueryChaincodeDefinitionsResult is the message returned by
+// `_lifecycle.QueryChaincodeDefinitions`.
+type QueryChaincodeDefinitionsResult struct {
+	ChaincodeDefinitions []*QueryChaincodeDefinitionsResult_ChaincodeDefinition `protobuf:"bytes,1,rep,name=chaincode_definitions,json=chaincodeDefinitions,proto3" json:"chaincode_definitions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                                               `json:"-"`
+	XXX_unrecognized     []byte                                                 `json:"-"`
+	XXX_sizecache        int32                                                  `json:"-"`
+}
+
+func (m *QueryChaincodeDefinitionsResult) Reset()         { *m = QueryChaincodeDefinitionsResult{} }
+func (m *QueryChaincodeDefinitionsResult) String() string { return proto.CompactTextString(m) }
+func (*QueryChaincodeDefinitionsResult) ProtoMessage()    {}
+func (*QueryChaincodeDefinitionsResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{20}
+}
+func (m *QueryChaincodeDefinitionsResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryChaincodeDefinitionsResult.Unmarshal(m, b)
+}
+func (m *QueryChaincodeDefinitionsResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryChaincodeDefinitionsResult.Marshal(b, m, deterministic)
+}
+func (dst *QueryChaincodeDefinitionsResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryChaincodeDefinitionsResult.Merge(dst, src)
+}
+func (m *QueryChaincodeDefinitionsResult) XXX_Size() int {
+	return xxx_messageInfo_QueryChaincodeDefinitionsResult.Size(m)
+}
+func (m *QueryChaincodeDefinitionsResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryChaincodeDefinitionsResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryChaincodeDefinitionsResult proto.InternalMessageInfo
+
+func (m *QueryChaincodeDefinitionsResult) GetChaincodeDefinitions() []*QueryChaincodeDefinitionsResult_ChaincodeDefinition {
+	if m != nil {
+		return m.ChaincodeDefinitions
+	}
+	return nil
+}
+
+type QueryChaincodeDefinitionsResult_ChaincodeDefinition struct {
+	Name                 string                          `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Sequence             int64                           `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Version              string                          `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	EndorsementPlugin    string                          `protobuf:"bytes,4,opt,name=endorsement_plugin,json=endorsementPlugin,proto3" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin     string                          `protobuf:"bytes,5,opt,name=validation_plugin,json=validationPlugin,proto3" json:"validation_plugin,omitempty"`
+	ValidationParameter  []byte                          `protobuf:"bytes,6,opt,name=validation_parameter,json=validationParameter,proto3" json:"validation_parameter,omitempty"`
+	Collections          *common.CollectionConfigPackage `protobuf:"bytes,7,opt,name=collections,proto3" json:"collections,omitempty"`
+	InitRequired         bool                            `protobuf:"varint,8,opt,name=init_required,json=initRequired,proto3" json:"init_required,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *QueryChaincodeDefinitionsResult_ChaincodeDefinition) Reset() {
+	*m = QueryChaincodeDefinitionsResult_ChaincodeDefinition{}
+}
+func (m *QueryChaincodeDefinitionsResult_ChaincodeDefinition) String() string {
+	return proto.CompactTextString(m)
+}
+func (*QueryChaincodeDefinitionsResult_ChaincodeDefinition) ProtoMessage() {}
+func (*QueryChaincodeDefinitionsResult_ChaincodeDefinition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_0f273e62a308daa3, []int{20, 0}
+}
+func (m *QueryChaincodeDefinitionsResult_ChaincodeDefinition) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryChaincodeDefinitionsResult_ChaincodeDefinition.Unmarshal(m, b)
+}
+func (m *QueryChaincodeDefinitionsResult_ChaincodeDefinition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryChaincodeDefinitionsResult_ChaincodeDefinition.Marshal(b, m, deterministic)
+}
+func (dst *QueryChaincodeDefinitionsResult_ChaincodeDefinition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryChaincodeDefinitionsResult_ChaincodeDefinition.Merge(dst, src)
+}
+func (m *QueryChaincodeDefinitionsResult_ChaincodeDefinition) XXX_Size() int {
+	return xxx_messageInfo_QueryChaincodeDefinitionsResult_ChaincodeDefinition.Size(m)
+}
+func (m *QueryChaincodeDefinitionsResult_ChaincodeDefinition) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryChaincodeDefinitionsResult_ChaincodeDefinition.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryChaincodeDefinitionsResult_ChaincodeDefinition proto.InternalMessageInfo
+
+func (m *QueryChaincodeDefinitionsResult_ChaincodeDefinition) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *QueryChaincodeDefinitionsResult_ChaincodeDefinition) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *QueryChaincodeDefinitionsResult_ChaincodeDefinition) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *QueryChaincodeDefinitionsResult_ChaincodeDefinition) GetEndorsementPlugin() string {
+	if m != nil {
+		return m.EndorsementPlugin
+	}
+	return ""
+}
+
+func (m *QueryChaincodeDefinitionsResult_ChaincodeDefinition) GetValidationPlugin() string {
+	if m != nil {
+		return m.ValidationPlugin
+	}
+	return ""
+}
+
+func (m *QueryChaincodeDefinitionsResult_ChaincodeDefinition) GetValidationParameter() []byte {
+	if m != nil {
+		return m.ValidationParameter
+	}
+	return nil
+}
+
+func (m *QueryChaincodeDefinitionsResult_ChaincodeDefinition) GetCollections() *common.CollectionConfigPackage {
+	if m != nil {
+		return m.Collections
+	}
+	return nil
+}
+
+func (m *QueryChaincodeDefinitionsResult_ChaincodeDefinition) GetInitRequired() bool {
+	if m != nil {
+		return m.InitRequired
+	}
+	return false
+}
+
+func init() {
+	proto.RegisterType((*InstallChaincodeArgs)(nil), "sdk.lifecycle.InstallChaincodeArgs")
+	proto.RegisterType((*InstallChaincodeResult)(nil), "sdk.lifecycle.InstallChaincodeResult")
+	proto.RegisterType((*QueryInstalledChaincodeArgs)(nil), "sdk.lifecycle.QueryInstalledChaincodeArgs")
+	proto.RegisterType((*QueryInstalledChaincodeResult)(nil), "sdk.lifecycle.QueryInstalledChaincodeResult")
+	proto.RegisterMapType((map[string]*QueryInstalledChaincodeResult_References)(nil), "sdk.lifecycle.QueryInstalledChaincodeResult.ReferencesEntry")
+	proto.RegisterType((*QueryInstalledChaincodeResult_References)(nil), "sdk.lifecycle.QueryInstalledChaincodeResult.References")
+	proto.RegisterType((*QueryInstalledChaincodeResult_Chaincode)(nil), "sdk.lifecycle.QueryInstalledChaincodeResult.Chaincode")
+	proto.RegisterType((*GetInstalledChaincodePackageArgs)(nil), "sdk.lifecycle.GetInstalledChaincodePackageArgs")
+	proto.RegisterType((*GetInstalledChaincodePackageResult)(nil), "sdk.lifecycle.GetInstalledChaincodePackageResult")
+	proto.RegisterType((*QueryInstalledChaincodesArgs)(nil), "sdk.lifecycle.QueryInstalledChaincodesArgs")
+	proto.RegisterType((*QueryInstalledChaincodesResult)(nil), "sdk.lifecycle.QueryInstalledChaincodesResult")
+	proto.RegisterType((*QueryInstalledChaincodesResult_InstalledChaincode)(nil), "sdk.lifecycle.QueryInstalledChaincodesResult.InstalledChaincode")
+	proto.RegisterMapType((map[string]*QueryInstalledChaincodesResult_References)(nil), "sdk.lifecycle.QueryInstalledChaincodesResult.InstalledChaincode.ReferencesEntry")
+	proto.RegisterType((*QueryInstalledChaincodesResult_References)(nil), "sdk.lifecycle.QueryInstalledChaincodesResult.References")
+	proto.RegisterType((*QueryInstalledChaincodesResult_Chaincode)(nil), "sdk.lifecycle.QueryInstalledChaincodesResult.Chaincode")
+	proto.RegisterType((*ApproveChaincodeDefinitionForMyOrgArgs)(nil), "sdk.lifecycle.ApproveChaincodeDefinitionForMyOrgArgs")
+	proto.RegisterType((*ChaincodeSource)(nil), "sdk.lifecycle.ChaincodeSource")
+	proto.RegisterType((*ChaincodeSource_Unavailable)(nil), "sdk.lifecycle.ChaincodeSource.Unavailable")
+	proto.RegisterType((*ChaincodeSource_Local)(nil), "sdk.lifecycle.ChaincodeSource.Local")
+	proto.RegisterType((*ApproveChaincodeDefinitionForMyOrgResult)(nil), "sdk.lifecycle.ApproveChaincodeDefinitionForMyOrgResult")
+	proto.RegisterType((*CommitChaincodeDefinitionArgs)(nil), "sdk.lifecycle.CommitChaincodeDefinitionArgs")
+	proto.RegisterType((*CommitChaincodeDefinitionResult)(nil), "sdk.lifecycle.CommitChaincodeDefinitionResult")
+	proto.RegisterType((*CheckCommitReadinessArgs)(nil), "sdk.lifecycle.CheckCommitReadinessArgs")
+	proto.RegisterType((*CheckCommitReadinessResult)(nil), "sdk.lifecycle.CheckCommitReadinessResult")
+	proto.RegisterMapType((map[string]bool)(nil), "sdk.lifecycle.CheckCommitReadinessResult.ApprovalsEntry")
+	proto.RegisterType((*QueryApprovedChaincodeDefinitionArgs)(nil), "sdk.lifecycle.QueryApprovedChaincodeDefinitionArgs")
+	proto.RegisterType((*QueryApprovedChaincodeDefinitionResult)(nil), "sdk.lifecycle.QueryApprovedChaincodeDefinitionResult")
+	proto.RegisterType((*QueryChaincodeDefinitionArgs)(nil), "sdk.lifecycle.QueryChaincodeDefinitionArgs")
+	proto.RegisterType((*QueryChaincodeDefinitionResult)(nil), "sdk.lifecycle.QueryChaincodeDefinitionResult")
+	proto.RegisterMapType((map[string]bool)(nil), "sdk.lifecycle.QueryChaincodeDefinitionResult.ApprovalsEntry")
+	proto.RegisterType((*QueryChaincodeDefinitionsArgs)(nil), "sdk.lifecycle.QueryChaincodeDefinitionsArgs")
+	proto.RegisterType((*QueryChaincodeDefinitionsResult)(nil), "sdk.lifecycle.QueryChaincodeDefinitionsResult")
+	proto.RegisterType((*QueryChaincodeDefinitionsResult_ChaincodeDefinition)(nil), "sdk.lifecycle.QueryChaincodeDefinitionsResult.ChaincodeDefinition")
+}
+
+func init() {
+	proto.RegisterFile("peer/lifecycle/lifecycle.proto", fileDescriptor_lifecycle_0f273e62a308daa3)
+}
+
+var fileDescriptor_lifecycle_0f273e62a308daa3 = []byte{
+	// 1037 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x58, 0x5f, 0x6f, 0xdb, 0x54,
+	0x14, 0x5f, 0xe2, 0xfe, 0x49, 0x4e, 0x5a, 0xb6, 0xdd, 0x06, 0x66, 0x0c, 0x6d, 0x83, 0x41, 0x55,
+	0x05, 0xd4, 0x11, 0xe9, 0x1e, 0xc6, 0x54, 0x21, 0x65, 0x01, 0xb6, 0x4e, 0x9b, 0x18, 0x1e, 0x4c,
+	0x88, 0x97, 0xec, 0xc6, 0x3e, 0x49, 0xae, 0xea, 0xd8, 0xd9, 0xb5, 0x13, 0x29, 0x1f, 0x81, 0x77,
+	0x5e, 0xf9, 0x06, 0x88, 0xaf, 0xc0, 0xb7, 0xe0, 0x05, 0x09, 0x21, 0x21, 0x9e, 0xf9, 0x0a, 0xc8,
+	0xd7, 0x37, 0xb6, 0xd3, 0xd8, 0x69, 0xb2, 0x76, 0x6f, 0x7d, 0xb3, 0xef, 0xf9, 0x9d, 0xdf, 0xb9,
+	0xf7, 0x9c, 0xdf, 0xb9, 0x27, 0x0e, 0xec, 0x0d, 0x11, 0x79, 0xdd, 0x61, 0x5d, 0xb4, 0x26, 0x96,
+	0x83, 0xc9, 0x93, 0x31, 0xe4, 0x5e, 0xe0, 0x91, 0x72, 0xbc, 0xa0, 0xdd, 0xb1, 0xbc, 0xc1, 0xc0,
+	0x73, 0xeb, 0x96, 0xe7, 0x38, 0x68, 0x05, 0xcc, 0x73, 0x23, 0x8c, 0x6e, 0x42, 0xf5, 0xd4, 0xf5,
+	0x03, 0xea, 0x38, 0xad, 0x3e, 0x65, 0xae, 0xe5, 0xd9, 0xd8, 0xe4, 0x3d, 0x9f, 0xdc, 0x87, 0x77,
+	0xad, 0xe9, 0x42, 0x9b, 0x45, 0x88, 0xf6, 0x90, 0x5a, 0x67, 0xb4, 0x87, 0x6a, 0xa1, 0x56, 0x38,
+	0xdc, 0x32, 0xef, 0xc4, 0x00, 0xc9, 0xf0, 0x2c, 0x32, 0xeb, 0x4f, 0xe1, 0x9d, 0xf3, 0x9c, 0x26,
+	0xfa, 0x23, 0x27, 0x20, 0xbb, 0x00, 0x92, 0xa3, 0xcd, 0x6c, 0x41, 0x53, 0x36, 0xcb, 0x72, 0xe5,
+	0xd4, 0x26, 0x55, 0x58, 0x77, 0x68, 0x07, 0x1d, 0xb5, 0x28, 0x2c, 0xd1, 0x8b, 0x7e, 0x02, 0xef,
+	0x7d, 0x3b, 0x42, 0x3e, 0x91, 0x9c, 0x68, 0xcf, 0xee, 0x74, 0x31, 0xa7, 0xfe, 0xbb, 0x02, 0xbb,
+	0x39, 0xee, 0x97, 0xd8, 0x14, 0xf9, 0x01, 0x80, 0x63, 0x17, 0x39, 0xba, 0x16, 0xfa, 0xaa, 0x52,
+	0x53, 0x0e, 0x2b, 0x8d, 0x7b, 0x46, 0x52, 0x81, 0x85, 0x21, 0x0d, 0x33, 0x76, 0xfd, 0xca, 0x0d,
+	0xf8, 0xc4, 0x4c, 0x71, 0x69, 0x1c, 0x6e, 0x9e, 0x33, 0x93, 0x5b, 0xa0, 0x9c, 0xe1, 0x44, 0x6e,
+	0x2d, 0x7c, 0x24, 0xa7, 0xb0, 0x3e, 0xa6, 0xce, 0x08, 0xc5, 0xa6, 0x2a, 0x8d, 0xe3, 0xd7, 0x88,
+	0x6c, 0x46, 0x0c, 0xf7, 0x8b, 0xf7, 0x0a, 0xda, 0x4b, 0x80, 0xc4, 0x40, 0x4c, 0x80, 0xb8, 0xb4,
+	0xbe, 0x5a, 0x10, 0x67, 0x6b, 0x2c, 0x1d, 0x21, 0x79, 0x4f, 0xb1, 0x68, 0x9f, 0x43, 0x39, 0x36,
+	0x10, 0x02, 0x6b, 0x2e, 0x1d, 0xa0, 0x3c, 0x90, 0x78, 0x26, 0x2a, 0x6c, 0x8e, 0x91, 0xfb, 0xcc,
+	0x73, 0x65, 0xa2, 0xa7, 0xaf, 0x7a, 0x13, 0x6a, 0x0f, 0x31, 0x98, 0x8f, 0x27, 0xe5, 0xb6, 0x8c,
+	0x08, 0x5e, 0x82, 0xbe, 0x88, 0x42, 0x0a, 0xe1, 0x32, 0x9a, 0xdf, 0x83, 0xf7, 0x73, 0xd2, 0xe2,
+	0x87, 0x1b, 0xd4, 0xff, 0x5a, 0x83, 0xbd, 0x3c, 0x80, 0x0c, 0xef, 0x41, 0x95, 0x4d, 0x8d, 0xed,
+	0xb9, 0x02, 0x9c, 0x5c, 0x5c, 0x00, 0x49, 0x64, 0xcc, 0x5b, 0xcc, 0x1d, 0x36, 0x8f, 0xd6, 0x7e,
+	0x2d, 0x02, 0x99, 0xc7, 0xbe, 0x5e, 0x3f, 0x38, 0x19, 0xfd, 0xf0, 0xe4, 0x32, 0x5b, 0x5e, 0xd8,
+	0x23, 0xfe, 0x32, 0x3d, 0xf2, 0x78, 0xb6, 0x47, 0xee, 0x2e, 0xbf, 0x9b, 0xec, 0x26, 0xa1, 0x33,
+	0x4d, 0xf2, 0x3c, 0xa3, 0x49, 0x8e, 0x97, 0x0f, 0x71, 0xe5, 0x5d, 0xf2, 0x8b, 0x02, 0x07, 0xcd,
+	0xe1, 0x90, 0x7b, 0x63, 0x8c, 0x29, 0xbe, 0xc4, 0x2e, 0x73, 0x59, 0x78, 0xdb, 0x7f, 0xed, 0xf1,
+	0xa7, 0x93, 0x6f, 0x78, 0x4f, 0x34, 0x8b, 0x06, 0x25, 0x1f, 0x5f, 0x8d, 0xc2, 0x73, 0x08, 0x72,
+	0xc5, 0x8c, 0xdf, 0xe3, 0xa0, 0xc5, 0xec, 0xa0, 0xca, 0x4c, 0x50, 0x72, 0x04, 0x04, 0x5d, 0xdb,
+	0xe3, 0x3e, 0x0e, 0xd0, 0x0d, 0xda, 0x43, 0x67, 0xd4, 0x63, 0xae, 0xba, 0x26, 0x40, 0xb7, 0x53,
+	0x96, 0x67, 0xc2, 0x40, 0x3e, 0x81, 0xdb, 0x63, 0xea, 0x30, 0x9b, 0x86, 0x5b, 0x9a, 0xa2, 0xd7,
+	0x05, 0xfa, 0x56, 0x62, 0x90, 0xe0, 0xcf, 0xa0, 0x9a, 0x06, 0x53, 0x4e, 0x07, 0x18, 0x20, 0x57,
+	0x37, 0x44, 0x23, 0xee, 0xa4, 0xf0, 0x53, 0x13, 0x69, 0x42, 0x25, 0x19, 0x70, 0xbe, 0xba, 0x29,
+	0xea, 0xbe, 0x6f, 0x44, 0xb3, 0xcf, 0x68, 0xc5, 0xa6, 0x96, 0xe7, 0x76, 0x59, 0x6f, 0xda, 0xfc,
+	0x69, 0x1f, 0xf2, 0x21, 0x6c, 0x87, 0x29, 0x6b, 0x73, 0x7c, 0x35, 0x62, 0x1c, 0x6d, 0xb5, 0x54,
+	0x2b, 0x1c, 0x96, 0xcc, 0xad, 0x70, 0xd1, 0x94, 0x6b, 0xa4, 0x01, 0x1b, 0xbe, 0x37, 0xe2, 0x16,
+	0xaa, 0x65, 0x11, 0x42, 0x4b, 0xd5, 0x3d, 0x4e, 0xfe, 0x73, 0x81, 0x30, 0x25, 0x52, 0xff, 0xb7,
+	0x00, 0x37, 0xcf, 0xd9, 0xc8, 0x63, 0xa8, 0x8c, 0x5c, 0x3a, 0xa6, 0xcc, 0xa1, 0x1d, 0x27, 0xaa,
+	0x45, 0xa5, 0x71, 0x90, 0x4f, 0x66, 0x7c, 0x9f, 0xa0, 0x1f, 0xdd, 0x30, 0xd3, 0xce, 0xe4, 0x21,
+	0x6c, 0x3b, 0x9e, 0x45, 0x93, 0x0b, 0x2b, 0x52, 0x7d, 0x6d, 0x01, 0xdb, 0x93, 0x10, 0xff, 0xe8,
+	0x86, 0xb9, 0x25, 0x1c, 0x65, 0x3a, 0xb4, 0x6d, 0xa8, 0xa4, 0xc2, 0x68, 0x07, 0xb0, 0x2e, 0x70,
+	0x17, 0x5c, 0x0b, 0x0f, 0x36, 0x60, 0xed, 0xbb, 0xc9, 0x10, 0xf5, 0x8f, 0xe1, 0xf0, 0x62, 0x19,
+	0x46, 0x4d, 0xa0, 0xff, 0x5d, 0x84, 0xdd, 0x96, 0x37, 0x18, 0xb0, 0x20, 0x03, 0x7b, 0x2d, 0xd5,
+	0x2b, 0x90, 0xaa, 0xfe, 0x01, 0xec, 0xe7, 0x66, 0x58, 0x56, 0xe1, 0xcf, 0x22, 0xa8, 0xad, 0x3e,
+	0x5a, 0x67, 0x11, 0xd0, 0x44, 0x6a, 0x33, 0x17, 0x7d, 0xff, 0xba, 0x00, 0x57, 0x51, 0x80, 0xdf,
+	0x0a, 0xa0, 0x65, 0x65, 0x57, 0x0e, 0x7d, 0x13, 0xca, 0x54, 0xb4, 0x0b, 0x75, 0xa6, 0x53, 0xe4,
+	0xee, 0x4c, 0xcb, 0xe6, 0x79, 0x1a, 0xcd, 0xa9, 0x5b, 0x34, 0x1e, 0x13, 0x1a, 0xed, 0x04, 0xde,
+	0x9a, 0x35, 0x66, 0x0c, 0xc7, 0x6a, 0x7a, 0x38, 0x96, 0x52, 0x63, 0x4e, 0x7f, 0x01, 0x1f, 0x89,
+	0xd9, 0x15, 0x51, 0xa0, 0x9d, 0x21, 0x1c, 0xa1, 0x8c, 0xac, 0xf1, 0x94, 0x56, 0x4b, 0x71, 0x56,
+	0x2d, 0xfa, 0x4f, 0x0a, 0x1c, 0x5c, 0x44, 0x2c, 0x93, 0xb2, 0x48, 0x74, 0xb9, 0x13, 0x30, 0x47,
+	0x60, 0xca, 0x4a, 0x02, 0x5b, 0x5b, 0x51, 0x60, 0xeb, 0x4b, 0x0b, 0x6c, 0xe3, 0x2a, 0x04, 0xb6,
+	0xb9, 0x70, 0x18, 0x95, 0x96, 0x1e, 0x46, 0x0d, 0xf9, 0x6b, 0x75, 0x85, 0xda, 0xea, 0xff, 0x28,
+	0xb0, 0x97, 0xe7, 0x74, 0x5d, 0xb7, 0xd5, 0xeb, 0xf6, 0x22, 0xdd, 0xf9, 0xa5, 0xec, 0x0f, 0xc8,
+	0xdc, 0x54, 0xbf, 0xb1, 0xee, 0xdf, 0x87, 0xdd, 0xbc, 0xc8, 0xd1, 0x87, 0xcc, 0x7f, 0x0a, 0xec,
+	0xe7, 0x22, 0xa4, 0x0e, 0x7c, 0x78, 0x3b, 0xf9, 0x90, 0xb2, 0x13, 0xb3, 0xbc, 0xe0, 0xbe, 0x58,
+	0xe2, 0x98, 0x73, 0xbf, 0x93, 0x13, 0x93, 0x59, 0xb5, 0x32, 0xf0, 0xda, 0x1f, 0x45, 0xd8, 0xc9,
+	0x40, 0xaf, 0x7a, 0x4f, 0x5d, 0x4f, 0xb0, 0x73, 0x42, 0x7d, 0xf0, 0x73, 0x01, 0x3e, 0xf5, 0x78,
+	0xcf, 0xe8, 0x4f, 0x86, 0xc8, 0x1d, 0xb4, 0x7b, 0xc8, 0x8d, 0x2e, 0xed, 0x70, 0x66, 0x45, 0xff,
+	0x21, 0xf9, 0xc6, 0x10, 0x91, 0x27, 0x35, 0xfd, 0xd1, 0xee, 0xb1, 0xa0, 0x3f, 0xea, 0x84, 0x3b,
+	0xa9, 0xa7, 0x9c, 0xea, 0x91, 0xd3, 0x91, 0x6f, 0x9f, 0x1d, 0xf5, 0xbc, 0x7a, 0xd0, 0x67, 0xdc,
+	0x0e, 0x4f, 0x1a, 0x4c, 0xea, 0x0b, 0x1d, 0xea, 0x51, 0x94, 0xfa, 0xec, 0xbf, 0x5d, 0x9d, 0x0d,
+	0xb1, 0x7c, 0xfc, 0xff, 0x00, 0x14, 0x4b, 0xe7, 0xb3, 0x06, 0x13, 0x00, 0x00,
+}
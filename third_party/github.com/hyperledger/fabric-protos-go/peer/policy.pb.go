@@ -0,0 +1,195 @@
+/*
+Notice: This file has been modified for Hyperledger Fabric SDK Go usage.
+It is generated from the Fabric 2.x protos (fabric-protos-go fee30f3ccd23) and is not
+managed by the third_party pinning scripts; see the README in this directory.
+*/
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: peer/policy.proto
+
+package peer // import "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric-protos-go/peer"
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import common "github.com/hyperledger/fabric-sdk-go/third_party/github.com/hyperledger/fabric/protos/common"
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// ApplicationPolicy captures the diffenrent policy types that
+// are set and evaluted at the application level.
+type ApplicationPolicy struct {
+	// Types that are valid to be assigned to Type:
+	//	*ApplicationPolicy_SignaturePolicy
+	//	*ApplicationPolicy_ChannelConfigPolicyReference
+	Type                 isApplicationPolicy_Type `protobuf_oneof:"Type"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *ApplicationPolicy) Reset()         { *m = ApplicationPolicy{} }
+func (m *ApplicationPolicy) String() string { return proto.CompactTextString(m) }
+func (*ApplicationPolicy) ProtoMessage()    {}
+func (*ApplicationPolicy) Descriptor() ([]byte, []int) {
+	return fileDescriptor_policy_e1cb687b35340c05, []int{0}
+}
+func (m *ApplicationPolicy) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApplicationPolicy.Unmarshal(m, b)
+}
+func (m *ApplicationPolicy) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApplicationPolicy.Marshal(b, m, deterministic)
+}
+func (dst *ApplicationPolicy) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApplicationPolicy.Merge(dst, src)
+}
+func (m *ApplicationPolicy) XXX_Size() int {
+	return xxx_messageInfo_ApplicationPolicy.Size(m)
+}
+func (m *ApplicationPolicy) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApplicationPolicy.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApplicationPolicy proto.InternalMessageInfo
+
+type isApplicationPolicy_Type interface {
+	isApplicationPolicy_Type()
+}
+
+type ApplicationPolicy_SignaturePolicy struct {
+	SignaturePolicy *common.SignaturePolicyEnvelope `protobuf:"bytes,1,opt,name=signature_policy,json=signaturePolicy,proto3,oneof"`
+}
+
+type ApplicationPolicy_ChannelConfigPolicyReference struct {
+	ChannelConfigPolicyReference string `protobuf:"bytes,2,opt,name=channel_config_policy_reference,json=channelConfigPolicyReference,proto3,oneof"`
+}
+
+func (*ApplicationPolicy_SignaturePolicy) isApplicationPolicy_Type() {}
+
+func (*ApplicationPolicy_ChannelConfigPolicyReference) isApplicationPolicy_Type() {}
+
+func (m *ApplicationPolicy) GetType() isApplicationPolicy_Type {
+	if m != nil {
+		return m.Type
+	}
+	return nil
+}
+
+func (m *ApplicationPolicy) GetSignaturePolicy() *common.SignaturePolicyEnvelope {
+	if x, ok := m.GetType().(*ApplicationPolicy_SignaturePolicy); ok {
+		return x.SignaturePolicy
+	}
+	return nil
+}
+
+func (m *ApplicationPolicy) GetChannelConfigPolicyReference() string {
+	if x, ok := m.GetType().(*ApplicationPolicy_ChannelConfigPolicyReference); ok {
+		return x.ChannelConfigPolicyReference
+	}
+	return ""
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*ApplicationPolicy) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _ApplicationPolicy_OneofMarshaler, _ApplicationPolicy_OneofUnmarshaler, _ApplicationPolicy_OneofSizer, []interface{}{
+		(*ApplicationPolicy_SignaturePolicy)(nil),
+		(*ApplicationPolicy_ChannelConfigPolicyReference)(nil),
+	}
+}
+
+func _ApplicationPolicy_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*ApplicationPolicy)
+	// Type
+	switch x := m.Type.(type) {
+	case *ApplicationPolicy_SignaturePolicy:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SignaturePolicy); err != nil {
+			return err
+		}
+	case *ApplicationPolicy_ChannelConfigPolicyReference:
+		b.EncodeVarint(2<<3 | proto.WireBytes)
+		b.EncodeStringBytes(x.ChannelConfigPolicyReference)
+	case nil:
+	default:
+		return fmt.Errorf("ApplicationPolicy.Type has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _ApplicationPolicy_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*ApplicationPolicy)
+	switch tag {
+	case 1: // Type.signature_policy
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(common.SignaturePolicyEnvelope)
+		err := b.DecodeMessage(msg)
+		m.Type = &ApplicationPolicy_SignaturePolicy{msg}
+		return true, err
+	case 2: // Type.channel_config_policy_reference
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeStringBytes()
+		m.Type = &ApplicationPolicy_ChannelConfigPolicyReference{x}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _ApplicationPolicy_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*ApplicationPolicy)
+	// Type
+	switch x := m.Type.(type) {
+	case *ApplicationPolicy_SignaturePolicy:
+		s := proto.Size(x.SignaturePolicy)
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *ApplicationPolicy_ChannelConfigPolicyReference:
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(len(x.ChannelConfigPolicyReference)))
+		n += len(x.ChannelConfigPolicyReference)
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+func init() {
+	proto.RegisterType((*ApplicationPolicy)(nil), "sdk.protos.ApplicationPolicy")
+}
+
+func init() { proto.RegisterFile("peer/policy.proto", fileDescriptor_policy_e1cb687b35340c05) }
+
+var fileDescriptor_policy_e1cb687b35340c05 = []byte{
+	// 257 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x90, 0xc1, 0x4a, 0xc3, 0x40,
+	0x10, 0x86, 0x1b, 0x91, 0x82, 0xeb, 0x41, 0x1b, 0x10, 0x8a, 0x08, 0x2d, 0x3d, 0xf5, 0xd2, 0x5d,
+	0xd0, 0x27, 0xb0, 0x22, 0xf6, 0xe0, 0x41, 0xa2, 0xa7, 0x5e, 0x42, 0xb2, 0x99, 0x6c, 0x16, 0xd3,
+	0x9d, 0x65, 0x76, 0x2b, 0xe4, 0xe6, 0x33, 0xf9, 0x84, 0x92, 0x4c, 0x0b, 0x7a, 0xe9, 0x69, 0x0f,
+	0xff, 0xf7, 0x7f, 0xec, 0xfc, 0x62, 0xe2, 0x01, 0x48, 0x79, 0x6c, 0xad, 0xee, 0xa4, 0x27, 0x8c,
+	0x98, 0x8e, 0x87, 0x27, 0xdc, 0xde, 0x68, 0xdc, 0xed, 0xd0, 0x71, 0x68, 0x21, 0x70, 0xbc, 0xf8,
+	0x49, 0xc4, 0xe4, 0xd1, 0xfb, 0xd6, 0xea, 0x22, 0x5a, 0x74, 0x6f, 0x43, 0x35, 0x7d, 0x15, 0xd7,
+	0xc1, 0x1a, 0x57, 0xc4, 0x3d, 0x41, 0xce, 0xba, 0x69, 0x32, 0x4f, 0x96, 0x97, 0xf7, 0x33, 0xc9,
+	0x1e, 0xf9, 0x7e, 0xcc, 0xb9, 0xf2, 0xec, 0xbe, 0xa0, 0x45, 0x0f, 0x9b, 0x51, 0x76, 0x15, 0xfe,
+	0x47, 0xe9, 0x8b, 0x98, 0xe9, 0xa6, 0x70, 0x0e, 0xda, 0x5c, 0xa3, 0xab, 0xad, 0x39, 0x28, 0x73,
+	0x82, 0x1a, 0x08, 0x9c, 0x86, 0xe9, 0xd9, 0x3c, 0x59, 0x5e, 0x6c, 0x46, 0xd9, 0xdd, 0x01, 0x7c,
+	0x1a, 0x38, 0xee, 0x67, 0x47, 0x6a, 0x3d, 0x16, 0xe7, 0x1f, 0x9d, 0x87, 0xf5, 0x77, 0x22, 0x16,
+	0x48, 0x46, 0x36, 0x9d, 0x07, 0x6a, 0xa1, 0x32, 0x40, 0xb2, 0x2e, 0x4a, 0xb2, 0x9a, 0xaf, 0x0a,
+	0xb2, 0xdf, 0x61, 0xbb, 0x35, 0x36, 0x36, 0xfb, 0xb2, 0xff, 0xb1, 0xfa, 0x83, 0x2a, 0x46, 0x57,
+	0xa1, 0xfa, 0x5c, 0x19, 0x54, 0xb1, 0xb1, 0x54, 0xe5, 0xbe, 0xa0, 0xd8, 0xa9, 0x93, 0x05, 0xc5,
+	0x6e, 0xd5, 0xbb, 0x4b, 0x9e, 0xf5, 0xe1, 0x77, 0x00, 0xb1, 0x04, 0x02, 0x82, 0x72, 0x01, 0x00,
+	0x00,
+}